@@ -8,6 +8,7 @@ CREATE TABLE mst_user(
  role VARCHAR(100) NOT NULL DEFAULT 'user',
  email VARCHAR(100) NOT NULL UNIQUE, 
  phone_number VARCHAR(100) NOT NULL UNIQUE,
+ payment_tag VARCHAR(30) UNIQUE,
  created_at TIMESTAMP,
  updated_at TIMESTAMP
 );
@@ -25,19 +26,34 @@ CREATE TABLE mst_user_datas(
 
 CREATE TABLE mst_rekening_user(
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-    user_id UUID NOT NULL UNIQUE,
+    user_id UUID NOT NULL,
+    bank_code VARCHAR(10) NOT NULL,
     rekening VARCHAR(20) NOT NULL,
+    nama_pemilik VARCHAR(100) NOT NULL,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    is_verified BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMP NOT NULL,
     updated_at TIMESTAMP NOT NULL,
+    UNIQUE(user_id, bank_code, rekening),
     FOREIGN KEY(user_id) REFERENCES mst_user(id)
 );
 
+CREATE UNIQUE INDEX mst_rekening_user_default_key ON mst_rekening_user(user_id) WHERE is_default;
+
 CREATE TABLE withdraw_saldo(
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id UUID NOT NULL,
     withdraw BIGINT NOT NULL,
+    biaya BIGINT NOT NULL DEFAULT 0,
+    fee_rule_id UUID,
+    bank_code VARCHAR(10),
     rekening VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    hold_id UUID,
+    payout_ref VARCHAR(100),
+    alasan_gagal VARCHAR(250),
     created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
     FOREIGN KEY(user_id) REFERENCES mst_user(id)
 );
 
@@ -67,9 +83,21 @@ CREATE TABLE trx_send_transfer(
  jumlah_transfer BIGINT NOT NULL,
  jenis_transfer VARCHAR(100) NOT NULL,
  transfer_at VARCHAR(50) NOT NULL,
+ biaya BIGINT NOT NULL DEFAULT 0,
+ status VARCHAR(30) NOT NULL DEFAULT 'success',
+ reversal_of UUID,
+ reversed_by UUID,
+ catatan VARCHAR(250),
+ referensi VARCHAR(100),
+ kategori_pengirim VARCHAR(50),
  FOREIGN KEY(tujuan_transfer) REFERENCES mst_user(id),
- FOREIGN KEY(user_id) REFERENCES mst_user(id)
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(reversal_of) REFERENCES trx_send_transfer(id),
+ FOREIGN KEY(reversed_by) REFERENCES trx_send_transfer(id)
 );
+
+CREATE UNIQUE INDEX trx_send_transfer_schedule_key ON trx_send_transfer(user_id, referensi) WHERE referensi LIKE 'schedule:%';
+
 CREATE TABLE trx_receive_transfer(
  id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
  trx_id UUID NOT NULL,
@@ -78,6 +106,7 @@ CREATE TABLE trx_receive_transfer(
  jumlah_transfer BIGINT NOT NULL,
  jenis_transfer VARCHAR(100) NOT NULL,
  transfer_at VARCHAR(100) NOT NULL,
+ kategori_penerima VARCHAR(50),
  FOREIGN KEY(tujuan_transfer) REFERENCES mst_user(id),
  FOREIGN KEY(user_id) REFERENCES mst_user(id),
  FOREIGN KEY(trx_id) REFERENCES trx_send_transfer(id)
@@ -88,6 +117,9 @@ CREATE TABLE trx_topup_method_payment(
  user_id UUID NOT NULL,
  token_midtrans VARCHAR(255),
  ammount BIGINT NOT NULL,
+ biaya BIGINT NOT NULL DEFAULT 0,
+ fee_rule_id UUID,
+ channel VARCHAR(50),
  deskripsi VARCHAR(250) ,
  status VARCHAR(100) NOT NULL,
  url_payment VARCHAR(255),
@@ -95,3 +127,529 @@ CREATE TABLE trx_topup_method_payment(
  updated_at TIMESTAMP,
  FOREIGN KEY(user_id) REFERENCES mst_user(id)
 );
+
+CREATE TABLE trx_reversal(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ trx_id UUID NOT NULL,
+ requested_by UUID NOT NULL,
+ alasan VARCHAR(250),
+ policy VARCHAR(20) NOT NULL DEFAULT 'reject',
+ require_consent BOOLEAN NOT NULL DEFAULT FALSE,
+ jumlah_diminta BIGINT NOT NULL,
+ jumlah_dikembalikan BIGINT NOT NULL DEFAULT 0,
+ reversal_trx_id UUID,
+ status VARCHAR(30) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(trx_id) REFERENCES trx_send_transfer(id),
+ FOREIGN KEY(requested_by) REFERENCES mst_admin(id),
+ FOREIGN KEY(reversal_trx_id) REFERENCES trx_send_transfer(id)
+);
+
+CREATE UNIQUE INDEX trx_reversal_active_key ON trx_reversal(trx_id) WHERE status IN ('pending', 'pending_consent', 'completed');
+
+CREATE TABLE trx_transfer_intent(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ tujuan_transfer UUID NOT NULL,
+ jumlah_transfer BIGINT NOT NULL,
+ biaya BIGINT NOT NULL DEFAULT 0,
+ fee_rule_id UUID,
+ catatan VARCHAR(250),
+ referensi VARCHAR(100),
+ kategori VARCHAR(50),
+ status VARCHAR(20) NOT NULL DEFAULT 'pending',
+ transfer_id UUID,
+ expires_at TIMESTAMP NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(tujuan_transfer) REFERENCES mst_user(id),
+ FOREIGN KEY(transfer_id) REFERENCES trx_send_transfer(id)
+);
+
+CREATE TABLE trx_fund_hold(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ jumlah BIGINT NOT NULL,
+ jumlah_captured BIGINT NOT NULL DEFAULT 0,
+ alasan VARCHAR(250),
+ reference_id VARCHAR(100),
+ sumber VARCHAR(20) NOT NULL DEFAULT 'user',
+ penerima_jenis VARCHAR(20),
+ penerima_id VARCHAR(100),
+ status VARCHAR(30) NOT NULL DEFAULT 'active',
+ expires_at TIMESTAMP,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_dispute(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ trx_type VARCHAR(20) NOT NULL,
+ trx_id UUID NOT NULL,
+ counterparty_id UUID,
+ jumlah BIGINT NOT NULL,
+ deskripsi VARCHAR(500) NOT NULL,
+ status VARCHAR(30) NOT NULL DEFAULT 'open',
+ hold_id UUID,
+ reversal_id UUID,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(counterparty_id) REFERENCES mst_user(id),
+ FOREIGN KEY(hold_id) REFERENCES trx_fund_hold(id),
+ FOREIGN KEY(reversal_id) REFERENCES trx_reversal(id)
+);
+
+CREATE UNIQUE INDEX trx_dispute_active_key ON trx_dispute(trx_id) WHERE status IN ('open', 'investigating');
+
+CREATE TABLE trx_dispute_comment(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ dispute_id UUID NOT NULL,
+ author_id UUID NOT NULL,
+ author_role VARCHAR(10) NOT NULL,
+ komentar VARCHAR(500) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(dispute_id) REFERENCES trx_dispute(id)
+);
+
+ALTER TABLE withdraw_saldo ADD FOREIGN KEY(hold_id) REFERENCES trx_fund_hold(id);
+
+CREATE TABLE mst_fee_rule(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ operation VARCHAR(20) NOT NULL,
+ channel VARCHAR(50),
+ fee_type VARCHAR(20) NOT NULL,
+ nilai NUMERIC(12,4) NOT NULL DEFAULT 0,
+ tiers JSONB,
+ min_fee BIGINT NOT NULL DEFAULT 0,
+ max_fee BIGINT NOT NULL DEFAULT 0,
+ free_quota INTEGER NOT NULL DEFAULT 0,
+ is_active BOOLEAN NOT NULL DEFAULT TRUE,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX mst_fee_rule_active_key ON mst_fee_rule(operation, COALESCE(channel, '')) WHERE is_active;
+
+CREATE TABLE mst_house_wallet(
+ nama VARCHAR(50) PRIMARY KEY,
+ saldo BIGINT NOT NULL DEFAULT 0,
+ updated_at TIMESTAMP
+);
+
+INSERT INTO mst_house_wallet (nama, saldo, updated_at) VALUES ('revenue', 0, NOW());
+
+CREATE TABLE trx_fee(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ operation VARCHAR(20) NOT NULL,
+ reference_id UUID NOT NULL,
+ rule_id UUID,
+ jumlah BIGINT NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(rule_id) REFERENCES mst_fee_rule(id)
+);
+
+CREATE TABLE trx_scheduled_transfer(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ tujuan_transfer UUID NOT NULL,
+ jumlah_transfer BIGINT NOT NULL,
+ catatan VARCHAR(250),
+ kategori VARCHAR(50),
+ frekuensi VARCHAR(20) NOT NULL,
+ start_at TIMESTAMP NOT NULL,
+ next_run_at TIMESTAMP NOT NULL,
+ end_date TIMESTAMP,
+ max_runs INTEGER NOT NULL DEFAULT 0,
+ run_count INTEGER NOT NULL DEFAULT 0,
+ retry_count INTEGER NOT NULL DEFAULT 0,
+ status VARCHAR(20) NOT NULL DEFAULT 'active',
+ last_error VARCHAR(250),
+ last_transfer_id UUID,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(tujuan_transfer) REFERENCES mst_user(id),
+ FOREIGN KEY(last_transfer_id) REFERENCES trx_send_transfer(id)
+);
+
+CREATE INDEX trx_scheduled_transfer_due_idx ON trx_scheduled_transfer(next_run_at) WHERE status = 'active';
+
+CREATE TABLE mst_notification(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ jenis VARCHAR(50) NOT NULL,
+ judul VARCHAR(100) NOT NULL,
+ pesan VARCHAR(500) NOT NULL,
+ reference_id VARCHAR(100),
+ is_read BOOLEAN NOT NULL DEFAULT FALSE,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_split_bill(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ organiser_id UUID NOT NULL,
+ judul VARCHAR(100) NOT NULL,
+ total BIGINT NOT NULL,
+ mode VARCHAR(10) NOT NULL,
+ status VARCHAR(20) NOT NULL DEFAULT 'open',
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(organiser_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_payment_request(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ requester_id UUID NOT NULL,
+ payer_id UUID NOT NULL,
+ jumlah BIGINT NOT NULL,
+ catatan VARCHAR(250),
+ status VARCHAR(20) NOT NULL DEFAULT 'pending',
+ transfer_id UUID,
+ split_id UUID,
+ expires_at TIMESTAMP NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(requester_id) REFERENCES mst_user(id),
+ FOREIGN KEY(payer_id) REFERENCES mst_user(id),
+ FOREIGN KEY(transfer_id) REFERENCES trx_send_transfer(id),
+ FOREIGN KEY(split_id) REFERENCES trx_split_bill(id)
+);
+
+CREATE TABLE trx_batch_transfer(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ mode VARCHAR(20) NOT NULL,
+ status VARCHAR(30) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_batch_item(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ batch_id UUID NOT NULL,
+ baris INTEGER NOT NULL,
+ tujuan VARCHAR(100) NOT NULL,
+ tujuan_transfer UUID,
+ nama_penerima VARCHAR(100),
+ jumlah BIGINT NOT NULL,
+ biaya BIGINT NOT NULL DEFAULT 0,
+ fee_rule_id UUID,
+ catatan VARCHAR(250),
+ status VARCHAR(20) NOT NULL,
+ error VARCHAR(250),
+ transfer_id UUID,
+ FOREIGN KEY(batch_id) REFERENCES trx_batch_transfer(id),
+ FOREIGN KEY(tujuan_transfer) REFERENCES mst_user(id),
+ FOREIGN KEY(transfer_id) REFERENCES trx_send_transfer(id)
+);
+
+CREATE TABLE mst_merchant(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ owner_id UUID NOT NULL,
+ nama VARCHAR(100) NOT NULL,
+ kategori VARCHAR(4) NOT NULL,
+ kota VARCHAR(50) NOT NULL,
+ alamat VARCHAR(250),
+ bank_code VARCHAR(10) NOT NULL,
+ rekening VARCHAR(20) NOT NULL,
+ nama_pemilik VARCHAR(100) NOT NULL,
+ saldo BIGINT NOT NULL DEFAULT 0,
+ jadwal_settlement VARCHAR(5) NOT NULL DEFAULT 'T+1',
+ minimum_settlement BIGINT NOT NULL DEFAULT 0,
+ status VARCHAR(20) NOT NULL DEFAULT 'active',
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(owner_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_merchant_payment(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ merchant_id UUID NOT NULL,
+ user_id UUID NOT NULL,
+ qr_id UUID,
+ jumlah BIGINT NOT NULL,
+ jumlah_refund BIGINT NOT NULL DEFAULT 0,
+ catatan VARCHAR(250),
+ referensi VARCHAR(100),
+ status VARCHAR(20) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id),
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_merchant_qr(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ merchant_id UUID NOT NULL,
+ jumlah BIGINT NOT NULL,
+ referensi VARCHAR(100),
+ status VARCHAR(20) NOT NULL DEFAULT 'active',
+ payment_id UUID,
+ expires_at TIMESTAMP NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id),
+ FOREIGN KEY(payment_id) REFERENCES trx_merchant_payment(id)
+);
+
+ALTER TABLE trx_merchant_payment ADD FOREIGN KEY(qr_id) REFERENCES trx_merchant_qr(id);
+
+CREATE TABLE mst_merchant_api_key(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ merchant_id UUID NOT NULL,
+ key_id VARCHAR(40) NOT NULL UNIQUE,
+ secret_enc TEXT NOT NULL,
+ scopes TEXT[] NOT NULL DEFAULT '{}',
+ status VARCHAR(20) NOT NULL DEFAULT 'active',
+ last_used_at TIMESTAMP,
+ created_at TIMESTAMP NOT NULL,
+ revoked_at TIMESTAMP,
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id)
+);
+
+CREATE TABLE trx_api_nonce(
+ key_id VARCHAR(40) NOT NULL,
+ nonce VARCHAR(64) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ PRIMARY KEY(key_id, nonce)
+);
+
+CREATE TABLE mst_webhook_endpoint(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ merchant_id UUID NOT NULL,
+ url VARCHAR(500) NOT NULL,
+ secret_enc TEXT NOT NULL,
+ events TEXT[] NOT NULL DEFAULT '{}',
+ is_active BOOLEAN NOT NULL DEFAULT TRUE,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id)
+);
+
+CREATE TABLE trx_webhook_delivery(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ endpoint_id UUID NOT NULL,
+ event_id UUID NOT NULL,
+ event_type VARCHAR(50) NOT NULL,
+ payload JSONB NOT NULL,
+ status VARCHAR(20) NOT NULL DEFAULT 'pending',
+ attempts INTEGER NOT NULL DEFAULT 0,
+ next_attempt_at TIMESTAMP NOT NULL,
+ last_status_code INTEGER,
+ last_error VARCHAR(250),
+ delivered_at TIMESTAMP,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(endpoint_id) REFERENCES mst_webhook_endpoint(id)
+);
+
+CREATE INDEX trx_webhook_delivery_due_idx ON trx_webhook_delivery(next_attempt_at) WHERE status = 'pending';
+
+CREATE TABLE trx_webhook_attempt(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ delivery_id UUID NOT NULL,
+ attempt INTEGER NOT NULL,
+ status_code INTEGER,
+ error VARCHAR(250),
+ duration_ms INTEGER NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(delivery_id) REFERENCES trx_webhook_delivery(id)
+);
+
+CREATE TABLE trx_checkout_session(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ merchant_id UUID NOT NULL,
+ order_id VARCHAR(100) NOT NULL,
+ jumlah BIGINT NOT NULL,
+ deskripsi VARCHAR(250),
+ success_url VARCHAR(500) NOT NULL,
+ cancel_url VARCHAR(500) NOT NULL,
+ status VARCHAR(20) NOT NULL DEFAULT 'open',
+ user_id UUID,
+ payment_id UUID,
+ expires_at TIMESTAMP NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ UNIQUE(merchant_id, order_id),
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id),
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(payment_id) REFERENCES trx_merchant_payment(id)
+);
+
+CREATE TABLE trx_settlement(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ merchant_id UUID NOT NULL,
+ tanggal DATE NOT NULL,
+ cutoff TIMESTAMP NOT NULL,
+ jumlah_transaksi INTEGER NOT NULL,
+ total_gross BIGINT NOT NULL,
+ total_biaya BIGINT NOT NULL,
+ total_net BIGINT NOT NULL,
+ bank_code VARCHAR(10) NOT NULL,
+ rekening VARCHAR(20) NOT NULL,
+ status VARCHAR(20) NOT NULL,
+ payout_ref VARCHAR(100),
+ alasan_gagal VARCHAR(250),
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ UNIQUE(merchant_id, tanggal),
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id)
+);
+
+CREATE TABLE trx_settlement_item(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ settlement_id UUID NOT NULL,
+ payment_id UUID NOT NULL UNIQUE,
+ jumlah BIGINT NOT NULL,
+ biaya BIGINT NOT NULL,
+ fee_rule_id UUID,
+ FOREIGN KEY(settlement_id) REFERENCES trx_settlement(id),
+ FOREIGN KEY(payment_id) REFERENCES trx_merchant_payment(id),
+ FOREIGN KEY(fee_rule_id) REFERENCES mst_fee_rule(id)
+);
+
+CREATE TABLE trx_merchant_refund(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ payment_id UUID NOT NULL,
+ merchant_id UUID NOT NULL,
+ user_id UUID NOT NULL,
+ jumlah BIGINT NOT NULL,
+ alasan VARCHAR(250),
+ referensi VARCHAR(100),
+ status VARCHAR(20) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ UNIQUE(merchant_id, referensi),
+ FOREIGN KEY(payment_id) REFERENCES trx_merchant_payment(id),
+ FOREIGN KEY(merchant_id) REFERENCES mst_merchant(id),
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE mst_budget(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ kategori VARCHAR(50) NOT NULL DEFAULT '',
+ jumlah BIGINT NOT NULL,
+ is_active BOOLEAN NOT NULL DEFAULT TRUE,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ UNIQUE(user_id, kategori),
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_budget_alert(
+ budget_id UUID NOT NULL,
+ periode VARCHAR(7) NOT NULL,
+ ambang INTEGER NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ PRIMARY KEY(budget_id, periode, ambang),
+ FOREIGN KEY(budget_id) REFERENCES mst_budget(id) ON DELETE CASCADE
+);
+
+CREATE TABLE mst_pocket(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ user_id UUID NOT NULL,
+ nama VARCHAR(50) NOT NULL,
+ saldo BIGINT NOT NULL DEFAULT 0 CHECK (saldo >= 0),
+ target BIGINT NOT NULL DEFAULT 0,
+ target_date TIMESTAMP,
+ status VARCHAR(20) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ closed_at TIMESTAMP,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE UNIQUE INDEX mst_pocket_active_name_key ON mst_pocket(user_id, LOWER(nama)) WHERE status = 'active';
+
+-- arah masuk: dari saldo utama ke pocket, keluar: dari pocket ke saldo utama
+CREATE TABLE trx_pocket_move(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ pocket_id UUID NOT NULL,
+ user_id UUID NOT NULL,
+ arah VARCHAR(10) NOT NULL,
+ jumlah BIGINT NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(pocket_id) REFERENCES mst_pocket(id),
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE mst_family(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ owner_id UUID NOT NULL UNIQUE,
+ nama VARCHAR(50) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(owner_id) REFERENCES mst_user(id)
+);
+
+-- limit_harian dan ambang_persetujuan 0 berarti tanpa batas
+CREATE TABLE mst_family_member(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ family_id UUID NOT NULL,
+ user_id UUID NOT NULL UNIQUE,
+ limit_harian BIGINT NOT NULL DEFAULT 0,
+ jenis_diizinkan TEXT[] NOT NULL DEFAULT '{}',
+ ambang_persetujuan BIGINT NOT NULL DEFAULT 0,
+ status VARCHAR(20) NOT NULL DEFAULT 'pending',
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(family_id) REFERENCES mst_family(id) ON DELETE CASCADE,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_family_approval(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ family_id UUID NOT NULL,
+ user_id UUID NOT NULL,
+ jenis VARCHAR(20) NOT NULL,
+ tujuan VARCHAR(100) NOT NULL DEFAULT '',
+ keterangan VARCHAR(250),
+ jumlah BIGINT NOT NULL,
+ status VARCHAR(20) NOT NULL,
+ expires_at TIMESTAMP NOT NULL,
+ decided_at TIMESTAMP,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(family_id) REFERENCES mst_family(id) ON DELETE CASCADE,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE TABLE trx_family_activity(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ family_id UUID NOT NULL,
+ user_id UUID NOT NULL,
+ jenis VARCHAR(30) NOT NULL,
+ jumlah BIGINT NOT NULL DEFAULT 0,
+ keterangan VARCHAR(250),
+ referensi VARCHAR(100),
+ created_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(family_id) REFERENCES mst_family(id) ON DELETE CASCADE,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id)
+);
+
+CREATE INDEX trx_family_activity_family_idx ON trx_family_activity(family_id, created_at);
+
+-- reservasi active dihitung ke limit harian anggota, released jika transaksinya gagal
+CREATE TABLE trx_family_reservation(
+ id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+ family_id UUID NOT NULL,
+ user_id UUID NOT NULL,
+ jenis VARCHAR(20) NOT NULL,
+ jumlah BIGINT NOT NULL,
+ approval_id UUID,
+ status VARCHAR(20) NOT NULL,
+ created_at TIMESTAMP NOT NULL,
+ updated_at TIMESTAMP NOT NULL,
+ FOREIGN KEY(family_id) REFERENCES mst_family(id) ON DELETE CASCADE,
+ FOREIGN KEY(user_id) REFERENCES mst_user(id),
+ FOREIGN KEY(approval_id) REFERENCES trx_family_approval(id)
+);
+
+CREATE INDEX trx_family_reservation_user_idx ON trx_family_reservation(user_id, created_at);
@@ -71,7 +71,7 @@ func (c *Config) readConfig() error {
 	}
 
 	if c.ApiPort == "" || c.Host == "" || c.Port == "" || c.Name == "" || c.User == "" || c.FilePath == "" || c.IssuerName == "" ||
-		c.JwtSignatureKey == nil || c.JwtLifeTime == 0 {
+		c.JwtSignatureKey == nil || c.JwtLifeTime == 0 || os.Getenv("API_SECRET_KEY") == "" {
 		return errors.New("environment required")
 	}
 
@@ -14,6 +14,7 @@ import (
 type AdminController struct {
 	ua usecase.AdminUseCase
 	uc usecase.UserUseCase
+	us usecase.StatementUseCase
 	rg *gin.RouterGroup
 }
 
@@ -55,15 +56,27 @@ func (a *AdminController) GetUserInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
+// StatementHandler membuat rekening koran user mana pun untuk keperluan audit
+func (a *AdminController) StatementHandler(c *gin.Context) {
+	statement, err := a.us.Generate(c.Param("id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sendStatement(c, a.us, statement)
+}
+
 func (a *AdminController) Route() {
 	rg := a.rg.Group("/admin")
 	{
 		rg.POST("/", a.RegisterHandler)
 		rg.POST("/login", a.LoginHandler)
 		rg.GET("/user/:id", common.JWTAuth("admin"), a.GetUserInfo)
+		rg.GET("/user/:id/statement", common.JWTAuth("admin"), a.StatementHandler)
 	}
 }
 
-func NewAdminController(ua usecase.AdminUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *AdminController {
-	return &AdminController{ua: ua, uc: uc, rg: rg}
+func NewAdminController(ua usecase.AdminUseCase, uc usecase.UserUseCase, us usecase.StatementUseCase, rg *gin.RouterGroup) *AdminController {
+	return &AdminController{ua: ua, uc: uc, us: us, rg: rg}
 }
@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type AnalyticsController struct {
+	ua usecase.AnalyticsUseCase
+	rg *gin.RouterGroup
+}
+
+func (a *AnalyticsController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := a.ua.GetAnalytics(id, c.Query("from"), c.Query("to"), c.Query("period"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (a *AnalyticsController) Route() {
+	a.rg.GET("/analytics", common.JWTAuth("user"), a.GetHandler)
+}
+
+func NewAnalyticsController(ua usecase.AnalyticsUseCase, rg *gin.RouterGroup) *AnalyticsController {
+	return &AnalyticsController{ua: ua, rg: rg}
+}
@@ -0,0 +1,173 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/delivery/middleware"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+// satu batch me-resolve banyak penerima sekaligus, jumlah baris yang divalidasi per user dibatasi per jam
+// di samping recipientLimit per request
+const batchRecipientRowLimit = 1000
+
+var batchRecipientLimiter = middleware.NewRateLimiter(batchRecipientRowLimit, time.Hour)
+
+type BatchController struct {
+	ub usecase.BatchUseCase
+	uc usecase.UserUseCase
+	rg *gin.RouterGroup
+}
+
+// CreateHandler menerima file CSV (multipart "file" atau body text/csv) maupun JSON
+func (b *BatchController) CreateHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+
+	var mode string
+	var rows []dto.BatchRow
+	switch {
+	case strings.HasPrefix(c.ContentType(), "multipart/form-data"):
+		file, err := c.FormFile("file")
+		if err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, "file CSV harus diunggah")
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		defer f.Close()
+		rows, err = usecase.ParseBatchCSV(f)
+		if err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		mode = c.PostForm("mode")
+	case c.ContentType() == "text/csv":
+		var err error
+		rows, err = usecase.ParseBatchCSV(c.Request.Body)
+		if err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		mode = c.Query("mode")
+	default:
+		var payload dto.BatchTransferRequest
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		mode, rows = payload.Mode, payload.Items
+	}
+	if !batchRecipientLimiter.Allow(userId, len(rows)) {
+		common.SendErrorResponse(c, http.StatusTooManyRequests, "terlalu banyak penerima divalidasi, coba lagi nanti")
+		return
+	}
+
+	response, err := b.ub.CreateBatch(userId, mode, rows)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (b *BatchController) GetAllHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := b.ub.FindBatches(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (b *BatchController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := b.ub.FindBatch(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+// ConfirmHandler cukup satu kali PIN untuk seluruh baris dalam batch
+func (b *BatchController) ConfirmHandler(c *gin.Context) {
+	var payload dto.PinRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+	sendBalance, err := b.uc.GetBalanceCase(userId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sendBalance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+
+	response, err := b.ub.ConfirmBatch(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (b *BatchController) Route() {
+	rg := b.rg.Group("/batch-transfers")
+	{
+		rg.POST("/", common.JWTAuth("user"), recipientLimit, b.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), b.GetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user"), b.GetHandler)
+		rg.POST("/:id/confirm", common.JWTAuth("user"), b.ConfirmHandler)
+	}
+}
+
+func NewBatchController(ub usecase.BatchUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *BatchController {
+	return &BatchController{ub: ub, uc: uc, rg: rg}
+}
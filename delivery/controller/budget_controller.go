@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type BudgetController struct {
+	ub usecase.BudgetUseCase
+	rg *gin.RouterGroup
+}
+
+func (b *BudgetController) CreateHandler(c *gin.Context) {
+	var payload dto.BudgetRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := b.ub.CreateBudget(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (b *BudgetController) GetAllHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := b.ub.FindBudgets(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (b *BudgetController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := b.ub.FindBudget(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (b *BudgetController) UpdateHandler(c *gin.Context) {
+	var payload dto.BudgetRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := b.ub.UpdateBudget(c.Param("id"), payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (b *BudgetController) DeleteHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	if err := b.ub.DeleteBudget(c.Param("id"), id); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", nil)
+}
+
+func (b *BudgetController) Route() {
+	rg := b.rg.Group("/budgets")
+	{
+		rg.POST("/", common.JWTAuth("user"), b.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), b.GetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user"), b.GetHandler)
+		rg.PUT("/:id", common.JWTAuth("user"), b.UpdateHandler)
+		rg.DELETE("/:id", common.JWTAuth("user"), b.DeleteHandler)
+	}
+}
+
+func NewBudgetController(ub usecase.BudgetUseCase, rg *gin.RouterGroup) *BudgetController {
+	return &BudgetController{ub: ub, rg: rg}
+}
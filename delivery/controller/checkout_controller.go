@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/delivery/middleware"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type CheckoutController struct {
+	uco usecase.CheckoutUseCase
+	uk  usecase.MerchantKeyUseCase
+	uc  usecase.UserUseCase
+	rg  *gin.RouterGroup
+}
+
+func (co *CheckoutController) CreateHandler(c *gin.Context) {
+	var payload dto.CheckoutSessionRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := co.uco.CreateSession(key.MerchantId, payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (co *CheckoutController) MerchantGetHandler(c *gin.Context) {
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := co.uco.FindMerchantSession(c.Param("id"), key.MerchantId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (co *CheckoutController) GetHandler(c *gin.Context) {
+	response, err := co.uco.FindSession(c.Param("id"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+// ConfirmHandler mengembalikan redirect_url ke success_url merchant setelah pembayaran berhasil
+func (co *CheckoutController) ConfirmHandler(c *gin.Context) {
+	var payload dto.PinRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+	sendBalance, err := co.uc.GetBalanceCase(userId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sendBalance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+
+	response, err := co.uco.ConfirmSession(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (co *CheckoutController) CancelHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := co.uco.CancelSession(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (co *CheckoutController) Route() {
+	api := co.rg.Group("/merchant/checkout-sessions")
+	{
+		api.POST("/", middleware.MerchantAuthMiddleware(co.uk, model.MerchantScopeCheckout), co.CreateHandler)
+		api.GET("/:id", middleware.MerchantAuthMiddleware(co.uk, model.MerchantScopeCheckout), co.MerchantGetHandler)
+	}
+	rg := co.rg.Group("/checkout")
+	{
+		rg.GET("/:id", common.JWTAuth("user"), co.GetHandler)
+		rg.POST("/:id/confirm", common.JWTAuth("user"), co.ConfirmHandler)
+		rg.POST("/:id/cancel", common.JWTAuth("user"), co.CancelHandler)
+	}
+}
+
+func NewCheckoutController(uco usecase.CheckoutUseCase, uk usecase.MerchantKeyUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *CheckoutController {
+	return &CheckoutController{uco: uco, uk: uk, uc: uc, rg: rg}
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type DisputeController struct {
+	ud usecase.DisputeUseCase
+	rg *gin.RouterGroup
+}
+
+func (d *DisputeController) CreateHandler(c *gin.Context) {
+	var payload dto.DisputeRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := d.ud.OpenDispute(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (d *DisputeController) GetAllHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := d.ud.FindAll(id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (d *DisputeController) AdminGetAllHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+
+	datas, err := d.ud.FindByStatus(c.Query("status"), page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (d *DisputeController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	dataClaims := claims.(*common.JwtClaim).DataClaims
+
+	response, err := d.ud.FindById(c.Param("id"), dataClaims.Id, dataClaims.Role)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (d *DisputeController) CommentHandler(c *gin.Context) {
+	var payload dto.DisputeCommentRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	dataClaims := claims.(*common.JwtClaim).DataClaims
+
+	response, err := d.ud.AddComment(c.Param("id"), payload, dataClaims.Id, dataClaims.Role)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (d *DisputeController) UpdateStatusHandler(c *gin.Context) {
+	var payload dto.DisputeStatusRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	adminId := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := d.ud.UpdateStatus(c.Param("id"), payload, adminId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (d *DisputeController) Route() {
+	rg := d.rg.Group("/disputes")
+	{
+		rg.POST("/", common.JWTAuth("user"), d.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), d.GetAllHandler)
+		rg.GET("/admin", common.JWTAuth("admin"), d.AdminGetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user", "admin"), d.GetHandler)
+		rg.POST("/:id/comments", common.JWTAuth("user", "admin"), d.CommentHandler)
+		rg.PUT("/:id/status", common.JWTAuth("admin"), d.UpdateStatusHandler)
+	}
+}
+
+func NewDisputeController(ud usecase.DisputeUseCase, rg *gin.RouterGroup) *DisputeController {
+	return &DisputeController{ud: ud, rg: rg}
+}
@@ -0,0 +1,357 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+// kategori transfer untuk dana yang dikirim owner ke anggota keluarga
+const familyFundCategory = "keluarga"
+
+type FamilyController struct {
+	uf usecase.FamilyUseCase
+	ut usecase.TransferUseCase
+	uc usecase.UserUseCase
+	rg *gin.RouterGroup
+}
+
+func (f *FamilyController) CreateHandler(c *gin.Context) {
+	var payload dto.FamilyRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OwnerId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.CreateFamily(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.FindFamily(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) AddMemberHandler(c *gin.Context) {
+	var payload dto.FamilyMemberRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.User == "" {
+		common.SendErrorResponse(c, http.StatusBadRequest, "user harus diisi")
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OwnerId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.AddMember(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) GetMemberHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.FindMember(c.Param("userId"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) UpdateMemberHandler(c *gin.Context) {
+	var payload dto.FamilyMemberRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OwnerId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.UpdateMember(c.Param("userId"), payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) RemoveMemberHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	if err := f.uf.RemoveMember(c.Param("userId"), id); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", nil)
+}
+
+func (f *FamilyController) AcceptInviteHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.AcceptInvite(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) DeclineInviteHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	if err := f.uf.DeclineInvite(id); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", nil)
+}
+
+func (f *FamilyController) LeaveHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	if err := f.uf.LeaveFamily(id); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", nil)
+}
+
+// FundHandler mengirim saldo owner ke anggota keluarga lewat transfer biasa lalu mencatatnya di feed
+func (f *FamilyController) FundHandler(c *gin.Context) {
+	var payload dto.FamilyFundRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	ownerId := claims.(*common.JwtClaim).DataClaims.Id
+	member, err := f.uf.FindMember(c.Param("userId"), ownerId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	if member.Status != model.FamilyMemberActive {
+		common.SendErrorResponse(c, http.StatusBadRequest, "anggota belum menerima undangan keluarga")
+		return
+	}
+	send, err := f.uc.FindById(ownerId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	sendBalance, err := f.uc.GetBalanceCase(ownerId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sendBalance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+	receive, err := f.uc.FindById(member.UserId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	receiveBalance, err := f.uc.GetBalanceCase(member.UserId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Penerima harus memverifikasi akun terlebih dahulu")
+		return
+	}
+	send.Saldo = sendBalance.Saldo
+	receive.Saldo = receiveBalance.Saldo
+
+	response, err := f.ut.TransferRequest(dto.TransferRequest{
+		UserId:         ownerId,
+		TujuanTransfer: member.UserId,
+		JumlahTransfer: payload.Jumlah,
+		Catatan:        payload.Catatan,
+		Kategori:       familyFundCategory,
+	}, send, receive)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	f.uf.Record(model.FamilyActivity{UserId: member.UserId, Jenis: model.FamilyActivityFunding, Jumlah: payload.Jumlah,
+		Keterangan: payload.Catatan, Referensi: response.Id})
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) GetApprovalsHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.FindApprovals(id, c.Query("status"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) ApproveHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.ApproveRequest(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) RejectHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.RejectRequest(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) GetActivityHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.FindActivity(id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FamilyController) Route() {
+	rg := f.rg.Group("/families")
+	{
+		rg.POST("/", common.JWTAuth("user"), f.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), f.GetHandler)
+		rg.POST("/members", common.JWTAuth("user"), recipientLimit, f.AddMemberHandler)
+		rg.GET("/members/:userId", common.JWTAuth("user"), f.GetMemberHandler)
+		rg.PUT("/members/:userId", common.JWTAuth("user"), f.UpdateMemberHandler)
+		rg.DELETE("/members/:userId", common.JWTAuth("user"), f.RemoveMemberHandler)
+		rg.POST("/members/:userId/fund", common.JWTAuth("user"), f.FundHandler)
+		rg.POST("/invite/accept", common.JWTAuth("user"), f.AcceptInviteHandler)
+		rg.POST("/invite/decline", common.JWTAuth("user"), f.DeclineInviteHandler)
+		rg.POST("/leave", common.JWTAuth("user"), f.LeaveHandler)
+		rg.GET("/approvals", common.JWTAuth("user"), f.GetApprovalsHandler)
+		rg.POST("/approvals/:id/approve", common.JWTAuth("user"), f.ApproveHandler)
+		rg.POST("/approvals/:id/reject", common.JWTAuth("user"), f.RejectHandler)
+		rg.GET("/activity", common.JWTAuth("user"), f.GetActivityHandler)
+	}
+}
+
+func NewFamilyController(uf usecase.FamilyUseCase, ut usecase.TransferUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *FamilyController {
+	return &FamilyController{uf: uf, ut: ut, uc: uc, rg: rg}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type FeeController struct {
+	uf usecase.FeeUseCase
+	rg *gin.RouterGroup
+}
+
+func (f *FeeController) QuoteHandler(c *gin.Context) {
+	var payload dto.FeeQuoteRequest
+	if err := c.ShouldBindQuery(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := f.uf.Quote(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FeeController) HistoryHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := f.uf.FindFees(id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (f *FeeController) CreateRuleHandler(c *gin.Context) {
+	var payload model.FeeRule
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := f.uf.CreateRule(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (f *FeeController) GetRulesHandler(c *gin.Context) {
+	datas, err := f.uf.FindRules()
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (f *FeeController) UpdateRuleHandler(c *gin.Context) {
+	var payload model.FeeRule
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := f.uf.UpdateRule(c.Param("id"), payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FeeController) DeactivateRuleHandler(c *gin.Context) {
+	response, err := f.uf.DeactivateRule(c.Param("id"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FeeController) RevenueHandler(c *gin.Context) {
+	response, err := f.uf.GetRevenue()
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (f *FeeController) Route() {
+	rg := f.rg.Group("/fees")
+	{
+		rg.GET("/quote", common.JWTAuth("user"), f.QuoteHandler)
+		rg.GET("/history", common.JWTAuth("user"), f.HistoryHandler)
+		rg.GET("/revenue", common.JWTAuth("admin"), f.RevenueHandler)
+		rr := rg.Group("/rules")
+		{
+			rr.POST("/", common.JWTAuth("admin"), f.CreateRuleHandler)
+			rr.GET("/", common.JWTAuth("admin"), f.GetRulesHandler)
+			rr.PUT("/:id", common.JWTAuth("admin"), f.UpdateRuleHandler)
+			rr.DELETE("/:id", common.JWTAuth("admin"), f.DeactivateRuleHandler)
+		}
+	}
+}
+
+func NewFeeController(uf usecase.FeeUseCase, rg *gin.RouterGroup) *FeeController {
+	return &FeeController{uf: uf, rg: rg}
+}
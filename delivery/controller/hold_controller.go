@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/delivery/middleware"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type HoldController struct {
+	uh usecase.HoldUseCase
+	uc usecase.UserUseCase
+	uk usecase.MerchantKeyUseCase
+	rg *gin.RouterGroup
+}
+
+func (h *HoldController) AuthorizeHandler(c *gin.Context) {
+	var payload dto.HoldRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+	balance, err := h.uc.GetBalanceCase(payload.UserId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if balance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+
+	response, err := h.uh.Authorize(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (h *HoldController) GetActiveHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := h.uh.FindActive(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (h *HoldController) CaptureHandler(c *gin.Context) {
+	var payload dto.CaptureRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := h.uh.Capture(c.Param("id"), payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (h *HoldController) VoidHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	dataClaims := claims.(*common.JwtClaim).DataClaims
+
+	response, err := h.uh.Void(c.Param("id"), dataClaims.Id, dataClaims.Role)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (h *HoldController) MerchantCaptureHandler(c *gin.Context) {
+	var payload dto.MerchantCaptureRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := h.uh.MerchantCapture(c.Param("id"), key.MerchantId, payload.Jumlah)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (h *HoldController) MerchantVoidHandler(c *gin.Context) {
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := h.uh.MerchantVoid(c.Param("id"), key.MerchantId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+// Route: authorize selalu dilakukan user dengan PIN (merchant_id diisi untuk hold merchant), merchant hanya
+// bisa capture atau void hold yang dibuat untuknya lewat API yang ditandatangani
+func (h *HoldController) Route() {
+	rg := h.rg.Group("/holds")
+	{
+		rg.POST("/", common.JWTAuth("user"), h.AuthorizeHandler)
+		rg.GET("/", common.JWTAuth("user"), h.GetActiveHandler)
+		rg.POST("/:id/capture", common.JWTAuth("admin"), h.CaptureHandler)
+		rg.POST("/:id/void", common.JWTAuth("user", "admin"), h.VoidHandler)
+	}
+	signed := h.rg.Group("/merchant/holds")
+	{
+		signed.POST("/:id/capture", middleware.MerchantAuthMiddleware(h.uk, model.MerchantScopeHoldsWrite), h.MerchantCaptureHandler)
+		signed.POST("/:id/void", middleware.MerchantAuthMiddleware(h.uk, model.MerchantScopeHoldsWrite), h.MerchantVoidHandler)
+	}
+}
+
+func NewHoldController(uh usecase.HoldUseCase, uc usecase.UserUseCase, uk usecase.MerchantKeyUseCase, rg *gin.RouterGroup) *HoldController {
+	return &HoldController{uh: uh, uc: uc, uk: uk, rg: rg}
+}
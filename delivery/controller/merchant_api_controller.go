@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/delivery/middleware"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+// MerchantApiController mengelola API key merchant (login user) dan endpoint server-to-server
+// untuk merchant yang ditandatangani HMAC
+type MerchantApiController struct {
+	uk usecase.MerchantKeyUseCase
+	um usecase.MerchantUseCase
+	rg *gin.RouterGroup
+}
+
+func (m *MerchantApiController) CreateKeyHandler(c *gin.Context) {
+	var payload dto.MerchantApiKeyRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.uk.CreateKey(c.Param("id"), id, payload.Scopes)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantApiController) GetKeysHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := m.uk.FindKeys(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (m *MerchantApiController) RotateKeyHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.uk.RotateKey(c.Param("keyId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantApiController) RevokeKeyHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.uk.RevokeKey(c.Param("keyId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantApiController) ProfileHandler(c *gin.Context) {
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := m.um.FindMerchant(key.MerchantId, key.OwnerId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantApiController) CreateQRHandler(c *gin.Context) {
+	var payload dto.MerchantQRRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := m.um.CreateDynamicQR(key.MerchantId, key.OwnerId, payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantApiController) TransactionsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	datas, err := m.um.FindPayments(key.MerchantId, key.OwnerId, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (m *MerchantApiController) TransactionHandler(c *gin.Context) {
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	response, err := m.um.FindPayment(c.Param("id"), key.MerchantId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantApiController) Route() {
+	keys := m.rg.Group("/merchants/:id/api-keys")
+	{
+		keys.POST("/", common.JWTAuth("user"), m.CreateKeyHandler)
+		keys.GET("/", common.JWTAuth("user"), m.GetKeysHandler)
+		keys.POST("/:keyId/rotate", common.JWTAuth("user"), m.RotateKeyHandler)
+		keys.DELETE("/:keyId", common.JWTAuth("user"), m.RevokeKeyHandler)
+	}
+	rg := m.rg.Group("/merchant")
+	{
+		rg.GET("/profile", middleware.MerchantAuthMiddleware(m.uk, ""), m.ProfileHandler)
+		rg.POST("/qr", middleware.MerchantAuthMiddleware(m.uk, model.MerchantScopeQRWrite), m.CreateQRHandler)
+		rg.GET("/transactions", middleware.MerchantAuthMiddleware(m.uk, model.MerchantScopePaymentsRead), m.TransactionsHandler)
+		rg.GET("/transactions/:id", middleware.MerchantAuthMiddleware(m.uk, model.MerchantScopePaymentsRead), m.TransactionHandler)
+	}
+}
+
+func NewMerchantApiController(uk usecase.MerchantKeyUseCase, um usecase.MerchantUseCase, rg *gin.RouterGroup) *MerchantApiController {
+	return &MerchantApiController{uk: uk, um: um, rg: rg}
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type MerchantController struct {
+	um usecase.MerchantUseCase
+	uc usecase.UserUseCase
+	rg *gin.RouterGroup
+}
+
+func (m *MerchantController) CreateHandler(c *gin.Context) {
+	var payload dto.MerchantRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OwnerId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.um.RegisterMerchant(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) GetAllHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := m.um.FindMerchants(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (m *MerchantController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.um.FindMerchant(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) UpdateHandler(c *gin.Context) {
+	var payload dto.MerchantRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OwnerId = claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.um.UpdateMerchant(c.Param("id"), payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) StaticQRHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.um.StaticQR(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) DynamicQRHandler(c *gin.Context) {
+	var payload dto.MerchantQRRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := m.um.CreateDynamicQR(c.Param("id"), id, payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) TransactionsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := m.um.FindPayments(c.Param("id"), id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (m *MerchantController) PreviewQRHandler(c *gin.Context) {
+	var payload dto.QRPreviewRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := m.um.PreviewQR(payload.Payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) PayQRHandler(c *gin.Context) {
+	var payload dto.QRPaymentRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+	sendBalance, err := m.uc.GetBalanceCase(payload.UserId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sendBalance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+
+	response, err := m.um.PayQR(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (m *MerchantController) Route() {
+	rg := m.rg.Group("/merchants")
+	{
+		rg.POST("/", common.JWTAuth("user"), m.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), m.GetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user"), m.GetHandler)
+		rg.PUT("/:id", common.JWTAuth("user"), m.UpdateHandler)
+		rg.GET("/:id/qr", common.JWTAuth("user"), m.StaticQRHandler)
+		rg.POST("/:id/qr", common.JWTAuth("user"), m.DynamicQRHandler)
+		rg.GET("/:id/transactions", common.JWTAuth("user"), m.TransactionsHandler)
+	}
+	pay := m.rg.Group("/pay")
+	{
+		pay.POST("/qr/preview", common.JWTAuth("user"), m.PreviewQRHandler)
+		pay.POST("/qr", common.JWTAuth("user"), m.PayQRHandler)
+	}
+}
+
+func NewMerchantController(um usecase.MerchantUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *MerchantController {
+	return &MerchantController{um: um, uc: uc, rg: rg}
+}
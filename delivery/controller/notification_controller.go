@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type NotificationController struct {
+	un usecase.NotificationUseCase
+	rg *gin.RouterGroup
+}
+
+func (n *NotificationController) GetAllHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := n.un.FindAll(id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (n *NotificationController) MarkReadHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	if err := n.un.MarkRead(c.Param("id"), id); err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", nil)
+}
+
+func (n *NotificationController) Route() {
+	rg := n.rg.Group("/notifications")
+	{
+		rg.GET("/", common.JWTAuth("user"), n.GetAllHandler)
+		rg.PUT("/:id/read", common.JWTAuth("user"), n.MarkReadHandler)
+	}
+}
+
+func NewNotificationController(un usecase.NotificationUseCase, rg *gin.RouterGroup) *NotificationController {
+	return &NotificationController{un: un, rg: rg}
+}
@@ -0,0 +1,202 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type PaymentRequestController struct {
+	up usecase.PaymentRequestUseCase
+	uc usecase.UserUseCase
+	rg *gin.RouterGroup
+}
+
+func (p *PaymentRequestController) CreateHandler(c *gin.Context) {
+	var payload dto.PaymentRequestRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.RequesterId = claims.(*common.JwtClaim).DataClaims.Id
+	payer, err := p.uc.ResolveRecipient(payload.Payer)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	response, err := p.up.CreateRequest(payload, payer)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (p *PaymentRequestController) GetIncomingHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := p.up.FindIncoming(id, c.Query("status"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (p *PaymentRequestController) GetOutgoingHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := p.up.FindOutgoing(id, c.Query("status"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (p *PaymentRequestController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := p.up.FindRequest(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (p *PaymentRequestController) AcceptHandler(c *gin.Context) {
+	var payload dto.PinRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+	request, err := p.up.FindRequest(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	sendBalance, err := p.uc.GetBalanceCase(userId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sendBalance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+	send, err := p.uc.FindById(userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	receive, err := p.uc.FindById(request.RequesterId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	receiveBalance, err := p.uc.GetBalanceCase(receive.Id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Penerima harus memverifikasi akun terlebih dahulu")
+		return
+	}
+	send.Saldo = sendBalance.Saldo
+	receive.Saldo = receiveBalance.Saldo
+
+	response, err := p.up.AcceptRequest(request.Id, send, receive)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (p *PaymentRequestController) DeclineHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := p.up.DeclineRequest(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (p *PaymentRequestController) CancelHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := p.up.CancelRequest(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (p *PaymentRequestController) Route() {
+	rg := p.rg.Group("/payment-requests")
+	{
+		rg.POST("/", common.JWTAuth("user"), recipientLimit, p.CreateHandler)
+		rg.GET("/incoming", common.JWTAuth("user"), p.GetIncomingHandler)
+		rg.GET("/outgoing", common.JWTAuth("user"), p.GetOutgoingHandler)
+		rg.GET("/:id", common.JWTAuth("user"), p.GetHandler)
+		rg.POST("/:id/accept", common.JWTAuth("user"), p.AcceptHandler)
+		rg.POST("/:id/decline", common.JWTAuth("user"), p.DeclineHandler)
+		rg.POST("/:id/cancel", common.JWTAuth("user"), p.CancelHandler)
+	}
+}
+
+func NewPaymentRequestController(up usecase.PaymentRequestUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *PaymentRequestController {
+	return &PaymentRequestController{up: up, uc: uc, rg: rg}
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/delivery/middleware"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type RefundController struct {
+	ur usecase.RefundUseCase
+	uk usecase.MerchantKeyUseCase
+	rg *gin.RouterGroup
+}
+
+func (r *RefundController) CreateHandler(c *gin.Context) {
+	var payload dto.RefundRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OwnerId = claims.(*common.JwtClaim).DataClaims.Id
+	payload.MerchantId = c.Param("id")
+	payload.PaymentId = c.Param("paymentId")
+
+	response, err := r.ur.CreateRefund(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (r *RefundController) PaymentRefundsHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := r.ur.FindPaymentRefunds(c.Param("paymentId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (r *RefundController) MerchantRefundsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := r.ur.FindMerchantRefunds(c.Param("id"), id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (r *RefundController) UserRefundsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := r.ur.FindUserRefunds(id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (r *RefundController) SignedCreateHandler(c *gin.Context) {
+	var payload dto.RefundRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+	payload.OwnerId = key.OwnerId
+	payload.MerchantId = key.MerchantId
+	payload.PaymentId = c.Param("id")
+
+	response, err := r.ur.CreateRefund(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (r *RefundController) SignedRefundsHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	key := c.MustGet("merchant_key").(model.MerchantApiKey)
+
+	datas, err := r.ur.FindMerchantRefunds(key.MerchantId, key.OwnerId, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (r *RefundController) Route() {
+	rg := r.rg.Group("/merchants/:id")
+	{
+		rg.POST("/transactions/:paymentId/refunds", common.JWTAuth("user"), r.CreateHandler)
+		rg.GET("/transactions/:paymentId/refunds", common.JWTAuth("user"), r.PaymentRefundsHandler)
+		rg.GET("/refunds", common.JWTAuth("user"), r.MerchantRefundsHandler)
+	}
+	r.rg.GET("/pay/refunds", common.JWTAuth("user"), r.UserRefundsHandler)
+	signed := r.rg.Group("/merchant")
+	{
+		signed.POST("/transactions/:id/refunds", middleware.MerchantAuthMiddleware(r.uk, model.MerchantScopeRefundsWrite), r.SignedCreateHandler)
+		signed.GET("/refunds", middleware.MerchantAuthMiddleware(r.uk, model.MerchantScopePaymentsRead), r.SignedRefundsHandler)
+	}
+}
+
+func NewRefundController(ur usecase.RefundUseCase, uk usecase.MerchantKeyUseCase, rg *gin.RouterGroup) *RefundController {
+	return &RefundController{ur: ur, uk: uk, rg: rg}
+}
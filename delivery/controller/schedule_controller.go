@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type ScheduleController struct {
+	us usecase.ScheduleUseCase
+	uc usecase.UserUseCase
+	rg *gin.RouterGroup
+}
+
+// authorize memvalidasi PIN sebagai pre-otorisasi dan mengubah tujuan transfer menjadi id user
+func (s *ScheduleController) authorize(c *gin.Context, payload *dto.ScheduleRequest) bool {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return false
+	}
+	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
+	balance, err := s.uc.GetBalanceCase(payload.UserId)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
+			return false
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if balance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return false
+	}
+	if payload.TujuanTransfer != "" {
+		receive, err := s.uc.ResolveRecipient(payload.TujuanTransfer)
+		if err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			return false
+		}
+		payload.TujuanTransfer = receive.Id
+	}
+	return true
+}
+
+func (s *ScheduleController) CreateHandler(c *gin.Context) {
+	var payload dto.ScheduleRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.TujuanTransfer == "" {
+		common.SendErrorResponse(c, http.StatusBadRequest, "tujuan transfer harus diisi")
+		return
+	}
+	if !s.authorize(c, &payload) {
+		return
+	}
+
+	response, err := s.us.CreateSchedule(payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (s *ScheduleController) GetAllHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := s.us.FindSchedules(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (s *ScheduleController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.us.FindSchedule(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *ScheduleController) UpdateHandler(c *gin.Context) {
+	var payload dto.ScheduleRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !s.authorize(c, &payload) {
+		return
+	}
+
+	response, err := s.us.UpdateSchedule(c.Param("id"), payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *ScheduleController) PauseHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.us.PauseSchedule(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *ScheduleController) ResumeHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.us.ResumeSchedule(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *ScheduleController) CancelHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.us.CancelSchedule(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *ScheduleController) Route() {
+	rg := s.rg.Group("/schedules")
+	{
+		rg.POST("/", common.JWTAuth("user"), recipientLimit, s.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), s.GetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user"), s.GetHandler)
+		rg.PUT("/:id", common.JWTAuth("user"), recipientLimit, s.UpdateHandler)
+		rg.POST("/:id/pause", common.JWTAuth("user"), s.PauseHandler)
+		rg.POST("/:id/resume", common.JWTAuth("user"), s.ResumeHandler)
+		rg.DELETE("/:id", common.JWTAuth("user"), s.CancelHandler)
+	}
+}
+
+func NewScheduleController(us usecase.ScheduleUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *ScheduleController {
+	return &ScheduleController{us: us, uc: uc, rg: rg}
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type SettlementController struct {
+	us usecase.SettlementUseCase
+	um usecase.MerchantUseCase
+	rg *gin.RouterGroup
+}
+
+func (s *SettlementController) ConfigHandler(c *gin.Context) {
+	var payload dto.SettlementConfigRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.um.UpdateSettlementConfig(c.Param("id"), id, payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *SettlementController) GetAllHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := s.us.FindSettlements(c.Param("id"), id, page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (s *SettlementController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.us.FindSettlement(c.Param("settlementId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *SettlementController) ReportHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	settlement, err := s.us.FindSettlement(c.Param("settlementId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	filename := fmt.Sprintf("settlement-%s.csv", settlement.Tanggal.Format("2006-01-02"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", "text/csv")
+	c.Status(http.StatusOK)
+	if err := s.us.WriteReport(c.Writer, settlement); err != nil {
+		c.Error(err)
+	}
+}
+
+func (s *SettlementController) RetryHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.us.RetrySettlement(c.Param("settlementId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *SettlementController) Route() {
+	rg := s.rg.Group("/merchants/:id")
+	{
+		rg.PUT("/settlement-config", common.JWTAuth("user"), s.ConfigHandler)
+		rg.GET("/settlements", common.JWTAuth("user"), s.GetAllHandler)
+		rg.GET("/settlements/:settlementId", common.JWTAuth("user"), s.GetHandler)
+		rg.GET("/settlements/:settlementId/report", common.JWTAuth("user"), s.ReportHandler)
+		rg.POST("/settlements/:settlementId/retry", common.JWTAuth("user"), s.RetryHandler)
+	}
+}
+
+func NewSettlementController(us usecase.SettlementUseCase, um usecase.MerchantUseCase, rg *gin.RouterGroup) *SettlementController {
+	return &SettlementController{us: us, um: um, rg: rg}
+}
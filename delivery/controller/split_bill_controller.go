@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type SplitBillController struct {
+	up usecase.PaymentRequestUseCase
+	uc usecase.UserUseCase
+	rg *gin.RouterGroup
+}
+
+func (s *SplitBillController) CreateHandler(c *gin.Context) {
+	var payload dto.SplitBillRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	payload.OrganiserId = claims.(*common.JwtClaim).DataClaims.Id
+	peserta := make([]model.User, len(payload.Peserta))
+	for i, participant := range payload.Peserta {
+		user, err := s.uc.ResolveRecipient(participant.User)
+		if err != nil {
+			common.SendErrorResponse(c, http.StatusBadRequest, participant.User+": "+err.Error())
+			return
+		}
+		peserta[i] = user
+	}
+
+	response, err := s.up.CreateSplit(payload, peserta)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (s *SplitBillController) GetAllHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := s.up.FindSplits(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (s *SplitBillController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.up.FindSplit(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *SplitBillController) CancelHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := s.up.CancelSplit(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (s *SplitBillController) Route() {
+	rg := s.rg.Group("/split-bills")
+	{
+		rg.POST("/", common.JWTAuth("user"), recipientLimit, s.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), s.GetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user"), s.GetHandler)
+		rg.POST("/:id/cancel", common.JWTAuth("user"), s.CancelHandler)
+	}
+}
+
+func NewSplitBillController(up usecase.PaymentRequestUseCase, uc usecase.UserUseCase, rg *gin.RouterGroup) *SplitBillController {
+	return &SplitBillController{up: up, uc: uc, rg: rg}
+}
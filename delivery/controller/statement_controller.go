@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type StatementController struct {
+	us usecase.StatementUseCase
+	rg *gin.RouterGroup
+}
+
+// sendStatement mengirim rekening koran sesuai query format: json (default), csv atau pdf
+func sendStatement(c *gin.Context, us usecase.StatementUseCase, statement model.Statement) {
+	filename := fmt.Sprintf("statement-%s-%s", statement.Dari.Format("20060102"), statement.Sampai.Format("20060102"))
+	var err error
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		common.SendSingleResponse(c, "SUCCESS", statement)
+		return
+	case "csv":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".csv"))
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		err = us.WriteCSV(c.Writer, statement)
+	case "pdf":
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".pdf"))
+		c.Header("Content-Type", "application/pdf")
+		c.Status(http.StatusOK)
+		err = us.WritePDF(c.Writer, statement)
+	default:
+		common.SendErrorResponse(c, http.StatusBadRequest, "format harus json, csv atau pdf")
+		return
+	}
+	if err != nil {
+		c.Error(err)
+	}
+}
+
+func (s *StatementController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	statement, err := s.us.Generate(id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sendStatement(c, s.us, statement)
+}
+
+func (s *StatementController) Route() {
+	s.rg.GET("/statements", common.JWTAuth("user"), s.GetHandler)
+}
+
+func NewStatementController(us usecase.StatementUseCase, rg *gin.RouterGroup) *StatementController {
+	return &StatementController{us: us, rg: rg}
+}
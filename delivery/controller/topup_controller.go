@@ -23,6 +23,7 @@ func (t *TopupController) CreateTopupHandler(c *gin.Context) {
 	var ammount dto.TopupRequest
 	c.ShouldBind(&ammount)
 	payload.TransactionDetails.GrossAmt = int64(ammount.Ammount)
+	payload.Channel = ammount.Channel
 	claims, exists := c.Get("claims")
 	if !exists {
 		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
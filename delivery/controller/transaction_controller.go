@@ -0,0 +1,66 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type TransactionController struct {
+	ut usecase.TransactionUseCase
+	rg *gin.RouterGroup
+}
+
+func (t *TransactionController) GetAllHandler(c *gin.Context) {
+	var filter dto.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.FindTransactions(id, filter)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransactionController) GetHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.FindTransaction(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransactionController) Route() {
+	rg := t.rg.Group("/transactions")
+	{
+		rg.GET("/", common.JWTAuth("user"), t.GetAllHandler)
+		rg.GET("/:id", common.JWTAuth("user"), t.GetHandler)
+	}
+}
+
+func NewTransactionController(ut usecase.TransactionUseCase, rg *gin.RouterGroup) *TransactionController {
+	return &TransactionController{ut: ut, rg: rg}
+}
@@ -3,14 +3,23 @@ package controller
 import (
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/delivery/middleware"
 	"github.com/yafireyhan01/e-wallet/model"
 	"github.com/yafireyhan01/e-wallet/model/dto"
 	"github.com/yafireyhan01/e-wallet/usecase"
 	"github.com/yafireyhan01/e-wallet/utils/common"
 )
 
+// batas lookup penerima per user per menit untuk mencegah enumerasi user
+const recipientLookupLimit = 10
+
+// recipientLimit dipasang di semua route yang me-resolve penerima (transfer, quote, recipient, batch, split bill,
+// payment request, jadwal dan anggota keluarga) sehingga semuanya berbagi kuota yang sama
+var recipientLimit = middleware.RateLimitMiddleware(recipientLookupLimit, time.Minute)
+
 type TransferController struct {
 	ut usecase.TransferUseCase
 	uc usecase.UserUseCase
@@ -38,7 +47,19 @@ func (t *TransferController) TransferHandler(c *gin.Context) {
 		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	receive, err := t.uc.FindById(payload.TujuanTransfer)
+	sendBalance, _ := t.uc.GetBalanceCase(send.Id)
+	if sendBalance.Pin != payload.Pin { // cek apakah pin di input benar
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+	// penerima boleh berupa UUID, payment tag, username, nomor hp atau email
+	receive, err := t.uc.ResolveRecipient(payload.TujuanTransfer)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	payload.TujuanTransfer = receive.Id
+	receiveBalance, err := t.uc.GetBalanceCase(receive.Id)
 	if err != nil {
 		if err.Error() == "1" {
 			common.SendErrorResponse(c, http.StatusBadRequest, "Penerima harus memverifikasi akun terlebih dahulu")
@@ -47,23 +68,108 @@ func (t *TransferController) TransferHandler(c *gin.Context) {
 		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	sendBalance, _ := t.uc.GetBalanceCase(send.Id)
-	if sendBalance.Pin != payload.Pin { // cek apakah pin di input benar
-		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+	send.Saldo = sendBalance.Saldo
+	receive.Saldo = receiveBalance.Saldo
+	response, err := t.ut.TransferRequest(payload, send, receive)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	receiveBalance, err := t.uc.GetBalanceCase(receive.Id)
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) QuoteHandler(c *gin.Context) {
+	payload := dto.TransferRequest{}
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	send, err := t.uc.FindById(claims.(*common.JwtClaim).DataClaims.Id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	sendBalance, err := t.uc.GetBalanceCase(send.Id)
 	if err != nil {
 		if err.Error() == "1" {
-			common.SendErrorResponse(c, http.StatusBadRequest, "Penerima harus memverifikasi akun terlebih dahulu")
+			common.SendErrorResponse(c, http.StatusBadRequest, "Anda harus memverifikasi akun terlebih dahulu")
 			return
 		}
 		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	receive, err := t.uc.ResolveRecipient(payload.TujuanTransfer)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if _, err := t.uc.GetBalanceCase(receive.Id); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Penerima harus memverifikasi akun terlebih dahulu")
+		return
+	}
+	// quote memakai saldo tersedia (saldo dikurangi hold)
+	send.Saldo = sendBalance.SaldoTersedia
+
+	response, err := t.ut.QuoteTransfer(payload, send, receive)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) ConfirmHandler(c *gin.Context) {
+	var payload dto.TransferConfirmRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+	intent, err := t.ut.FindIntent(payload.IntentId, userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+	sendBalance, err := t.uc.GetBalanceCase(userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if sendBalance.Pin != payload.Pin {
+		common.SendErrorResponse(c, http.StatusBadRequest, "pin salah!")
+		return
+	}
+	send, err := t.uc.FindById(userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	receive, err := t.uc.FindById(intent.TujuanTransfer)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	receiveBalance, err := t.uc.GetBalanceCase(receive.Id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Penerima harus memverifikasi akun terlebih dahulu")
+		return
+	}
 	send.Saldo = sendBalance.Saldo
 	receive.Saldo = receiveBalance.Saldo
-	response, err := t.ut.TransferRequest(payload, send, receive)
+
+	response, err := t.ut.ConfirmTransfer(payload.IntentId, send, receive)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -72,6 +178,40 @@ func (t *TransferController) TransferHandler(c *gin.Context) {
 	common.SendSingleResponse(c, "SUCCESS", response)
 }
 
+func (t *TransferController) GetIntentHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.FindIntent(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) RecipientHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.uc.LookupRecipient(c.Query("query"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
 func (t *TransferController) GetSendTransferHandler(c *gin.Context) {
 	var id string
 	var page int
@@ -87,7 +227,10 @@ func (t *TransferController) GetSendTransferHandler(c *gin.Context) {
 	}
 	id = claims.(*common.JwtClaim).DataClaims.Id
 
-	datas, err := t.ut.GetSend(id, page)
+	var filter dto.TransferFilter
+	c.ShouldBindQuery(&filter)
+
+	datas, err := t.ut.GetSend(id, page, filter)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -110,7 +253,10 @@ func (t *TransferController) GetReceiveTransferHandler(c *gin.Context) {
 	}
 	id = claims.(*common.JwtClaim).DataClaims.Id
 
-	datas, err := t.ut.GetReceive(id, page)
+	var filter dto.TransferFilter
+	c.ShouldBindQuery(&filter)
+
+	datas, err := t.ut.GetReceive(id, page, filter)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -129,7 +275,10 @@ func (t *TransferController) AdminGetSendTransferHandler(c *gin.Context) {
 
 	id = c.Param("id")
 
-	datas, err := t.ut.GetSend(id, page)
+	var filter dto.TransferFilter
+	c.ShouldBindQuery(&filter)
+
+	datas, err := t.ut.GetSend(id, page, filter)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -147,7 +296,10 @@ func (t *TransferController) AdminGetReceiveTransferHandler(c *gin.Context) {
 	}
 	id = c.Param("id")
 
-	datas, err := t.ut.GetReceive(id, page)
+	var filter dto.TransferFilter
+	c.ShouldBindQuery(&filter)
+
+	datas, err := t.ut.GetReceive(id, page, filter)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -155,6 +307,28 @@ func (t *TransferController) AdminGetReceiveTransferHandler(c *gin.Context) {
 	common.SendSingleResponse(c, "SUCCESS", datas)
 }
 
+func (t *TransferController) UpdateCategoryHandler(c *gin.Context) {
+	var payload dto.TransferCategoryRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.UpdateCategory(c.Param("id"), id, payload.Kategori)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
 func (t *TransferController) WithdrawHander(c *gin.Context) {
 	payload := model.Withdraw{}
 	c.ShouldBind(&payload)
@@ -164,7 +338,13 @@ func (t *TransferController) WithdrawHander(c *gin.Context) {
 		return
 	}
 	payload.UserId = claims.(*common.JwtClaim).DataClaims.Id
-	_, err := t.uc.FindRekening(payload.UserId)
+	var rekening model.Rekening
+	var err error
+	if payload.RekeningId != "" {
+		rekening, err = t.uc.FindRekeningById(payload.RekeningId, payload.UserId)
+	} else {
+		rekening, err = t.uc.FindRekening(payload.UserId)
+	}
 	if err != nil {
 		if err.Error() == "1" {
 			common.SendErrorResponse(c, http.StatusBadRequest, "rekening tidak ada, silahkan atur rekening anda")
@@ -173,14 +353,14 @@ func (t *TransferController) WithdrawHander(c *gin.Context) {
 		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	saldo, _ := t.uc.GetBalanceCase(payload.UserId)
-	saldo.Saldo -= payload.Withdraw
-	if saldo.Saldo < 0 {
-		common.SendErrorResponse(c, http.StatusBadRequest, "saldo anda tidak mencukup untuk transfer")
+	if !rekening.IsVerified {
+		common.SendErrorResponse(c, http.StatusBadRequest, "rekening belum terverifikasi, lakukan inquiry rekening terlebih dahulu")
 		return
 	}
-	response, err := t.ut.Withdraw(payload, saldo)
+	payload.BankCode = rekening.BankCode
+	payload.Rekening = rekening.Rekening
+
+	response, err := t.ut.Withdraw(payload)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -211,17 +391,138 @@ func (t *TransferController) GetWithdrawsHandler(c *gin.Context) {
 	common.SendSingleResponse(c, "SUCCESS", datas)
 }
 
+func (t *TransferController) GetWithdrawHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	dataClaims := claims.(*common.JwtClaim).DataClaims
+
+	response, err := t.ut.FindWithdraw(c.Param("id"), dataClaims.Id, dataClaims.Role)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) CancelWithdrawHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.CancelWithdraw(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) ReversalHandler(c *gin.Context) {
+	var payload dto.ReversalRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	adminId := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.ReverseTransfer(payload, adminId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) GetPendingReversalHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := t.ut.GetPendingReversal(id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (t *TransferController) ApproveReversalHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.ApproveReversal(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (t *TransferController) RejectReversalHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	userId := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := t.ut.RejectReversal(c.Param("id"), userId)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
 func (t *TransferController) Route() {
 	rg := t.rg.Group("/transfer")
 	{
 		// tulis route disini
-		rg.POST("/", common.JWTAuth("user"), t.TransferHandler)
+		rg.POST("/", common.JWTAuth("user"), recipientLimit, t.TransferHandler)
+		rg.POST("/quote", common.JWTAuth("user"), recipientLimit, t.QuoteHandler)
+		rg.GET("/quote/:id", common.JWTAuth("user"), t.GetIntentHandler)
+		rg.POST("/confirm", common.JWTAuth("user"), t.ConfirmHandler)
+		rg.GET("/recipient", common.JWTAuth("user"), recipientLimit, t.RecipientHandler)
 		rg.POST("/withdraw", common.JWTAuth("user"), t.WithdrawHander)
 		rg.GET("/withdraw", common.JWTAuth("user"), t.GetWithdrawsHandler)
+		rg.GET("/withdraw/:id", common.JWTAuth("user", "admin"), t.GetWithdrawHandler)
+		rg.POST("/withdraw/:id/cancel", common.JWTAuth("user"), t.CancelWithdrawHandler)
+		rr := rg.Group("/reversal")
+		{
+			rr.POST("/", common.JWTAuth("admin"), t.ReversalHandler)
+			rr.GET("/", common.JWTAuth("user"), t.GetPendingReversalHandler)
+			rr.POST("/:id/approve", common.JWTAuth("user"), t.ApproveReversalHandler)
+			rr.POST("/:id/reject", common.JWTAuth("user"), t.RejectReversalHandler)
+		}
 		rh := rg.Group("/history")
 		{
 			rh.GET("/send", common.JWTAuth("user"), t.GetSendTransferHandler)
 			rh.GET("/receive", common.JWTAuth("user"), t.GetReceiveTransferHandler)
+			rh.PUT("/:id/kategori", common.JWTAuth("user"), t.UpdateCategoryHandler)
 			rh.GET("/admin/send/:id", common.JWTAuth("admin"), t.AdminGetSendTransferHandler)
 			rh.GET("/admin/receive/:id", common.JWTAuth("admin"), t.AdminGetReceiveTransferHandler)
 		}
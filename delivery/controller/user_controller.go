@@ -186,7 +186,7 @@ func (p *UserController) GetRekeningHandler(c *gin.Context) {
 		return
 	}
 	id := claims.(*common.JwtClaim).DataClaims.Id
-	res, err := p.uc.FindRekening(id)
+	res, err := p.uc.FindAllRekening(id)
 	if err != nil {
 		common.SendErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -195,6 +195,79 @@ func (p *UserController) GetRekeningHandler(c *gin.Context) {
 	common.SendSingleResponse(c, "SUCCESS", res)
 }
 
+func (p *UserController) SetDefaultRekeningHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusUnauthorized, "sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+	res, err := p.uc.SetDefaultRekening(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", res)
+}
+
+func (p *UserController) DeleteRekeningHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusUnauthorized, "sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+	if err := p.uc.DeleteRekening(c.Param("id"), id); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", nil)
+}
+
+func (p *UserController) InquiryRekeningHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusUnauthorized, "sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+	res, err := p.uc.InquiryRekening(c.Param("id"), id)
+	if err != nil {
+		if err.Error() == "1" {
+			common.SendErrorResponse(c, http.StatusBadRequest, "Rekening tidak ditemukan atau akun anda belum diverifikasi")
+			return
+		}
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", res)
+}
+
+func (u *UserController) PaymentTagHandler(c *gin.Context) {
+	var payload dto.PaymentTagRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusInternalServerError, "Claims jwt tidak ada!")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := u.uc.SetPaymentTag(id, payload.PaymentTag)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
 func (p *UserController) Route() {
 	p.rg.POST("/users/login", p.loginHandler)
 	p.rg.POST("/users", p.createHandler)
@@ -203,8 +276,12 @@ func (p *UserController) Route() {
 	p.rg.PUT("/users", common.JWTAuth("user"), p.UpdateHandler)
 	p.rg.POST("/users/verify", common.JWTAuth("user"), p.VerifyHandler)
 	p.rg.PUT("/users/pin", common.JWTAuth("user"), p.UpdatePinHandler)
+	p.rg.PUT("/users/payment-tag", common.JWTAuth("user"), p.PaymentTagHandler)
 	p.rg.POST("/users/rekening", common.JWTAuth("user"), p.CreateRekeningHandler)
 	p.rg.GET("/users/rekening", common.JWTAuth("user"), p.GetRekeningHandler)
+	p.rg.PUT("/users/rekening/:id/default", common.JWTAuth("user"), p.SetDefaultRekeningHandler)
+	p.rg.DELETE("/users/rekening/:id", common.JWTAuth("user"), p.DeleteRekeningHandler)
+	p.rg.POST("/users/rekening/:id/inquiry", common.JWTAuth("user"), p.InquiryRekeningHandler)
 
 }
 
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type WebhookController struct {
+	uw usecase.WebhookUseCase
+	rg *gin.RouterGroup
+}
+
+func (w *WebhookController) CreateHandler(c *gin.Context) {
+	var payload dto.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := w.uw.RegisterEndpoint(c.Param("id"), id, payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendCreateResponse(c, "SUCCESS", response)
+}
+
+func (w *WebhookController) GetAllHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := w.uw.FindEndpoints(c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (w *WebhookController) UpdateHandler(c *gin.Context) {
+	var payload dto.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := w.uw.UpdateEndpoint(c.Param("webhookId"), c.Param("id"), id, payload)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (w *WebhookController) TestHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := w.uw.SendTest(c.Param("webhookId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (w *WebhookController) DeliveriesHandler(c *gin.Context) {
+	page, _ := strconv.Atoi(c.Query("page"))
+	if page == 0 {
+		page = 1
+	}
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	datas, err := w.uw.FindDeliveries(c.Param("webhookId"), c.Param("id"), id, c.Query("status"), page)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", datas)
+}
+
+func (w *WebhookController) DeliveryHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := w.uw.FindDelivery(c.Param("deliveryId"), c.Param("webhookId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (w *WebhookController) RedeliverHandler(c *gin.Context) {
+	claims, exists := c.Get("claims")
+	if !exists {
+		common.SendErrorResponse(c, http.StatusBadRequest, "Sepertinya login anda tidak valid")
+		return
+	}
+	id := claims.(*common.JwtClaim).DataClaims.Id
+
+	response, err := w.uw.Redeliver(c.Param("deliveryId"), c.Param("webhookId"), c.Param("id"), id)
+	if err != nil {
+		common.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	common.SendSingleResponse(c, "SUCCESS", response)
+}
+
+func (w *WebhookController) Route() {
+	rg := w.rg.Group("/merchants/:id/webhooks")
+	{
+		rg.POST("/", common.JWTAuth("user"), w.CreateHandler)
+		rg.GET("/", common.JWTAuth("user"), w.GetAllHandler)
+		rg.PUT("/:webhookId", common.JWTAuth("user"), w.UpdateHandler)
+		rg.POST("/:webhookId/test", common.JWTAuth("user"), w.TestHandler)
+		rg.GET("/:webhookId/deliveries", common.JWTAuth("user"), w.DeliveriesHandler)
+		rg.GET("/:webhookId/deliveries/:deliveryId", common.JWTAuth("user"), w.DeliveryHandler)
+		rg.POST("/:webhookId/deliveries/:deliveryId/redeliver", common.JWTAuth("user"), w.RedeliverHandler)
+	}
+}
+
+func NewWebhookController(uw usecase.WebhookUseCase, rg *gin.RouterGroup) *WebhookController {
+	return &WebhookController{uw: uw, rg: rg}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+// batas ukuran body request merchant yang dibaca untuk verifikasi tanda tangan
+const merchantMaxBodyBytes = 1 << 20
+
+// MerchantAuthMiddleware memeriksa request merchant yang ditandatangani HMAC dan scope API key,
+// key yang valid disimpan di context dengan nama "merchant_key"
+func MerchantAuthMiddleware(uk usecase.MerchantKeyUseCase, scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, merchantMaxBodyBytes))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				common.SendErrorResponse(ctx, http.StatusRequestEntityTooLarge, "body request maksimal 1 MB")
+				ctx.Abort()
+				return
+			}
+			common.SendErrorResponse(ctx, http.StatusBadRequest, err.Error())
+			ctx.Abort()
+			return
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+		key, err := uk.Authenticate(dto.SignedRequest{
+			KeyId:     ctx.GetHeader("X-Api-Key"),
+			Timestamp: ctx.GetHeader("X-Timestamp"),
+			Nonce:     ctx.GetHeader("X-Nonce"),
+			Signature: ctx.GetHeader("X-Signature"),
+			Method:    ctx.Request.Method,
+			Path:      ctx.Request.URL.RequestURI(),
+			Body:      body,
+		})
+		if err != nil {
+			common.SendErrorResponse(ctx, http.StatusUnauthorized, err.Error())
+			ctx.Abort()
+			return
+		}
+		if !key.HasScope(scope) {
+			common.SendErrorResponse(ctx, http.StatusForbidden, "API key tidak memiliki scope "+scope)
+			ctx.Abort()
+			return
+		}
+		ctx.Set("merchant_key", key)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yafireyhan01/e-wallet/utils/common"
+)
+
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+// RateLimiter menghitung kuota per key dalam satu window, satu instance bisa dipakai bersama oleh beberapa route
+type RateLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	windows map[string]*rateWindow
+}
+
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{limit: limit, window: window, windows: map[string]*rateWindow{}}
+}
+
+// Allow memakai n kuota milik key, false jika kuota window ini terlampaui
+func (r *RateLimiter) Allow(key string, n int) bool {
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.windows) > 10000 {
+		for k, w := range r.windows {
+			if now.After(w.resetAt) {
+				delete(r.windows, k)
+			}
+		}
+	}
+	w, ok := r.windows[key]
+	if !ok || now.After(w.resetAt) {
+		w = &rateWindow{resetAt: now.Add(r.window)}
+		r.windows[key] = w
+	}
+	w.count += n
+
+	return w.count <= r.limit
+}
+
+// Middleware memakai satu kuota per request, per user (atau per IP jika belum login)
+func (r *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !r.Allow(rateLimitKey(ctx), 1) {
+			common.SendErrorResponse(ctx, http.StatusTooManyRequests, "terlalu banyak permintaan, coba lagi nanti")
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// rateLimitKey mengembalikan id user yang login, atau IP client jika belum login
+func rateLimitKey(ctx *gin.Context) string {
+	if claims, exists := ctx.Get("claims"); exists {
+		return claims.(*common.JwtClaim).DataClaims.Id
+	}
+	return ctx.ClientIP()
+}
+
+// RateLimitMiddleware membatasi jumlah request per user (atau per IP jika belum login) dalam satu window
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	return NewRateLimiter(limit, window).Middleware()
+}
@@ -3,6 +3,7 @@ package delivery
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yafireyhan01/e-wallet/config"
@@ -11,6 +12,8 @@ import (
 	"github.com/yafireyhan01/e-wallet/manager"
 )
 
+const webhookDispatchInterval = 15 * time.Second
+
 type Server struct {
 	uc     manager.UseCaseManager
 	engine *gin.Engine
@@ -23,11 +26,87 @@ func (s *Server) setupControllers() {
 	controller.NewTransferController(s.uc.TransferUseCase(), s.uc.UserUseCase(), rg).Route()
 	controller.NewTopupController(s.uc.TopupUseCase(), s.uc.UserUseCase(), rg).Route()
 	controller.NewUserController(s.uc.UserUseCase(), rg).Route()
-	controller.NewAdminController(s.uc.AdminUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewAdminController(s.uc.AdminUseCase(), s.uc.UserUseCase(), s.uc.StatementUseCase(), rg).Route()
+	controller.NewDisputeController(s.uc.DisputeUseCase(), rg).Route()
+	controller.NewHoldController(s.uc.HoldUseCase(), s.uc.UserUseCase(), s.uc.MerchantKeyUseCase(), rg).Route()
+	controller.NewFeeController(s.uc.FeeUseCase(), rg).Route()
+	controller.NewScheduleController(s.uc.ScheduleUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewNotificationController(s.uc.NotificationUseCase(), rg).Route()
+	controller.NewPaymentRequestController(s.uc.PaymentRequestUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewSplitBillController(s.uc.PaymentRequestUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewBatchController(s.uc.BatchUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewMerchantController(s.uc.MerchantUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewMerchantApiController(s.uc.MerchantKeyUseCase(), s.uc.MerchantUseCase(), rg).Route()
+	controller.NewWebhookController(s.uc.WebhookUseCase(), rg).Route()
+	controller.NewCheckoutController(s.uc.CheckoutUseCase(), s.uc.MerchantKeyUseCase(), s.uc.UserUseCase(), rg).Route()
+	controller.NewSettlementController(s.uc.SettlementUseCase(), s.uc.MerchantUseCase(), rg).Route()
+	controller.NewRefundController(s.uc.RefundUseCase(), s.uc.MerchantKeyUseCase(), rg).Route()
+	controller.NewTransactionController(s.uc.TransactionUseCase(), rg).Route()
+	controller.NewStatementController(s.uc.StatementUseCase(), rg).Route()
+	controller.NewAnalyticsController(s.uc.AnalyticsUseCase(), rg).Route()
+	controller.NewBudgetController(s.uc.BudgetUseCase(), rg).Route()
+	controller.NewPocketController(s.uc.PocketUseCase(), rg).Route()
+	controller.NewFamilyController(s.uc.FamilyUseCase(), s.uc.TransferUseCase(), s.uc.UserUseCase(), rg).Route()
+}
+
+// runScheduler mengeksekusi transfer terjadwal yang sudah jatuh tempo setiap menit
+func (s *Server) runScheduler() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	schedules := s.uc.ScheduleUseCase()
+	for now := range ticker.C {
+		if _, err := schedules.RunDue(now); err != nil {
+			log.Println("scheduler:", err.Error())
+		}
+	}
+}
+
+// runWebhookDispatcher mengirim webhook yang sudah jatuh tempo, termasuk percobaan ulang dengan backoff
+func (s *Server) runWebhookDispatcher() {
+	ticker := time.NewTicker(webhookDispatchInterval)
+	defer ticker.Stop()
+	webhooks := s.uc.WebhookUseCase()
+	for now := range ticker.C {
+		if _, err := webhooks.RunDue(now); err != nil {
+			log.Println("webhook dispatcher:", err.Error())
+		}
+	}
+}
+
+// runSettlement membuat batch settlement merchant, dicek setiap jam dan hanya berjalan setelah jam settlement.
+// Settlement yang macet di processing dikirim ulang setiap jam.
+func (s *Server) runSettlement() {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	settlements := s.uc.SettlementUseCase()
+	for now := range ticker.C {
+		if _, err := settlements.RetryStuckSettlements(now); err != nil {
+			log.Println("settlement sweeper:", err.Error())
+		}
+		if _, err := settlements.RunDue(now); err != nil {
+			log.Println("settlement:", err.Error())
+		}
+	}
+}
+
+// runWithdrawSweeper mengulang withdraw yang macet agar hold-nya tidak tertahan selamanya
+func (s *Server) runWithdrawSweeper() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	transfers := s.uc.TransferUseCase()
+	for now := range ticker.C {
+		if _, err := transfers.RetryStuckWithdraws(now); err != nil {
+			log.Println("withdraw sweeper:", err.Error())
+		}
+	}
 }
 
 func (s *Server) Run() {
 	s.setupControllers()
+	go s.runScheduler()
+	go s.runWithdrawSweeper()
+	go s.runWebhookDispatcher()
+	go s.runSettlement()
 	if err := s.engine.Run(s.host); err != nil {
 		log.Fatal("server can't run")
 	}
@@ -9,6 +9,25 @@ type RepoManager interface {
 	TopupRepo() repository.TopupRepository
 	UserRepo() repository.UserRepository
 	AdminRepo() repository.AdminRepository
+	HoldRepo() repository.HoldRepository
+	DisputeRepo() repository.DisputeRepository
+	FeeRepo() repository.FeeRepository
+	NotificationRepo() repository.NotificationRepository
+	ScheduleRepo() repository.ScheduleRepository
+	PaymentRequestRepo() repository.PaymentRequestRepository
+	BatchRepo() repository.BatchRepository
+	MerchantRepo() repository.MerchantRepository
+	MerchantKeyRepo() repository.MerchantKeyRepository
+	WebhookRepo() repository.WebhookRepository
+	CheckoutRepo() repository.CheckoutRepository
+	SettlementRepo() repository.SettlementRepository
+	RefundRepo() repository.RefundRepository
+	TransactionRepo() repository.TransactionRepository
+	StatementRepo() repository.StatementRepository
+	AnalyticsRepo() repository.AnalyticsRepository
+	BudgetRepo() repository.BudgetRepository
+	PocketRepo() repository.PocketRepository
+	FamilyRepo() repository.FamilyRepository
 }
 
 type repoManager struct {
@@ -31,6 +50,82 @@ func (r *repoManager) AdminRepo() repository.AdminRepository {
 	return repository.NewAdminRepository(r.infra.Conn())
 }
 
+func (r *repoManager) HoldRepo() repository.HoldRepository {
+	return repository.NewHoldRepository(r.infra.Conn())
+}
+
+func (r *repoManager) DisputeRepo() repository.DisputeRepository {
+	return repository.NewDisputeRepository(r.infra.Conn())
+}
+
+func (r *repoManager) FeeRepo() repository.FeeRepository {
+	return repository.NewFeeRepository(r.infra.Conn())
+}
+
+func (r *repoManager) NotificationRepo() repository.NotificationRepository {
+	return repository.NewNotificationRepository(r.infra.Conn())
+}
+
+func (r *repoManager) ScheduleRepo() repository.ScheduleRepository {
+	return repository.NewScheduleRepository(r.infra.Conn())
+}
+
+func (r *repoManager) PaymentRequestRepo() repository.PaymentRequestRepository {
+	return repository.NewPaymentRequestRepository(r.infra.Conn())
+}
+
+func (r *repoManager) BatchRepo() repository.BatchRepository {
+	return repository.NewBatchRepository(r.infra.Conn())
+}
+
+func (r *repoManager) MerchantRepo() repository.MerchantRepository {
+	return repository.NewMerchantRepository(r.infra.Conn())
+}
+
+func (r *repoManager) MerchantKeyRepo() repository.MerchantKeyRepository {
+	return repository.NewMerchantKeyRepository(r.infra.Conn())
+}
+
+func (r *repoManager) WebhookRepo() repository.WebhookRepository {
+	return repository.NewWebhookRepository(r.infra.Conn())
+}
+
+func (r *repoManager) CheckoutRepo() repository.CheckoutRepository {
+	return repository.NewCheckoutRepository(r.infra.Conn())
+}
+
+func (r *repoManager) SettlementRepo() repository.SettlementRepository {
+	return repository.NewSettlementRepository(r.infra.Conn())
+}
+
+func (r *repoManager) RefundRepo() repository.RefundRepository {
+	return repository.NewRefundRepository(r.infra.Conn())
+}
+
+func (r *repoManager) TransactionRepo() repository.TransactionRepository {
+	return repository.NewTransactionRepository(r.infra.Conn())
+}
+
+func (r *repoManager) StatementRepo() repository.StatementRepository {
+	return repository.NewStatementRepository(r.infra.Conn())
+}
+
+func (r *repoManager) AnalyticsRepo() repository.AnalyticsRepository {
+	return repository.NewAnalyticsRepository(r.infra.Conn())
+}
+
+func (r *repoManager) BudgetRepo() repository.BudgetRepository {
+	return repository.NewBudgetRepository(r.infra.Conn())
+}
+
+func (r *repoManager) PocketRepo() repository.PocketRepository {
+	return repository.NewPocketRepository(r.infra.Conn())
+}
+
+func (r *repoManager) FamilyRepo() repository.FamilyRepository {
+	return repository.NewFamilyRepository(r.infra.Conn())
+}
+
 func NewRepoManager(infra InfraManager) RepoManager {
 	return &repoManager{infra: infra}
 }
@@ -1,14 +1,41 @@
 package manager
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/yafireyhan01/e-wallet/usecase"
+	"github.com/yafireyhan01/e-wallet/utils/bank"
+	"github.com/yafireyhan01/e-wallet/utils/payout"
 )
 
+// batas waktu satu percobaan pengiriman webhook
+const webhookTimeout = 10 * time.Second
+
 type UseCaseManager interface {
 	TransferUseCase() usecase.TransferUseCase
 	TopupUseCase() usecase.TopupUseCase
 	UserUseCase() usecase.UserUseCase
 	AdminUseCase() usecase.AdminUseCase
+	DisputeUseCase() usecase.DisputeUseCase
+	HoldUseCase() usecase.HoldUseCase
+	FeeUseCase() usecase.FeeUseCase
+	NotificationUseCase() usecase.NotificationUseCase
+	ScheduleUseCase() usecase.ScheduleUseCase
+	PaymentRequestUseCase() usecase.PaymentRequestUseCase
+	BatchUseCase() usecase.BatchUseCase
+	MerchantUseCase() usecase.MerchantUseCase
+	MerchantKeyUseCase() usecase.MerchantKeyUseCase
+	WebhookUseCase() usecase.WebhookUseCase
+	CheckoutUseCase() usecase.CheckoutUseCase
+	SettlementUseCase() usecase.SettlementUseCase
+	RefundUseCase() usecase.RefundUseCase
+	TransactionUseCase() usecase.TransactionUseCase
+	StatementUseCase() usecase.StatementUseCase
+	AnalyticsUseCase() usecase.AnalyticsUseCase
+	BudgetUseCase() usecase.BudgetUseCase
+	PocketUseCase() usecase.PocketUseCase
+	FamilyUseCase() usecase.FamilyUseCase
 }
 
 type useCaseManager struct {
@@ -16,21 +43,99 @@ type useCaseManager struct {
 }
 
 func (u *useCaseManager) TransferUseCase() usecase.TransferUseCase {
-	return usecase.NewTransferUseCase(u.repo.TransferRepo())
+	return usecase.NewTransferUseCase(u.repo.TransferRepo(), payout.NewFakePayoutProvider(), u.FeeUseCase(), u.BudgetUseCase(),
+		u.FamilyUseCase())
 }
 
 func (u *useCaseManager) TopupUseCase() usecase.TopupUseCase {
-	return usecase.NewTopupUseCase(u.repo.TopupRepo())
+	return usecase.NewTopupUseCase(u.repo.TopupRepo(), u.FeeUseCase())
 }
 
 func (u *useCaseManager) UserUseCase() usecase.UserUseCase {
-	return usecase.NewUserUseCase(u.repo.UserRepo())
+	return usecase.NewUserUseCase(u.repo.UserRepo(), bank.NewFakeBankInquiry())
 }
 
 func (u *useCaseManager) AdminUseCase() usecase.AdminUseCase {
 	return usecase.NewAdminUseCase(u.repo.AdminRepo())
 }
 
+func (u *useCaseManager) DisputeUseCase() usecase.DisputeUseCase {
+	return usecase.NewDisputeUseCase(u.repo.DisputeRepo(), u.repo.HoldRepo(), u.TransferUseCase())
+}
+
+func (u *useCaseManager) HoldUseCase() usecase.HoldUseCase {
+	return usecase.NewHoldUseCase(u.repo.HoldRepo())
+}
+
+func (u *useCaseManager) FeeUseCase() usecase.FeeUseCase {
+	return usecase.NewFeeUseCase(u.repo.FeeRepo())
+}
+
+func (u *useCaseManager) NotificationUseCase() usecase.NotificationUseCase {
+	return usecase.NewNotificationUseCase(u.repo.NotificationRepo())
+}
+
+func (u *useCaseManager) ScheduleUseCase() usecase.ScheduleUseCase {
+	return usecase.NewScheduleUseCase(u.repo.ScheduleRepo(), u.TransferUseCase(), u.UserUseCase(), u.NotificationUseCase())
+}
+
+func (u *useCaseManager) PaymentRequestUseCase() usecase.PaymentRequestUseCase {
+	return usecase.NewPaymentRequestUseCase(u.repo.PaymentRequestRepo(), u.TransferUseCase(), u.NotificationUseCase())
+}
+
+func (u *useCaseManager) BatchUseCase() usecase.BatchUseCase {
+	return usecase.NewBatchUseCase(u.repo.BatchRepo(), u.UserUseCase(), u.FeeUseCase(), u.BudgetUseCase(), u.FamilyUseCase())
+}
+
+func (u *useCaseManager) MerchantUseCase() usecase.MerchantUseCase {
+	return usecase.NewMerchantUseCase(u.repo.MerchantRepo(), bank.NewFakeBankInquiry(), u.WebhookUseCase(), u.BudgetUseCase(),
+		u.FamilyUseCase())
+}
+
+func (u *useCaseManager) MerchantKeyUseCase() usecase.MerchantKeyUseCase {
+	return usecase.NewMerchantKeyUseCase(u.repo.MerchantKeyRepo(), u.MerchantUseCase())
+}
+
+func (u *useCaseManager) WebhookUseCase() usecase.WebhookUseCase {
+	return usecase.NewWebhookUseCase(u.repo.WebhookRepo(), u.repo.MerchantRepo(), &http.Client{Timeout: webhookTimeout})
+}
+
+func (u *useCaseManager) CheckoutUseCase() usecase.CheckoutUseCase {
+	return usecase.NewCheckoutUseCase(u.repo.CheckoutRepo(), u.WebhookUseCase(), u.BudgetUseCase(), u.FamilyUseCase())
+}
+
+func (u *useCaseManager) SettlementUseCase() usecase.SettlementUseCase {
+	return usecase.NewSettlementUseCase(u.repo.SettlementRepo(), u.MerchantUseCase(), u.FeeUseCase(), payout.NewFakePayoutProvider())
+}
+
+func (u *useCaseManager) RefundUseCase() usecase.RefundUseCase {
+	return usecase.NewRefundUseCase(u.repo.RefundRepo(), u.MerchantUseCase(), u.WebhookUseCase(), u.NotificationUseCase())
+}
+
+func (u *useCaseManager) TransactionUseCase() usecase.TransactionUseCase {
+	return usecase.NewTransactionUseCase(u.repo.TransactionRepo())
+}
+
+func (u *useCaseManager) StatementUseCase() usecase.StatementUseCase {
+	return usecase.NewStatementUseCase(u.repo.StatementRepo())
+}
+
+func (u *useCaseManager) AnalyticsUseCase() usecase.AnalyticsUseCase {
+	return usecase.NewAnalyticsUseCase(u.repo.AnalyticsRepo())
+}
+
+func (u *useCaseManager) BudgetUseCase() usecase.BudgetUseCase {
+	return usecase.NewBudgetUseCase(u.repo.BudgetRepo(), u.NotificationUseCase())
+}
+
+func (u *useCaseManager) PocketUseCase() usecase.PocketUseCase {
+	return usecase.NewPocketUseCase(u.repo.PocketRepo())
+}
+
+func (u *useCaseManager) FamilyUseCase() usecase.FamilyUseCase {
+	return usecase.NewFamilyUseCase(u.repo.FamilyRepo(), u.UserUseCase(), u.NotificationUseCase())
+}
+
 func NewUseCaseManager(repo RepoManager) UseCaseManager {
 	return &useCaseManager{repo: repo}
 }
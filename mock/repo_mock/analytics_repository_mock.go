@@ -0,0 +1,32 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type AnalyticsRepoMock struct {
+	mock.Mock
+}
+
+func (a *AnalyticsRepoMock) GetTotals(userId string, dari, sampai time.Time) (model.AnalyticsBucket, error) {
+	args := a.Called(userId, dari, sampai)
+	return args.Get(0).(model.AnalyticsBucket), args.Error(1)
+}
+
+func (a *AnalyticsRepoMock) GetByCategory(userId string, dari, sampai time.Time) ([]model.AnalyticsBucket, error) {
+	args := a.Called(userId, dari, sampai)
+	return args.Get(0).([]model.AnalyticsBucket), args.Error(1)
+}
+
+func (a *AnalyticsRepoMock) GetByCounterparty(userId string, dari, sampai time.Time, limit int) ([]model.AnalyticsBucket, error) {
+	args := a.Called(userId, dari, sampai, limit)
+	return args.Get(0).([]model.AnalyticsBucket), args.Error(1)
+}
+
+func (a *AnalyticsRepoMock) GetByPeriod(userId string, dari, sampai time.Time, periode string) ([]model.AnalyticsBucket, error) {
+	args := a.Called(userId, dari, sampai, periode)
+	return args.Get(0).([]model.AnalyticsBucket), args.Error(1)
+}
@@ -0,0 +1,40 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type BatchRepoMock struct {
+	mock.Mock
+}
+
+func (b *BatchRepoMock) Create(batch model.BatchTransfer) (model.BatchTransfer, error) {
+	args := b.Called(batch)
+	return args.Get(0).(model.BatchTransfer), args.Error(1)
+}
+
+func (b *BatchRepoMock) Get(id string) (model.BatchTransfer, error) {
+	args := b.Called(id)
+	return args.Get(0).(model.BatchTransfer), args.Error(1)
+}
+
+func (b *BatchRepoMock) GetByUser(userId string) ([]model.BatchTransfer, error) {
+	args := b.Called(userId)
+	return args.Get(0).([]model.BatchTransfer), args.Error(1)
+}
+
+func (b *BatchRepoMock) Claim(id string) (bool, error) {
+	args := b.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (b *BatchRepoMock) Release(id, status string) (bool, error) {
+	args := b.Called(id, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (b *BatchRepoMock) Execute(id string) (model.BatchTransfer, error) {
+	args := b.Called(id)
+	return args.Get(0).(model.BatchTransfer), args.Error(1)
+}
@@ -0,0 +1,47 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type BudgetRepoMock struct {
+	mock.Mock
+}
+
+func (b *BudgetRepoMock) Create(payload model.Budget) (model.Budget, error) {
+	args := b.Called(payload)
+	return args.Get(0).(model.Budget), args.Error(1)
+}
+
+func (b *BudgetRepoMock) Get(id string) (model.Budget, error) {
+	args := b.Called(id)
+	return args.Get(0).(model.Budget), args.Error(1)
+}
+
+func (b *BudgetRepoMock) GetByUser(userId string, activeOnly bool) ([]model.Budget, error) {
+	args := b.Called(userId, activeOnly)
+	return args.Get(0).([]model.Budget), args.Error(1)
+}
+
+func (b *BudgetRepoMock) Update(payload model.Budget) (model.Budget, error) {
+	args := b.Called(payload)
+	return args.Get(0).(model.Budget), args.Error(1)
+}
+
+func (b *BudgetRepoMock) Delete(id string) error {
+	args := b.Called(id)
+	return args.Error(0)
+}
+
+func (b *BudgetRepoMock) GetSpent(userId, kategori string, dari, sampai time.Time) (int, error) {
+	args := b.Called(userId, kategori, dari, sampai)
+	return args.Int(0), args.Error(1)
+}
+
+func (b *BudgetRepoMock) ClaimAlert(budgetId, periode string, ambang int) (bool, error) {
+	args := b.Called(budgetId, periode, ambang)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type CheckoutRepoMock struct {
+	mock.Mock
+}
+
+func (c *CheckoutRepoMock) Create(payload model.CheckoutSession) (model.CheckoutSession, error) {
+	args := c.Called(payload)
+	return args.Get(0).(model.CheckoutSession), args.Error(1)
+}
+
+func (c *CheckoutRepoMock) Get(id string) (model.CheckoutSession, error) {
+	args := c.Called(id)
+	return args.Get(0).(model.CheckoutSession), args.Error(1)
+}
+
+func (c *CheckoutRepoMock) Pay(id, userId string) (model.CheckoutSession, model.MerchantPayment, error) {
+	args := c.Called(id, userId)
+	return args.Get(0).(model.CheckoutSession), args.Get(1).(model.MerchantPayment), args.Error(2)
+}
+
+func (c *CheckoutRepoMock) Cancel(id, userId string) (bool, error) {
+	args := c.Called(id, userId)
+	return args.Bool(0), args.Error(1)
+}
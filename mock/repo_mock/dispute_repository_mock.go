@@ -0,0 +1,50 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type DisputeRepoMock struct {
+	mock.Mock
+}
+
+func (d *DisputeRepoMock) Create(payload model.Dispute) (model.Dispute, error) {
+	args := d.Called(payload)
+	return args.Get(0).(model.Dispute), args.Error(1)
+}
+
+func (d *DisputeRepoMock) Get(id string) (model.Dispute, error) {
+	args := d.Called(id)
+	return args.Get(0).(model.Dispute), args.Error(1)
+}
+
+func (d *DisputeRepoMock) GetAll(userId string, page int) ([]model.Dispute, error) {
+	args := d.Called(userId, page)
+	return args.Get(0).([]model.Dispute), args.Error(1)
+}
+
+func (d *DisputeRepoMock) GetByStatus(status string, page int) ([]model.Dispute, error) {
+	args := d.Called(status, page)
+	return args.Get(0).([]model.Dispute), args.Error(1)
+}
+
+func (d *DisputeRepoMock) Update(payload model.Dispute) (model.Dispute, error) {
+	args := d.Called(payload)
+	return args.Get(0).(model.Dispute), args.Error(1)
+}
+
+func (d *DisputeRepoMock) FindTransaction(trxType, trxId, userId string) (model.Dispute, error) {
+	args := d.Called(trxType, trxId, userId)
+	return args.Get(0).(model.Dispute), args.Error(1)
+}
+
+func (d *DisputeRepoMock) AddComment(payload model.DisputeComment) (model.DisputeComment, error) {
+	args := d.Called(payload)
+	return args.Get(0).(model.DisputeComment), args.Error(1)
+}
+
+func (d *DisputeRepoMock) GetComments(disputeId string) ([]model.DisputeComment, error) {
+	args := d.Called(disputeId)
+	return args.Get(0).([]model.DisputeComment), args.Error(1)
+}
@@ -0,0 +1,107 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type FamilyRepoMock struct {
+	mock.Mock
+}
+
+func (f *FamilyRepoMock) Create(payload model.Family) (model.Family, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.Family), args.Error(1)
+}
+
+func (f *FamilyRepoMock) GetByOwner(ownerId string) (model.Family, error) {
+	args := f.Called(ownerId)
+	return args.Get(0).(model.Family), args.Error(1)
+}
+
+func (f *FamilyRepoMock) Get(id string) (model.Family, error) {
+	args := f.Called(id)
+	return args.Get(0).(model.Family), args.Error(1)
+}
+
+func (f *FamilyRepoMock) GetMembers(familyId string) ([]model.FamilyMember, error) {
+	args := f.Called(familyId)
+	return args.Get(0).([]model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyRepoMock) GetMember(userId string) (model.FamilyMember, error) {
+	args := f.Called(userId)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyRepoMock) AddMember(payload model.FamilyMember) (model.FamilyMember, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyRepoMock) UpdateMember(payload model.FamilyMember) (model.FamilyMember, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyRepoMock) ActivateMember(userId string) (bool, error) {
+	args := f.Called(userId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (f *FamilyRepoMock) RemoveMember(familyId, userId string) error {
+	args := f.Called(familyId, userId)
+	return args.Error(0)
+}
+
+func (f *FamilyRepoMock) GetSpentSince(userId string, since time.Time) (int, error) {
+	args := f.Called(userId, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (f *FamilyRepoMock) Reserve(payload model.FamilyReservation, since time.Time) (model.FamilyReservation, error) {
+	args := f.Called(payload, since)
+	return args.Get(0).(model.FamilyReservation), args.Error(1)
+}
+
+func (f *FamilyRepoMock) Release(id string) error {
+	args := f.Called(id)
+	return args.Error(0)
+}
+
+func (f *FamilyRepoMock) CreateApproval(payload model.FamilyApproval) (model.FamilyApproval, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyRepoMock) GetApproval(id string) (model.FamilyApproval, error) {
+	args := f.Called(id)
+	return args.Get(0).(model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyRepoMock) GetApprovals(familyId, status string) ([]model.FamilyApproval, error) {
+	args := f.Called(familyId, status)
+	return args.Get(0).([]model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyRepoMock) FindApproval(userId, jenis, tujuan string, jumlah int, status string) (model.FamilyApproval, error) {
+	args := f.Called(userId, jenis, tujuan, jumlah, status)
+	return args.Get(0).(model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyRepoMock) DecideApproval(id, status string) (bool, error) {
+	args := f.Called(id, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func (f *FamilyRepoMock) CreateActivity(payload model.FamilyActivity) error {
+	args := f.Called(payload)
+	return args.Error(0)
+}
+
+func (f *FamilyRepoMock) GetActivity(familyId string, page int) ([]model.FamilyActivity, error) {
+	args := f.Called(familyId, page)
+	return args.Get(0).([]model.FamilyActivity), args.Error(1)
+}
@@ -0,0 +1,50 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type FeeRepoMock struct {
+	mock.Mock
+}
+
+func (f *FeeRepoMock) CreateRule(payload model.FeeRule) (model.FeeRule, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeRepoMock) UpdateRule(payload model.FeeRule) (model.FeeRule, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeRepoMock) GetRule(id string) (model.FeeRule, error) {
+	args := f.Called(id)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeRepoMock) GetRules() ([]model.FeeRule, error) {
+	args := f.Called()
+	return args.Get(0).([]model.FeeRule), args.Error(1)
+}
+
+func (f *FeeRepoMock) FindActiveRule(operation, channel string) (model.FeeRule, error) {
+	args := f.Called(operation, channel)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeRepoMock) CountThisMonth(userId, operation string) (int, error) {
+	args := f.Called(userId, operation)
+	return args.Int(0), args.Error(1)
+}
+
+func (f *FeeRepoMock) GetFees(userId string, page int) ([]model.FeeLine, error) {
+	args := f.Called(userId, page)
+	return args.Get(0).([]model.FeeLine), args.Error(1)
+}
+
+func (f *FeeRepoMock) GetHouseWallet() (model.HouseWallet, error) {
+	args := f.Called()
+	return args.Get(0).(model.HouseWallet), args.Error(1)
+}
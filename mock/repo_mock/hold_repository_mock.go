@@ -0,0 +1,40 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type HoldRepoMock struct {
+	mock.Mock
+}
+
+func (h *HoldRepoMock) Authorize(payload model.Hold) (model.Hold, error) {
+	args := h.Called(payload)
+	return args.Get(0).(model.Hold), args.Error(1)
+}
+
+func (h *HoldRepoMock) Get(id string) (model.Hold, error) {
+	args := h.Called(id)
+	return args.Get(0).(model.Hold), args.Error(1)
+}
+
+func (h *HoldRepoMock) GetActive(userId string) ([]model.Hold, error) {
+	args := h.Called(userId)
+	return args.Get(0).([]model.Hold), args.Error(1)
+}
+
+func (h *HoldRepoMock) Capture(id string, jumlah int, penerimaJenis, penerimaId string) (model.Hold, error) {
+	args := h.Called(id, jumlah, penerimaJenis, penerimaId)
+	return args.Get(0).(model.Hold), args.Error(1)
+}
+
+func (h *HoldRepoMock) Void(id string) (model.Hold, error) {
+	args := h.Called(id)
+	return args.Get(0).(model.Hold), args.Error(1)
+}
+
+func (h *HoldRepoMock) ExpireDue() (int, error) {
+	args := h.Called()
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,52 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type MerchantKeyRepoMock struct {
+	mock.Mock
+}
+
+func (m *MerchantKeyRepoMock) Create(payload model.MerchantApiKey) (model.MerchantApiKey, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.MerchantApiKey), args.Error(1)
+}
+
+func (m *MerchantKeyRepoMock) Get(id string) (model.MerchantApiKey, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.MerchantApiKey), args.Error(1)
+}
+
+func (m *MerchantKeyRepoMock) GetByKeyId(keyId string) (model.MerchantApiKey, error) {
+	args := m.Called(keyId)
+	return args.Get(0).(model.MerchantApiKey), args.Error(1)
+}
+
+func (m *MerchantKeyRepoMock) GetByMerchant(merchantId string) ([]model.MerchantApiKey, error) {
+	args := m.Called(merchantId)
+	return args.Get(0).([]model.MerchantApiKey), args.Error(1)
+}
+
+func (m *MerchantKeyRepoMock) Rotate(id string, payload model.MerchantApiKey) (model.MerchantApiKey, error) {
+	args := m.Called(id, payload)
+	return args.Get(0).(model.MerchantApiKey), args.Error(1)
+}
+
+func (m *MerchantKeyRepoMock) Revoke(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MerchantKeyRepoMock) Touch(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MerchantKeyRepoMock) UseNonce(keyId, nonce string, retention time.Duration) (bool, error) {
+	args := m.Called(keyId, nonce, retention)
+	return args.Bool(0), args.Error(1)
+}
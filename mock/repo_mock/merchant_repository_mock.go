@@ -0,0 +1,55 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type MerchantRepoMock struct {
+	mock.Mock
+}
+
+func (m *MerchantRepoMock) Create(payload model.Merchant) (model.Merchant, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Merchant), args.Error(1)
+}
+
+func (m *MerchantRepoMock) Get(id string) (model.Merchant, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.Merchant), args.Error(1)
+}
+
+func (m *MerchantRepoMock) GetByOwner(ownerId string) ([]model.Merchant, error) {
+	args := m.Called(ownerId)
+	return args.Get(0).([]model.Merchant), args.Error(1)
+}
+
+func (m *MerchantRepoMock) Update(payload model.Merchant) (model.Merchant, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.Merchant), args.Error(1)
+}
+
+func (m *MerchantRepoMock) CreateQR(payload model.MerchantQR) (model.MerchantQR, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.MerchantQR), args.Error(1)
+}
+
+func (m *MerchantRepoMock) GetQR(id string) (model.MerchantQR, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.MerchantQR), args.Error(1)
+}
+
+func (m *MerchantRepoMock) Pay(payload model.MerchantPayment) (model.MerchantPayment, error) {
+	args := m.Called(payload)
+	return args.Get(0).(model.MerchantPayment), args.Error(1)
+}
+
+func (m *MerchantRepoMock) GetPayment(id string) (model.MerchantPayment, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.MerchantPayment), args.Error(1)
+}
+
+func (m *MerchantRepoMock) GetPayments(merchantId string, page int) ([]model.MerchantPayment, error) {
+	args := m.Called(merchantId, page)
+	return args.Get(0).([]model.MerchantPayment), args.Error(1)
+}
@@ -0,0 +1,65 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type PaymentRequestRepoMock struct {
+	mock.Mock
+}
+
+func (p *PaymentRequestRepoMock) Create(payload model.PaymentRequest) (model.PaymentRequest, error) {
+	args := p.Called(payload)
+	return args.Get(0).(model.PaymentRequest), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) Get(id string) (model.PaymentRequest, error) {
+	args := p.Called(id)
+	return args.Get(0).(model.PaymentRequest), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) GetIncoming(payerId, status string) ([]model.PaymentRequest, error) {
+	args := p.Called(payerId, status)
+	return args.Get(0).([]model.PaymentRequest), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) GetOutgoing(requesterId, status string) ([]model.PaymentRequest, error) {
+	args := p.Called(requesterId, status)
+	return args.Get(0).([]model.PaymentRequest), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) UpdateStatus(id, from, to, transferId string) (bool, error) {
+	args := p.Called(id, from, to, transferId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) ExpireDue() (int, error) {
+	args := p.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) CreateSplit(split model.SplitBill, requests []model.PaymentRequest) (model.SplitBill, error) {
+	args := p.Called(split, requests)
+	return args.Get(0).(model.SplitBill), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) GetSplit(id string) (model.SplitBill, error) {
+	args := p.Called(id)
+	return args.Get(0).(model.SplitBill), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) GetSplitsByOrganiser(userId string) ([]model.SplitBill, error) {
+	args := p.Called(userId)
+	return args.Get(0).([]model.SplitBill), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) SettleSplit(id string) (bool, error) {
+	args := p.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (p *PaymentRequestRepoMock) CancelSplit(id string) error {
+	args := p.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,50 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type PocketRepoMock struct {
+	mock.Mock
+}
+
+func (p *PocketRepoMock) Create(payload model.Pocket) (model.Pocket, error) {
+	args := p.Called(payload)
+	return args.Get(0).(model.Pocket), args.Error(1)
+}
+
+func (p *PocketRepoMock) Get(id string) (model.Pocket, error) {
+	args := p.Called(id)
+	return args.Get(0).(model.Pocket), args.Error(1)
+}
+
+func (p *PocketRepoMock) GetByUser(userId, status string) ([]model.Pocket, error) {
+	args := p.Called(userId, status)
+	return args.Get(0).([]model.Pocket), args.Error(1)
+}
+
+func (p *PocketRepoMock) CountActive(userId string) (int, error) {
+	args := p.Called(userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (p *PocketRepoMock) Update(payload model.Pocket) (model.Pocket, error) {
+	args := p.Called(payload)
+	return args.Get(0).(model.Pocket), args.Error(1)
+}
+
+func (p *PocketRepoMock) Move(id, userId, arah string, jumlah int) (model.Pocket, error) {
+	args := p.Called(id, userId, arah, jumlah)
+	return args.Get(0).(model.Pocket), args.Error(1)
+}
+
+func (p *PocketRepoMock) Close(id, userId string) (model.Pocket, error) {
+	args := p.Called(id, userId)
+	return args.Get(0).(model.Pocket), args.Error(1)
+}
+
+func (p *PocketRepoMock) GetMoves(id string, page int) ([]model.PocketMove, error) {
+	args := p.Called(id, page)
+	return args.Get(0).([]model.PocketMove), args.Error(1)
+}
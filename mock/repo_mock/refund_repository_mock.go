@@ -0,0 +1,35 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type RefundRepoMock struct {
+	mock.Mock
+}
+
+func (r *RefundRepoMock) Create(payload model.MerchantRefund) (model.MerchantRefund, error) {
+	args := r.Called(payload)
+	return args.Get(0).(model.MerchantRefund), args.Error(1)
+}
+
+func (r *RefundRepoMock) Get(id string) (model.MerchantRefund, error) {
+	args := r.Called(id)
+	return args.Get(0).(model.MerchantRefund), args.Error(1)
+}
+
+func (r *RefundRepoMock) GetByMerchant(merchantId string, page int) ([]model.MerchantRefund, error) {
+	args := r.Called(merchantId, page)
+	return args.Get(0).([]model.MerchantRefund), args.Error(1)
+}
+
+func (r *RefundRepoMock) GetByPayment(paymentId string) ([]model.MerchantRefund, error) {
+	args := r.Called(paymentId)
+	return args.Get(0).([]model.MerchantRefund), args.Error(1)
+}
+
+func (r *RefundRepoMock) GetByUser(userId string, page int) ([]model.MerchantRefund, error) {
+	args := r.Called(userId, page)
+	return args.Get(0).([]model.MerchantRefund), args.Error(1)
+}
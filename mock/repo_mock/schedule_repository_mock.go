@@ -0,0 +1,52 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type ScheduleRepoMock struct {
+	mock.Mock
+}
+
+func (s *ScheduleRepoMock) Create(payload model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	args := s.Called(payload)
+	return args.Get(0).(model.ScheduledTransfer), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) Get(id string) (model.ScheduledTransfer, error) {
+	args := s.Called(id)
+	return args.Get(0).(model.ScheduledTransfer), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) GetByUser(userId string) ([]model.ScheduledTransfer, error) {
+	args := s.Called(userId)
+	return args.Get(0).([]model.ScheduledTransfer), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) Update(payload model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	args := s.Called(payload)
+	return args.Get(0).(model.ScheduledTransfer), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) GetDue(now time.Time, limit int) ([]model.ScheduledTransfer, error) {
+	args := s.Called(now, limit)
+	return args.Get(0).([]model.ScheduledTransfer), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) ClaimDue(id string, dueAt, leaseUntil time.Time) (bool, error) {
+	args := s.Called(id, dueAt, leaseUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) UpdateRun(payload model.ScheduledTransfer) (bool, error) {
+	args := s.Called(payload)
+	return args.Bool(0), args.Error(1)
+}
+
+func (s *ScheduleRepoMock) GetRunTransfer(userId, referensi string) (string, error) {
+	args := s.Called(userId, referensi)
+	return args.String(0), args.Error(1)
+}
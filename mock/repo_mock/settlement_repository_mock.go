@@ -0,0 +1,57 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type SettlementRepoMock struct {
+	mock.Mock
+}
+
+func (s *SettlementRepoMock) GetMerchantsDue(tanggal time.Time) ([]model.Merchant, error) {
+	args := s.Called(tanggal)
+	return args.Get(0).([]model.Merchant), args.Error(1)
+}
+
+func (s *SettlementRepoMock) GetUnsettled(merchantId string, cutoff time.Time) ([]model.SettlementItem, error) {
+	args := s.Called(merchantId, cutoff)
+	return args.Get(0).([]model.SettlementItem), args.Error(1)
+}
+
+func (s *SettlementRepoMock) Create(payload model.Settlement) (model.Settlement, error) {
+	args := s.Called(payload)
+	return args.Get(0).(model.Settlement), args.Error(1)
+}
+
+func (s *SettlementRepoMock) Get(id string) (model.Settlement, error) {
+	args := s.Called(id)
+	return args.Get(0).(model.Settlement), args.Error(1)
+}
+
+func (s *SettlementRepoMock) GetByMerchant(merchantId string, page int) ([]model.Settlement, error) {
+	args := s.Called(merchantId, page)
+	return args.Get(0).([]model.Settlement), args.Error(1)
+}
+
+func (s *SettlementRepoMock) Claim(id string) (bool, error) {
+	args := s.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (s *SettlementRepoMock) GetStuck(before time.Time, limit int) ([]model.Settlement, error) {
+	args := s.Called(before, limit)
+	return args.Get(0).([]model.Settlement), args.Error(1)
+}
+
+func (s *SettlementRepoMock) Reclaim(id string, updatedAt time.Time) (bool, error) {
+	args := s.Called(id, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (s *SettlementRepoMock) Finish(id, status, payoutRef, alasan string) (model.Settlement, error) {
+	args := s.Called(id, status, payoutRef, alasan)
+	return args.Get(0).(model.Settlement), args.Error(1)
+}
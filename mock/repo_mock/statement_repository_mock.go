@@ -0,0 +1,27 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type StatementRepoMock struct {
+	mock.Mock
+}
+
+func (s *StatementRepoMock) GetAccount(userId string) (model.Statement, error) {
+	args := s.Called(userId)
+	return args.Get(0).(model.Statement), args.Error(1)
+}
+
+func (s *StatementRepoMock) GetLines(userId string, dari, sampai time.Time) ([]model.StatementLine, error) {
+	args := s.Called(userId, dari, sampai)
+	return args.Get(0).([]model.StatementLine), args.Error(1)
+}
+
+func (s *StatementRepoMock) GetNetSince(userId string, since time.Time) (int, error) {
+	args := s.Called(userId, since)
+	return args.Int(0), args.Error(1)
+}
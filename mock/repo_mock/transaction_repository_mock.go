@@ -0,0 +1,21 @@
+package repomock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type TransactionRepoMock struct {
+	mock.Mock
+}
+
+func (t *TransactionRepoMock) GetAll(query dto.TransactionQuery) ([]model.Transaction, error) {
+	args := t.Called(query)
+	return args.Get(0).([]model.Transaction), args.Error(1)
+}
+
+func (t *TransactionRepoMock) Get(id, userId string) (model.Transaction, error) {
+	args := t.Called(id, userId)
+	return args.Get(0).(model.Transaction), args.Error(1)
+}
@@ -0,0 +1,123 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type TransferRepoMock struct {
+	mock.Mock
+}
+
+func (t *TransferRepoMock) Create(payload dto.TransferRequest, send, receive model.User) (model.Transfer, error) {
+	args := t.Called(payload, send, receive)
+	return args.Get(0).(model.Transfer), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetSend(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
+	args := t.Called(id, page, filter)
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetReceive(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
+	args := t.Called(id, page, filter)
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (t *TransferRepoMock) CreateWithdraw(payload model.Withdraw) (model.Withdraw, error) {
+	args := t.Called(payload)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetWithdraw(id string, page int) ([]model.Withdraw, error) {
+	args := t.Called(id, page)
+	return args.Get(0).([]model.Withdraw), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetWithdrawById(id string) (model.Withdraw, error) {
+	args := t.Called(id)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferRepoMock) ClaimWithdraw(id string) (bool, error) {
+	args := t.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetStuckWithdraws(before time.Time, limit int) ([]model.Withdraw, error) {
+	args := t.Called(before, limit)
+	return args.Get(0).([]model.Withdraw), args.Error(1)
+}
+
+func (t *TransferRepoMock) ReclaimWithdraw(id string, updatedAt time.Time) (bool, error) {
+	args := t.Called(id, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (t *TransferRepoMock) FinishWithdraw(id, status, payoutRef, alasan string) (model.Withdraw, error) {
+	args := t.Called(id, status, payoutRef, alasan)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetById(id string) (model.Transfer, error) {
+	args := t.Called(id)
+	return args.Get(0).(model.Transfer), args.Error(1)
+}
+
+func (t *TransferRepoMock) CreateReversal(payload model.Reversal) (model.Reversal, error) {
+	args := t.Called(payload)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetReversal(id string) (model.Reversal, error) {
+	args := t.Called(id)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetActiveReversal(trxId string) (model.Reversal, error) {
+	args := t.Called(trxId)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetPendingReversal(userId string) ([]model.Reversal, error) {
+	args := t.Called(userId)
+	return args.Get(0).([]model.Reversal), args.Error(1)
+}
+
+func (t *TransferRepoMock) UpdateReversalStatus(id, status string) (model.Reversal, error) {
+	args := t.Called(id, status)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferRepoMock) ExecuteReversal(id string) (model.Reversal, error) {
+	args := t.Called(id)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferRepoMock) CreateIntent(payload model.TransferIntent) (model.TransferIntent, error) {
+	args := t.Called(payload)
+	return args.Get(0).(model.TransferIntent), args.Error(1)
+}
+
+func (t *TransferRepoMock) GetIntent(id string) (model.TransferIntent, error) {
+	args := t.Called(id)
+	return args.Get(0).(model.TransferIntent), args.Error(1)
+}
+
+func (t *TransferRepoMock) ClaimIntent(id string) (bool, error) {
+	args := t.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (t *TransferRepoMock) FinishIntent(id, status, transferId string) error {
+	args := t.Called(id, status, transferId)
+	return args.Error(0)
+}
+
+func (t *TransferRepoMock) UpdateCategory(trxId, userId, kategori string) (model.Transfer, error) {
+	args := t.Called(trxId, userId, kategori)
+	return args.Get(0).(model.Transfer), args.Error(1)
+}
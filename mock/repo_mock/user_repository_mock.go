@@ -59,3 +59,43 @@ func (u *UserRepoMock) CreateRekening(payload model.Rekening) (model.Rekening, e
 	args := u.Called(payload)
 	return args.Get(0).(model.Rekening), args.Error(1)
 }
+
+func (u *UserRepoMock) GetRekeningById(id string) (model.Rekening, error) {
+	args := u.Called(id)
+	return args.Get(0).(model.Rekening), args.Error(1)
+}
+
+func (u *UserRepoMock) GetAllRekening(userId string) ([]model.Rekening, error) {
+	args := u.Called(userId)
+	return args.Get(0).([]model.Rekening), args.Error(1)
+}
+
+func (u *UserRepoMock) SetDefaultRekening(id, userId string) (model.Rekening, error) {
+	args := u.Called(id, userId)
+	return args.Get(0).(model.Rekening), args.Error(1)
+}
+
+func (u *UserRepoMock) DeleteRekening(id, userId string) error {
+	args := u.Called(id, userId)
+	return args.Error(0)
+}
+
+func (u *UserRepoMock) VerifyRekening(id string) (model.Rekening, error) {
+	args := u.Called(id)
+	return args.Get(0).(model.Rekening), args.Error(1)
+}
+
+func (u *UserRepoMock) GetByRecipient(query, phone string) (model.User, error) {
+	args := u.Called(query, phone)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (u *UserRepoMock) GetByPaymentTag(tag string) (model.User, error) {
+	args := u.Called(tag)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (u *UserRepoMock) UpdatePaymentTag(id, tag string) (model.User, error) {
+	args := u.Called(id, tag)
+	return args.Get(0).(model.User), args.Error(1)
+}
@@ -0,0 +1,67 @@
+package repomock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type WebhookRepoMock struct {
+	mock.Mock
+}
+
+func (w *WebhookRepoMock) CreateEndpoint(payload model.WebhookEndpoint) (model.WebhookEndpoint, error) {
+	args := w.Called(payload)
+	return args.Get(0).(model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookRepoMock) GetEndpoint(id string) (model.WebhookEndpoint, error) {
+	args := w.Called(id)
+	return args.Get(0).(model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookRepoMock) GetEndpoints(merchantId string) ([]model.WebhookEndpoint, error) {
+	args := w.Called(merchantId)
+	return args.Get(0).([]model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookRepoMock) GetSubscribedEndpoints(merchantId, eventType string) ([]model.WebhookEndpoint, error) {
+	args := w.Called(merchantId, eventType)
+	return args.Get(0).([]model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookRepoMock) UpdateEndpoint(payload model.WebhookEndpoint) (model.WebhookEndpoint, error) {
+	args := w.Called(payload)
+	return args.Get(0).(model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookRepoMock) CreateDeliveries(payload []model.WebhookDelivery) ([]model.WebhookDelivery, error) {
+	args := w.Called(payload)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookRepoMock) GetDelivery(id string) (model.WebhookDelivery, error) {
+	args := w.Called(id)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookRepoMock) GetDeliveries(endpointId, status string, page int) ([]model.WebhookDelivery, error) {
+	args := w.Called(endpointId, status, page)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookRepoMock) GetDue(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	args := w.Called(now, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookRepoMock) ClaimDue(id string, dueAt, leaseUntil time.Time) (bool, error) {
+	args := w.Called(id, dueAt, leaseUntil)
+	return args.Bool(0), args.Error(1)
+}
+
+func (w *WebhookRepoMock) RecordAttempt(payload model.WebhookDelivery, attempt model.WebhookAttempt) error {
+	args := w.Called(payload, attempt)
+	return args.Error(0)
+}
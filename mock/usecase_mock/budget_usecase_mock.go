@@ -0,0 +1,41 @@
+package usecasemock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type BudgetUseCaseMock struct {
+	mock.Mock
+}
+
+func (b *BudgetUseCaseMock) CreateBudget(payload dto.BudgetRequest) (model.Budget, error) {
+	args := b.Called(payload)
+	return args.Get(0).(model.Budget), args.Error(1)
+}
+
+func (b *BudgetUseCaseMock) FindBudgets(userId string) ([]model.Budget, error) {
+	args := b.Called(userId)
+	return args.Get(0).([]model.Budget), args.Error(1)
+}
+
+func (b *BudgetUseCaseMock) FindBudget(id, userId string) (model.Budget, error) {
+	args := b.Called(id, userId)
+	return args.Get(0).(model.Budget), args.Error(1)
+}
+
+func (b *BudgetUseCaseMock) UpdateBudget(id string, payload dto.BudgetRequest) (model.Budget, error) {
+	args := b.Called(id, payload)
+	return args.Get(0).(model.Budget), args.Error(1)
+}
+
+func (b *BudgetUseCaseMock) DeleteBudget(id, userId string) error {
+	args := b.Called(id, userId)
+	return args.Error(0)
+}
+
+func (b *BudgetUseCaseMock) Evaluate(userId string) error {
+	args := b.Called(userId)
+	return args.Error(0)
+}
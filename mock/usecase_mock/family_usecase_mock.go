@@ -0,0 +1,96 @@
+package usecasemock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type FamilyUseCaseMock struct {
+	mock.Mock
+}
+
+func (f *FamilyUseCaseMock) CreateFamily(payload dto.FamilyRequest) (model.Family, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.Family), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) FindFamily(userId string) (model.Family, error) {
+	args := f.Called(userId)
+	return args.Get(0).(model.Family), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) AddMember(payload dto.FamilyMemberRequest) (model.FamilyMember, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) UpdateMember(memberId string, payload dto.FamilyMemberRequest) (model.FamilyMember, error) {
+	args := f.Called(memberId, payload)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) RemoveMember(memberId, ownerId string) error {
+	args := f.Called(memberId, ownerId)
+	return args.Error(0)
+}
+
+func (f *FamilyUseCaseMock) FindMember(memberId, ownerId string) (model.FamilyMember, error) {
+	args := f.Called(memberId, ownerId)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) AcceptInvite(userId string) (model.FamilyMember, error) {
+	args := f.Called(userId)
+	return args.Get(0).(model.FamilyMember), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) DeclineInvite(userId string) error {
+	args := f.Called(userId)
+	return args.Error(0)
+}
+
+func (f *FamilyUseCaseMock) LeaveFamily(userId string) error {
+	args := f.Called(userId)
+	return args.Error(0)
+}
+
+func (f *FamilyUseCaseMock) Authorize(payload dto.FamilySpendRequest) (model.FamilyReservation, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FamilyReservation), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) Check(payload dto.FamilySpendRequest) (bool, error) {
+	args := f.Called(payload)
+	return args.Bool(0), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) Release(reservationId string) error {
+	args := f.Called(reservationId)
+	return args.Error(0)
+}
+
+func (f *FamilyUseCaseMock) Record(payload model.FamilyActivity) error {
+	args := f.Called(payload)
+	return args.Error(0)
+}
+
+func (f *FamilyUseCaseMock) FindApprovals(ownerId, status string) ([]model.FamilyApproval, error) {
+	args := f.Called(ownerId, status)
+	return args.Get(0).([]model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) ApproveRequest(id, ownerId string) (model.FamilyApproval, error) {
+	args := f.Called(id, ownerId)
+	return args.Get(0).(model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) RejectRequest(id, ownerId string) (model.FamilyApproval, error) {
+	args := f.Called(id, ownerId)
+	return args.Get(0).(model.FamilyApproval), args.Error(1)
+}
+
+func (f *FamilyUseCaseMock) FindActivity(ownerId string, page int) ([]model.FamilyActivity, error) {
+	args := f.Called(ownerId, page)
+	return args.Get(0).([]model.FamilyActivity), args.Error(1)
+}
@@ -0,0 +1,46 @@
+package usecasemock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type FeeUseCaseMock struct {
+	mock.Mock
+}
+
+func (f *FeeUseCaseMock) Quote(payload dto.FeeQuoteRequest) (model.FeeQuote, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FeeQuote), args.Error(1)
+}
+
+func (f *FeeUseCaseMock) CreateRule(payload model.FeeRule) (model.FeeRule, error) {
+	args := f.Called(payload)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeUseCaseMock) UpdateRule(id string, payload model.FeeRule) (model.FeeRule, error) {
+	args := f.Called(id, payload)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeUseCaseMock) DeactivateRule(id string) (model.FeeRule, error) {
+	args := f.Called(id)
+	return args.Get(0).(model.FeeRule), args.Error(1)
+}
+
+func (f *FeeUseCaseMock) FindRules() ([]model.FeeRule, error) {
+	args := f.Called()
+	return args.Get(0).([]model.FeeRule), args.Error(1)
+}
+
+func (f *FeeUseCaseMock) FindFees(userId string, page int) ([]model.FeeLine, error) {
+	args := f.Called(userId, page)
+	return args.Get(0).([]model.FeeLine), args.Error(1)
+}
+
+func (f *FeeUseCaseMock) GetRevenue() (model.HouseWallet, error) {
+	args := f.Called()
+	return args.Get(0).(model.HouseWallet), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package usecasemock
+
+import (
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type NotificationUseCaseMock struct {
+	mock.Mock
+}
+
+func (n *NotificationUseCaseMock) Notify(payload model.Notification) error {
+	args := n.Called(payload)
+	return args.Error(0)
+}
+
+func (n *NotificationUseCaseMock) FindAll(userId string, page int) ([]model.Notification, error) {
+	args := n.Called(userId, page)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (n *NotificationUseCaseMock) MarkRead(id, userId string) error {
+	args := n.Called(id, userId)
+	return args.Error(0)
+}
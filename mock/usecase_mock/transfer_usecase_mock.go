@@ -0,0 +1,103 @@
+package usecasemock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type TransferUseCaseMock struct {
+	mock.Mock
+}
+
+func (t *TransferUseCaseMock) TransferRequest(payload dto.TransferRequest, send, receive model.User) (model.Transfer, error) {
+	args := t.Called(payload, send, receive)
+	return args.Get(0).(model.Transfer), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) QuoteTransfer(payload dto.TransferRequest, send, receive model.User) (model.TransferIntent, error) {
+	args := t.Called(payload, send, receive)
+	return args.Get(0).(model.TransferIntent), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) FindIntent(id, userId string) (model.TransferIntent, error) {
+	args := t.Called(id, userId)
+	return args.Get(0).(model.TransferIntent), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) ConfirmTransfer(id string, send, receive model.User) (model.Transfer, error) {
+	args := t.Called(id, send, receive)
+	return args.Get(0).(model.Transfer), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) GetSend(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
+	args := t.Called(id, page, filter)
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) GetReceive(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
+	args := t.Called(id, page, filter)
+	return args.Get(0).([]model.Transfer), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) UpdateCategory(trxId, userId, kategori string) (model.Transfer, error) {
+	args := t.Called(trxId, userId, kategori)
+	return args.Get(0).(model.Transfer), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) Withdraw(payload model.Withdraw) (model.Withdraw, error) {
+	args := t.Called(payload)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) GetAllWithDraw(id string, page int) ([]model.Withdraw, error) {
+	args := t.Called(id, page)
+	return args.Get(0).([]model.Withdraw), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) FindWithdraw(id, userId, role string) (model.Withdraw, error) {
+	args := t.Called(id, userId, role)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) ProcessWithdraw(id string) (model.Withdraw, error) {
+	args := t.Called(id)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) RetryStuckWithdraws(now time.Time) (int, error) {
+	args := t.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) CancelWithdraw(id, userId string) (model.Withdraw, error) {
+	args := t.Called(id, userId)
+	return args.Get(0).(model.Withdraw), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) ReverseTransfer(payload dto.ReversalRequest, adminId string) (model.Reversal, error) {
+	args := t.Called(payload, adminId)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) FindActiveReversal(trxId string) (model.Reversal, error) {
+	args := t.Called(trxId)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) GetPendingReversal(userId string) ([]model.Reversal, error) {
+	args := t.Called(userId)
+	return args.Get(0).([]model.Reversal), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) ApproveReversal(id, userId string) (model.Reversal, error) {
+	args := t.Called(id, userId)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
+
+func (t *TransferUseCaseMock) RejectReversal(id, userId string) (model.Reversal, error) {
+	args := t.Called(id, userId)
+	return args.Get(0).(model.Reversal), args.Error(1)
+}
@@ -54,3 +54,43 @@ func (u *UserUseCaseMock) CreateRekening(payload model.Rekening) (model.Rekening
 	args := u.Called(payload)
 	return args.Get(0).(model.Rekening), args.Error(1)
 }
+
+func (u *UserUseCaseMock) FindRekeningById(id, userId string) (model.Rekening, error) {
+	args := u.Called(id, userId)
+	return args.Get(0).(model.Rekening), args.Error(1)
+}
+
+func (u *UserUseCaseMock) FindAllRekening(userId string) ([]model.Rekening, error) {
+	args := u.Called(userId)
+	return args.Get(0).([]model.Rekening), args.Error(1)
+}
+
+func (u *UserUseCaseMock) SetDefaultRekening(id, userId string) (model.Rekening, error) {
+	args := u.Called(id, userId)
+	return args.Get(0).(model.Rekening), args.Error(1)
+}
+
+func (u *UserUseCaseMock) DeleteRekening(id, userId string) error {
+	args := u.Called(id, userId)
+	return args.Error(0)
+}
+
+func (u *UserUseCaseMock) InquiryRekening(id, userId string) (model.Rekening, error) {
+	args := u.Called(id, userId)
+	return args.Get(0).(model.Rekening), args.Error(1)
+}
+
+func (u *UserUseCaseMock) ResolveRecipient(query string) (model.User, error) {
+	args := u.Called(query)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (u *UserUseCaseMock) LookupRecipient(query, userId string) (dto.RecipientResponse, error) {
+	args := u.Called(query, userId)
+	return args.Get(0).(dto.RecipientResponse), args.Error(1)
+}
+
+func (u *UserUseCaseMock) SetPaymentTag(userId, tag string) (model.User, error) {
+	args := u.Called(userId, tag)
+	return args.Get(0).(model.User), args.Error(1)
+}
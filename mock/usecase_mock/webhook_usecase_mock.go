@@ -0,0 +1,58 @@
+package usecasemock
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type WebhookUseCaseMock struct {
+	mock.Mock
+}
+
+func (w *WebhookUseCaseMock) RegisterEndpoint(merchantId, ownerId string, payload dto.WebhookEndpointRequest) (model.WebhookEndpoint, error) {
+	args := w.Called(merchantId, ownerId, payload)
+	return args.Get(0).(model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) FindEndpoints(merchantId, ownerId string) ([]model.WebhookEndpoint, error) {
+	args := w.Called(merchantId, ownerId)
+	return args.Get(0).([]model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) UpdateEndpoint(id, merchantId, ownerId string, payload dto.WebhookEndpointRequest) (model.WebhookEndpoint, error) {
+	args := w.Called(id, merchantId, ownerId, payload)
+	return args.Get(0).(model.WebhookEndpoint), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) SendTest(id, merchantId, ownerId string) (model.WebhookDelivery, error) {
+	args := w.Called(id, merchantId, ownerId)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) FindDeliveries(id, merchantId, ownerId, status string, page int) ([]model.WebhookDelivery, error) {
+	args := w.Called(id, merchantId, ownerId, status, page)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) FindDelivery(deliveryId, id, merchantId, ownerId string) (model.WebhookDelivery, error) {
+	args := w.Called(deliveryId, id, merchantId, ownerId)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) Redeliver(deliveryId, id, merchantId, ownerId string) (model.WebhookDelivery, error) {
+	args := w.Called(deliveryId, id, merchantId, ownerId)
+	return args.Get(0).(model.WebhookDelivery), args.Error(1)
+}
+
+func (w *WebhookUseCaseMock) Publish(merchantId, eventType string, data any) error {
+	args := w.Called(merchantId, eventType, data)
+	return args.Error(0)
+}
+
+func (w *WebhookUseCaseMock) RunDue(now time.Time) (int, error) {
+	args := w.Called(now)
+	return args.Int(0), args.Error(1)
+}
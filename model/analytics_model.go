@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	AnalyticsPeriodDaily   = "daily"
+	AnalyticsPeriodWeekly  = "weekly"
+	AnalyticsPeriodMonthly = "monthly"
+)
+
+// AnalyticsBucket adalah agregat dana masuk dan keluar untuk satu kelompok (kategori, pihak atau periode)
+type AnalyticsBucket struct {
+	Label           string `json:"label"`
+	Masuk           int    `json:"masuk"`
+	Keluar          int    `json:"keluar"`
+	JumlahTransaksi int    `json:"jumlah_transaksi"`
+}
+
+// MonthComparison membandingkan bulan terakhir periode dengan bulan sebelumnya, persen nil jika bulan lalu kosong
+type MonthComparison struct {
+	BulanIni        AnalyticsBucket `json:"bulan_ini"`
+	BulanLalu       AnalyticsBucket `json:"bulan_lalu"`
+	PerubahanMasuk  *float64        `json:"perubahan_masuk_persen"`
+	PerubahanKeluar *float64        `json:"perubahan_keluar_persen"`
+}
+
+type SpendingAnalytics struct {
+	Dari         time.Time         `json:"dari"`
+	Sampai       time.Time         `json:"sampai"`
+	Periode      string            `json:"periode"`
+	Total        AnalyticsBucket   `json:"total"`
+	Net          int               `json:"net"`
+	PerKategori  []AnalyticsBucket `json:"per_kategori"`
+	PerPihak     []AnalyticsBucket `json:"per_pihak"`
+	PerPeriode   []AnalyticsBucket `json:"per_periode"`
+	Perbandingan MonthComparison   `json:"perbandingan_bulanan"`
+}
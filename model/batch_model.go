@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+const (
+	BatchModeAllOrNothing = "all_or_nothing"
+	BatchModeBestEffort   = "best_effort"
+)
+
+const (
+	BatchStatusValidated          = "validated"
+	BatchStatusInvalid            = "invalid"
+	BatchStatusProcessing         = "processing"
+	BatchStatusCompleted          = "completed"
+	BatchStatusPartiallyCompleted = "partially_completed"
+	BatchStatusFailed             = "failed"
+)
+
+const (
+	BatchItemValid   = "valid"
+	BatchItemInvalid = "invalid"
+	BatchItemSuccess = "success"
+	BatchItemFailed  = "failed"
+	BatchItemSkipped = "skipped"
+)
+
+type BatchTransfer struct {
+	Id            string      `json:"id"`
+	UserId        string      `json:"user_id"`
+	Mode          string      `json:"mode"`
+	Status        string      `json:"status"`
+	JumlahItem    int         `json:"jumlah_item"`
+	JumlahValid   int         `json:"jumlah_valid"`
+	TotalJumlah   int         `json:"total_jumlah"`
+	TotalBiaya    int         `json:"total_biaya"`
+	SaldoTersedia int         `json:"saldo_tersedia,omitempty"`
+	Items         []BatchItem `json:"items,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+type BatchItem struct {
+	Id             string `json:"id"`
+	BatchId        string `json:"batch_id"`
+	Baris          int    `json:"baris"`
+	Tujuan         string `json:"tujuan"`
+	TujuanTransfer string `json:"tujuan_transfer,omitempty"`
+	NamaPenerima   string `json:"nama_penerima,omitempty"`
+	Jumlah         int    `json:"jumlah"`
+	Biaya          int    `json:"biaya"`
+	FeeRuleId      string `json:"-"`
+	Catatan        string `json:"catatan,omitempty"`
+	Status         string `json:"status"`
+	Error          string `json:"error,omitempty"`
+	TransferId     string `json:"transfer_id,omitempty"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ambang pemakaian budget (persen) yang memicu notifikasi, diurutkan dari yang tertinggi
+var BudgetThresholds = []int{100, 80}
+
+// Budget bulanan per kategori, kategori kosong berarti budget seluruh pengeluaran.
+// Terpakai, Sisa dan Persen dihitung untuk bulan berjalan.
+type Budget struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"user_id"`
+	Kategori  string    `json:"kategori"`
+	Jumlah    int       `json:"jumlah"`
+	IsActive  bool      `json:"is_active"`
+	Periode   string    `json:"periode,omitempty"`
+	Terpakai  int       `json:"terpakai"`
+	Sisa      int       `json:"sisa"`
+	Persen    float64   `json:"persen"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
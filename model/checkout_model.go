@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	CheckoutStatusOpen      = "open"
+	CheckoutStatusCompleted = "completed"
+	CheckoutStatusCancelled = "cancelled"
+	CheckoutStatusExpired   = "expired"
+)
+
+// CheckoutSession dibuat merchant lewat API lalu disetujui user wallet dengan PIN,
+// hasilnya dikirim ke merchant melalui redirect dan webhook
+type CheckoutSession struct {
+	Id           string    `json:"id"`
+	MerchantId   string    `json:"merchant_id"`
+	NamaMerchant string    `json:"nama_merchant,omitempty"`
+	OwnerId      string    `json:"-"`
+	OrderId      string    `json:"order_id"`
+	Jumlah       int       `json:"jumlah"`
+	Deskripsi    string    `json:"deskripsi,omitempty"`
+	SuccessUrl   string    `json:"success_url"`
+	CancelUrl    string    `json:"cancel_url"`
+	Status       string    `json:"status"`
+	UserId       string    `json:"user_id,omitempty"`
+	PaymentId    string    `json:"payment_id,omitempty"`
+	CheckoutUrl  string    `json:"checkout_url,omitempty"`
+	RedirectUrl  string    `json:"redirect_url,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
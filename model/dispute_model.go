@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	DisputeStatusOpen             = "open"
+	DisputeStatusInvestigating    = "investigating"
+	DisputeStatusResolvedInFavour = "resolved_in_favour"
+	DisputeStatusRejected         = "rejected"
+	DisputeTrxTypeTransfer        = "transfer"
+	DisputeTrxTypeTopup           = "topup"
+)
+
+type Dispute struct {
+	Id             string           `json:"id"`
+	UserId         string           `json:"user_id"`
+	TrxType        string           `json:"trx_type"`
+	TrxId          string           `json:"trx_id"`
+	CounterpartyId string           `json:"counterparty_id,omitempty"`
+	Jumlah         int              `json:"jumlah"`
+	Deskripsi      string           `json:"deskripsi"`
+	Status         string           `json:"status"`
+	HoldId         string           `json:"hold_id,omitempty"`
+	ReversalId     string           `json:"reversal_id,omitempty"`
+	Comments       []DisputeComment `json:"comments,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+type DisputeComment struct {
+	Id         string    `json:"id"`
+	DisputeId  string    `json:"dispute_id"`
+	AuthorId   string    `json:"author_id"`
+	AuthorRole string    `json:"author_role"`
+	Komentar   string    `json:"komentar"`
+	CreatedAt  time.Time `json:"created_at"`
+}
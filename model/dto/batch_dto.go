@@ -0,0 +1,14 @@
+package dto
+
+type BatchRow struct {
+	Tujuan  string `json:"tujuan"`
+	Jumlah  int    `json:"jumlah"`
+	Catatan string `json:"catatan"`
+	// diisi parser CSV jika baris tidak bisa dibaca
+	Error string `json:"-"`
+}
+
+type BatchTransferRequest struct {
+	Mode  string     `json:"mode"`
+	Items []BatchRow `json:"items" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type BudgetRequest struct {
+	UserId string `json:"-"`
+	// kosong berarti budget keseluruhan
+	Kategori string `json:"kategori"`
+	Jumlah   int    `json:"jumlah" binding:"required"`
+	IsActive *bool  `json:"is_active"`
+}
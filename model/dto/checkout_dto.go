@@ -0,0 +1,11 @@
+package dto
+
+type CheckoutSessionRequest struct {
+	OrderId    string `json:"order_id" binding:"required"`
+	Jumlah     int    `json:"jumlah" binding:"required"`
+	Deskripsi  string `json:"deskripsi"`
+	SuccessUrl string `json:"success_url" binding:"required"`
+	CancelUrl  string `json:"cancel_url" binding:"required"`
+	// masa berlaku dalam menit, default 30 menit
+	ExpiresIn int `json:"expires_in"`
+}
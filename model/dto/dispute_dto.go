@@ -0,0 +1,19 @@
+package dto
+
+type DisputeRequest struct {
+	UserId    string `json:"user_id"`
+	TrxType   string `json:"trx_type" binding:"required"`
+	TrxId     string `json:"trx_id" binding:"required"`
+	Deskripsi string `json:"deskripsi" binding:"required"`
+}
+
+type DisputeCommentRequest struct {
+	Komentar string `json:"komentar" binding:"required"`
+}
+
+type DisputeStatusRequest struct {
+	Status    string `json:"status" binding:"required"`
+	Komentar  string `json:"komentar"`
+	TahanDana bool   `json:"tahan_dana"`
+	Policy    string `json:"policy"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type FamilyRequest struct {
+	OwnerId string `json:"-"`
+	Nama    string `json:"nama" binding:"required"`
+}
+
+type FamilyMemberRequest struct {
+	OwnerId string `json:"-"`
+	// uuid, payment tag, username, nomor hp atau email anggota
+	User              string   `json:"user"`
+	LimitHarian       int      `json:"limit_harian"`
+	JenisDiizinkan    []string `json:"jenis_diizinkan"`
+	AmbangPersetujuan int      `json:"ambang_persetujuan"`
+}
+
+// FamilySpendRequest adalah pengeluaran yang akan dilakukan user, diperiksa sebelum saldo dipindahkan
+type FamilySpendRequest struct {
+	UserId     string
+	Jenis      string
+	Jumlah     int
+	Tujuan     string
+	Keterangan string
+}
+
+type FamilyFundRequest struct {
+	Jumlah  int    `json:"jumlah" binding:"required"`
+	Catatan string `json:"catatan"`
+	Pin     string `json:"pin" binding:"required"`
+}
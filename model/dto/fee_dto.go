@@ -0,0 +1,10 @@
+package dto
+
+type FeeQuoteRequest struct {
+	UserId    string `form:"-"`
+	Operation string `form:"operation" binding:"required"`
+	Channel   string `form:"channel"`
+	Jumlah    int    `form:"jumlah" binding:"required"`
+	// operasi sebelumnya dalam batch yang sama yang belum tercatat, ikut mengurangi kuota gratis
+	Terpakai int `form:"-"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+type HoldRequest struct {
+	UserId      string `json:"user_id"`
+	Pin         string `json:"pin" binding:"required"`
+	Jumlah      int    `json:"jumlah" binding:"required"`
+	Alasan      string `json:"alasan"`
+	ReferenceId string `json:"reference_id"`
+	// lama hold dalam menit, 0 berarti tidak kedaluwarsa
+	ExpiresIn int `json:"expires_in"`
+	// hold untuk merchant hanya bisa di-capture atau dilepas oleh merchant tersebut lewat API merchant
+	MerchantId string `json:"merchant_id"`
+}
+
+type MerchantCaptureRequest struct {
+	// 0 berarti capture seluruh jumlah hold
+	Jumlah int `json:"jumlah"`
+}
+
+type CaptureRequest struct {
+	// 0 berarti capture seluruh jumlah hold
+	Jumlah int `json:"jumlah"`
+	// merchant, user atau house
+	PenerimaJenis string `json:"penerima_jenis" binding:"required"`
+	// id merchant, id user atau nama house wallet (default revenue)
+	PenerimaId string `json:"penerima_id"`
+}
@@ -0,0 +1,62 @@
+package dto
+
+type MerchantRequest struct {
+	OwnerId     string `json:"-"`
+	Nama        string `json:"nama" binding:"required"`
+	Kategori    string `json:"kategori" binding:"required"`
+	Kota        string `json:"kota" binding:"required"`
+	Alamat      string `json:"alamat"`
+	BankCode    string `json:"bank_code" binding:"required"`
+	Rekening    string `json:"rekening" binding:"required"`
+	NamaPemilik string `json:"nama_pemilik" binding:"required"`
+}
+
+type MerchantQRRequest struct {
+	Jumlah    int    `json:"jumlah" binding:"required"`
+	Referensi string `json:"referensi"`
+	// masa berlaku dalam menit, default 15 menit
+	ExpiresIn int `json:"expires_in"`
+}
+
+type QRPreviewRequest struct {
+	Payload string `json:"payload" binding:"required"`
+}
+
+type QRPaymentRequest struct {
+	UserId  string `json:"-"`
+	Payload string `json:"payload" binding:"required"`
+	// wajib untuk QR statis, diabaikan untuk QR dinamis
+	Jumlah  int    `json:"jumlah"`
+	Catatan string `json:"catatan"`
+	Pin     string `json:"pin" binding:"required"`
+}
+
+type MerchantApiKeyRequest struct {
+	Scopes []string `json:"scopes" binding:"required"`
+}
+
+// SignedRequest berisi header dan isi request merchant yang diperiksa tanda tangannya
+type SignedRequest struct {
+	KeyId     string
+	Timestamp string
+	Nonce     string
+	Signature string
+	Method    string
+	Path      string
+	Body      []byte
+}
+
+type SettlementConfigRequest struct {
+	Jadwal  string `json:"jadwal" binding:"required"`
+	Minimum int    `json:"minimum"`
+}
+
+type RefundRequest struct {
+	MerchantId string `json:"-"`
+	OwnerId    string `json:"-"`
+	PaymentId  string `json:"-"`
+	// jumlah kosong berarti refund seluruh sisa pembayaran
+	Jumlah    int    `json:"jumlah"`
+	Alasan    string `json:"alasan"`
+	Referensi string `json:"referensi"`
+}
@@ -0,0 +1,30 @@
+package dto
+
+type PaymentRequestRequest struct {
+	RequesterId string `json:"-"`
+	Payer       string `json:"payer" binding:"required"`
+	Jumlah      int    `json:"jumlah" binding:"required"`
+	Catatan     string `json:"catatan"`
+	// masa berlaku dalam jam, default 72 jam
+	ExpiresIn int `json:"expires_in"`
+}
+
+type PinRequest struct {
+	Pin string `json:"pin" binding:"required"`
+}
+
+type SplitParticipant struct {
+	User   string `json:"user" binding:"required"`
+	Jumlah int    `json:"jumlah"`
+}
+
+type SplitBillRequest struct {
+	OrganiserId string             `json:"-"`
+	Judul       string             `json:"judul" binding:"required"`
+	Total       int                `json:"total" binding:"required"`
+	Mode        string             `json:"mode" binding:"required"`
+	Peserta     []SplitParticipant `json:"peserta" binding:"required"`
+	// organiser ikut menanggung bagian sendiri (tanpa payment request)
+	TermasukSaya bool `json:"termasuk_saya"`
+	ExpiresIn    int  `json:"expires_in"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type PocketRequest struct {
+	UserId     string     `json:"-"`
+	Nama       string     `json:"nama" binding:"required"`
+	Target     int        `json:"target"`
+	TargetDate *time.Time `json:"target_date"`
+}
+
+type PocketMoveRequest struct {
+	UserId   string `json:"-"`
+	PocketId string `json:"-"`
+	Arah     string `json:"arah" binding:"required"`
+	Jumlah   int    `json:"jumlah" binding:"required"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "time"
+
+type ScheduleRequest struct {
+	UserId         string     `json:"-"`
+	TujuanTransfer string     `json:"tujuan_transfer"`
+	JumlahTransfer int        `json:"jumlah_transfer"`
+	Catatan        string     `json:"catatan"`
+	Kategori       string     `json:"kategori"`
+	Frekuensi      string     `json:"frekuensi"`
+	StartAt        time.Time  `json:"start_at"`
+	EndDate        *time.Time `json:"end_date"`
+	MaxRuns        int        `json:"max_runs"`
+	Pin            string     `json:"pin" binding:"required"`
+}
@@ -1,7 +1,8 @@
 package dto
 
 type TopupRequest struct {
-	Ammount int `json:"ammount"`
+	Ammount int    `json:"ammount"`
+	Channel string `json:"channel"`
 }
 
 type ResponsePayment struct {
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// TransactionFilter dibaca dari query string GET /transactions, tanggal berformat YYYY-MM-DD
+type TransactionFilter struct {
+	Jenis  string `form:"type"`
+	Arah   string `form:"direction"`
+	Dari   string `form:"from"`
+	Sampai string `form:"to"`
+	Min    int    `form:"min_amount"`
+	Max    int    `form:"max_amount"`
+	Pihak  string `form:"counterparty"`
+	Status string `form:"status"`
+	Limit  int    `form:"limit"`
+	Cursor string `form:"cursor"`
+}
+
+// TransactionQuery adalah filter yang sudah divalidasi dan siap dipakai repository
+type TransactionQuery struct {
+	UserId   string
+	Jenis    string
+	Arah     string
+	Dari     *time.Time
+	Sampai   *time.Time
+	Min      int
+	Max      int
+	Pihak    string
+	Status   string
+	Limit    int
+	CursorAt *time.Time
+	CursorId string
+}
@@ -5,4 +5,36 @@ type TransferRequest struct {
 	TujuanTransfer string `json:"tujuan_transfer"`
 	Pin            string `json:"pin"`
 	JumlahTransfer int    `json:"jumlah_transfer"`
+	Catatan        string `json:"catatan"`
+	Referensi      string `json:"referensi"`
+	Kategori       string `json:"kategori"`
+	Biaya          int    `json:"-"`
+	FeeRuleId      string `json:"-"`
+}
+
+type ReversalRequest struct {
+	TrxId          string `json:"trx_id" binding:"required"`
+	Alasan         string `json:"alasan"`
+	Policy         string `json:"policy"`
+	RequireConsent bool   `json:"require_consent"`
+}
+
+type RecipientResponse struct {
+	NamaTampilan string `json:"nama_tampilan"`
+	PaymentTag   string `json:"payment_tag,omitempty"`
+}
+
+type TransferConfirmRequest struct {
+	IntentId string `json:"intent_id" binding:"required"`
+	Pin      string `json:"pin" binding:"required"`
+}
+
+// TransferFilter menyaring riwayat transfer berdasarkan kata kunci (catatan, referensi, nama) dan kategori
+type TransferFilter struct {
+	Search   string `form:"q"`
+	Kategori string `form:"kategori"`
+}
+
+type TransferCategoryRequest struct {
+	Kategori string `json:"kategori" binding:"required"`
 }
@@ -30,3 +30,7 @@ type UpdatePinRequest struct {
 	OldPin string `json:"old_pin" binding:"required"`
 	NewPin string `json:"new_pin" binding:"required"`
 }
+
+type PaymentTagRequest struct {
+	PaymentTag string `json:"payment_tag" binding:"required"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+type WebhookEndpointRequest struct {
+	Url    string   `json:"url" binding:"required"`
+	Events []string `json:"events"`
+	// hanya dipakai saat update, nil berarti tidak diubah
+	IsActive *bool `json:"is_active"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import "time"
+
+// jenis transaksi yang bisa dibatasi untuk anggota keluarga
+const (
+	FamilySpendTransfer = "transfer"
+	FamilySpendMerchant = "merchant"
+	FamilySpendWithdraw = "withdraw"
+)
+
+var FamilySpendTypes = []string{FamilySpendTransfer, FamilySpendMerchant, FamilySpendWithdraw}
+
+// anggota baru berstatus pending sampai undangan diterima, kontrol keluarga hanya berlaku untuk anggota active
+const (
+	FamilyMemberPending = "pending"
+	FamilyMemberActive  = "active"
+)
+
+const (
+	FamilyApprovalPending  = "pending"
+	FamilyApprovalApproved = "approved"
+	FamilyApprovalRejected = "rejected"
+	FamilyApprovalUsed     = "used"
+	FamilyApprovalExpired  = "expired"
+)
+
+const (
+	FamilyReservationActive   = "active"
+	FamilyReservationReleased = "released"
+)
+
+// jenis aktivitas di feed keluarga selain jenis transaksi
+const (
+	FamilyActivityFunding          = "funding"
+	FamilyActivityApprovalRequest  = "approval_requested"
+	FamilyActivityApprovalApproved = "approval_approved"
+	FamilyActivityApprovalRejected = "approval_rejected"
+	FamilyActivityMemberJoined     = "member_joined"
+	FamilyActivityMemberLeft       = "member_left"
+)
+
+type Family struct {
+	Id        string         `json:"id"`
+	OwnerId   string         `json:"owner_id"`
+	NamaOwner string         `json:"nama_owner,omitempty"`
+	Nama      string         `json:"nama"`
+	Members   []FamilyMember `json:"members"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// FamilyMember adalah user biasa dengan mst_saldo sendiri, pengeluarannya dibatasi oleh owner
+type FamilyMember struct {
+	Id                string    `json:"id"`
+	FamilyId          string    `json:"family_id"`
+	UserId            string    `json:"user_id"`
+	Nama              string    `json:"nama,omitempty"`
+	LimitHarian       int       `json:"limit_harian"`
+	JenisDiizinkan    []string  `json:"jenis_diizinkan"`
+	AmbangPersetujuan int       `json:"ambang_persetujuan"`
+	TerpakaiHariIni   int       `json:"terpakai_hari_ini"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type FamilyApproval struct {
+	Id         string     `json:"id"`
+	FamilyId   string     `json:"family_id"`
+	UserId     string     `json:"user_id"`
+	Nama       string     `json:"nama,omitempty"`
+	Jenis      string     `json:"jenis"`
+	Tujuan     string     `json:"tujuan,omitempty"`
+	Keterangan string     `json:"keterangan,omitempty"`
+	Jumlah     int        `json:"jumlah"`
+	Status     string     `json:"status"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	DecidedAt  *time.Time `json:"decided_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// FamilyReservation mencatat pengeluaran anggota terhadap limit harian sebelum saldo dipindahkan,
+// dilepas kembali jika transaksinya gagal
+type FamilyReservation struct {
+	Id         string    `json:"id"`
+	FamilyId   string    `json:"family_id"`
+	UserId     string    `json:"user_id"`
+	Jenis      string    `json:"jenis"`
+	Jumlah     int       `json:"jumlah"`
+	ApprovalId string    `json:"approval_id,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type FamilyActivity struct {
+	Id         string    `json:"id"`
+	FamilyId   string    `json:"family_id"`
+	UserId     string    `json:"user_id"`
+	Nama       string    `json:"nama,omitempty"`
+	Jenis      string    `json:"jenis"`
+	Jumlah     int       `json:"jumlah"`
+	Keterangan string    `json:"keterangan,omitempty"`
+	Referensi  string    `json:"referensi,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+const (
+	FeeOperationTransfer = "transfer"
+	FeeOperationTopup    = "topup"
+	FeeOperationWithdraw = "withdraw"
+	// merchant discount rate, dipotong dari penerimaan merchant saat settlement
+	FeeOperationMerchant = "merchant"
+
+	FeeTypeFlat       = "flat"
+	FeeTypePercentage = "percentage"
+	FeeTypeTiered     = "tiered"
+
+	HouseWalletRevenue = "revenue"
+)
+
+// FeeRule: flat memakai Nilai sebagai rupiah, percentage memakai Nilai sebagai persen,
+// tiered memakai Fee dari tier dengan MinJumlah terbesar yang terpenuhi
+type FeeRule struct {
+	Id        string    `json:"id"`
+	Operation string    `json:"operation"`
+	Channel   string    `json:"channel,omitempty"`
+	FeeType   string    `json:"fee_type"`
+	Nilai     float64   `json:"nilai"`
+	Tiers     []FeeTier `json:"tiers,omitempty"`
+	MinFee    int       `json:"min_fee"`
+	MaxFee    int       `json:"max_fee"`
+	FreeQuota int       `json:"free_quota"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type FeeTier struct {
+	MinJumlah int `json:"min_jumlah"`
+	Fee       int `json:"fee"`
+}
+
+type FeeQuote struct {
+	Operation  string `json:"operation"`
+	Channel    string `json:"channel,omitempty"`
+	Jumlah     int    `json:"jumlah"`
+	Biaya      int    `json:"biaya"`
+	Total      int    `json:"total"`
+	RuleId     string `json:"rule_id,omitempty"`
+	SisaGratis int    `json:"sisa_gratis"`
+}
+
+type FeeLine struct {
+	Id          string    `json:"id"`
+	UserId      string    `json:"user_id"`
+	Operation   string    `json:"operation"`
+	ReferenceId string    `json:"reference_id"`
+	RuleId      string    `json:"rule_id,omitempty"`
+	Jumlah      int       `json:"jumlah"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type HouseWallet struct {
+	Nama      string    `json:"nama"`
+	Saldo     int       `json:"saldo"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
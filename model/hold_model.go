@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusVoided   = "voided"
+	HoldStatusExpired  = "expired"
+)
+
+// sumber hold, hanya hold yang dibuat user sendiri yang boleh dilepas atau di-capture lewat endpoint hold.
+// Hold merchant dibuat user untuk satu merchant dan hanya bisa di-capture atau dilepas oleh merchant tersebut.
+const (
+	HoldSourceUser     = "user"
+	HoldSourceDispute  = "dispute"
+	HoldSourceWithdraw = "withdraw"
+	HoldSourceMerchant = "merchant"
+)
+
+// penerima dana hasil capture
+const (
+	HoldPayeeMerchant = "merchant"
+	HoldPayeeUser     = "user"
+	HoldPayeeHouse    = "house"
+)
+
+type Hold struct {
+	Id             string     `json:"id"`
+	UserId         string     `json:"user_id"`
+	Jumlah         int        `json:"jumlah"`
+	JumlahCaptured int        `json:"jumlah_captured"`
+	Alasan         string     `json:"alasan"`
+	ReferenceId    string     `json:"reference_id,omitempty"`
+	Sumber         string     `json:"sumber"`
+	PenerimaJenis  string     `json:"penerima_jenis,omitempty"`
+	PenerimaId     string     `json:"penerima_id,omitempty"`
+	Status         string     `json:"status"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
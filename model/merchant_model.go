@@ -0,0 +1,143 @@
+package model
+
+import "time"
+
+const (
+	MerchantStatusActive    = "active"
+	MerchantStatusSuspended = "suspended"
+)
+
+const (
+	MerchantQRStatic  = "static"
+	MerchantQRDynamic = "dynamic"
+)
+
+const (
+	MerchantQRActive  = "active"
+	MerchantQRPaid    = "paid"
+	MerchantQRExpired = "expired"
+)
+
+const MerchantPaymentSuccess = "success"
+
+// Merchant dimiliki oleh user dan memiliki saldo sendiri yang nantinya disettle ke rekening settlement
+type Merchant struct {
+	Id          string `json:"id"`
+	OwnerId     string `json:"owner_id"`
+	Nama        string `json:"nama"`
+	Kategori    string `json:"kategori"`
+	Kota        string `json:"kota"`
+	Alamat      string `json:"alamat"`
+	BankCode    string `json:"bank_code"`
+	Rekening    string `json:"rekening"`
+	NamaPemilik string `json:"nama_pemilik"`
+	Saldo       int    `json:"saldo"`
+	// jadwal settlement T+0 atau T+1, penerimaan di bawah minimum ditunda ke hari berikutnya
+	JadwalSettlement  string    `json:"jadwal_settlement"`
+	MinimumSettlement int       `json:"minimum_settlement"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+type MerchantQR struct {
+	Id         string     `json:"id,omitempty"`
+	MerchantId string     `json:"merchant_id"`
+	Jenis      string     `json:"jenis"`
+	Jumlah     int        `json:"jumlah,omitempty"`
+	Referensi  string     `json:"referensi,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	PaymentId  string     `json:"payment_id,omitempty"`
+	Payload    string     `json:"payload"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type MerchantPayment struct {
+	Id           string    `json:"id"`
+	MerchantId   string    `json:"merchant_id"`
+	NamaMerchant string    `json:"nama_merchant,omitempty"`
+	UserId       string    `json:"user_id"`
+	NamaPembayar string    `json:"nama_pembayar,omitempty"`
+	QrId         string    `json:"qr_id,omitempty"`
+	Jumlah       int       `json:"jumlah"`
+	JumlahRefund int       `json:"jumlah_refund"`
+	Catatan      string    `json:"catatan,omitempty"`
+	Referensi    string    `json:"referensi,omitempty"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+const MerchantRefundSuccess = "success"
+
+// MerchantRefund mengembalikan sebagian atau seluruh pembayaran ke wallet pembayar,
+// total refund satu pembayaran tidak pernah melebihi jumlah pembayaran
+type MerchantRefund struct {
+	Id           string    `json:"id"`
+	PaymentId    string    `json:"payment_id"`
+	MerchantId   string    `json:"merchant_id"`
+	NamaMerchant string    `json:"nama_merchant,omitempty"`
+	UserId       string    `json:"user_id"`
+	NamaPembayar string    `json:"nama_pembayar,omitempty"`
+	Jumlah       int       `json:"jumlah"`
+	Alasan       string    `json:"alasan,omitempty"`
+	Referensi    string    `json:"referensi,omitempty"`
+	Status       string    `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// QRPreview ditampilkan ke user setelah scan, sebelum memasukkan PIN
+type QRPreview struct {
+	MerchantId   string `json:"merchant_id"`
+	NamaMerchant string `json:"nama_merchant"`
+	Kota         string `json:"kota"`
+	Jenis        string `json:"jenis"`
+	Jumlah       int    `json:"jumlah,omitempty"`
+	QrId         string `json:"qr_id,omitempty"`
+}
+
+const (
+	MerchantScopeQRWrite      = "qr:write"
+	MerchantScopePaymentsRead = "payments:read"
+	MerchantScopeCheckout     = "checkout:write"
+	MerchantScopeRefundsWrite = "refunds:write"
+	MerchantScopeHoldsWrite   = "holds:write"
+)
+
+// MerchantScopes berisi scope yang boleh diberikan ke API key merchant
+var MerchantScopes = []string{MerchantScopeQRWrite, MerchantScopePaymentsRead, MerchantScopeCheckout, MerchantScopeRefundsWrite,
+	MerchantScopeHoldsWrite}
+
+const (
+	ApiKeyStatusActive  = "active"
+	ApiKeyStatusRevoked = "revoked"
+)
+
+// MerchantApiKey dipakai merchant untuk request server-to-server yang ditandatangani HMAC,
+// Secret hanya dikembalikan sekali saat key dibuat atau dirotasi
+type MerchantApiKey struct {
+	Id         string     `json:"id"`
+	MerchantId string     `json:"merchant_id"`
+	OwnerId    string     `json:"-"`
+	KeyId      string     `json:"key_id"`
+	Secret     string     `json:"secret,omitempty"`
+	SecretEnc  string     `json:"-"`
+	Scopes     []string   `json:"scopes"`
+	Status     string     `json:"status"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// HasScope memeriksa apakah key memiliki scope tertentu, scope kosong berarti cukup key yang valid
+func (k MerchantApiKey) HasScope(scope string) bool {
+	if scope == "" {
+		return true
+	}
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	NotificationScheduleSuccess = "schedule_success"
+	NotificationScheduleRetry   = "schedule_retry"
+	NotificationScheduleFailed  = "schedule_failed"
+	NotificationPaymentRequest  = "payment_request"
+	NotificationRefund          = "refund"
+	NotificationBudgetWarning   = "budget_warning"
+	NotificationBudgetExceeded  = "budget_exceeded"
+	NotificationFamilyApproval  = "family_approval"
+	NotificationFamilyDecision  = "family_decision"
+	NotificationFamilyInvite    = "family_invite"
+	NotificationFamilyMember    = "family_member"
+)
+
+type Notification struct {
+	Id          string    `json:"id"`
+	UserId      string    `json:"user_id"`
+	Jenis       string    `json:"jenis"`
+	Judul       string    `json:"judul"`
+	Pesan       string    `json:"pesan"`
+	ReferenceId string    `json:"reference_id,omitempty"`
+	IsRead      bool      `json:"is_read"`
+	CreatedAt   time.Time `json:"created_at"`
+}
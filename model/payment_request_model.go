@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+const (
+	PaymentRequestPending    = "pending"
+	PaymentRequestProcessing = "processing"
+	PaymentRequestPaid       = "paid"
+	PaymentRequestDeclined   = "declined"
+	PaymentRequestCancelled  = "cancelled"
+	PaymentRequestExpired    = "expired"
+)
+
+type PaymentRequest struct {
+	Id           string    `json:"id"`
+	RequesterId  string    `json:"requester_id"`
+	NamaPeminta  string    `json:"nama_peminta,omitempty"`
+	PayerId      string    `json:"payer_id"`
+	NamaPembayar string    `json:"nama_pembayar,omitempty"`
+	Jumlah       int       `json:"jumlah"`
+	Catatan      string    `json:"catatan,omitempty"`
+	Status       string    `json:"status"`
+	TransferId   string    `json:"transfer_id,omitempty"`
+	SplitId      string    `json:"split_id,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+const (
+	SplitModeEqual  = "equal"
+	SplitModeCustom = "custom"
+)
+
+const (
+	SplitStatusOpen      = "open"
+	SplitStatusSettled   = "settled"
+	SplitStatusCancelled = "cancelled"
+)
+
+// SplitBill membagi tagihan ke beberapa peserta, tiap bagian menjadi satu PaymentRequest
+type SplitBill struct {
+	Id             string           `json:"id"`
+	OrganiserId    string           `json:"organiser_id"`
+	Judul          string           `json:"judul"`
+	Total          int              `json:"total"`
+	Mode           string           `json:"mode"`
+	Status         string           `json:"status"`
+	JumlahTerbayar int              `json:"jumlah_terbayar"`
+	Shares         []PaymentRequest `json:"shares"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	PocketStatusActive = "active"
+	PocketStatusClosed = "closed"
+)
+
+// arah perpindahan dana dilihat dari sisi pocket
+const (
+	PocketMoveIn  = "masuk"
+	PocketMoveOut = "keluar"
+)
+
+// Pocket adalah kantong tabungan di dalam satu akun. Dana pocket sudah dikurangi dari mst_saldo
+// sehingga tidak ikut terhitung sebagai saldo yang bisa dibelanjakan
+type Pocket struct {
+	Id         string     `json:"id"`
+	UserId     string     `json:"user_id"`
+	Nama       string     `json:"nama"`
+	Saldo      int        `json:"saldo"`
+	Target     int        `json:"target,omitempty"`
+	TargetDate *time.Time `json:"target_date,omitempty"`
+	Status     string     `json:"status"`
+	Progress   float64    `json:"progress,omitempty"`
+	SisaTarget int        `json:"sisa_target,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ClosedAt   *time.Time `json:"closed_at,omitempty"`
+}
+
+type PocketMove struct {
+	Id        string    `json:"id"`
+	PocketId  string    `json:"pocket_id"`
+	Arah      string    `json:"arah"`
+	Jumlah    int       `json:"jumlah"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	ReversalStatusPending        = "pending"
+	ReversalStatusPendingConsent = "pending_consent"
+	ReversalStatusCompleted      = "completed"
+	ReversalStatusDeclined       = "declined"
+	ReversalStatusFailed         = "failed"
+
+	// reject: gagal jika saldo penerima kurang, partial: tarik sisa saldo yang ada
+	ReversalPolicyReject  = "reject"
+	ReversalPolicyPartial = "partial"
+)
+
+type Reversal struct {
+	Id                 string    `json:"id"`
+	TrxId              string    `json:"trx_id"`
+	RequestedBy        string    `json:"requested_by"`
+	Alasan             string    `json:"alasan"`
+	Policy             string    `json:"policy"`
+	RequireConsent     bool      `json:"require_consent"`
+	JumlahDiminta      int       `json:"jumlah_diminta"`
+	JumlahDikembalikan int       `json:"jumlah_dikembalikan"`
+	ReversalTrxId      string    `json:"reversal_trx_id,omitempty"`
+	Status             string    `json:"status"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+const (
+	ScheduleOnce    = "once"
+	ScheduleDaily   = "daily"
+	ScheduleWeekly  = "weekly"
+	ScheduleMonthly = "monthly"
+)
+
+const (
+	ScheduleStatusActive    = "active"
+	ScheduleStatusPaused    = "paused"
+	ScheduleStatusCompleted = "completed"
+	ScheduleStatusCancelled = "cancelled"
+	ScheduleStatusFailed    = "failed"
+)
+
+// ScheduledTransfer adalah transfer terjadwal yang dieksekusi scheduler tanpa PIN karena sudah diotorisasi saat dibuat
+type ScheduledTransfer struct {
+	Id             string     `json:"id"`
+	UserId         string     `json:"user_id"`
+	TujuanTransfer string     `json:"tujuan_transfer"`
+	JumlahTransfer int        `json:"jumlah_transfer"`
+	Catatan        string     `json:"catatan,omitempty"`
+	Kategori       string     `json:"kategori,omitempty"`
+	Frekuensi      string     `json:"frekuensi"`
+	StartAt        time.Time  `json:"start_at"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	EndDate        *time.Time `json:"end_date,omitempty"`
+	MaxRuns        int        `json:"max_runs,omitempty"`
+	RunCount       int        `json:"run_count"`
+	RetryCount     int        `json:"retry_count"`
+	Status         string     `json:"status"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastTransferId string     `json:"last_transfer_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+const (
+	SettlementScheduleT0 = "T+0"
+	SettlementScheduleT1 = "T+1"
+)
+
+const (
+	SettlementStatusProcessing = "processing"
+	SettlementStatusPaid       = "paid"
+	SettlementStatusFailed     = "failed"
+)
+
+// Settlement mengumpulkan penerimaan merchant yang belum disettle dan mengirim nilai bersihnya ke rekening settlement
+type Settlement struct {
+	Id              string           `json:"id"`
+	MerchantId      string           `json:"merchant_id"`
+	OwnerId         string           `json:"-"`
+	Tanggal         time.Time        `json:"tanggal"`
+	Cutoff          time.Time        `json:"cutoff"`
+	JumlahTransaksi int              `json:"jumlah_transaksi"`
+	TotalGross      int              `json:"total_gross"`
+	TotalBiaya      int              `json:"total_biaya"`
+	TotalNet        int              `json:"total_net"`
+	BankCode        string           `json:"bank_code"`
+	Rekening        string           `json:"rekening"`
+	Status          string           `json:"status"`
+	PayoutRef       string           `json:"payout_ref,omitempty"`
+	AlasanGagal     string           `json:"alasan_gagal,omitempty"`
+	Items           []SettlementItem `json:"items,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+type SettlementItem struct {
+	PaymentId    string    `json:"payment_id"`
+	NamaPembayar string    `json:"nama_pembayar,omitempty"`
+	Referensi    string    `json:"referensi,omitempty"`
+	Jumlah       int       `json:"jumlah"`
+	Biaya        int       `json:"biaya"`
+	FeeRuleId    string    `json:"-"`
+	Net          int       `json:"net"`
+	PaidAt       time.Time `json:"paid_at"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Statement adalah rekening koran satu periode, saldo berjalan dihitung dari saldo awal
+type Statement struct {
+	UserId      string          `json:"user_id"`
+	Nama        string          `json:"nama"`
+	Dari        time.Time       `json:"dari"`
+	Sampai      time.Time       `json:"sampai"`
+	SaldoAwal   int             `json:"saldo_awal"`
+	TotalMasuk  int             `json:"total_masuk"`
+	TotalKeluar int             `json:"total_keluar"`
+	SaldoAkhir  int             `json:"saldo_akhir"`
+	Lines       []StatementLine `json:"mutasi"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// StatementLine adalah satu mutasi saldo, Mutasi positif berarti dana masuk
+type StatementLine struct {
+	Id        string    `json:"id"`
+	Tanggal   time.Time `json:"tanggal"`
+	Jenis     string    `json:"jenis"`
+	Pihak     string    `json:"pihak,omitempty"`
+	Catatan   string    `json:"catatan,omitempty"`
+	Referensi string    `json:"referensi,omitempty"`
+	Kategori  string    `json:"kategori,omitempty"`
+	Mutasi    int       `json:"mutasi"`
+	Saldo     int       `json:"saldo"`
+}
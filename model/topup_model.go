@@ -8,6 +8,9 @@ import (
 
 type TopupModel struct {
 	User               User
+	Channel            string
+	Biaya              int
+	FeeRuleId          string
 	TransactionDetails midtrans.TransactionDetails `json:"transaction_details"`
 }
 
@@ -16,6 +19,8 @@ type TableTopupPayment struct {
 	UserId        string    `json:"user_id"`
 	TokenMidtrans string    `json:"token_midtrans"`
 	Ammount       int64     `json:"ammount"`
+	Biaya         int64     `json:"biaya"`
+	Channel       string    `json:"channel,omitempty"`
 	UrlPayment    string    `json:"url_payment"`
 	Status        string    `json:"status"`
 	Deskripsi     string    `json:"deskripsi"`
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+const (
+	TransactionTypeTransfer        = "transfer"
+	TransactionTypeTopup           = "topup"
+	TransactionTypeWithdraw        = "withdraw"
+	TransactionTypeMerchantPayment = "merchant_payment"
+	TransactionTypeRefund          = "refund"
+	TransactionTypeHoldCapture     = "hold_capture"
+	TransactionTypePocket          = "pocket"
+)
+
+const (
+	TransactionDirectionIn  = "masuk"
+	TransactionDirectionOut = "keluar"
+)
+
+// Transaction adalah tampilan gabungan semua jenis transaksi wallet dari sudut pandang satu user
+type Transaction struct {
+	Id        string    `json:"id"`
+	Jenis     string    `json:"jenis"`
+	Arah      string    `json:"arah"`
+	Jumlah    int       `json:"jumlah"`
+	Biaya     int       `json:"biaya"`
+	Status    string    `json:"status"`
+	PihakId   string    `json:"pihak_id,omitempty"`
+	Pihak     string    `json:"pihak,omitempty"`
+	Catatan   string    `json:"catatan,omitempty"`
+	Referensi string    `json:"referensi,omitempty"`
+	Kategori  string    `json:"kategori,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TransactionPage struct {
+	Data       []Transaction `json:"data"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
@@ -1,5 +1,21 @@
 package model
 
+import "time"
+
+const (
+	TransferStatusSuccess           = "success"
+	TransferStatusReversed          = "reversed"
+	TransferStatusPartiallyReversed = "partially_reversed"
+)
+
+const (
+	IntentStatusPending    = "pending"
+	IntentStatusProcessing = "processing"
+	IntentStatusConfirmed  = "confirmed"
+	IntentStatusFailed     = "failed"
+	IntentStatusExpired    = "expired"
+)
+
 type Transfer struct {
 	Id             string `json:"id"`
 	SenderName     string `json:"nama_pengirim,omitempty"`
@@ -8,5 +24,35 @@ type Transfer struct {
 	Receiver       string `json:"nama_penerima,omitempty"`
 	TujuanTransfer string `json:"tujuan_transfer"`
 	JumlahTransfer int    `json:"jumlah_transfer"`
+	Biaya          int    `json:"biaya,omitempty"`
 	JenisTransfer  string `json:"jenis_transfer"`
+	Status         string `json:"status,omitempty"`
+	ReversalOf     string `json:"reversal_of,omitempty"`
+	ReversedBy     string `json:"reversed_by,omitempty"`
+	Catatan        string `json:"catatan,omitempty"`
+	Referensi      string `json:"referensi,omitempty"`
+	Kategori       string `json:"kategori,omitempty"`
+}
+
+// TransferIntent menyimpan rincian transfer yang sudah di-quote, dieksekusi persis sama saat konfirmasi
+type TransferIntent struct {
+	Id             string `json:"id"`
+	UserId         string `json:"user_id"`
+	TujuanTransfer string `json:"tujuan_transfer"`
+	NamaPenerima   string `json:"nama_penerima,omitempty"`
+	JumlahTransfer int    `json:"jumlah_transfer"`
+	Biaya          int    `json:"biaya"`
+	Total          int    `json:"total"`
+	SaldoSetelah   int    `json:"saldo_setelah,omitempty"`
+	// transfer di atas ambang keluarga, permintaan persetujuan dibuat saat konfirmasi
+	PerluPersetujuan bool      `json:"perlu_persetujuan,omitempty"`
+	FeeRuleId        string    `json:"-"`
+	Catatan          string    `json:"catatan,omitempty"`
+	Referensi        string    `json:"referensi,omitempty"`
+	Kategori         string    `json:"kategori,omitempty"`
+	Status           string    `json:"status"`
+	TransferId       string    `json:"transfer_id,omitempty"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
@@ -10,6 +10,7 @@ type User struct {
 	Role        string    `json:"role"`
 	Email       string    `json:"email"`
 	PhoneNumber string    `json:"phone_number"`
+	PaymentTag  string    `json:"payment_tag,omitempty"`
 	Saldo       int       `json:"saldo,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
@@ -31,15 +32,22 @@ type JwtClaims struct {
 }
 
 type UserSaldo struct {
-	User  UserResponse `json:"user"`
-	Saldo int          `json:"saldo"`
-	Pin   string       `json:"pin"`
+	User          UserResponse `json:"user"`
+	Saldo         int          `json:"saldo"`
+	SaldoTertahan int          `json:"saldo_tertahan"`
+	SaldoTersedia int          `json:"saldo_tersedia"`
+	SaldoPocket   int          `json:"saldo_pocket"`
+	Pin           string       `json:"pin"`
 }
 
 type Rekening struct {
-	Id         string    `json:"id"`
-	UserId     string    `json:"user_id"`
-	Rekening   string    `json:"rekening"`
-	Created_at time.Time `json:"created_at"`
-	Updated_at time.Time `json:"updated_at"`
+	Id          string    `json:"id"`
+	UserId      string    `json:"user_id"`
+	BankCode    string    `json:"bank_code"`
+	Rekening    string    `json:"rekening"`
+	NamaPemilik string    `json:"nama_pemilik"`
+	IsDefault   bool      `json:"is_default"`
+	IsVerified  bool      `json:"is_verified"`
+	Created_at  time.Time `json:"created_at"`
+	Updated_at  time.Time `json:"updated_at"`
 }
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookEventPaymentSucceeded  = "payment.succeeded"
+	WebhookEventRefundCreated     = "refund.created"
+	WebhookEventCheckoutCompleted = "checkout.completed"
+	WebhookEventTest              = "webhook.test"
+)
+
+// WebhookEvents berisi event yang bisa dilanggan endpoint merchant
+var WebhookEvents = []string{WebhookEventPaymentSucceeded, WebhookEventRefundCreated, WebhookEventCheckoutCompleted, WebhookEventTest}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookEndpoint menerima event merchant, Events kosong berarti melanggan semua event.
+// Secret hanya dikembalikan sekali saat endpoint didaftarkan.
+type WebhookEndpoint struct {
+	Id         string    `json:"id"`
+	MerchantId string    `json:"merchant_id"`
+	Url        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	SecretEnc  string    `json:"-"`
+	Events     []string  `json:"events"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WebhookEvent adalah envelope yang dikirim sebagai body request
+type WebhookEvent struct {
+	Id         string    `json:"id"`
+	Type       string    `json:"type"`
+	MerchantId string    `json:"merchant_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	Data       any       `json:"data"`
+}
+
+type WebhookDelivery struct {
+	Id             string           `json:"id"`
+	EndpointId     string           `json:"endpoint_id"`
+	EventId        string           `json:"event_id"`
+	EventType      string           `json:"event_type"`
+	Payload        json.RawMessage  `json:"payload"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	NextAttemptAt  time.Time        `json:"next_attempt_at"`
+	LastStatusCode int              `json:"last_status_code,omitempty"`
+	LastError      string           `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time       `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Log            []WebhookAttempt `json:"log,omitempty"`
+}
+
+// WebhookAttempt adalah satu percobaan pengiriman pada delivery log
+type WebhookAttempt struct {
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int       `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
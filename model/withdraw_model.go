@@ -2,9 +2,27 @@ package model
 
 import "time"
 
+const (
+	WithdrawStatusRequested  = "requested"
+	WithdrawStatusProcessing = "processing"
+	WithdrawStatusPaid       = "paid"
+	WithdrawStatusFailed     = "failed"
+	WithdrawStatusCancelled  = "cancelled"
+)
+
 type Withdraw struct {
-	Id         string    `json:"id"`
-	UserId     string    `json:"user_id"`
-	Withdraw   int       `json:"withdraw"`
-	Created_at time.Time `json:"created_at"`
+	Id          string    `json:"id"`
+	UserId      string    `json:"user_id"`
+	Withdraw    int       `json:"withdraw"`
+	Biaya       int       `json:"biaya"`
+	FeeRuleId   string    `json:"-"`
+	RekeningId  string    `json:"rekening_id,omitempty"`
+	BankCode    string    `json:"bank_code"`
+	Rekening    string    `json:"rekening"`
+	Status      string    `json:"status"`
+	HoldId      string    `json:"hold_id,omitempty"`
+	PayoutRef   string    `json:"payout_ref,omitempty"`
+	AlasanGagal string    `json:"alasan_gagal,omitempty"`
+	Created_at  time.Time `json:"created_at"`
+	Updated_at  time.Time `json:"updated_at"`
 }
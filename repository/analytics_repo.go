@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type AnalyticsRepository interface {
+	GetTotals(userId string, dari, sampai time.Time) (model.AnalyticsBucket, error)
+	GetByCategory(userId string, dari, sampai time.Time) ([]model.AnalyticsBucket, error)
+	GetByCounterparty(userId string, dari, sampai time.Time, limit int) ([]model.AnalyticsBucket, error)
+	GetByPeriod(userId string, dari, sampai time.Time, periode string) ([]model.AnalyticsBucket, error)
+}
+
+type analyticsRepository struct {
+	db *sql.DB
+}
+
+// analyticsColumns mengagregasi ledger (lihat statement_repo.go) menjadi dana masuk, keluar dan jumlah transaksi
+const analyticsColumns = `COALESCE(SUM(CASE WHEN mutasi > 0 THEN mutasi ELSE 0 END), 0),
+		COALESCE(SUM(CASE WHEN mutasi < 0 THEN -mutasi ELSE 0 END), 0),
+		COUNT(*)`
+
+// analyticsWhere membatasi periode, perpindahan ke pocket bukan pemasukan atau pengeluaran
+const analyticsWhere = ` WHERE tanggal >= $2 AND tanggal < $3 AND jenis <> 'pocket'`
+
+// periodTrunc memetakan periode ke unit date_trunc PostgreSQL
+var periodTrunc = map[string]string{
+	model.AnalyticsPeriodDaily:   "day",
+	model.AnalyticsPeriodWeekly:  "week",
+	model.AnalyticsPeriodMonthly: "month",
+}
+
+func (a *analyticsRepository) GetTotals(userId string, dari, sampai time.Time) (model.AnalyticsBucket, error) {
+	var data model.AnalyticsBucket
+	err := a.db.QueryRow(ledgerUnion+` SELECT `+analyticsColumns+` FROM ledger`+analyticsWhere,
+		userId, dari, sampai).Scan(&data.Masuk, &data.Keluar, &data.JumlahTransaksi)
+	return data, err
+}
+
+// GetByCategory memakai kategori transfer, transaksi tanpa kategori dikelompokkan menurut jenisnya
+func (a *analyticsRepository) GetByCategory(userId string, dari, sampai time.Time) ([]model.AnalyticsBucket, error) {
+	return a.list(ledgerUnion+` SELECT COALESCE(NULLIF(kategori, ''), jenis) AS label, `+analyticsColumns+` FROM ledger`+analyticsWhere+`
+	GROUP BY label ORDER BY 3 DESC, 2 DESC`, userId, dari, sampai)
+}
+
+func (a *analyticsRepository) GetByCounterparty(userId string, dari, sampai time.Time, limit int) ([]model.AnalyticsBucket, error) {
+	return a.list(ledgerUnion+` SELECT pihak, `+analyticsColumns+` FROM ledger`+analyticsWhere+` AND pihak <> ''
+	GROUP BY pihak ORDER BY 3 DESC, 2 DESC LIMIT $4`, userId, dari, sampai, limit)
+}
+
+func (a *analyticsRepository) GetByPeriod(userId string, dari, sampai time.Time, periode string) ([]model.AnalyticsBucket, error) {
+	return a.list(ledgerUnion+` SELECT TO_CHAR(DATE_TRUNC($4, tanggal), 'YYYY-MM-DD') AS label, `+analyticsColumns+` FROM ledger`+analyticsWhere+`
+	GROUP BY label ORDER BY label`, userId, dari, sampai, periodTrunc[periode])
+}
+
+func (a *analyticsRepository) list(query string, args ...any) ([]model.AnalyticsBucket, error) {
+	rows, err := a.db.Query(query, args...)
+	if err != nil {
+		return []model.AnalyticsBucket{}, err
+	}
+	defer rows.Close()
+
+	datas := []model.AnalyticsBucket{}
+	for rows.Next() {
+		var data model.AnalyticsBucket
+		if err := rows.Scan(&data.Label, &data.Masuk, &data.Keluar, &data.JumlahTransaksi); err != nil {
+			return []model.AnalyticsBucket{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func NewAnalyticsRepository(db *sql.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
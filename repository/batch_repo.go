@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type BatchRepository interface {
+	Create(batch model.BatchTransfer) (model.BatchTransfer, error)
+	Get(id string) (model.BatchTransfer, error)
+	GetByUser(userId string) ([]model.BatchTransfer, error)
+	Claim(id string) (bool, error)
+	Release(id, status string) (bool, error)
+	Execute(id string) (model.BatchTransfer, error)
+}
+
+type batchRepository struct {
+	db *sql.DB
+}
+
+const batchItemColumns = `id,
+		batch_id,
+		baris,
+		tujuan,
+		COALESCE(tujuan_transfer::text, ''),
+		COALESCE(nama_penerima, ''),
+		jumlah,
+		biaya,
+		COALESCE(fee_rule_id::text, ''),
+		COALESCE(catatan, ''),
+		status,
+		COALESCE(error, ''),
+		COALESCE(transfer_id::text, '')`
+
+func scanBatchItem(row interface{ Scan(dest ...any) error }) (model.BatchItem, error) {
+	var data model.BatchItem
+	err := row.Scan(&data.Id, &data.BatchId, &data.Baris, &data.Tujuan, &data.TujuanTransfer, &data.NamaPenerima,
+		&data.Jumlah, &data.Biaya, &data.FeeRuleId, &data.Catatan, &data.Status, &data.Error, &data.TransferId)
+	return data, err
+}
+
+// Create menyimpan batch hasil validasi beserta semua barisnya, termasuk baris yang tidak valid
+func (b *batchRepository) Create(batch model.BatchTransfer) (model.BatchTransfer, error) {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = time.Now()
+	err = tx.QueryRow(`INSERT INTO trx_batch_transfer (user_id, mode, status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5)
+	RETURNING id`, batch.UserId, batch.Mode, batch.Status, batch.CreatedAt, batch.UpdatedAt).Scan(&batch.Id)
+	if err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	for i, item := range batch.Items {
+		item.BatchId = batch.Id
+		err = tx.QueryRow(`INSERT INTO trx_batch_item
+		(batch_id, baris, tujuan, tujuan_transfer, nama_penerima, jumlah, biaya, fee_rule_id, catatan, status, error)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+		RETURNING id`, item.BatchId, item.Baris, item.Tujuan, nullString(item.TujuanTransfer), nullString(item.NamaPenerima),
+			item.Jumlah, item.Biaya, nullString(item.FeeRuleId), nullString(item.Catatan), item.Status, nullString(item.Error)).Scan(&item.Id)
+		if err != nil {
+			tx.Rollback()
+			return model.BatchTransfer{}, err
+		}
+		batch.Items[i] = item
+	}
+	if err := tx.Commit(); err != nil {
+		return model.BatchTransfer{}, err
+	}
+
+	return batch, nil
+}
+
+func (b *batchRepository) Get(id string) (model.BatchTransfer, error) {
+	var batch model.BatchTransfer
+	err := b.db.QueryRow(`SELECT id, user_id, mode, status, created_at, updated_at FROM trx_batch_transfer WHERE id = $1`, id).
+		Scan(&batch.Id, &batch.UserId, &batch.Mode, &batch.Status, &batch.CreatedAt, &batch.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.BatchTransfer{}, fmt.Errorf("batch transfer %s tidak ditemukan", id)
+		}
+		return model.BatchTransfer{}, err
+	}
+
+	rows, err := b.db.Query(`SELECT `+batchItemColumns+` FROM trx_batch_item WHERE batch_id = $1 ORDER BY baris`, id)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		item, err := scanBatchItem(rows)
+		if err != nil {
+			return model.BatchTransfer{}, err
+		}
+		batch.Items = append(batch.Items, item)
+	}
+
+	return batch, nil
+}
+
+func (b *batchRepository) GetByUser(userId string) ([]model.BatchTransfer, error) {
+	rows, err := b.db.Query(`SELECT b.id, b.user_id, b.mode, b.status, b.created_at, b.updated_at,
+		COUNT(i.id),
+		COUNT(i.id) FILTER (WHERE i.status <> $2),
+		COALESCE(SUM(i.jumlah) FILTER (WHERE i.status <> $2), 0),
+		COALESCE(SUM(i.biaya) FILTER (WHERE i.status <> $2), 0)
+	FROM trx_batch_transfer AS b
+	LEFT JOIN trx_batch_item AS i ON i.batch_id = b.id
+	WHERE b.user_id = $1
+	GROUP BY b.id
+	ORDER BY b.created_at DESC`, userId, model.BatchItemInvalid)
+	if err != nil {
+		return []model.BatchTransfer{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.BatchTransfer
+	for rows.Next() {
+		var data model.BatchTransfer
+		err := rows.Scan(&data.Id, &data.UserId, &data.Mode, &data.Status, &data.CreatedAt, &data.UpdatedAt,
+			&data.JumlahItem, &data.JumlahValid, &data.TotalJumlah, &data.TotalBiaya)
+		if err != nil {
+			return []model.BatchTransfer{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Claim memindahkan batch validated ke processing agar tidak dieksekusi dua kali
+func (b *batchRepository) Claim(id string) (bool, error) {
+	res, err := b.db.Exec(`UPDATE trx_batch_transfer SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+		model.BatchStatusProcessing, time.Now(), id, model.BatchStatusValidated)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// Release mengeluarkan batch dari processing ketika eksekusi tidak jadi atau gagal di tengah jalan,
+// batch yang sudah selesai dieksekusi tidak ikut berubah
+func (b *batchRepository) Release(id, status string) (bool, error) {
+	res, err := b.db.Exec(`UPDATE trx_batch_transfer SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+		status, time.Now(), id, model.BatchStatusProcessing)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// Execute menjalankan semua baris valid dalam satu transaksi database. Tiap baris memakai savepoint:
+// mode all_or_nothing membatalkan seluruh batch saat satu baris gagal, mode best_effort hanya melewati baris itu.
+func (b *batchRepository) Execute(id string) (model.BatchTransfer, error) {
+	batch, err := b.Get(id)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	if batch.Status != model.BatchStatusProcessing {
+		return model.BatchTransfer{}, fmt.Errorf("batch transfer berstatus %s", batch.Status)
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	var saldo int
+	if err := tx.QueryRow(`SELECT saldo FROM mst_saldo WHERE user_id=$1 FOR UPDATE`, batch.UserId).Scan(&saldo); err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	held, err := heldAmount(tx, batch.UserId)
+	if err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	available := saldo - held
+	debited := 0
+
+	for i := range batch.Items {
+		item := &batch.Items[i]
+		if item.Status != model.BatchItemValid {
+			continue
+		}
+		var itemErr error
+		if item.Jumlah+item.Biaya > available-debited {
+			itemErr = fmt.Errorf("saldo tersedia tidak mencukupi")
+		} else if _, itemErr = tx.Exec(`SAVEPOINT batch_item`); itemErr == nil {
+			item.TransferId, itemErr = insertBatchTransfer(tx, batch, *item)
+			if itemErr != nil {
+				tx.Exec(`ROLLBACK TO SAVEPOINT batch_item`)
+			} else {
+				tx.Exec(`RELEASE SAVEPOINT batch_item`)
+			}
+		}
+
+		if itemErr != nil {
+			if batch.Mode == model.BatchModeAllOrNothing {
+				tx.Rollback()
+				return b.failBatch(batch, item.Id, itemErr)
+			}
+			item.Status = model.BatchItemFailed
+			item.Error = itemErr.Error()
+			item.TransferId = ""
+		} else {
+			item.Status = model.BatchItemSuccess
+			debited += item.Jumlah + item.Biaya
+		}
+		_, err = tx.Exec(`UPDATE trx_batch_item SET status=$1, error=$2, transfer_id=$3 WHERE id=$4`,
+			item.Status, nullString(item.Error), nullString(item.TransferId), item.Id)
+		if err != nil {
+			tx.Rollback()
+			return model.BatchTransfer{}, err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE mst_saldo SET saldo = saldo - $1 WHERE user_id=$2`, debited, batch.UserId); err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	batch.Status = batchOutcome(batch.Items)
+	if _, err := tx.Exec(`UPDATE trx_batch_transfer SET status=$1, updated_at=$2 WHERE id=$3`, batch.Status, time.Now(), id); err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.BatchTransfer{}, err
+	}
+
+	return b.Get(id)
+}
+
+// insertBatchTransfer mencatat satu transfer batch dan mengkredit penerima, saldo pengirim dipotong sekali di akhir batch
+func insertBatchTransfer(tx *sql.Tx, batch model.BatchTransfer, item model.BatchItem) (string, error) {
+	now := time.Now()
+	var trxId string
+	err := tx.QueryRow(`INSERT INTO trx_send_transfer
+		(user_id, tujuan_transfer, jumlah_transfer, jenis_transfer, transfer_at, biaya, catatan, referensi)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	RETURNING id`, batch.UserId, item.TujuanTransfer, item.Jumlah, "mengirim", now, item.Biaya, nullString(item.Catatan),
+		"batch:"+batch.Id).Scan(&trxId)
+	if err != nil {
+		return "", err
+	}
+	res, err := tx.Exec(`UPDATE mst_saldo SET saldo = saldo + $1 WHERE user_id=$2`, item.Jumlah, item.TujuanTransfer)
+	if err != nil {
+		return "", err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return "", fmt.Errorf("penerima belum memverifikasi akun")
+	}
+	_, err = tx.Exec(`INSERT INTO trx_receive_transfer (user_id, trx_id, tujuan_transfer, jumlah_transfer, jenis_transfer, transfer_at)
+	VALUES ($1,$2,$3,$4,$5,$6)`, batch.UserId, trxId, item.TujuanTransfer, item.Jumlah, "menerima", now)
+	if err != nil {
+		return "", err
+	}
+	if err := postFee(tx, batch.UserId, model.FeeOperationTransfer, trxId, item.FeeRuleId, item.Biaya); err != nil {
+		return "", err
+	}
+
+	return trxId, nil
+}
+
+// failBatch dipanggil setelah rollback mode all_or_nothing: baris penyebab ditandai failed, sisanya skipped
+func (b *batchRepository) failBatch(batch model.BatchTransfer, failedId string, cause error) (model.BatchTransfer, error) {
+	tx, err := b.db.Begin()
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	_, err = tx.Exec(`UPDATE trx_batch_item SET status=$1 WHERE batch_id=$2 AND status=$3`,
+		model.BatchItemSkipped, batch.Id, model.BatchItemValid)
+	if err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	_, err = tx.Exec(`UPDATE trx_batch_item SET status=$1, error=$2 WHERE id=$3`, model.BatchItemFailed, cause.Error(), failedId)
+	if err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	_, err = tx.Exec(`UPDATE trx_batch_transfer SET status=$1, updated_at=$2 WHERE id=$3`, model.BatchStatusFailed, time.Now(), batch.Id)
+	if err != nil {
+		tx.Rollback()
+		return model.BatchTransfer{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.BatchTransfer{}, err
+	}
+
+	return b.Get(batch.Id)
+}
+
+// batchOutcome menentukan status akhir batch dari hasil tiap baris
+func batchOutcome(items []model.BatchItem) string {
+	success, failed := 0, 0
+	for _, item := range items {
+		switch item.Status {
+		case model.BatchItemSuccess:
+			success++
+		case model.BatchItemFailed:
+			failed++
+		}
+	}
+	switch {
+	case failed == 0 && success > 0:
+		return model.BatchStatusCompleted
+	case success > 0:
+		return model.BatchStatusPartiallyCompleted
+	}
+	return model.BatchStatusFailed
+}
+
+func NewBatchRepository(db *sql.DB) BatchRepository {
+	return &batchRepository{db: db}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type BudgetRepository interface {
+	Create(payload model.Budget) (model.Budget, error)
+	Get(id string) (model.Budget, error)
+	GetByUser(userId string, activeOnly bool) ([]model.Budget, error)
+	Update(payload model.Budget) (model.Budget, error)
+	Delete(id string) error
+	GetSpent(userId, kategori string, dari, sampai time.Time) (int, error)
+	ClaimAlert(budgetId, periode string, ambang int) (bool, error)
+}
+
+type budgetRepository struct {
+	db *sql.DB
+}
+
+const budgetColumns = `id, user_id, kategori, jumlah, is_active, created_at, updated_at`
+
+func scanBudget(row interface{ Scan(dest ...any) error }) (model.Budget, error) {
+	var data model.Budget
+	err := row.Scan(&data.Id, &data.UserId, &data.Kategori, &data.Jumlah, &data.IsActive, &data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+func budgetError(err error, kategori string) error {
+	pgErr, ok := err.(*pq.Error)
+	if ok && pgErr.Code == "23505" {
+		if kategori == "" {
+			return fmt.Errorf("budget keseluruhan sudah ada")
+		}
+		return fmt.Errorf("budget kategori %s sudah ada", kategori)
+	}
+	return err
+}
+
+func (b *budgetRepository) Create(payload model.Budget) (model.Budget, error) {
+	data, err := scanBudget(b.db.QueryRow(`INSERT INTO mst_budget (user_id, kategori, jumlah, is_active, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6)
+	RETURNING `+budgetColumns, payload.UserId, payload.Kategori, payload.Jumlah, payload.IsActive, time.Now(), time.Now()))
+	if err != nil {
+		return model.Budget{}, budgetError(err, payload.Kategori)
+	}
+
+	return data, nil
+}
+
+func (b *budgetRepository) Get(id string) (model.Budget, error) {
+	data, err := scanBudget(b.db.QueryRow(`SELECT `+budgetColumns+` FROM mst_budget WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.Budget{}, fmt.Errorf("budget %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (b *budgetRepository) GetByUser(userId string, activeOnly bool) ([]model.Budget, error) {
+	rows, err := b.db.Query(`SELECT `+budgetColumns+` FROM mst_budget WHERE user_id = $1 AND (NOT $2 OR is_active)
+	ORDER BY kategori`, userId, activeOnly)
+	if err != nil {
+		return []model.Budget{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.Budget
+	for rows.Next() {
+		data, err := scanBudget(rows)
+		if err != nil {
+			return []model.Budget{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (b *budgetRepository) Update(payload model.Budget) (model.Budget, error) {
+	data, err := scanBudget(b.db.QueryRow(`UPDATE mst_budget SET kategori=$1, jumlah=$2, is_active=$3, updated_at=$4 WHERE id=$5
+	RETURNING `+budgetColumns, payload.Kategori, payload.Jumlah, payload.IsActive, time.Now(), payload.Id))
+	if err != nil {
+		return model.Budget{}, budgetError(err, payload.Kategori)
+	}
+
+	return data, nil
+}
+
+func (b *budgetRepository) Delete(id string) error {
+	_, err := b.db.Exec(`DELETE FROM mst_budget WHERE id = $1`, id)
+	return err
+}
+
+// GetSpent menjumlahkan pengeluaran (dana keluar selain withdraw dan pocket) dari ledger, kategori memakai label yang sama
+// dengan analitik: kategori transfer atau jenis transaksi jika tidak berkategori
+func (b *budgetRepository) GetSpent(userId, kategori string, dari, sampai time.Time) (int, error) {
+	var total int
+	err := b.db.QueryRow(ledgerUnion+` SELECT COALESCE(SUM(-mutasi), 0) FROM ledger
+	WHERE mutasi < 0 AND jenis NOT IN ('withdraw', 'pocket') AND tanggal >= $2 AND tanggal < $3
+		AND ($4 = '' OR COALESCE(NULLIF(kategori, ''), jenis) = $4)`, userId, dari, sampai, kategori).Scan(&total)
+	return total, err
+}
+
+// ClaimAlert mencatat bahwa ambang sudah diberitahukan untuk periode ini, false jika sudah pernah
+func (b *budgetRepository) ClaimAlert(budgetId, periode string, ambang int) (bool, error) {
+	res, err := b.db.Exec(`INSERT INTO trx_budget_alert (budget_id, periode, ambang, created_at) VALUES ($1,$2,$3,$4)
+	ON CONFLICT DO NOTHING`, budgetId, periode, ambang, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func NewBudgetRepository(db *sql.DB) BudgetRepository {
+	return &budgetRepository{db: db}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type CheckoutRepository interface {
+	Create(payload model.CheckoutSession) (model.CheckoutSession, error)
+	Get(id string) (model.CheckoutSession, error)
+	Pay(id, userId string) (model.CheckoutSession, model.MerchantPayment, error)
+	Cancel(id, userId string) (bool, error)
+}
+
+type checkoutRepository struct {
+	db *sql.DB
+}
+
+const checkoutColumns = `cs.id,
+		cs.merchant_id,
+		m.nama,
+		m.owner_id,
+		cs.order_id,
+		cs.jumlah,
+		COALESCE(cs.deskripsi, ''),
+		cs.success_url,
+		cs.cancel_url,
+		CASE WHEN cs.status = 'open' AND cs.expires_at <= NOW() THEN 'expired' ELSE cs.status END,
+		COALESCE(cs.user_id::text, ''),
+		COALESCE(cs.payment_id::text, ''),
+		cs.expires_at,
+		cs.created_at,
+		cs.updated_at`
+
+const checkoutFrom = ` FROM trx_checkout_session AS cs
+	JOIN mst_merchant AS m ON cs.merchant_id = m.id`
+
+func scanCheckout(row interface{ Scan(dest ...any) error }) (model.CheckoutSession, error) {
+	var data model.CheckoutSession
+	err := row.Scan(&data.Id, &data.MerchantId, &data.NamaMerchant, &data.OwnerId, &data.OrderId, &data.Jumlah, &data.Deskripsi,
+		&data.SuccessUrl, &data.CancelUrl, &data.Status, &data.UserId, &data.PaymentId, &data.ExpiresAt, &data.CreatedAt,
+		&data.UpdatedAt)
+	return data, err
+}
+
+func (c *checkoutRepository) Create(payload model.CheckoutSession) (model.CheckoutSession, error) {
+	payload.Status = model.CheckoutStatusOpen
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := c.db.QueryRow(`INSERT INTO trx_checkout_session (merchant_id, order_id, jumlah, deskripsi, success_url, cancel_url, status, expires_at, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+	RETURNING id`, payload.MerchantId, payload.OrderId, payload.Jumlah, nullString(payload.Deskripsi), payload.SuccessUrl,
+		payload.CancelUrl, payload.Status, payload.ExpiresAt, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.CheckoutSession{}, fmt.Errorf("order_id %s sudah memiliki checkout session", payload.OrderId)
+		}
+		return model.CheckoutSession{}, err
+	}
+
+	return c.Get(payload.Id)
+}
+
+func (c *checkoutRepository) Get(id string) (model.CheckoutSession, error) {
+	data, err := scanCheckout(c.db.QueryRow(`SELECT `+checkoutColumns+checkoutFrom+` WHERE cs.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.CheckoutSession{}, fmt.Errorf("checkout session %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+// Pay menutup session dan membayar merchant dalam satu transaksi lewat jalur debit wallet yang sama dengan pembayaran QR
+func (c *checkoutRepository) Pay(id, userId string) (model.CheckoutSession, model.MerchantPayment, error) {
+	tx, err := c.db.Begin()
+	if err != nil {
+		return model.CheckoutSession{}, model.MerchantPayment{}, err
+	}
+	defer tx.Rollback()
+
+	payment := model.MerchantPayment{UserId: userId}
+	err = tx.QueryRow(`UPDATE trx_checkout_session SET status=$1, user_id=$2, updated_at=$3
+	WHERE id=$4 AND status=$5 AND expires_at > NOW()
+	RETURNING merchant_id, jumlah, order_id, COALESCE(deskripsi, '')`, model.CheckoutStatusCompleted, userId, time.Now(), id,
+		model.CheckoutStatusOpen).Scan(&payment.MerchantId, &payment.Jumlah, &payment.Referensi, &payment.Catatan)
+	if err == sql.ErrNoRows {
+		return model.CheckoutSession{}, model.MerchantPayment{}, fmt.Errorf("checkout session sudah dibayar, dibatalkan atau kedaluwarsa")
+	}
+	if err != nil {
+		return model.CheckoutSession{}, model.MerchantPayment{}, err
+	}
+	if err := debitWallet(tx, userId, payment.Jumlah); err != nil {
+		return model.CheckoutSession{}, model.MerchantPayment{}, err
+	}
+	payment, err = insertMerchantPayment(tx, payment)
+	if err != nil {
+		return model.CheckoutSession{}, model.MerchantPayment{}, err
+	}
+	_, err = tx.Exec(`UPDATE trx_checkout_session SET payment_id=$1 WHERE id=$2`, payment.Id, id)
+	if err != nil {
+		return model.CheckoutSession{}, model.MerchantPayment{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.CheckoutSession{}, model.MerchantPayment{}, err
+	}
+
+	session, err := c.Get(id)
+	return session, payment, err
+}
+
+func (c *checkoutRepository) Cancel(id, userId string) (bool, error) {
+	res, err := c.db.Exec(`UPDATE trx_checkout_session SET status=$1, user_id=$2, updated_at=$3
+	WHERE id=$4 AND status=$5 AND expires_at > NOW()`, model.CheckoutStatusCancelled, userId, time.Now(), id, model.CheckoutStatusOpen)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func NewCheckoutRepository(db *sql.DB) CheckoutRepository {
+	return &checkoutRepository{db: db}
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type DisputeRepository interface {
+	Create(payload model.Dispute) (model.Dispute, error)
+	Get(id string) (model.Dispute, error)
+	GetAll(userId string, page int) ([]model.Dispute, error)
+	GetByStatus(status string, page int) ([]model.Dispute, error)
+	Update(payload model.Dispute) (model.Dispute, error)
+	FindTransaction(trxType, trxId, userId string) (model.Dispute, error)
+	AddComment(payload model.DisputeComment) (model.DisputeComment, error)
+	GetComments(disputeId string) ([]model.DisputeComment, error)
+}
+
+type disputeRepository struct {
+	db *sql.DB
+}
+
+const disputeColumns = `id,
+		user_id,
+		trx_type,
+		trx_id,
+		COALESCE(counterparty_id::text, ''),
+		jumlah,
+		deskripsi,
+		status,
+		COALESCE(hold_id::text, ''),
+		COALESCE(reversal_id::text, ''),
+		created_at,
+		updated_at`
+
+func scanDispute(row interface{ Scan(dest ...any) error }) (model.Dispute, error) {
+	var data model.Dispute
+	err := row.Scan(&data.Id, &data.UserId, &data.TrxType, &data.TrxId, &data.CounterpartyId, &data.Jumlah,
+		&data.Deskripsi, &data.Status, &data.HoldId, &data.ReversalId, &data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+func (d *disputeRepository) Create(payload model.Dispute) (model.Dispute, error) {
+	payload.Status = model.DisputeStatusOpen
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	var counterparty any
+	if payload.CounterpartyId != "" {
+		counterparty = payload.CounterpartyId
+	}
+	err := d.db.QueryRow(`INSERT INTO trx_dispute (user_id,trx_type,trx_id,counterparty_id,jumlah,deskripsi,status,created_at,updated_at)
+	VALUES
+		($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	RETURNING id`, payload.UserId, payload.TrxType, payload.TrxId, counterparty, payload.Jumlah, payload.Deskripsi,
+		payload.Status, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" && pgErr.Constraint == "trx_dispute_active_key" {
+			return model.Dispute{}, fmt.Errorf("transaksi ini sudah memiliki dispute yang masih berjalan")
+		}
+		return model.Dispute{}, err
+	}
+
+	return payload, nil
+}
+
+func (d *disputeRepository) Get(id string) (model.Dispute, error) {
+	data, err := scanDispute(d.db.QueryRow(`SELECT `+disputeColumns+` FROM trx_dispute WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Dispute{}, fmt.Errorf("dispute %s tidak ditemukan", id)
+		}
+		return model.Dispute{}, err
+	}
+
+	return data, nil
+}
+
+func (d *disputeRepository) GetAll(userId string, page int) ([]model.Dispute, error) {
+	paging := 3
+	limit := (paging * page) - paging
+
+	return d.list(`SELECT `+disputeColumns+` FROM trx_dispute WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`, userId, paging, limit)
+}
+
+func (d *disputeRepository) GetByStatus(status string, page int) ([]model.Dispute, error) {
+	paging := 3
+	limit := (paging * page) - paging
+
+	if status == "" {
+		return d.list(`SELECT `+disputeColumns+` FROM trx_dispute ORDER BY created_at DESC LIMIT $1 OFFSET $2`, paging, limit)
+	}
+	return d.list(`SELECT `+disputeColumns+` FROM trx_dispute WHERE status = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`, status, paging, limit)
+}
+
+func (d *disputeRepository) list(query string, args ...any) ([]model.Dispute, error) {
+	var datas []model.Dispute
+	res, err := d.db.Query(query, args...)
+	if err != nil {
+		return []model.Dispute{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		data, err := scanDispute(res)
+		if err != nil {
+			return []model.Dispute{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (d *disputeRepository) Update(payload model.Dispute) (model.Dispute, error) {
+	var holdId, reversalId any
+	if payload.HoldId != "" {
+		holdId = payload.HoldId
+	}
+	if payload.ReversalId != "" {
+		reversalId = payload.ReversalId
+	}
+	_, err := d.db.Exec(`UPDATE trx_dispute SET status=$1, hold_id=$2, reversal_id=$3, updated_at=$4 WHERE id=$5`,
+		payload.Status, holdId, reversalId, time.Now(), payload.Id)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+
+	return d.Get(payload.Id)
+}
+
+// FindTransaction mencari transaksi milik user yang akan di-dispute beserta lawan transaksinya
+func (d *disputeRepository) FindTransaction(trxType, trxId, userId string) (model.Dispute, error) {
+	data := model.Dispute{TrxType: trxType, TrxId: trxId, UserId: userId}
+	var err error
+	switch trxType {
+	case model.DisputeTrxTypeTransfer:
+		err = d.db.QueryRow(`SELECT tujuan_transfer, jumlah_transfer FROM trx_send_transfer WHERE id=$1 AND user_id=$2`, trxId, userId).Scan(
+			&data.CounterpartyId, &data.Jumlah)
+	case model.DisputeTrxTypeTopup:
+		err = d.db.QueryRow(`SELECT ammount FROM trx_topup_method_payment WHERE id=$1 AND user_id=$2`, trxId, userId).Scan(&data.Jumlah)
+	default:
+		return model.Dispute{}, fmt.Errorf("trx_type harus %s atau %s", model.DisputeTrxTypeTransfer, model.DisputeTrxTypeTopup)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Dispute{}, fmt.Errorf("transaksi %s tidak ditemukan di riwayat anda", trxId)
+		}
+		return model.Dispute{}, err
+	}
+
+	return data, nil
+}
+
+func (d *disputeRepository) AddComment(payload model.DisputeComment) (model.DisputeComment, error) {
+	payload.CreatedAt = time.Now()
+	err := d.db.QueryRow(`INSERT INTO trx_dispute_comment (dispute_id,author_id,author_role,komentar,created_at)
+	VALUES
+		($1,$2,$3,$4,$5)
+	RETURNING id`, payload.DisputeId, payload.AuthorId, payload.AuthorRole, payload.Komentar, payload.CreatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.DisputeComment{}, err
+	}
+
+	return payload, nil
+}
+
+func (d *disputeRepository) GetComments(disputeId string) ([]model.DisputeComment, error) {
+	var datas []model.DisputeComment
+	res, err := d.db.Query(`SELECT 
+		id,
+		dispute_id,
+		author_id,
+		author_role,
+		komentar,
+		created_at
+	FROM 
+		trx_dispute_comment
+	WHERE 
+		dispute_id = $1
+	ORDER BY 
+		created_at ASC`, disputeId)
+	if err != nil {
+		return []model.DisputeComment{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var data model.DisputeComment
+		err := res.Scan(&data.Id, &data.DisputeId, &data.AuthorId, &data.AuthorRole, &data.Komentar, &data.CreatedAt)
+		if err != nil {
+			return []model.DisputeComment{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func NewDisputeRepository(db *sql.DB) DisputeRepository {
+	return &disputeRepository{db: db}
+}
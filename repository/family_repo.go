@@ -0,0 +1,399 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type FamilyRepository interface {
+	Create(payload model.Family) (model.Family, error)
+	GetByOwner(ownerId string) (model.Family, error)
+	Get(id string) (model.Family, error)
+	GetMembers(familyId string) ([]model.FamilyMember, error)
+	GetMember(userId string) (model.FamilyMember, error)
+	AddMember(payload model.FamilyMember) (model.FamilyMember, error)
+	UpdateMember(payload model.FamilyMember) (model.FamilyMember, error)
+	ActivateMember(userId string) (bool, error)
+	RemoveMember(familyId, userId string) error
+	GetSpentSince(userId string, since time.Time) (int, error)
+	Reserve(payload model.FamilyReservation, since time.Time) (model.FamilyReservation, error)
+	Release(id string) error
+	CreateApproval(payload model.FamilyApproval) (model.FamilyApproval, error)
+	GetApproval(id string) (model.FamilyApproval, error)
+	GetApprovals(familyId, status string) ([]model.FamilyApproval, error)
+	FindApproval(userId, jenis, tujuan string, jumlah int, status string) (model.FamilyApproval, error)
+	DecideApproval(id, status string) (bool, error)
+	CreateActivity(payload model.FamilyActivity) error
+	GetActivity(familyId string, page int) ([]model.FamilyActivity, error)
+}
+
+type familyRepository struct {
+	db *sql.DB
+}
+
+const familyColumns = `f.id, f.owner_id, u.name, f.nama, f.created_at, f.updated_at`
+
+func scanFamily(row interface{ Scan(dest ...any) error }) (model.Family, error) {
+	var data model.Family
+	err := row.Scan(&data.Id, &data.OwnerId, &data.NamaOwner, &data.Nama, &data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+func (f *familyRepository) Create(payload model.Family) (model.Family, error) {
+	err := f.db.QueryRow(`INSERT INTO mst_family (owner_id, nama, created_at, updated_at) VALUES ($1,$2,$3,$4)
+	RETURNING id, created_at, updated_at`, payload.OwnerId, payload.Nama, time.Now(), time.Now()).
+		Scan(&payload.Id, &payload.CreatedAt, &payload.UpdatedAt)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.Family{}, fmt.Errorf("anda sudah memiliki keluarga")
+		}
+		return model.Family{}, err
+	}
+
+	return payload, nil
+}
+
+func (f *familyRepository) GetByOwner(ownerId string) (model.Family, error) {
+	data, err := scanFamily(f.db.QueryRow(`SELECT `+familyColumns+` FROM mst_family AS f
+	JOIN mst_user AS u ON f.owner_id = u.id WHERE f.owner_id = $1`, ownerId))
+	if err == sql.ErrNoRows {
+		return model.Family{}, fmt.Errorf("anda belum memiliki keluarga")
+	}
+	return data, err
+}
+
+func (f *familyRepository) Get(id string) (model.Family, error) {
+	data, err := scanFamily(f.db.QueryRow(`SELECT `+familyColumns+` FROM mst_family AS f
+	JOIN mst_user AS u ON f.owner_id = u.id WHERE f.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.Family{}, fmt.Errorf("keluarga %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+const familyMemberColumns = `m.id, m.family_id, m.user_id, u.name, m.limit_harian, m.jenis_diizinkan, m.ambang_persetujuan,
+		m.status, m.created_at, m.updated_at`
+
+func scanFamilyMember(row interface{ Scan(dest ...any) error }) (model.FamilyMember, error) {
+	var data model.FamilyMember
+	err := row.Scan(&data.Id, &data.FamilyId, &data.UserId, &data.Nama, &data.LimitHarian, pq.Array(&data.JenisDiizinkan),
+		&data.AmbangPersetujuan, &data.Status, &data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+func (f *familyRepository) GetMembers(familyId string) ([]model.FamilyMember, error) {
+	rows, err := f.db.Query(`SELECT `+familyMemberColumns+` FROM mst_family_member AS m
+	JOIN mst_user AS u ON m.user_id = u.id WHERE m.family_id = $1 ORDER BY m.created_at`, familyId)
+	if err != nil {
+		return []model.FamilyMember{}, err
+	}
+	defer rows.Close()
+
+	datas := []model.FamilyMember{}
+	for rows.Next() {
+		data, err := scanFamilyMember(rows)
+		if err != nil {
+			return []model.FamilyMember{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// GetMember mengembalikan data kosong tanpa error jika user bukan anggota keluarga manapun
+func (f *familyRepository) GetMember(userId string) (model.FamilyMember, error) {
+	data, err := scanFamilyMember(f.db.QueryRow(`SELECT `+familyMemberColumns+` FROM mst_family_member AS m
+	JOIN mst_user AS u ON m.user_id = u.id WHERE m.user_id = $1`, userId))
+	if err == sql.ErrNoRows {
+		return model.FamilyMember{}, nil
+	}
+	return data, err
+}
+
+// AddMember menyimpan anggota sebagai undangan pending, lihat ActivateMember
+func (f *familyRepository) AddMember(payload model.FamilyMember) (model.FamilyMember, error) {
+	payload.Status = model.FamilyMemberPending
+	err := f.db.QueryRow(`INSERT INTO mst_family_member (family_id, user_id, limit_harian, jenis_diizinkan, ambang_persetujuan,
+		status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	RETURNING id, created_at, updated_at`, payload.FamilyId, payload.UserId, payload.LimitHarian, pq.Array(payload.JenisDiizinkan),
+		payload.AmbangPersetujuan, payload.Status, time.Now(), time.Now()).Scan(&payload.Id, &payload.CreatedAt, &payload.UpdatedAt)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.FamilyMember{}, fmt.Errorf("user sudah menjadi atau diundang sebagai anggota keluarga")
+		}
+		return model.FamilyMember{}, err
+	}
+
+	return payload, nil
+}
+
+func (f *familyRepository) UpdateMember(payload model.FamilyMember) (model.FamilyMember, error) {
+	payload.UpdatedAt = time.Now()
+	_, err := f.db.Exec(`UPDATE mst_family_member SET limit_harian=$1, jenis_diizinkan=$2, ambang_persetujuan=$3, updated_at=$4
+	WHERE id=$5`, payload.LimitHarian, pq.Array(payload.JenisDiizinkan), payload.AmbangPersetujuan, payload.UpdatedAt, payload.Id)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+
+	return payload, nil
+}
+
+// ActivateMember menerima undangan yang masih pending
+func (f *familyRepository) ActivateMember(userId string) (bool, error) {
+	res, err := f.db.Exec(`UPDATE mst_family_member SET status=$1, updated_at=$2 WHERE user_id=$3 AND status=$4`,
+		model.FamilyMemberActive, time.Now(), userId, model.FamilyMemberPending)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func (f *familyRepository) RemoveMember(familyId, userId string) error {
+	res, err := f.db.Exec(`DELETE FROM mst_family_member WHERE family_id=$1 AND user_id=$2`, familyId, userId)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("user bukan anggota keluarga anda")
+	}
+
+	return nil
+}
+
+// reservedSince menjumlahkan reservasi anggota yang belum dilepas sejak waktu tertentu
+func reservedSince(q queryRower, userId string, since time.Time) (int, error) {
+	var total int
+	err := q.QueryRow(`SELECT COALESCE(SUM(jumlah), 0) FROM trx_family_reservation
+	WHERE user_id=$1 AND status=$2 AND created_at >= $3`, userId, model.FamilyReservationActive, since).Scan(&total)
+	return total, err
+}
+
+func (f *familyRepository) GetSpentSince(userId string, since time.Time) (int, error) {
+	return reservedSince(f.db, userId, since)
+}
+
+// Reserve memakai persetujuan (jika ada), memeriksa limit harian dan mencatat reservasi dalam satu transaksi.
+// Baris anggota dikunci agar pengeluaran bersamaan dari anggota yang sama diperiksa bergantian.
+func (f *familyRepository) Reserve(payload model.FamilyReservation, since time.Time) (model.FamilyReservation, error) {
+	tx, err := f.db.Begin()
+	if err != nil {
+		return model.FamilyReservation{}, err
+	}
+
+	var limitHarian int
+	err = tx.QueryRow(`SELECT limit_harian FROM mst_family_member WHERE user_id=$1 AND status=$2 FOR UPDATE`,
+		payload.UserId, model.FamilyMemberActive).Scan(&limitHarian)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return model.FamilyReservation{}, fmt.Errorf("keanggotaan keluarga berubah, silahkan ulangi transaksi")
+		}
+		return model.FamilyReservation{}, err
+	}
+
+	now := time.Now()
+	if payload.ApprovalId != "" {
+		res, err := tx.Exec(`UPDATE trx_family_approval SET status=$1, updated_at=$2
+		WHERE id=$3 AND user_id=$4 AND status=$5 AND expires_at > $2`,
+			model.FamilyApprovalUsed, now, payload.ApprovalId, payload.UserId, model.FamilyApprovalApproved)
+		if err != nil {
+			tx.Rollback()
+			return model.FamilyReservation{}, err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			tx.Rollback()
+			return model.FamilyReservation{}, fmt.Errorf("persetujuan %s sudah dipakai atau kedaluwarsa", payload.ApprovalId)
+		}
+	}
+
+	if limitHarian > 0 {
+		spent, err := reservedSince(tx, payload.UserId, since)
+		if err != nil {
+			tx.Rollback()
+			return model.FamilyReservation{}, err
+		}
+		if spent+payload.Jumlah > limitHarian {
+			tx.Rollback()
+			return model.FamilyReservation{}, fmt.Errorf("melebihi limit harian keluarga, sisa limit hari ini %d", max(limitHarian-spent, 0))
+		}
+	}
+
+	payload.Status = model.FamilyReservationActive
+	payload.CreatedAt = now
+	payload.UpdatedAt = now
+	err = tx.QueryRow(`INSERT INTO trx_family_reservation (family_id, user_id, jenis, jumlah, approval_id, status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	RETURNING id`, payload.FamilyId, payload.UserId, payload.Jenis, payload.Jumlah, nullString(payload.ApprovalId),
+		payload.Status, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		tx.Rollback()
+		return model.FamilyReservation{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.FamilyReservation{}, err
+	}
+
+	return payload, nil
+}
+
+// Release melepas reservasi transaksi yang gagal dan mengembalikan persetujuannya agar bisa dipakai lagi.
+// Reservasi yang sudah dilepas diabaikan.
+func (f *familyRepository) Release(id string) error {
+	tx, err := f.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	var approvalId string
+	err = tx.QueryRow(`UPDATE trx_family_reservation SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4
+	RETURNING COALESCE(approval_id::text, '')`, model.FamilyReservationReleased, time.Now(), id, model.FamilyReservationActive).
+		Scan(&approvalId)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+	if approvalId != "" {
+		_, err = tx.Exec(`UPDATE trx_family_approval SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+			model.FamilyApprovalApproved, time.Now(), approvalId, model.FamilyApprovalUsed)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+const familyApprovalColumns = `a.id, a.family_id, a.user_id, u.name, a.jenis, a.tujuan, COALESCE(a.keterangan, ''), a.jumlah,
+		a.status, a.expires_at, a.decided_at, a.created_at, a.updated_at`
+
+const familyApprovalFrom = ` FROM trx_family_approval AS a JOIN mst_user AS u ON a.user_id = u.id`
+
+func scanFamilyApproval(row interface{ Scan(dest ...any) error }) (model.FamilyApproval, error) {
+	var data model.FamilyApproval
+	var decidedAt sql.NullTime
+	err := row.Scan(&data.Id, &data.FamilyId, &data.UserId, &data.Nama, &data.Jenis, &data.Tujuan, &data.Keterangan,
+		&data.Jumlah, &data.Status, &data.ExpiresAt, &decidedAt, &data.CreatedAt, &data.UpdatedAt)
+	if decidedAt.Valid {
+		data.DecidedAt = &decidedAt.Time
+	}
+	return data, err
+}
+
+func (f *familyRepository) CreateApproval(payload model.FamilyApproval) (model.FamilyApproval, error) {
+	payload.Status = model.FamilyApprovalPending
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := f.db.QueryRow(`INSERT INTO trx_family_approval (family_id, user_id, jenis, tujuan, keterangan, jumlah, status,
+		expires_at, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+	RETURNING id`, payload.FamilyId, payload.UserId, payload.Jenis, payload.Tujuan, nullString(payload.Keterangan), payload.Jumlah,
+		payload.Status, payload.ExpiresAt, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.FamilyApproval{}, err
+	}
+
+	return payload, nil
+}
+
+func (f *familyRepository) GetApproval(id string) (model.FamilyApproval, error) {
+	data, err := scanFamilyApproval(f.db.QueryRow(`SELECT `+familyApprovalColumns+familyApprovalFrom+` WHERE a.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.FamilyApproval{}, fmt.Errorf("permintaan persetujuan %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (f *familyRepository) GetApprovals(familyId, status string) ([]model.FamilyApproval, error) {
+	rows, err := f.db.Query(`SELECT `+familyApprovalColumns+familyApprovalFrom+`
+	WHERE a.family_id = $1 AND ($2 = '' OR a.status = $2)
+	ORDER BY a.created_at DESC`, familyId, status)
+	if err != nil {
+		return []model.FamilyApproval{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.FamilyApproval
+	for rows.Next() {
+		data, err := scanFamilyApproval(rows)
+		if err != nil {
+			return []model.FamilyApproval{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// FindApproval mencari permintaan yang sama dengan status tertentu dan belum kedaluwarsa, data kosong jika tidak ada
+func (f *familyRepository) FindApproval(userId, jenis, tujuan string, jumlah int, status string) (model.FamilyApproval, error) {
+	data, err := scanFamilyApproval(f.db.QueryRow(`SELECT `+familyApprovalColumns+familyApprovalFrom+`
+	WHERE a.user_id = $1 AND a.jenis = $2 AND a.tujuan = $3 AND a.jumlah = $4 AND a.status = $5 AND a.expires_at > NOW()
+	ORDER BY a.created_at DESC LIMIT 1`, userId, jenis, tujuan, jumlah, status))
+	if err == sql.ErrNoRows {
+		return model.FamilyApproval{}, nil
+	}
+	return data, err
+}
+
+// DecideApproval menyetujui atau menolak permintaan yang masih pending dan belum kedaluwarsa
+func (f *familyRepository) DecideApproval(id, status string) (bool, error) {
+	res, err := f.db.Exec(`UPDATE trx_family_approval SET status=$1, decided_at=$2, updated_at=$2
+	WHERE id=$3 AND status=$4 AND expires_at > $2`, status, time.Now(), id, model.FamilyApprovalPending)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func (f *familyRepository) CreateActivity(payload model.FamilyActivity) error {
+	_, err := f.db.Exec(`INSERT INTO trx_family_activity (family_id, user_id, jenis, jumlah, keterangan, referensi, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)`, payload.FamilyId, payload.UserId, payload.Jenis, payload.Jumlah,
+		nullString(payload.Keterangan), nullString(payload.Referensi), time.Now())
+	return err
+}
+
+func (f *familyRepository) GetActivity(familyId string, page int) ([]model.FamilyActivity, error) {
+	paging := 10
+	offset := paging*page - paging
+	rows, err := f.db.Query(`SELECT a.id, a.family_id, a.user_id, u.name, a.jenis, a.jumlah, COALESCE(a.keterangan, ''),
+		COALESCE(a.referensi, ''), a.created_at
+	FROM trx_family_activity AS a JOIN mst_user AS u ON a.user_id = u.id
+	WHERE a.family_id = $1
+	ORDER BY a.created_at DESC LIMIT $2 OFFSET $3`, familyId, paging, offset)
+	if err != nil {
+		return []model.FamilyActivity{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.FamilyActivity
+	for rows.Next() {
+		var data model.FamilyActivity
+		if err := rows.Scan(&data.Id, &data.FamilyId, &data.UserId, &data.Nama, &data.Jenis, &data.Jumlah, &data.Keterangan,
+			&data.Referensi, &data.CreatedAt); err != nil {
+			return []model.FamilyActivity{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func NewFamilyRepository(db *sql.DB) FamilyRepository {
+	return &familyRepository{db: db}
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type FamilyRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    FamilyRepository
+}
+
+func (suite *FamilyRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDB = db
+	suite.mockSql = mock
+	suite.repo = NewFamilyRepository(suite.mockDB)
+}
+
+func TestFamilyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(FamilyRepositoryTestSuite))
+}
+
+func (suite *FamilyRepositoryTestSuite) TestReserve_Success() {
+	since := time.Now().Truncate(24 * time.Hour)
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT limit_harian FROM mst_family_member WHERE user_id=\\$1 AND status=\\$2 FOR UPDATE").
+		WithArgs("anak", model.FamilyMemberActive).WillReturnRows(sqlmock.NewRows([]string{"limit_harian"}).AddRow(100000))
+	suite.mockSql.ExpectQuery("SELECT COALESCE\\(SUM\\(jumlah\\), 0\\) FROM trx_family_reservation").
+		WithArgs("anak", model.FamilyReservationActive, since).WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(60000))
+	suite.mockSql.ExpectQuery("INSERT INTO trx_family_reservation").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rsv-1"))
+	suite.mockSql.ExpectCommit()
+
+	result, err := suite.repo.Reserve(model.FamilyReservation{FamilyId: "f-1", UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 40000}, since)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "rsv-1", result.Id)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *FamilyRepositoryTestSuite) TestReserve_ApprovedOverLimit() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT limit_harian FROM mst_family_member").WillReturnRows(sqlmock.NewRows([]string{"limit_harian"}).AddRow(100000))
+	suite.mockSql.ExpectExec("UPDATE trx_family_approval SET status").
+		WithArgs(model.FamilyApprovalUsed, sqlmock.AnyArg(), "ap-1", "anak", model.FamilyApprovalApproved).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectQuery("SELECT COALESCE\\(SUM\\(jumlah\\), 0\\) FROM trx_family_reservation").
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(20000))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Reserve(model.FamilyReservation{UserId: "anak", Jumlah: 150000, ApprovalId: "ap-1"}, time.Now())
+	assert.EqualError(suite.T(), err, "melebihi limit harian keluarga, sisa limit hari ini 80000")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *FamilyRepositoryTestSuite) TestReserve_ApprovalAlreadyUsed() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT limit_harian FROM mst_family_member").WillReturnRows(sqlmock.NewRows([]string{"limit_harian"}).AddRow(0))
+	suite.mockSql.ExpectExec("UPDATE trx_family_approval SET status").WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Reserve(model.FamilyReservation{UserId: "anak", Jumlah: 150000, ApprovalId: "ap-1"}, time.Now())
+	assert.EqualError(suite.T(), err, "persetujuan ap-1 sudah dipakai atau kedaluwarsa")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *FamilyRepositoryTestSuite) TestRelease_RestoresApproval() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("UPDATE trx_family_reservation SET status").
+		WithArgs(model.FamilyReservationReleased, sqlmock.AnyArg(), "rsv-1", model.FamilyReservationActive).
+		WillReturnRows(sqlmock.NewRows([]string{"approval_id"}).AddRow("ap-1"))
+	suite.mockSql.ExpectExec("UPDATE trx_family_approval SET status").
+		WithArgs(model.FamilyApprovalApproved, sqlmock.AnyArg(), "ap-1", model.FamilyApprovalUsed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectCommit()
+
+	err := suite.repo.Release("rsv-1")
+	assert.Nil(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *FamilyRepositoryTestSuite) TestRelease_AlreadyReleased() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("UPDATE trx_family_reservation SET status").WillReturnError(sql.ErrNoRows)
+	suite.mockSql.ExpectRollback()
+
+	err := suite.repo.Release("rsv-1")
+	assert.Nil(suite.T(), err)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type FeeRepository interface {
+	CreateRule(payload model.FeeRule) (model.FeeRule, error)
+	UpdateRule(payload model.FeeRule) (model.FeeRule, error)
+	GetRule(id string) (model.FeeRule, error)
+	GetRules() ([]model.FeeRule, error)
+	FindActiveRule(operation, channel string) (model.FeeRule, error)
+	CountThisMonth(userId, operation string) (int, error)
+	GetFees(userId string, page int) ([]model.FeeLine, error)
+	GetHouseWallet() (model.HouseWallet, error)
+}
+
+type feeRepository struct {
+	db *sql.DB
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// postFee mencatat biaya sebagai line item terpisah dan memasukkannya ke house wallet, dipanggil di dalam transaksi pemanggil
+func postFee(tx execer, userId, operation, referenceId, ruleId string, jumlah int) error {
+	if jumlah <= 0 {
+		return nil
+	}
+	var rule any
+	if ruleId != "" {
+		rule = ruleId
+	}
+	_, err := tx.Exec(`INSERT INTO trx_fee (user_id,operation,reference_id,rule_id,jumlah,created_at)
+	VALUES ($1,$2,$3,$4,$5,$6)`, userId, operation, referenceId, rule, jumlah, time.Now())
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE mst_house_wallet SET saldo = saldo + $1, updated_at = $2 WHERE nama = $3`, jumlah, time.Now(), model.HouseWalletRevenue)
+
+	return err
+}
+
+const feeRuleColumns = `id,
+		operation,
+		COALESCE(channel, ''),
+		fee_type,
+		nilai,
+		tiers,
+		min_fee,
+		max_fee,
+		free_quota,
+		is_active,
+		created_at,
+		updated_at`
+
+func scanFeeRule(row interface{ Scan(dest ...any) error }) (model.FeeRule, error) {
+	var data model.FeeRule
+	var tiers []byte
+	err := row.Scan(&data.Id, &data.Operation, &data.Channel, &data.FeeType, &data.Nilai, &tiers, &data.MinFee,
+		&data.MaxFee, &data.FreeQuota, &data.IsActive, &data.CreatedAt, &data.UpdatedAt)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+	if len(tiers) > 0 {
+		if err := json.Unmarshal(tiers, &data.Tiers); err != nil {
+			return model.FeeRule{}, err
+		}
+	}
+	return data, nil
+}
+
+func feeRuleArgs(payload model.FeeRule) (channel any, tiers any, err error) {
+	if payload.Channel != "" {
+		channel = payload.Channel
+	}
+	if len(payload.Tiers) > 0 {
+		b, err := json.Marshal(payload.Tiers)
+		if err != nil {
+			return nil, nil, err
+		}
+		tiers = string(b)
+	}
+	return channel, tiers, nil
+}
+
+func feeRuleError(err error) error {
+	pgErr, ok := err.(*pq.Error)
+	if ok && pgErr.Code == "23505" && pgErr.Constraint == "mst_fee_rule_active_key" {
+		return fmt.Errorf("sudah ada rule aktif untuk operation dan channel ini")
+	}
+	return err
+}
+
+func (f *feeRepository) CreateRule(payload model.FeeRule) (model.FeeRule, error) {
+	channel, tiers, err := feeRuleArgs(payload)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+	data, err := scanFeeRule(f.db.QueryRow(`INSERT INTO mst_fee_rule 
+		(operation,channel,fee_type,nilai,tiers,min_fee,max_fee,free_quota,is_active,created_at,updated_at)
+	VALUES
+		($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	RETURNING `+feeRuleColumns, payload.Operation, channel, payload.FeeType, payload.Nilai, tiers, payload.MinFee,
+		payload.MaxFee, payload.FreeQuota, payload.IsActive, time.Now(), time.Now()))
+	if err != nil {
+		return model.FeeRule{}, feeRuleError(err)
+	}
+
+	return data, nil
+}
+
+func (f *feeRepository) UpdateRule(payload model.FeeRule) (model.FeeRule, error) {
+	channel, tiers, err := feeRuleArgs(payload)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+	data, err := scanFeeRule(f.db.QueryRow(`UPDATE mst_fee_rule SET 
+		operation=$1,
+		channel=$2,
+		fee_type=$3,
+		nilai=$4,
+		tiers=$5,
+		min_fee=$6,
+		max_fee=$7,
+		free_quota=$8,
+		is_active=$9,
+		updated_at=$10
+	WHERE id=$11
+	RETURNING `+feeRuleColumns, payload.Operation, channel, payload.FeeType, payload.Nilai, tiers, payload.MinFee,
+		payload.MaxFee, payload.FreeQuota, payload.IsActive, time.Now(), payload.Id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.FeeRule{}, fmt.Errorf("fee rule %s tidak ditemukan", payload.Id)
+		}
+		return model.FeeRule{}, feeRuleError(err)
+	}
+
+	return data, nil
+}
+
+func (f *feeRepository) GetRule(id string) (model.FeeRule, error) {
+	data, err := scanFeeRule(f.db.QueryRow(`SELECT `+feeRuleColumns+` FROM mst_fee_rule WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.FeeRule{}, fmt.Errorf("fee rule %s tidak ditemukan", id)
+		}
+		return model.FeeRule{}, err
+	}
+
+	return data, nil
+}
+
+func (f *feeRepository) GetRules() ([]model.FeeRule, error) {
+	var datas []model.FeeRule
+	res, err := f.db.Query(`SELECT ` + feeRuleColumns + ` FROM mst_fee_rule ORDER BY operation, channel, created_at DESC`)
+	if err != nil {
+		return []model.FeeRule{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		data, err := scanFeeRule(res)
+		if err != nil {
+			return []model.FeeRule{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// FindActiveRule mendahulukan rule khusus channel, lalu rule umum operation. Tanpa rule berarti gratis
+func (f *feeRepository) FindActiveRule(operation, channel string) (model.FeeRule, error) {
+	data, err := scanFeeRule(f.db.QueryRow(`SELECT `+feeRuleColumns+` FROM mst_fee_rule 
+	WHERE is_active AND operation = $1 AND (channel = $2 OR channel IS NULL)
+	ORDER BY channel IS NULL
+	LIMIT 1`, operation, channel))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.FeeRule{}, nil
+		}
+		return model.FeeRule{}, err
+	}
+
+	return data, nil
+}
+
+// CountThisMonth menghitung jumlah operasi user sejak awal bulan, dipakai untuk kuota gratis
+func (f *feeRepository) CountThisMonth(userId, operation string) (int, error) {
+	var query string
+	switch operation {
+	case model.FeeOperationTransfer:
+		query = `SELECT COUNT(*) FROM trx_send_transfer 
+		WHERE user_id = $1 AND jenis_transfer = 'mengirim' AND transfer_at::timestamptz >= date_trunc('month', NOW())`
+	case model.FeeOperationWithdraw:
+		query = `SELECT COUNT(*) FROM withdraw_saldo 
+		WHERE user_id = $1 AND status NOT IN ('failed', 'cancelled') AND created_at >= date_trunc('month', NOW())`
+	case model.FeeOperationTopup:
+		query = `SELECT COUNT(*) FROM trx_topup_method_payment 
+		WHERE user_id = $1 AND status = 'Pembayaran berhasil' AND created_at >= date_trunc('month', NOW())`
+	case model.FeeOperationMerchant:
+		query = `SELECT COUNT(*) FROM trx_settlement_item AS si
+		JOIN trx_settlement AS s ON si.settlement_id = s.id
+		JOIN mst_merchant AS m ON s.merchant_id = m.id
+		WHERE m.owner_id = $1 AND s.created_at >= date_trunc('month', NOW())`
+	default:
+		return 0, fmt.Errorf("operation %s tidak dikenal", operation)
+	}
+
+	var count int
+	if err := f.db.QueryRow(query, userId).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (f *feeRepository) GetFees(userId string, page int) ([]model.FeeLine, error) {
+	var datas []model.FeeLine
+	paging := 3
+	limit := (paging * page) - paging
+
+	res, err := f.db.Query(`SELECT 
+		id,
+		user_id,
+		operation,
+		reference_id,
+		COALESCE(rule_id::text, ''),
+		jumlah,
+		created_at
+	FROM 
+		trx_fee
+	WHERE 
+		user_id = $1
+	ORDER BY 
+		created_at DESC
+	LIMIT $2 OFFSET $3`, userId, paging, limit)
+	if err != nil {
+		return []model.FeeLine{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var data model.FeeLine
+		err := res.Scan(&data.Id, &data.UserId, &data.Operation, &data.ReferenceId, &data.RuleId, &data.Jumlah, &data.CreatedAt)
+		if err != nil {
+			return []model.FeeLine{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (f *feeRepository) GetHouseWallet() (model.HouseWallet, error) {
+	var data model.HouseWallet
+	var updatedAt sql.NullTime
+	err := f.db.QueryRow(`SELECT nama, saldo, updated_at FROM mst_house_wallet WHERE nama = $1`, model.HouseWalletRevenue).Scan(
+		&data.Nama, &data.Saldo, &updatedAt)
+	if err != nil {
+		return model.HouseWallet{}, err
+	}
+	data.UpdatedAt = updatedAt.Time
+
+	return data, nil
+}
+
+func NewFeeRepository(db *sql.DB) FeeRepository {
+	return &feeRepository{db: db}
+}
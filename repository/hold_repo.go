@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type HoldRepository interface {
+	Authorize(payload model.Hold) (model.Hold, error)
+	Get(id string) (model.Hold, error)
+	GetActive(userId string) ([]model.Hold, error)
+	Capture(id string, jumlah int, penerimaJenis, penerimaId string) (model.Hold, error)
+	Void(id string) (model.Hold, error)
+	ExpireDue() (int, error)
+}
+
+type holdRepository struct {
+	db *sql.DB
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// heldAmount menghitung total saldo yang sedang ditahan untuk user
+func heldAmount(q queryRower, userId string) (int, error) {
+	var held int
+	err := q.QueryRow(`SELECT COALESCE(SUM(jumlah), 0) FROM trx_fund_hold 
+	WHERE user_id=$1 AND status=$2 AND (expires_at IS NULL OR expires_at > NOW())`, userId, model.HoldStatusActive).Scan(&held)
+	if err != nil {
+		return 0, err
+	}
+
+	return held, nil
+}
+
+const holdColumns = `id,
+		user_id,
+		jumlah,
+		jumlah_captured,
+		COALESCE(alasan, ''),
+		COALESCE(reference_id, ''),
+		sumber,
+		COALESCE(penerima_jenis, ''),
+		COALESCE(penerima_id, ''),
+		status,
+		expires_at,
+		created_at,
+		updated_at`
+
+func scanHold(row interface{ Scan(dest ...any) error }) (model.Hold, error) {
+	var data model.Hold
+	var expiresAt sql.NullTime
+	err := row.Scan(&data.Id, &data.UserId, &data.Jumlah, &data.JumlahCaptured, &data.Alasan, &data.ReferenceId,
+		&data.Sumber, &data.PenerimaJenis, &data.PenerimaId, &data.Status, &expiresAt, &data.CreatedAt, &data.UpdatedAt)
+	if expiresAt.Valid {
+		data.ExpiresAt = &expiresAt.Time
+	}
+	return data, err
+}
+
+func insertHold(q queryRower, payload model.Hold) (model.Hold, error) {
+	payload.Status = model.HoldStatusActive
+	if payload.Sumber == "" {
+		payload.Sumber = model.HoldSourceUser
+	}
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := q.QueryRow(`INSERT INTO trx_fund_hold (user_id,jumlah,alasan,reference_id,sumber,penerima_jenis,penerima_id,status,expires_at,created_at,updated_at)
+	VALUES
+		($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)
+	RETURNING id`, payload.UserId, payload.Jumlah, payload.Alasan, payload.ReferenceId, payload.Sumber, nullString(payload.PenerimaJenis),
+		nullString(payload.PenerimaId), payload.Status, payload.ExpiresAt, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	return payload, nil
+}
+
+// Authorize menahan dana hanya jika saldo tersedia mencukupi, hold merchant hanya untuk merchant aktif
+func (h *holdRepository) Authorize(payload model.Hold) (model.Hold, error) {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	if payload.Sumber == model.HoldSourceMerchant {
+		var status string
+		err = tx.QueryRow(`SELECT status FROM mst_merchant WHERE id::text = $1`, payload.PenerimaId).Scan(&status)
+		if err != nil && err != sql.ErrNoRows {
+			tx.Rollback()
+			return model.Hold{}, err
+		}
+		if status != model.MerchantStatusActive {
+			tx.Rollback()
+			return model.Hold{}, fmt.Errorf("merchant %s tidak ditemukan atau tidak aktif", payload.PenerimaId)
+		}
+	}
+
+	var saldo int
+	err = tx.QueryRow(`SELECT saldo FROM mst_saldo WHERE user_id=$1 FOR UPDATE`, payload.UserId).Scan(&saldo)
+	if err != nil {
+		tx.Rollback()
+		return model.Hold{}, err
+	}
+	held, err := heldAmount(tx, payload.UserId)
+	if err != nil {
+		tx.Rollback()
+		return model.Hold{}, err
+	}
+	if saldo-held < payload.Jumlah {
+		tx.Rollback()
+		return model.Hold{}, fmt.Errorf("saldo tersedia tidak mencukupi untuk menahan %d", payload.Jumlah)
+	}
+
+	payload, err = insertHold(tx, payload)
+	if err != nil {
+		tx.Rollback()
+		return model.Hold{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Hold{}, err
+	}
+
+	return payload, nil
+}
+
+func (h *holdRepository) Get(id string) (model.Hold, error) {
+	data, err := scanHold(h.db.QueryRow(`SELECT `+holdColumns+` FROM trx_fund_hold WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Hold{}, fmt.Errorf("hold %s tidak ditemukan", id)
+		}
+		return model.Hold{}, err
+	}
+
+	return data, nil
+}
+
+func (h *holdRepository) GetActive(userId string) ([]model.Hold, error) {
+	var datas []model.Hold
+	res, err := h.db.Query(`SELECT `+holdColumns+` FROM trx_fund_hold 
+	WHERE user_id = $1 AND status = $2 
+	ORDER BY created_at DESC`, userId, model.HoldStatusActive)
+	if err != nil {
+		return []model.Hold{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		data, err := scanHold(res)
+		if err != nil {
+			return []model.Hold{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Capture memindahkan dana yang di-capture ke penerima di transaksi yang sama, sisa hold otomatis dilepas
+func (h *holdRepository) Capture(id string, jumlah int, penerimaJenis, penerimaId string) (model.Hold, error) {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return model.Hold{}, err
+	}
+
+	hold, err := scanHold(tx.QueryRow(`SELECT `+holdColumns+` FROM trx_fund_hold WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return model.Hold{}, fmt.Errorf("hold %s tidak ditemukan", id)
+		}
+		return model.Hold{}, err
+	}
+	if hold.Status != model.HoldStatusActive || (hold.ExpiresAt != nil && hold.ExpiresAt.Before(time.Now())) {
+		tx.Rollback()
+		return model.Hold{}, fmt.Errorf("hold %s sudah tidak aktif", id)
+	}
+	if hold.Sumber == model.HoldSourceMerchant && (penerimaJenis != model.HoldPayeeMerchant || penerimaId != hold.PenerimaId) {
+		tx.Rollback()
+		return model.Hold{}, fmt.Errorf("hold %s hanya dapat di-capture ke merchant %s", id, hold.PenerimaId)
+	}
+	if hold.Sumber != model.HoldSourceUser && hold.Sumber != model.HoldSourceMerchant {
+		tx.Rollback()
+		return model.Hold{}, fmt.Errorf("hold %s dikelola oleh proses %s", id, hold.Sumber)
+	}
+	if jumlah == 0 {
+		jumlah = hold.Jumlah
+	}
+	if jumlah < 0 || jumlah > hold.Jumlah {
+		tx.Rollback()
+		return model.Hold{}, fmt.Errorf("jumlah capture harus antara 1 dan %d", hold.Jumlah)
+	}
+	if penerimaJenis == model.HoldPayeeHouse && penerimaId == "" {
+		penerimaId = model.HouseWalletRevenue
+	}
+
+	// hold ditutup lebih dulu agar debit hanya dibatasi oleh hold lain yang masih aktif
+	_, err = tx.Exec(`UPDATE trx_fund_hold SET status=$1, jumlah_captured=$2, penerima_jenis=$3, penerima_id=$4, updated_at=$5 WHERE id=$6`,
+		model.HoldStatusCaptured, jumlah, penerimaJenis, penerimaId, time.Now(), id)
+	if err != nil {
+		tx.Rollback()
+		return model.Hold{}, err
+	}
+	if err := debitWallet(tx, hold.UserId, jumlah); err != nil {
+		tx.Rollback()
+		return model.Hold{}, err
+	}
+	if err := creditHoldPayee(tx, hold, jumlah, penerimaJenis, penerimaId); err != nil {
+		tx.Rollback()
+		return model.Hold{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Hold{}, err
+	}
+
+	return h.Get(id)
+}
+
+// creditHoldPayee mengkredit penerima capture, pembayaran ke merchant dicatat sebagai pembayaran merchant
+func creditHoldPayee(tx *sql.Tx, hold model.Hold, jumlah int, penerimaJenis, penerimaId string) error {
+	switch penerimaJenis {
+	case model.HoldPayeeMerchant:
+		_, err := insertMerchantPayment(tx, model.MerchantPayment{
+			MerchantId: penerimaId,
+			UserId:     hold.UserId,
+			Jumlah:     jumlah,
+			Catatan:    hold.Alasan,
+			Referensi:  "hold:" + hold.Id,
+		})
+		return err
+	case model.HoldPayeeUser:
+		if penerimaId == hold.UserId {
+			return fmt.Errorf("penerima capture tidak boleh pemilik hold")
+		}
+		return creditWallet(tx, penerimaId, jumlah)
+	case model.HoldPayeeHouse:
+		res, err := tx.Exec(`UPDATE mst_house_wallet SET saldo = saldo + $1, updated_at = $2 WHERE nama = $3`, jumlah, time.Now(), penerimaId)
+		if err != nil {
+			return err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return fmt.Errorf("house wallet %s tidak ditemukan", penerimaId)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("penerima capture %s tidak dikenal", penerimaJenis)
+}
+
+func (h *holdRepository) Void(id string) (model.Hold, error) {
+	res, err := h.db.Exec(`UPDATE trx_fund_hold SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+		model.HoldStatusVoided, time.Now(), id, model.HoldStatusActive)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.Hold{}, fmt.Errorf("hold %s sudah tidak aktif", id)
+	}
+
+	return h.Get(id)
+}
+
+func (h *holdRepository) ExpireDue() (int, error) {
+	res, err := h.db.Exec(`UPDATE trx_fund_hold SET status=$1, updated_at=$2 
+	WHERE status=$3 AND expires_at IS NOT NULL AND expires_at <= NOW()`, model.HoldStatusExpired, time.Now(), model.HoldStatusActive)
+	if err != nil {
+		return 0, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return int(affected), nil
+}
+
+func NewHoldRepository(db *sql.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type HoldRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    HoldRepository
+}
+
+func (suite *HoldRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDB = db
+	suite.mockSql = mock
+	suite.repo = NewHoldRepository(suite.mockDB)
+}
+
+func TestHoldRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(HoldRepositoryTestSuite))
+}
+
+func holdRows(hold model.Hold) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "user_id", "jumlah", "jumlah_captured", "alasan", "reference_id", "sumber",
+		"penerima_jenis", "penerima_id", "status", "expires_at", "created_at", "updated_at"}).
+		AddRow(hold.Id, hold.UserId, hold.Jumlah, hold.JumlahCaptured, hold.Alasan, hold.ReferenceId, hold.Sumber,
+			hold.PenerimaJenis, hold.PenerimaId, hold.Status, hold.ExpiresAt, time.Now(), time.Now())
+}
+
+func (suite *HoldRepositoryTestSuite) TestAuthorize_OverAvailableBalance() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT saldo FROM mst_saldo").WillReturnRows(sqlmock.NewRows([]string{"saldo"}).AddRow(100000))
+	suite.mockSql.ExpectQuery("SELECT COALESCE\\(SUM\\(jumlah\\), 0\\) FROM trx_fund_hold").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(80000))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Authorize(model.Hold{UserId: "u-1", Jumlah: 30000})
+	assert.EqualError(suite.T(), err, "saldo tersedia tidak mencukupi untuk menahan 30000")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *HoldRepositoryTestSuite) TestCapture_PartialToUser() {
+	hold := model.Hold{Id: "h-1", UserId: "u-1", Jumlah: 50000, Sumber: model.HoldSourceUser, Status: model.HoldStatusActive}
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT (.+) FROM trx_fund_hold WHERE id = \\$1 FOR UPDATE").WithArgs("h-1").WillReturnRows(holdRows(hold))
+	suite.mockSql.ExpectExec("UPDATE trx_fund_hold SET status").
+		WithArgs(model.HoldStatusCaptured, 20000, model.HoldPayeeUser, "u-2", sqlmock.AnyArg(), "h-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectQuery("SELECT saldo FROM mst_saldo").WithArgs("u-1").WillReturnRows(sqlmock.NewRows([]string{"saldo"}).AddRow(60000))
+	suite.mockSql.ExpectQuery("SELECT COALESCE\\(SUM\\(jumlah\\), 0\\) FROM trx_fund_hold").WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(0))
+	suite.mockSql.ExpectExec("UPDATE mst_saldo SET saldo = saldo - \\$1").WithArgs(20000, "u-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectExec("UPDATE mst_saldo SET saldo = saldo \\+ \\$1").WithArgs(20000, "u-2").WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mockSql.ExpectCommit()
+	hold.Status = model.HoldStatusCaptured
+	hold.JumlahCaptured = 20000
+	hold.PenerimaJenis = model.HoldPayeeUser
+	hold.PenerimaId = "u-2"
+	suite.mockSql.ExpectQuery("SELECT (.+) FROM trx_fund_hold WHERE id = \\$1").WithArgs("h-1").WillReturnRows(holdRows(hold))
+
+	result, err := suite.repo.Capture("h-1", 20000, model.HoldPayeeUser, "u-2")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 20000, result.JumlahCaptured)
+	assert.Equal(suite.T(), "u-2", result.PenerimaId)
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *HoldRepositoryTestSuite) TestCapture_ExpiredHold() {
+	expiredAt := time.Now().Add(-time.Minute)
+	hold := model.Hold{Id: "h-1", UserId: "u-1", Jumlah: 50000, Sumber: model.HoldSourceUser, Status: model.HoldStatusActive, ExpiresAt: &expiredAt}
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT (.+) FROM trx_fund_hold WHERE id = \\$1 FOR UPDATE").WillReturnRows(holdRows(hold))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Capture("h-1", 0, model.HoldPayeeHouse, "")
+	assert.EqualError(suite.T(), err, "hold h-1 sudah tidak aktif")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *HoldRepositoryTestSuite) TestCapture_WithdrawHold() {
+	hold := model.Hold{Id: "h-1", UserId: "u-1", Jumlah: 50000, Sumber: model.HoldSourceWithdraw, Status: model.HoldStatusActive}
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT (.+) FROM trx_fund_hold WHERE id = \\$1 FOR UPDATE").WillReturnRows(holdRows(hold))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Capture("h-1", 0, model.HoldPayeeHouse, "")
+	assert.EqualError(suite.T(), err, "hold h-1 dikelola oleh proses withdraw")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *HoldRepositoryTestSuite) TestCapture_MerchantHoldOtherPayee() {
+	hold := model.Hold{Id: "h-1", UserId: "u-1", Jumlah: 50000, Sumber: model.HoldSourceMerchant, PenerimaJenis: model.HoldPayeeMerchant,
+		PenerimaId: "m-1", Status: model.HoldStatusActive}
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT (.+) FROM trx_fund_hold WHERE id = \\$1 FOR UPDATE").WillReturnRows(holdRows(hold))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Capture("h-1", 0, model.HoldPayeeMerchant, "m-2")
+	assert.EqualError(suite.T(), err, "hold h-1 hanya dapat di-capture ke merchant m-1")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
+
+func (suite *HoldRepositoryTestSuite) TestVoid_NotActive() {
+	suite.mockSql.ExpectExec("UPDATE trx_fund_hold SET status").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := suite.repo.Void("h-1")
+	assert.EqualError(suite.T(), err, "hold h-1 sudah tidak aktif")
+}
+
+func (suite *HoldRepositoryTestSuite) TestExpireDue_Success() {
+	suite.mockSql.ExpectExec("UPDATE trx_fund_hold SET status").
+		WithArgs(model.HoldStatusExpired, sqlmock.AnyArg(), model.HoldStatusActive).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	total, err := suite.repo.ExpireDue()
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 3, total)
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type MerchantKeyRepository interface {
+	Create(payload model.MerchantApiKey) (model.MerchantApiKey, error)
+	Get(id string) (model.MerchantApiKey, error)
+	GetByKeyId(keyId string) (model.MerchantApiKey, error)
+	GetByMerchant(merchantId string) ([]model.MerchantApiKey, error)
+	Rotate(id string, payload model.MerchantApiKey) (model.MerchantApiKey, error)
+	Revoke(id string) error
+	Touch(id string) error
+	UseNonce(keyId, nonce string, retention time.Duration) (bool, error)
+}
+
+type merchantKeyRepository struct {
+	db *sql.DB
+}
+
+const merchantKeyColumns = `k.id,
+		k.merchant_id,
+		m.owner_id,
+		k.key_id,
+		k.secret_enc,
+		k.scopes,
+		k.status,
+		k.last_used_at,
+		k.created_at,
+		k.revoked_at`
+
+const merchantKeyFrom = ` FROM mst_merchant_api_key AS k
+	JOIN mst_merchant AS m ON k.merchant_id = m.id`
+
+func scanMerchantKey(row interface{ Scan(dest ...any) error }) (model.MerchantApiKey, error) {
+	var data model.MerchantApiKey
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&data.Id, &data.MerchantId, &data.OwnerId, &data.KeyId, &data.SecretEnc, pq.Array(&data.Scopes),
+		&data.Status, &lastUsedAt, &data.CreatedAt, &revokedAt)
+	if lastUsedAt.Valid {
+		data.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		data.RevokedAt = &revokedAt.Time
+	}
+	return data, err
+}
+
+func insertMerchantKey(q queryRower, payload model.MerchantApiKey) (model.MerchantApiKey, error) {
+	payload.Status = model.ApiKeyStatusActive
+	payload.CreatedAt = time.Now()
+	err := q.QueryRow(`INSERT INTO mst_merchant_api_key (merchant_id, key_id, secret_enc, scopes, status, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6)
+	RETURNING id`, payload.MerchantId, payload.KeyId, payload.SecretEnc, pq.Array(payload.Scopes), payload.Status,
+		payload.CreatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+
+	return payload, nil
+}
+
+func (m *merchantKeyRepository) Create(payload model.MerchantApiKey) (model.MerchantApiKey, error) {
+	return insertMerchantKey(m.db, payload)
+}
+
+func (m *merchantKeyRepository) Get(id string) (model.MerchantApiKey, error) {
+	data, err := scanMerchantKey(m.db.QueryRow(`SELECT `+merchantKeyColumns+merchantKeyFrom+` WHERE k.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.MerchantApiKey{}, fmt.Errorf("API key %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (m *merchantKeyRepository) GetByKeyId(keyId string) (model.MerchantApiKey, error) {
+	data, err := scanMerchantKey(m.db.QueryRow(`SELECT `+merchantKeyColumns+merchantKeyFrom+` WHERE k.key_id = $1`, keyId))
+	if err == sql.ErrNoRows {
+		return model.MerchantApiKey{}, fmt.Errorf("API key tidak dikenal")
+	}
+	return data, err
+}
+
+func (m *merchantKeyRepository) GetByMerchant(merchantId string) ([]model.MerchantApiKey, error) {
+	var datas []model.MerchantApiKey
+	rows, err := m.db.Query(`SELECT `+merchantKeyColumns+merchantKeyFrom+` WHERE k.merchant_id = $1 ORDER BY k.created_at DESC`, merchantId)
+	if err != nil {
+		return []model.MerchantApiKey{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanMerchantKey(rows)
+		if err != nil {
+			return []model.MerchantApiKey{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Rotate mencabut key lama dan membuat key baru dengan scope yang sama dalam satu transaksi
+func (m *merchantKeyRepository) Rotate(id string, payload model.MerchantApiKey) (model.MerchantApiKey, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE mst_merchant_api_key SET status=$1, revoked_at=$2 WHERE id=$3 AND status=$4`,
+		model.ApiKeyStatusRevoked, time.Now(), id, model.ApiKeyStatusActive)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.MerchantApiKey{}, fmt.Errorf("API key sudah dicabut")
+	}
+	payload, err = insertMerchantKey(tx, payload)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.MerchantApiKey{}, err
+	}
+
+	return payload, nil
+}
+
+func (m *merchantKeyRepository) Revoke(id string) error {
+	_, err := m.db.Exec(`UPDATE mst_merchant_api_key SET status=$1, revoked_at=$2 WHERE id=$3 AND status=$4`,
+		model.ApiKeyStatusRevoked, time.Now(), id, model.ApiKeyStatusActive)
+	return err
+}
+
+func (m *merchantKeyRepository) Touch(id string) error {
+	_, err := m.db.Exec(`UPDATE mst_merchant_api_key SET last_used_at=$1 WHERE id=$2`, time.Now(), id)
+	return err
+}
+
+// UseNonce mencatat nonce, false jika nonce sudah pernah dipakai dalam masa retensi (replay)
+func (m *merchantKeyRepository) UseNonce(keyId, nonce string, retention time.Duration) (bool, error) {
+	_, err := m.db.Exec(`DELETE FROM trx_api_nonce WHERE key_id=$1 AND created_at < $2`, keyId, time.Now().Add(-retention))
+	if err != nil {
+		return false, err
+	}
+	res, err := m.db.Exec(`INSERT INTO trx_api_nonce (key_id, nonce, created_at) VALUES ($1,$2,$3)
+	ON CONFLICT DO NOTHING`, keyId, nonce, time.Now())
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func NewMerchantKeyRepository(db *sql.DB) MerchantKeyRepository {
+	return &merchantKeyRepository{db: db}
+}
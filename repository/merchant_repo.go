@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type MerchantRepository interface {
+	Create(payload model.Merchant) (model.Merchant, error)
+	Get(id string) (model.Merchant, error)
+	GetByOwner(ownerId string) ([]model.Merchant, error)
+	Update(payload model.Merchant) (model.Merchant, error)
+	CreateQR(payload model.MerchantQR) (model.MerchantQR, error)
+	GetQR(id string) (model.MerchantQR, error)
+	Pay(payload model.MerchantPayment) (model.MerchantPayment, error)
+	GetPayment(id string) (model.MerchantPayment, error)
+	GetPayments(merchantId string, page int) ([]model.MerchantPayment, error)
+}
+
+type merchantRepository struct {
+	db *sql.DB
+}
+
+const merchantColumns = `id,
+		owner_id,
+		nama,
+		kategori,
+		kota,
+		COALESCE(alamat, ''),
+		bank_code,
+		rekening,
+		nama_pemilik,
+		saldo,
+		jadwal_settlement,
+		minimum_settlement,
+		status,
+		created_at,
+		updated_at`
+
+func scanMerchant(row interface{ Scan(dest ...any) error }) (model.Merchant, error) {
+	var data model.Merchant
+	err := row.Scan(&data.Id, &data.OwnerId, &data.Nama, &data.Kategori, &data.Kota, &data.Alamat, &data.BankCode,
+		&data.Rekening, &data.NamaPemilik, &data.Saldo, &data.JadwalSettlement, &data.MinimumSettlement, &data.Status,
+		&data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+const merchantPaymentColumns = `mp.id,
+		mp.merchant_id,
+		m.nama,
+		mp.user_id,
+		u.name,
+		COALESCE(mp.qr_id::text, ''),
+		mp.jumlah,
+		mp.jumlah_refund,
+		COALESCE(mp.catatan, ''),
+		COALESCE(mp.referensi, ''),
+		mp.status,
+		mp.created_at`
+
+const merchantPaymentFrom = ` FROM trx_merchant_payment AS mp
+	JOIN mst_merchant AS m ON mp.merchant_id = m.id
+	LEFT JOIN mst_user AS u ON mp.user_id = u.id`
+
+func scanMerchantPayment(row interface{ Scan(dest ...any) error }) (model.MerchantPayment, error) {
+	var data model.MerchantPayment
+	err := row.Scan(&data.Id, &data.MerchantId, &data.NamaMerchant, &data.UserId, &data.NamaPembayar, &data.QrId,
+		&data.Jumlah, &data.JumlahRefund, &data.Catatan, &data.Referensi, &data.Status, &data.CreatedAt)
+	return data, err
+}
+
+func (m *merchantRepository) Create(payload model.Merchant) (model.Merchant, error) {
+	payload.Status = model.MerchantStatusActive
+	payload.JadwalSettlement = model.SettlementScheduleT1
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := m.db.QueryRow(`INSERT INTO mst_merchant (owner_id, nama, kategori, kota, alamat, bank_code, rekening, nama_pemilik, jadwal_settlement, status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+	RETURNING id`, payload.OwnerId, payload.Nama, payload.Kategori, payload.Kota, nullString(payload.Alamat), payload.BankCode,
+		payload.Rekening, payload.NamaPemilik, payload.JadwalSettlement, payload.Status, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	return payload, nil
+}
+
+func (m *merchantRepository) Get(id string) (model.Merchant, error) {
+	data, err := scanMerchant(m.db.QueryRow(`SELECT `+merchantColumns+` FROM mst_merchant WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.Merchant{}, fmt.Errorf("merchant %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (m *merchantRepository) GetByOwner(ownerId string) ([]model.Merchant, error) {
+	var datas []model.Merchant
+	rows, err := m.db.Query(`SELECT `+merchantColumns+` FROM mst_merchant WHERE owner_id = $1 ORDER BY created_at DESC`, ownerId)
+	if err != nil {
+		return []model.Merchant{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanMerchant(rows)
+		if err != nil {
+			return []model.Merchant{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (m *merchantRepository) Update(payload model.Merchant) (model.Merchant, error) {
+	_, err := m.db.Exec(`UPDATE mst_merchant SET nama=$1, kategori=$2, kota=$3, alamat=$4, bank_code=$5, rekening=$6,
+	nama_pemilik=$7, jadwal_settlement=$8, minimum_settlement=$9, status=$10, updated_at=$11 WHERE id=$12`, payload.Nama,
+		payload.Kategori, payload.Kota, nullString(payload.Alamat), payload.BankCode, payload.Rekening, payload.NamaPemilik,
+		payload.JadwalSettlement, payload.MinimumSettlement, payload.Status, time.Now(), payload.Id)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+
+	return m.Get(payload.Id)
+}
+
+func (m *merchantRepository) CreateQR(payload model.MerchantQR) (model.MerchantQR, error) {
+	payload.Status = model.MerchantQRActive
+	payload.CreatedAt = time.Now()
+	err := m.db.QueryRow(`INSERT INTO trx_merchant_qr (merchant_id, jumlah, referensi, status, expires_at, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6)
+	RETURNING id`, payload.MerchantId, payload.Jumlah, nullString(payload.Referensi), payload.Status, payload.ExpiresAt,
+		payload.CreatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+
+	return payload, nil
+}
+
+func (m *merchantRepository) GetQR(id string) (model.MerchantQR, error) {
+	data := model.MerchantQR{Jenis: model.MerchantQRDynamic}
+	var expiresAt time.Time
+	err := m.db.QueryRow(`SELECT id, merchant_id, jumlah, COALESCE(referensi, ''), 
+	CASE WHEN status = $2 AND expires_at <= NOW() THEN $3 ELSE status END,
+	COALESCE(payment_id::text, ''), expires_at, created_at
+	FROM trx_merchant_qr WHERE id = $1`, id, model.MerchantQRActive, model.MerchantQRExpired).Scan(&data.Id, &data.MerchantId,
+		&data.Jumlah, &data.Referensi, &data.Status, &data.PaymentId, &expiresAt, &data.CreatedAt)
+	if err == sql.ErrNoRows {
+		return model.MerchantQR{}, fmt.Errorf("QR %s tidak ditemukan", id)
+	}
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+	data.ExpiresAt = &expiresAt
+
+	return data, nil
+}
+
+// insertMerchantPayment mengkredit saldo merchant dan mencatat pembayaran, dipanggil di dalam transaksi pemanggil
+// setelah saldo user didebit
+func insertMerchantPayment(tx *sql.Tx, payload model.MerchantPayment) (model.MerchantPayment, error) {
+	res, err := tx.Exec(`UPDATE mst_merchant SET saldo = saldo + $1, updated_at = $2 WHERE id=$3 AND status=$4`,
+		payload.Jumlah, time.Now(), payload.MerchantId, model.MerchantStatusActive)
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.MerchantPayment{}, fmt.Errorf("merchant tidak aktif")
+	}
+
+	payload.Status = model.MerchantPaymentSuccess
+	payload.CreatedAt = time.Now()
+	err = tx.QueryRow(`INSERT INTO trx_merchant_payment (merchant_id, user_id, qr_id, jumlah, catatan, referensi, status, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	RETURNING id`, payload.MerchantId, payload.UserId, nullString(payload.QrId), payload.Jumlah, nullString(payload.Catatan),
+		nullString(payload.Referensi), payload.Status, payload.CreatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+
+	return payload, nil
+}
+
+// Pay memindahkan saldo user ke saldo merchant, QR dinamis diklaim di transaksi yang sama agar hanya bisa dibayar sekali
+func (m *merchantRepository) Pay(payload model.MerchantPayment) (model.MerchantPayment, error) {
+	tx, err := m.db.Begin()
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+	defer tx.Rollback()
+
+	if payload.QrId != "" {
+		res, err := tx.Exec(`UPDATE trx_merchant_qr SET status=$1 
+		WHERE id=$2 AND merchant_id=$3 AND status=$4 AND expires_at > NOW()`, model.MerchantQRPaid, payload.QrId,
+			payload.MerchantId, model.MerchantQRActive)
+		if err != nil {
+			return model.MerchantPayment{}, err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return model.MerchantPayment{}, err
+		}
+		if affected == 0 {
+			return model.MerchantPayment{}, fmt.Errorf("QR sudah dibayar atau kedaluwarsa")
+		}
+	}
+
+	if err := debitWallet(tx, payload.UserId, payload.Jumlah); err != nil {
+		return model.MerchantPayment{}, err
+	}
+	payload, err = insertMerchantPayment(tx, payload)
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+	if payload.QrId != "" {
+		_, err = tx.Exec(`UPDATE trx_merchant_qr SET payment_id=$1 WHERE id=$2`, payload.Id, payload.QrId)
+		if err != nil {
+			return model.MerchantPayment{}, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return model.MerchantPayment{}, err
+	}
+
+	return m.GetPayment(payload.Id)
+}
+
+func (m *merchantRepository) GetPayment(id string) (model.MerchantPayment, error) {
+	data, err := scanMerchantPayment(m.db.QueryRow(`SELECT `+merchantPaymentColumns+merchantPaymentFrom+` WHERE mp.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.MerchantPayment{}, fmt.Errorf("pembayaran %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (m *merchantRepository) GetPayments(merchantId string, page int) ([]model.MerchantPayment, error) {
+	var datas []model.MerchantPayment
+	paging := 10
+	offset := (paging * page) - paging
+
+	rows, err := m.db.Query(`SELECT `+merchantPaymentColumns+merchantPaymentFrom+` WHERE mp.merchant_id = $1
+	ORDER BY mp.created_at DESC LIMIT $2 OFFSET $3`, merchantId, paging, offset)
+	if err != nil {
+		return []model.MerchantPayment{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanMerchantPayment(rows)
+		if err != nil {
+			return []model.MerchantPayment{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func NewMerchantRepository(db *sql.DB) MerchantRepository {
+	return &merchantRepository{db: db}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type NotificationRepository interface {
+	Create(payload model.Notification) (model.Notification, error)
+	GetByUser(userId string, page int) ([]model.Notification, error)
+	MarkRead(id, userId string) error
+}
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func (n *notificationRepository) Create(payload model.Notification) (model.Notification, error) {
+	payload.CreatedAt = time.Now()
+	err := n.db.QueryRow(`INSERT INTO mst_notification (user_id, jenis, judul, pesan, reference_id, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6)
+	RETURNING id`, payload.UserId, payload.Jenis, payload.Judul, payload.Pesan, nullString(payload.ReferenceId),
+		payload.CreatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.Notification{}, err
+	}
+
+	return payload, nil
+}
+
+func (n *notificationRepository) GetByUser(userId string, page int) ([]model.Notification, error) {
+	paging := 10
+	offset := (paging * page) - paging
+	rows, err := n.db.Query(`SELECT id, user_id, jenis, judul, pesan, COALESCE(reference_id, ''), is_read, created_at
+	FROM mst_notification
+	WHERE user_id = $1
+	ORDER BY created_at DESC
+	LIMIT $2 OFFSET $3`, userId, paging, offset)
+	if err != nil {
+		return []model.Notification{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.Notification
+	for rows.Next() {
+		var data model.Notification
+		err := rows.Scan(&data.Id, &data.UserId, &data.Jenis, &data.Judul, &data.Pesan, &data.ReferenceId, &data.IsRead, &data.CreatedAt)
+		if err != nil {
+			return []model.Notification{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (n *notificationRepository) MarkRead(id, userId string) error {
+	res, err := n.db.Exec(`UPDATE mst_notification SET is_read = TRUE WHERE id = $1 AND user_id = $2`, id, userId)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("notifikasi %s tidak ditemukan", id)
+	}
+
+	return nil
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type PaymentRequestRepository interface {
+	Create(payload model.PaymentRequest) (model.PaymentRequest, error)
+	Get(id string) (model.PaymentRequest, error)
+	GetIncoming(payerId, status string) ([]model.PaymentRequest, error)
+	GetOutgoing(requesterId, status string) ([]model.PaymentRequest, error)
+	UpdateStatus(id, from, to, transferId string) (bool, error)
+	ExpireDue() (int, error)
+	CreateSplit(split model.SplitBill, requests []model.PaymentRequest) (model.SplitBill, error)
+	GetSplit(id string) (model.SplitBill, error)
+	GetSplitsByOrganiser(userId string) ([]model.SplitBill, error)
+	SettleSplit(id string) (bool, error)
+	CancelSplit(id string) error
+}
+
+type paymentRequestRepository struct {
+	db *sql.DB
+}
+
+const paymentRequestColumns = `pr.id,
+		pr.requester_id,
+		peminta.name,
+		pr.payer_id,
+		pembayar.name,
+		pr.jumlah,
+		COALESCE(pr.catatan, ''),
+		pr.status,
+		COALESCE(pr.transfer_id::text, ''),
+		COALESCE(pr.split_id::text, ''),
+		pr.expires_at,
+		pr.created_at,
+		pr.updated_at`
+
+const paymentRequestFrom = ` FROM trx_payment_request AS pr
+	LEFT JOIN mst_user AS peminta ON pr.requester_id = peminta.id
+	LEFT JOIN mst_user AS pembayar ON pr.payer_id = pembayar.id`
+
+func scanPaymentRequest(row interface{ Scan(dest ...any) error }) (model.PaymentRequest, error) {
+	var data model.PaymentRequest
+	err := row.Scan(&data.Id, &data.RequesterId, &data.NamaPeminta, &data.PayerId, &data.NamaPembayar, &data.Jumlah,
+		&data.Catatan, &data.Status, &data.TransferId, &data.SplitId, &data.ExpiresAt, &data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+func insertPaymentRequest(q queryRower, payload model.PaymentRequest) (model.PaymentRequest, error) {
+	payload.Status = model.PaymentRequestPending
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := q.QueryRow(`INSERT INTO trx_payment_request (requester_id, payer_id, jumlah, catatan, status, split_id, expires_at, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	RETURNING id`, payload.RequesterId, payload.PayerId, payload.Jumlah, nullString(payload.Catatan), payload.Status,
+		nullString(payload.SplitId), payload.ExpiresAt, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+
+	return payload, nil
+}
+
+func (p *paymentRequestRepository) Create(payload model.PaymentRequest) (model.PaymentRequest, error) {
+	return insertPaymentRequest(p.db, payload)
+}
+
+func (p *paymentRequestRepository) Get(id string) (model.PaymentRequest, error) {
+	data, err := scanPaymentRequest(p.db.QueryRow(`SELECT `+paymentRequestColumns+paymentRequestFrom+` WHERE pr.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (p *paymentRequestRepository) GetIncoming(payerId, status string) ([]model.PaymentRequest, error) {
+	return p.list(`pr.payer_id = $1 AND ($2 = '' OR pr.status = $2)`, payerId, status)
+}
+
+func (p *paymentRequestRepository) GetOutgoing(requesterId, status string) ([]model.PaymentRequest, error) {
+	return p.list(`pr.requester_id = $1 AND ($2 = '' OR pr.status = $2)`, requesterId, status)
+}
+
+func (p *paymentRequestRepository) list(where string, args ...any) ([]model.PaymentRequest, error) {
+	rows, err := p.db.Query(`SELECT `+paymentRequestColumns+paymentRequestFrom+`
+	WHERE `+where+`
+	ORDER BY pr.created_at DESC`, args...)
+	if err != nil {
+		return []model.PaymentRequest{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.PaymentRequest
+	for rows.Next() {
+		data, err := scanPaymentRequest(rows)
+		if err != nil {
+			return []model.PaymentRequest{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// UpdateStatus memindahkan status hanya jika status saat ini masih from, false jika sudah berubah
+func (p *paymentRequestRepository) UpdateStatus(id, from, to, transferId string) (bool, error) {
+	res, err := p.db.Exec(`UPDATE trx_payment_request SET status=$1, transfer_id=COALESCE($2, transfer_id), updated_at=$3
+	WHERE id=$4 AND status=$5`, to, nullString(transferId), time.Now(), id, from)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func (p *paymentRequestRepository) ExpireDue() (int, error) {
+	res, err := p.db.Exec(`UPDATE trx_payment_request SET status=$1, updated_at=NOW() WHERE status=$2 AND expires_at <= NOW()`,
+		model.PaymentRequestExpired, model.PaymentRequestPending)
+	if err != nil {
+		return 0, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return int(affected), nil
+}
+
+const splitColumns = `id, organiser_id, judul, total, mode, status, created_at, updated_at`
+
+func scanSplit(row interface{ Scan(dest ...any) error }) (model.SplitBill, error) {
+	var data model.SplitBill
+	err := row.Scan(&data.Id, &data.OrganiserId, &data.Judul, &data.Total, &data.Mode, &data.Status, &data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+// CreateSplit menyimpan split bill beserta payment request tiap peserta dalam satu transaksi
+func (p *paymentRequestRepository) CreateSplit(split model.SplitBill, requests []model.PaymentRequest) (model.SplitBill, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return model.SplitBill{}, err
+	}
+	now := time.Now()
+	split, err = scanSplit(tx.QueryRow(`INSERT INTO trx_split_bill (organiser_id, judul, total, mode, status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	RETURNING `+splitColumns, split.OrganiserId, split.Judul, split.Total, split.Mode, model.SplitStatusOpen, now, now))
+	if err != nil {
+		tx.Rollback()
+		return model.SplitBill{}, err
+	}
+	for _, request := range requests {
+		request.SplitId = split.Id
+		request, err = insertPaymentRequest(tx, request)
+		if err != nil {
+			tx.Rollback()
+			return model.SplitBill{}, err
+		}
+		split.Shares = append(split.Shares, request)
+	}
+	if err := tx.Commit(); err != nil {
+		return model.SplitBill{}, err
+	}
+
+	return split, nil
+}
+
+func (p *paymentRequestRepository) GetSplit(id string) (model.SplitBill, error) {
+	split, err := scanSplit(p.db.QueryRow(`SELECT `+splitColumns+` FROM trx_split_bill WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.SplitBill{}, fmt.Errorf("split bill %s tidak ditemukan", id)
+		}
+		return model.SplitBill{}, err
+	}
+	split.Shares, err = p.list(`pr.split_id = $1`, id)
+	if err != nil {
+		return model.SplitBill{}, err
+	}
+	for _, share := range split.Shares {
+		if share.Status == model.PaymentRequestPaid {
+			split.JumlahTerbayar += share.Jumlah
+		}
+	}
+
+	return split, nil
+}
+
+func (p *paymentRequestRepository) GetSplitsByOrganiser(userId string) ([]model.SplitBill, error) {
+	rows, err := p.db.Query(`SELECT `+splitColumns+` FROM trx_split_bill WHERE organiser_id = $1 ORDER BY created_at DESC`, userId)
+	if err != nil {
+		return []model.SplitBill{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.SplitBill
+	for rows.Next() {
+		data, err := scanSplit(rows)
+		if err != nil {
+			return []model.SplitBill{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// SettleSplit menutup split jika semua bagian sudah dibayar, false jika masih ada yang belum
+func (p *paymentRequestRepository) SettleSplit(id string) (bool, error) {
+	res, err := p.db.Exec(`UPDATE trx_split_bill SET status=$1, updated_at=$2
+	WHERE id=$3 AND status=$4
+	AND NOT EXISTS (SELECT 1 FROM trx_payment_request WHERE split_id=$3 AND status<>$5)`,
+		model.SplitStatusSettled, time.Now(), id, model.SplitStatusOpen, model.PaymentRequestPaid)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// CancelSplit membatalkan split dan semua bagian yang belum dibayar
+func (p *paymentRequestRepository) CancelSplit(id string) error {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	res, err := tx.Exec(`UPDATE trx_split_bill SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+		model.SplitStatusCancelled, time.Now(), id, model.SplitStatusOpen)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		tx.Rollback()
+		return fmt.Errorf("split bill sudah ditutup")
+	}
+	_, err = tx.Exec(`UPDATE trx_payment_request SET status=$1, updated_at=$2 WHERE split_id=$3 AND status=$4`,
+		model.PaymentRequestCancelled, time.Now(), id, model.PaymentRequestPending)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func NewPaymentRequestRepository(db *sql.DB) PaymentRequestRepository {
+	return &paymentRequestRepository{db: db}
+}
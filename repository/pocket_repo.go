@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type PocketRepository interface {
+	Create(payload model.Pocket) (model.Pocket, error)
+	Get(id string) (model.Pocket, error)
+	GetByUser(userId, status string) ([]model.Pocket, error)
+	CountActive(userId string) (int, error)
+	Update(payload model.Pocket) (model.Pocket, error)
+	Move(id, userId, arah string, jumlah int) (model.Pocket, error)
+	Close(id, userId string) (model.Pocket, error)
+	GetMoves(id string, page int) ([]model.PocketMove, error)
+}
+
+type pocketRepository struct {
+	db *sql.DB
+}
+
+const pocketColumns = `id, user_id, nama, saldo, target, target_date, status, created_at, updated_at, closed_at`
+
+func scanPocket(row interface{ Scan(dest ...any) error }) (model.Pocket, error) {
+	var data model.Pocket
+	var targetDate, closedAt sql.NullTime
+	err := row.Scan(&data.Id, &data.UserId, &data.Nama, &data.Saldo, &data.Target, &targetDate, &data.Status,
+		&data.CreatedAt, &data.UpdatedAt, &closedAt)
+	if targetDate.Valid {
+		data.TargetDate = &targetDate.Time
+	}
+	if closedAt.Valid {
+		data.ClosedAt = &closedAt.Time
+	}
+	return data, err
+}
+
+func pocketError(err error, nama string) error {
+	pgErr, ok := err.(*pq.Error)
+	if ok && pgErr.Code == "23505" {
+		return fmt.Errorf("pocket %s sudah ada", nama)
+	}
+	return err
+}
+
+func (p *pocketRepository) Create(payload model.Pocket) (model.Pocket, error) {
+	data, err := scanPocket(p.db.QueryRow(`INSERT INTO mst_pocket (user_id, nama, target, target_date, status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	RETURNING `+pocketColumns, payload.UserId, payload.Nama, payload.Target, payload.TargetDate, model.PocketStatusActive,
+		time.Now(), time.Now()))
+	if err != nil {
+		return model.Pocket{}, pocketError(err, payload.Nama)
+	}
+
+	return data, nil
+}
+
+func (p *pocketRepository) Get(id string) (model.Pocket, error) {
+	data, err := scanPocket(p.db.QueryRow(`SELECT `+pocketColumns+` FROM mst_pocket WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.Pocket{}, fmt.Errorf("pocket %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (p *pocketRepository) GetByUser(userId, status string) ([]model.Pocket, error) {
+	rows, err := p.db.Query(`SELECT `+pocketColumns+` FROM mst_pocket WHERE user_id = $1 AND ($2 = '' OR status = $2)
+	ORDER BY created_at`, userId, status)
+	if err != nil {
+		return []model.Pocket{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.Pocket
+	for rows.Next() {
+		data, err := scanPocket(rows)
+		if err != nil {
+			return []model.Pocket{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (p *pocketRepository) CountActive(userId string) (int, error) {
+	var total int
+	err := p.db.QueryRow(`SELECT COUNT(*) FROM mst_pocket WHERE user_id = $1 AND status = $2`,
+		userId, model.PocketStatusActive).Scan(&total)
+	return total, err
+}
+
+func (p *pocketRepository) Update(payload model.Pocket) (model.Pocket, error) {
+	data, err := scanPocket(p.db.QueryRow(`UPDATE mst_pocket SET nama=$1, target=$2, target_date=$3, updated_at=$4
+	WHERE id=$5 AND status=$6
+	RETURNING `+pocketColumns, payload.Nama, payload.Target, payload.TargetDate, time.Now(), payload.Id, model.PocketStatusActive))
+	if err == sql.ErrNoRows {
+		return model.Pocket{}, fmt.Errorf("pocket sudah ditutup")
+	}
+	if err != nil {
+		return model.Pocket{}, pocketError(err, payload.Nama)
+	}
+
+	return data, nil
+}
+
+// lockPocket mengunci pocket aktif milik user di dalam transaksi pemanggil
+func lockPocket(tx *sql.Tx, id, userId string) (model.Pocket, error) {
+	pocket, err := scanPocket(tx.QueryRow(`SELECT `+pocketColumns+` FROM mst_pocket WHERE id = $1 AND user_id = $2 FOR UPDATE`, id, userId))
+	if err == sql.ErrNoRows {
+		return model.Pocket{}, fmt.Errorf("pocket %s tidak ditemukan", id)
+	}
+	if err != nil {
+		return model.Pocket{}, err
+	}
+	if pocket.Status != model.PocketStatusActive {
+		return model.Pocket{}, fmt.Errorf("pocket sudah ditutup")
+	}
+	return pocket, nil
+}
+
+// movePocket memindahkan dana antara saldo utama dan pocket yang sudah dikunci lalu mencatat mutasinya
+func movePocket(tx *sql.Tx, pocket *model.Pocket, arah string, jumlah int) error {
+	delta := jumlah
+	switch arah {
+	case model.PocketMoveIn:
+		// dana yang sedang ditahan tidak bisa dipindahkan ke pocket
+		if err := debitWallet(tx, pocket.UserId, jumlah); err != nil {
+			return err
+		}
+	case model.PocketMoveOut:
+		if pocket.Saldo < jumlah {
+			return fmt.Errorf("saldo pocket tidak mencukupi, tersisa %d", pocket.Saldo)
+		}
+		if err := creditWallet(tx, pocket.UserId, jumlah); err != nil {
+			return err
+		}
+		delta = -jumlah
+	}
+	pocket.UpdatedAt = time.Now()
+	err := tx.QueryRow(`UPDATE mst_pocket SET saldo = saldo + $1, updated_at=$2 WHERE id=$3 RETURNING saldo`, delta, pocket.UpdatedAt, pocket.Id).Scan(&pocket.Saldo)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO trx_pocket_move (pocket_id, user_id, arah, jumlah, created_at) VALUES ($1,$2,$3,$4,$5)`,
+		pocket.Id, pocket.UserId, arah, jumlah, pocket.UpdatedAt)
+
+	return err
+}
+
+func (p *pocketRepository) Move(id, userId, arah string, jumlah int) (model.Pocket, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return model.Pocket{}, err
+	}
+	pocket, err := lockPocket(tx, id, userId)
+	if err != nil {
+		tx.Rollback()
+		return model.Pocket{}, err
+	}
+	if err := movePocket(tx, &pocket, arah, jumlah); err != nil {
+		tx.Rollback()
+		return model.Pocket{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Pocket{}, err
+	}
+
+	return pocket, nil
+}
+
+// Close mengembalikan seluruh saldo pocket ke saldo utama lalu menutup pocket
+func (p *pocketRepository) Close(id, userId string) (model.Pocket, error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return model.Pocket{}, err
+	}
+	pocket, err := lockPocket(tx, id, userId)
+	if err != nil {
+		tx.Rollback()
+		return model.Pocket{}, err
+	}
+	if pocket.Saldo > 0 {
+		if err := movePocket(tx, &pocket, model.PocketMoveOut, pocket.Saldo); err != nil {
+			tx.Rollback()
+			return model.Pocket{}, err
+		}
+	}
+	pocket, err = scanPocket(tx.QueryRow(`UPDATE mst_pocket SET status=$1, closed_at=$2, updated_at=$2 WHERE id=$3
+	RETURNING `+pocketColumns, model.PocketStatusClosed, time.Now(), id))
+	if err != nil {
+		tx.Rollback()
+		return model.Pocket{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Pocket{}, err
+	}
+
+	return pocket, nil
+}
+
+func (p *pocketRepository) GetMoves(id string, page int) ([]model.PocketMove, error) {
+	paging := 10
+	offset := paging*page - paging
+	rows, err := p.db.Query(`SELECT id, pocket_id, arah, jumlah, created_at FROM trx_pocket_move WHERE pocket_id = $1
+	ORDER BY created_at DESC LIMIT $2 OFFSET $3`, id, paging, offset)
+	if err != nil {
+		return []model.PocketMove{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.PocketMove
+	for rows.Next() {
+		var data model.PocketMove
+		if err := rows.Scan(&data.Id, &data.PocketId, &data.Arah, &data.Jumlah, &data.CreatedAt); err != nil {
+			return []model.PocketMove{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func NewPocketRepository(db *sql.DB) PocketRepository {
+	return &pocketRepository{db: db}
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type RefundRepository interface {
+	Create(payload model.MerchantRefund) (model.MerchantRefund, error)
+	Get(id string) (model.MerchantRefund, error)
+	GetByMerchant(merchantId string, page int) ([]model.MerchantRefund, error)
+	GetByPayment(paymentId string) ([]model.MerchantRefund, error)
+	GetByUser(userId string, page int) ([]model.MerchantRefund, error)
+}
+
+type refundRepository struct {
+	db *sql.DB
+}
+
+const refundColumns = `r.id,
+		r.payment_id,
+		r.merchant_id,
+		m.nama,
+		r.user_id,
+		u.name,
+		r.jumlah,
+		COALESCE(r.alasan, ''),
+		COALESCE(r.referensi, ''),
+		r.status,
+		r.created_at`
+
+const refundFrom = ` FROM trx_merchant_refund AS r
+	JOIN mst_merchant AS m ON r.merchant_id = m.id
+	LEFT JOIN mst_user AS u ON r.user_id = u.id`
+
+func scanRefund(row interface{ Scan(dest ...any) error }) (model.MerchantRefund, error) {
+	var data model.MerchantRefund
+	err := row.Scan(&data.Id, &data.PaymentId, &data.MerchantId, &data.NamaMerchant, &data.UserId, &data.NamaPembayar,
+		&data.Jumlah, &data.Alasan, &data.Referensi, &data.Status, &data.CreatedAt)
+	return data, err
+}
+
+// Create mengurangi saldo merchant dan mengkredit wallet pembayar dalam satu transaksi,
+// pembayaran dikunci agar refund paralel tidak melebihi jumlah pembayaran. Pembayaran yang sudah masuk settlement
+// tidak bisa direfund karena dananya sudah keluar dari saldo merchant.
+func (r *refundRepository) Create(payload model.MerchantRefund) (model.MerchantRefund, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	defer tx.Rollback()
+
+	var merchantId, userId string
+	var jumlah, jumlahRefund int
+	err = tx.QueryRow(`SELECT merchant_id, user_id, jumlah, jumlah_refund FROM trx_merchant_payment WHERE id=$1 FOR UPDATE`,
+		payload.PaymentId).Scan(&merchantId, &userId, &jumlah, &jumlahRefund)
+	if err == sql.ErrNoRows || (err == nil && merchantId != payload.MerchantId) {
+		return model.MerchantRefund{}, fmt.Errorf("pembayaran %s tidak ditemukan", payload.PaymentId)
+	}
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	var settled bool
+	err = tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM trx_settlement_item WHERE payment_id=$1)`, payload.PaymentId).Scan(&settled)
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	if settled {
+		return model.MerchantRefund{}, fmt.Errorf("pembayaran %s sudah masuk settlement dan tidak dapat direfund", payload.PaymentId)
+	}
+	if jumlahRefund+payload.Jumlah > jumlah {
+		return model.MerchantRefund{}, fmt.Errorf("jumlah refund melebihi sisa pembayaran %d", jumlah-jumlahRefund)
+	}
+
+	_, err = tx.Exec(`UPDATE trx_merchant_payment SET jumlah_refund = jumlah_refund + $1 WHERE id=$2`, payload.Jumlah, payload.PaymentId)
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	res, err := tx.Exec(`UPDATE mst_merchant SET saldo = saldo - $1, updated_at = $2 WHERE id=$3 AND saldo >= $1`,
+		payload.Jumlah, time.Now(), payload.MerchantId)
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.MerchantRefund{}, fmt.Errorf("saldo merchant tidak mencukupi untuk refund %d", payload.Jumlah)
+	}
+	_, err = tx.Exec(`UPDATE mst_saldo SET saldo = saldo + $1 WHERE user_id=$2`, payload.Jumlah, userId)
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+
+	payload.UserId = userId
+	payload.Status = model.MerchantRefundSuccess
+	payload.CreatedAt = time.Now()
+	err = tx.QueryRow(`INSERT INTO trx_merchant_refund (payment_id, merchant_id, user_id, jumlah, alasan, referensi, status, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+	RETURNING id`, payload.PaymentId, payload.MerchantId, payload.UserId, payload.Jumlah, nullString(payload.Alasan),
+		nullString(payload.Referensi), payload.Status, payload.CreatedAt).Scan(&payload.Id)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.MerchantRefund{}, fmt.Errorf("refund dengan referensi %s sudah ada", payload.Referensi)
+		}
+		return model.MerchantRefund{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.MerchantRefund{}, err
+	}
+
+	return r.Get(payload.Id)
+}
+
+func (r *refundRepository) Get(id string) (model.MerchantRefund, error) {
+	data, err := scanRefund(r.db.QueryRow(`SELECT `+refundColumns+refundFrom+` WHERE r.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.MerchantRefund{}, fmt.Errorf("refund %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (r *refundRepository) GetByMerchant(merchantId string, page int) ([]model.MerchantRefund, error) {
+	paging := 10
+	offset := (paging * page) - paging
+	return r.list(`r.merchant_id = $1 ORDER BY r.created_at DESC LIMIT $2 OFFSET $3`, merchantId, paging, offset)
+}
+
+func (r *refundRepository) GetByPayment(paymentId string) ([]model.MerchantRefund, error) {
+	return r.list(`r.payment_id = $1 ORDER BY r.created_at`, paymentId)
+}
+
+func (r *refundRepository) GetByUser(userId string, page int) ([]model.MerchantRefund, error) {
+	paging := 10
+	offset := (paging * page) - paging
+	return r.list(`r.user_id = $1 ORDER BY r.created_at DESC LIMIT $2 OFFSET $3`, userId, paging, offset)
+}
+
+func (r *refundRepository) list(where string, args ...any) ([]model.MerchantRefund, error) {
+	rows, err := r.db.Query(`SELECT `+refundColumns+refundFrom+` WHERE `+where, args...)
+	if err != nil {
+		return []model.MerchantRefund{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.MerchantRefund
+	for rows.Next() {
+		data, err := scanRefund(rows)
+		if err != nil {
+			return []model.MerchantRefund{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func NewRefundRepository(db *sql.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
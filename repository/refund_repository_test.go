@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type RefundRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    RefundRepository
+}
+
+func (suite *RefundRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDB = db
+	suite.mockSql = mock
+	suite.repo = NewRefundRepository(suite.mockDB)
+}
+
+func TestRefundRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundRepositoryTestSuite))
+}
+
+func (suite *RefundRepositoryTestSuite) TestCreate_PaymentAlreadySettled() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT merchant_id, user_id, jumlah, jumlah_refund FROM trx_merchant_payment").WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"merchant_id", "user_id", "jumlah", "jumlah_refund"}).AddRow("m-1", "u-1", 50000, 0))
+	suite.mockSql.ExpectQuery("SELECT EXISTS \\(SELECT 1 FROM trx_settlement_item").WithArgs("pay-1").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.Create(model.MerchantRefund{PaymentId: "pay-1", MerchantId: "m-1", Jumlah: 10000})
+	assert.EqualError(suite.T(), err, "pembayaran pay-1 sudah masuk settlement dan tidak dapat direfund")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
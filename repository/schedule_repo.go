@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type ScheduleRepository interface {
+	Create(payload model.ScheduledTransfer) (model.ScheduledTransfer, error)
+	Get(id string) (model.ScheduledTransfer, error)
+	GetByUser(userId string) ([]model.ScheduledTransfer, error)
+	Update(payload model.ScheduledTransfer) (model.ScheduledTransfer, error)
+	GetDue(now time.Time, limit int) ([]model.ScheduledTransfer, error)
+	ClaimDue(id string, dueAt, leaseUntil time.Time) (bool, error)
+	UpdateRun(payload model.ScheduledTransfer) (bool, error)
+	GetRunTransfer(userId, referensi string) (string, error)
+}
+
+type scheduleRepository struct {
+	db *sql.DB
+}
+
+const scheduleColumns = `id,
+		user_id,
+		tujuan_transfer,
+		jumlah_transfer,
+		COALESCE(catatan, ''),
+		COALESCE(kategori, ''),
+		frekuensi,
+		start_at,
+		next_run_at,
+		end_date,
+		max_runs,
+		run_count,
+		retry_count,
+		status,
+		COALESCE(last_error, ''),
+		COALESCE(last_transfer_id::text, ''),
+		created_at,
+		updated_at`
+
+func scanSchedule(row interface{ Scan(dest ...any) error }) (model.ScheduledTransfer, error) {
+	var data model.ScheduledTransfer
+	var endDate sql.NullTime
+	err := row.Scan(&data.Id, &data.UserId, &data.TujuanTransfer, &data.JumlahTransfer, &data.Catatan, &data.Kategori,
+		&data.Frekuensi, &data.StartAt, &data.NextRunAt, &endDate, &data.MaxRuns, &data.RunCount, &data.RetryCount,
+		&data.Status, &data.LastError, &data.LastTransferId, &data.CreatedAt, &data.UpdatedAt)
+	if endDate.Valid {
+		data.EndDate = &endDate.Time
+	}
+	return data, err
+}
+
+func (s *scheduleRepository) Create(payload model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	now := time.Now()
+	return scanSchedule(s.db.QueryRow(`INSERT INTO trx_scheduled_transfer (
+		user_id,
+		tujuan_transfer,
+		jumlah_transfer,
+		catatan,
+		kategori,
+		frekuensi,
+		start_at,
+		next_run_at,
+		end_date,
+		max_runs,
+		status,
+		created_at,
+		updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
+	RETURNING `+scheduleColumns, payload.UserId, payload.TujuanTransfer, payload.JumlahTransfer, nullString(payload.Catatan),
+		nullString(payload.Kategori), payload.Frekuensi, payload.StartAt, payload.NextRunAt, payload.EndDate, payload.MaxRuns,
+		model.ScheduleStatusActive, now, now))
+}
+
+func (s *scheduleRepository) Get(id string) (model.ScheduledTransfer, error) {
+	data, err := scanSchedule(s.db.QueryRow(`SELECT `+scheduleColumns+` FROM trx_scheduled_transfer WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.ScheduledTransfer{}, fmt.Errorf("jadwal transfer %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (s *scheduleRepository) GetByUser(userId string) ([]model.ScheduledTransfer, error) {
+	rows, err := s.db.Query(`SELECT `+scheduleColumns+` FROM trx_scheduled_transfer WHERE user_id = $1 ORDER BY created_at DESC`, userId)
+	if err != nil {
+		return []model.ScheduledTransfer{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.ScheduledTransfer
+	for rows.Next() {
+		data, err := scanSchedule(rows)
+		if err != nil {
+			return []model.ScheduledTransfer{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Update menyimpan semua kolom yang bisa berubah, baik dari edit user maupun hasil eksekusi scheduler
+func (s *scheduleRepository) Update(payload model.ScheduledTransfer) (model.ScheduledTransfer, error) {
+	var lastTransferId any
+	if payload.LastTransferId != "" {
+		lastTransferId = payload.LastTransferId
+	}
+	return scanSchedule(s.db.QueryRow(`UPDATE trx_scheduled_transfer SET
+		tujuan_transfer=$1,
+		jumlah_transfer=$2,
+		catatan=$3,
+		kategori=$4,
+		frekuensi=$5,
+		start_at=$6,
+		next_run_at=$7,
+		end_date=$8,
+		max_runs=$9,
+		run_count=$10,
+		retry_count=$11,
+		status=$12,
+		last_error=$13,
+		last_transfer_id=$14,
+		updated_at=$15
+	WHERE id=$16
+	RETURNING `+scheduleColumns, payload.TujuanTransfer, payload.JumlahTransfer, nullString(payload.Catatan),
+		nullString(payload.Kategori), payload.Frekuensi, payload.StartAt, payload.NextRunAt, payload.EndDate, payload.MaxRuns,
+		payload.RunCount, payload.RetryCount, payload.Status, nullString(payload.LastError), lastTransferId, time.Now(), payload.Id))
+}
+
+func (s *scheduleRepository) GetDue(now time.Time, limit int) ([]model.ScheduledTransfer, error) {
+	rows, err := s.db.Query(`SELECT `+scheduleColumns+` FROM trx_scheduled_transfer
+	WHERE status = $1 AND next_run_at <= $2
+	ORDER BY next_run_at
+	LIMIT $3`, model.ScheduleStatusActive, now, limit)
+	if err != nil {
+		return []model.ScheduledTransfer{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.ScheduledTransfer
+	for rows.Next() {
+		data, err := scanSchedule(rows)
+		if err != nil {
+			return []model.ScheduledTransfer{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// ClaimDue menggeser next_run_at ke batas lease agar jadwal tidak dieksekusi dua kali oleh instance lain,
+// jika proses mati di tengah jalan jadwal akan diambil lagi setelah lease habis
+func (s *scheduleRepository) ClaimDue(id string, dueAt, leaseUntil time.Time) (bool, error) {
+	res, err := s.db.Exec(`UPDATE trx_scheduled_transfer SET next_run_at=$1 WHERE id=$2 AND status=$3 AND next_run_at=$4`,
+		leaseUntil, id, model.ScheduleStatusActive, dueAt)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// UpdateRun hanya menulis hasil eksekusi dan hanya selama jadwal masih aktif,
+// sehingga jadwal yang dijeda/dibatalkan atau diubah user saat sedang berjalan tidak tertimpa
+func (s *scheduleRepository) UpdateRun(payload model.ScheduledTransfer) (bool, error) {
+	res, err := s.db.Exec(`UPDATE trx_scheduled_transfer SET
+		next_run_at=$1,
+		run_count=$2,
+		retry_count=$3,
+		status=$4,
+		last_error=$5,
+		last_transfer_id=$6,
+		updated_at=$7
+	WHERE id=$8 AND status=$9`, payload.NextRunAt, payload.RunCount, payload.RetryCount, payload.Status,
+		nullString(payload.LastError), nullString(payload.LastTransferId), time.Now(), payload.Id, model.ScheduleStatusActive)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// GetRunTransfer mencari transfer yang sudah tercatat untuk satu eksekusi jadwal, kosong jika belum ada
+func (s *scheduleRepository) GetRunTransfer(userId, referensi string) (string, error) {
+	var id string
+	err := s.db.QueryRow(`SELECT id FROM trx_send_transfer WHERE user_id=$1 AND referensi=$2 LIMIT 1`, userId, referensi).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return id, err
+}
+
+func NewScheduleRepository(db *sql.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type SettlementRepository interface {
+	GetMerchantsDue(tanggal time.Time) ([]model.Merchant, error)
+	GetUnsettled(merchantId string, cutoff time.Time) ([]model.SettlementItem, error)
+	Create(payload model.Settlement) (model.Settlement, error)
+	Get(id string) (model.Settlement, error)
+	GetByMerchant(merchantId string, page int) ([]model.Settlement, error)
+	Claim(id string) (bool, error)
+	GetStuck(before time.Time, limit int) ([]model.Settlement, error)
+	Reclaim(id string, updatedAt time.Time) (bool, error)
+	Finish(id, status, payoutRef, alasan string) (model.Settlement, error)
+}
+
+type settlementRepository struct {
+	db *sql.DB
+}
+
+const settlementColumns = `s.id,
+		s.merchant_id,
+		m.owner_id,
+		s.tanggal,
+		s.cutoff,
+		s.jumlah_transaksi,
+		s.total_gross,
+		s.total_biaya,
+		s.total_net,
+		s.bank_code,
+		s.rekening,
+		s.status,
+		COALESCE(s.payout_ref, ''),
+		COALESCE(s.alasan_gagal, ''),
+		s.created_at,
+		s.updated_at`
+
+const settlementFrom = ` FROM trx_settlement AS s
+	JOIN mst_merchant AS m ON s.merchant_id = m.id`
+
+func scanSettlement(row interface{ Scan(dest ...any) error }) (model.Settlement, error) {
+	var data model.Settlement
+	err := row.Scan(&data.Id, &data.MerchantId, &data.OwnerId, &data.Tanggal, &data.Cutoff, &data.JumlahTransaksi, &data.TotalGross,
+		&data.TotalBiaya, &data.TotalNet, &data.BankCode, &data.Rekening, &data.Status, &data.PayoutRef, &data.AlasanGagal,
+		&data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+// GetMerchantsDue mengembalikan merchant aktif yang belum memiliki settlement pada tanggal tersebut
+func (s *settlementRepository) GetMerchantsDue(tanggal time.Time) ([]model.Merchant, error) {
+	var datas []model.Merchant
+	rows, err := s.db.Query(`SELECT `+merchantColumns+` FROM mst_merchant AS m
+	WHERE m.status = $1 AND NOT EXISTS (SELECT 1 FROM trx_settlement AS s WHERE s.merchant_id = m.id AND s.tanggal = $2)`,
+		model.MerchantStatusActive, tanggal)
+	if err != nil {
+		return []model.Merchant{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanMerchant(rows)
+		if err != nil {
+			return []model.Merchant{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// GetUnsettled mengembalikan pembayaran sukses sebelum cutoff yang belum masuk settlement mana pun,
+// jumlah sudah dikurangi refund
+func (s *settlementRepository) GetUnsettled(merchantId string, cutoff time.Time) ([]model.SettlementItem, error) {
+	var datas []model.SettlementItem
+	rows, err := s.db.Query(`SELECT mp.id, u.name, COALESCE(mp.referensi, ''), mp.jumlah - mp.jumlah_refund, mp.created_at
+	FROM trx_merchant_payment AS mp
+	LEFT JOIN mst_user AS u ON mp.user_id = u.id
+	WHERE mp.merchant_id = $1 AND mp.status = $2 AND mp.created_at < $3 AND mp.jumlah > mp.jumlah_refund
+		AND NOT EXISTS (SELECT 1 FROM trx_settlement_item AS si WHERE si.payment_id = mp.id)
+	ORDER BY mp.created_at`, merchantId, model.MerchantPaymentSuccess, cutoff)
+	if err != nil {
+		return []model.SettlementItem{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var data model.SettlementItem
+		if err := rows.Scan(&data.PaymentId, &data.NamaPembayar, &data.Referensi, &data.Jumlah, &data.PaidAt); err != nil {
+			return []model.SettlementItem{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Create mencatat batch settlement, mengurangi saldo merchant sebesar gross dan memposting biaya MDR ke house wallet.
+// Unique payment_id pada item mencegah pembayaran yang sama masuk dua settlement. Pembayaran dikunci dan jumlahnya
+// dicocokkan ulang agar refund yang masuk setelah GetUnsettled tidak ikut tersettle.
+func (s *settlementRepository) Create(payload model.Settlement) (model.Settlement, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	defer tx.Rollback()
+
+	payload.Status = model.SettlementStatusProcessing
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err = tx.QueryRow(`INSERT INTO trx_settlement (merchant_id, tanggal, cutoff, jumlah_transaksi, total_gross, total_biaya, total_net, bank_code, rekening, status, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+	RETURNING id`, payload.MerchantId, payload.Tanggal, payload.Cutoff, payload.JumlahTransaksi, payload.TotalGross,
+		payload.TotalBiaya, payload.TotalNet, payload.BankCode, payload.Rekening, payload.Status, payload.CreatedAt,
+		payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.Settlement{}, fmt.Errorf("settlement tanggal %s sudah dibuat", payload.Tanggal.Format("2006-01-02"))
+		}
+		return model.Settlement{}, err
+	}
+	for _, item := range payload.Items {
+		var sisa int
+		err = tx.QueryRow(`SELECT jumlah - jumlah_refund FROM trx_merchant_payment WHERE id=$1 FOR UPDATE`, item.PaymentId).Scan(&sisa)
+		if err != nil {
+			return model.Settlement{}, err
+		}
+		if sisa != item.Jumlah {
+			return model.Settlement{}, fmt.Errorf("pembayaran %s berubah saat settlement diproses", item.PaymentId)
+		}
+		_, err = tx.Exec(`INSERT INTO trx_settlement_item (settlement_id, payment_id, jumlah, biaya, fee_rule_id)
+		VALUES ($1,$2,$3,$4,$5)`, payload.Id, item.PaymentId, item.Jumlah, item.Biaya, nullString(item.FeeRuleId))
+		if err != nil {
+			return model.Settlement{}, err
+		}
+	}
+	res, err := tx.Exec(`UPDATE mst_merchant SET saldo = saldo - $1, updated_at = $2 WHERE id = $3 AND saldo >= $1`,
+		payload.TotalGross, time.Now(), payload.MerchantId)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.Settlement{}, fmt.Errorf("saldo merchant tidak mencukupi untuk settlement")
+	}
+	if err := postFee(tx, payload.OwnerId, model.FeeOperationMerchant, payload.Id, "", payload.TotalBiaya); err != nil {
+		return model.Settlement{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return model.Settlement{}, err
+	}
+
+	return payload, nil
+}
+
+// Get mengembalikan settlement beserta seluruh transaksi yang disettle
+func (s *settlementRepository) Get(id string) (model.Settlement, error) {
+	data, err := scanSettlement(s.db.QueryRow(`SELECT `+settlementColumns+settlementFrom+` WHERE s.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.Settlement{}, fmt.Errorf("settlement %s tidak ditemukan", id)
+	}
+	if err != nil {
+		return model.Settlement{}, err
+	}
+
+	rows, err := s.db.Query(`SELECT si.payment_id, u.name, COALESCE(mp.referensi, ''), si.jumlah, si.biaya, mp.created_at
+	FROM trx_settlement_item AS si
+	JOIN trx_merchant_payment AS mp ON si.payment_id = mp.id
+	LEFT JOIN mst_user AS u ON mp.user_id = u.id
+	WHERE si.settlement_id = $1 ORDER BY mp.created_at`, id)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item model.SettlementItem
+		err := rows.Scan(&item.PaymentId, &item.NamaPembayar, &item.Referensi, &item.Jumlah, &item.Biaya, &item.PaidAt)
+		if err != nil {
+			return model.Settlement{}, err
+		}
+		item.Net = item.Jumlah - item.Biaya
+		data.Items = append(data.Items, item)
+	}
+
+	return data, nil
+}
+
+func (s *settlementRepository) GetByMerchant(merchantId string, page int) ([]model.Settlement, error) {
+	var datas []model.Settlement
+	paging := 10
+	offset := (paging * page) - paging
+
+	rows, err := s.db.Query(`SELECT `+settlementColumns+settlementFrom+` WHERE s.merchant_id = $1
+	ORDER BY s.tanggal DESC LIMIT $2 OFFSET $3`, merchantId, paging, offset)
+	if err != nil {
+		return []model.Settlement{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanSettlement(rows)
+		if err != nil {
+			return []model.Settlement{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Claim memindahkan settlement gagal kembali ke processing untuk dicoba ulang
+func (s *settlementRepository) Claim(id string) (bool, error) {
+	res, err := s.db.Exec(`UPDATE trx_settlement SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+		model.SettlementStatusProcessing, time.Now(), id, model.SettlementStatusFailed)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// GetStuck mengambil settlement yang tertahan di processing sejak batas waktu, misalnya karena proses mati
+// di antara Create dan Finish
+func (s *settlementRepository) GetStuck(before time.Time, limit int) ([]model.Settlement, error) {
+	var datas []model.Settlement
+	rows, err := s.db.Query(`SELECT `+settlementColumns+settlementFrom+`
+	WHERE s.status = $1 AND s.updated_at <= $2
+	ORDER BY s.updated_at
+	LIMIT $3`, model.SettlementStatusProcessing, before, limit)
+	if err != nil {
+		return []model.Settlement{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanSettlement(rows)
+		if err != nil {
+			return []model.Settlement{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// Reclaim mengambil alih settlement yang macet, updated_at dipakai sebagai lease agar hanya satu proses yang mengulang
+func (s *settlementRepository) Reclaim(id string, updatedAt time.Time) (bool, error) {
+	res, err := s.db.Exec(`UPDATE trx_settlement SET updated_at=$1 WHERE id=$2 AND status=$3 AND updated_at=$4`,
+		time.Now(), id, model.SettlementStatusProcessing, updatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func (s *settlementRepository) Finish(id, status, payoutRef, alasan string) (model.Settlement, error) {
+	_, err := s.db.Exec(`UPDATE trx_settlement SET status=$1, payout_ref=$2, alasan_gagal=$3, updated_at=$4
+	WHERE id=$5 AND status=$6`, status, nullString(payoutRef), nullString(alasan), time.Now(), id, model.SettlementStatusProcessing)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+
+	return s.Get(id)
+}
+
+func NewSettlementRepository(db *sql.DB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type StatementRepository interface {
+	GetAccount(userId string) (model.Statement, error)
+	GetLines(userId string, dari, sampai time.Time) ([]model.StatementLine, error)
+	GetNetSince(userId string, since time.Time) (int, error)
+}
+
+type statementRepository struct {
+	db *sql.DB
+}
+
+// sumber baris capture hold dan pemindahan pocket, dipakai bersama ledgerUnion dan transactionUnion
+// agar riwayat transaksi dan rekening koran mencakup perpindahan dana yang sama
+const (
+	holdCaptureOutFrom = ` FROM trx_fund_hold AS h
+	WHERE h.user_id = $1 AND h.status = 'captured' AND COALESCE(h.penerima_jenis, '') <> 'merchant'
+		AND NOT EXISTS (SELECT 1 FROM withdraw_saldo AS w WHERE w.hold_id = h.id)`
+	holdCaptureInFrom = ` FROM trx_fund_hold AS h
+	LEFT JOIN mst_user AS u ON h.user_id = u.id
+	WHERE h.penerima_jenis = 'user' AND h.penerima_id = $1::text AND h.status = 'captured'`
+	pocketMoveFrom = ` FROM trx_pocket_move AS pm
+	JOIN mst_pocket AS p ON pm.pocket_id = p.id
+	WHERE pm.user_id = $1`
+)
+
+// ledgerUnion berisi setiap perubahan mst_saldo milik user $1 beserta mutasi bertanda,
+// hanya status yang benar-benar memindahkan saldo yang diikutkan
+const ledgerUnion = `WITH ledger AS (
+	SELECT s.id::text AS id, CAST(s.transfer_at AS TIMESTAMP) AS tanggal, 'transfer' AS jenis,
+		COALESCE(u.name, '') AS pihak, COALESCE(s.catatan, '') AS catatan, COALESCE(s.referensi, '') AS referensi,
+		COALESCE(s.kategori_pengirim, '') AS kategori, -(s.jumlah_transfer + s.biaya) AS mutasi
+	FROM trx_send_transfer AS s
+	LEFT JOIN mst_user AS u ON s.tujuan_transfer = u.id
+	WHERE s.user_id = $1
+	UNION ALL
+	SELECT s.id::text, CAST(r.transfer_at AS TIMESTAMP), 'transfer',
+		COALESCE(u.name, ''), COALESCE(s.catatan, ''), COALESCE(s.referensi, ''),
+		COALESCE(r.kategori_penerima, ''), r.jumlah_transfer
+	FROM trx_receive_transfer AS r
+	JOIN trx_send_transfer AS s ON r.trx_id = s.id
+	LEFT JOIN mst_user AS u ON s.user_id = u.id
+	WHERE r.tujuan_transfer = $1
+	UNION ALL
+	SELECT t.id::text, t.updated_at, 'topup', COALESCE(t.channel, ''), COALESCE(t.deskripsi, ''), '', '', t.ammount
+	FROM trx_topup_method_payment AS t
+	WHERE t.user_id = $1 AND t.status = 'Pembayaran berhasil'
+	UNION ALL
+	SELECT w.id::text, w.updated_at, 'withdraw', TRIM(COALESCE(w.bank_code, '') || ' ' || w.rekening), '',
+		COALESCE(w.payout_ref, ''), '', -(w.withdraw + w.biaya)
+	FROM withdraw_saldo AS w
+	WHERE w.user_id = $1 AND w.status = 'paid'
+	UNION ALL
+	SELECT h.id::text, h.updated_at, 'hold_capture', '', COALESCE(h.alasan, ''), COALESCE(h.reference_id, ''), '',
+		-h.jumlah_captured` + holdCaptureOutFrom + `
+	UNION ALL
+	SELECT h.id::text, h.updated_at, 'hold_capture', COALESCE(u.name, ''), COALESCE(h.alasan, ''),
+		COALESCE(h.reference_id, ''), '', h.jumlah_captured` + holdCaptureInFrom + `
+	UNION ALL
+	SELECT mp.id::text, mp.created_at, 'merchant_payment', m.nama, COALESCE(mp.catatan, ''),
+		COALESCE(mp.referensi, ''), '', -mp.jumlah
+	FROM trx_merchant_payment AS mp
+	JOIN mst_merchant AS m ON mp.merchant_id = m.id
+	WHERE mp.user_id = $1
+	UNION ALL
+	SELECT rf.id::text, rf.created_at, 'refund', m.nama, COALESCE(rf.alasan, ''), COALESCE(rf.referensi, ''), '',
+		rf.jumlah
+	FROM trx_merchant_refund AS rf
+	JOIN mst_merchant AS m ON rf.merchant_id = m.id
+	WHERE rf.user_id = $1
+	UNION ALL
+	SELECT pm.id::text, pm.created_at, 'pocket', p.nama, '', '', '',
+		CASE WHEN pm.arah = 'masuk' THEN -pm.jumlah ELSE pm.jumlah END` + pocketMoveFrom + `
+)`
+
+func (s *statementRepository) GetAccount(userId string) (model.Statement, error) {
+	var data model.Statement
+	err := s.db.QueryRow(`SELECT u.id, u.name, s.saldo FROM mst_user AS u JOIN mst_saldo AS s ON s.user_id = u.id
+	WHERE u.id = $1`, userId).Scan(&data.UserId, &data.Nama, &data.SaldoAkhir)
+	if err == sql.ErrNoRows {
+		return model.Statement{}, fmt.Errorf("user %s tidak ditemukan atau belum memverifikasi akun", userId)
+	}
+	return data, err
+}
+
+func (s *statementRepository) GetLines(userId string, dari, sampai time.Time) ([]model.StatementLine, error) {
+	rows, err := s.db.Query(ledgerUnion+`
+	SELECT id, tanggal, jenis, pihak, catatan, referensi, kategori, mutasi FROM ledger
+	WHERE tanggal >= $2 AND tanggal < $3
+	ORDER BY tanggal, id`, userId, dari, sampai)
+	if err != nil {
+		return []model.StatementLine{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.StatementLine
+	for rows.Next() {
+		var data model.StatementLine
+		err := rows.Scan(&data.Id, &data.Tanggal, &data.Jenis, &data.Pihak, &data.Catatan, &data.Referensi, &data.Kategori, &data.Mutasi)
+		if err != nil {
+			return []model.StatementLine{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// GetNetSince menjumlahkan mutasi sejak waktu tertentu, dipakai untuk menghitung mundur saldo akhir periode dari saldo saat ini
+func (s *statementRepository) GetNetSince(userId string, since time.Time) (int, error) {
+	var total int
+	err := s.db.QueryRow(ledgerUnion+` SELECT COALESCE(SUM(mutasi), 0) FROM ledger WHERE tanggal >= $2`, userId, since).Scan(&total)
+	return total, err
+}
+
+func NewStatementRepository(db *sql.DB) StatementRepository {
+	return &statementRepository{db: db}
+}
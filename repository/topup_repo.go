@@ -28,11 +28,17 @@ func (t *topupRepository) Create(payload model.TopupModel) (common.ResponseMidtr
 		return common.ResponseMidtrans{}, err
 	}
 	deskripsi := fmt.Sprintf("%s ingin melakukan top up saldo sebesar %d", payload.User.Name, payload.TransactionDetails.GrossAmt)
-	err = tx.QueryRow(`INSERT INTO trx_topup_method_payment(user_id,ammount,status,deskripsi,created_at,updated_at) VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`, payload.User.Id, payload.TransactionDetails.GrossAmt, "Menunggu pembayaran", deskripsi, time.Now(), time.Now()).Scan(&payload.TransactionDetails.OrderID)
+	var channel any
+	if payload.Channel != "" {
+		channel = payload.Channel
+	}
+	err = tx.QueryRow(`INSERT INTO trx_topup_method_payment(user_id,ammount,biaya,fee_rule_id,channel,status,deskripsi,created_at,updated_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`, payload.User.Id, payload.TransactionDetails.GrossAmt, payload.Biaya, nullString(payload.FeeRuleId), channel, "Menunggu pembayaran", deskripsi, time.Now(), time.Now()).Scan(&payload.TransactionDetails.OrderID)
 	if err != nil {
 		tx.Rollback()
 		return common.ResponseMidtrans{}, err
 	}
+	// user membayar nominal topup ditambah biaya channel
+	payload.TransactionDetails.GrossAmt += int64(payload.Biaya)
 
 	midtransResponse, err := common.GenerateMidtrans(payload.TransactionDetails)
 	if err != nil {
@@ -79,9 +85,11 @@ func (t *topupRepository) Payment(payload dto.ResponsePayment) (dto.ResponsePaym
 	if err != nil {
 		return dto.ResponsePayment{}, err
 	}
-	var saldoTopup int
+	var saldoTopup, biaya int
+	var feeRuleId string
 	tabel, err := t.Getbyid(payload.OrderId)
 	if err != nil {
+		tx.Rollback()
 		return dto.ResponsePayment{}, err
 	}
 	if tabel.Status == "Pembayaran berhasil" {
@@ -89,19 +97,22 @@ func (t *topupRepository) Payment(payload dto.ResponsePayment) (dto.ResponsePaym
 		return dto.ResponsePayment{}, errors.New("link ini sudah tidak valid")
 	}
 	payload.UserId = tabel.UserId
-	err = tx.QueryRow(`SELECT saldo FROM mst_saldo WHERE user_id = $1`, payload.UserId).Scan(&payload.Saldo)
+	// status dicek ulang di dalam UPDATE agar notifikasi ganda tidak menambah saldo dua kali
+	err = tx.QueryRow("UPDATE trx_topup_method_payment SET status=$1, updated_at=$2 WHERE id =$3 AND status <> $1 RETURNING user_id,ammount,biaya,COALESCE(fee_rule_id::text, '')", "Pembayaran berhasil", time.Now(), payload.OrderId).Scan(&payload.UserId, &saldoTopup, &biaya, &feeRuleId)
 	if err != nil {
 		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return dto.ResponsePayment{}, errors.New("link ini sudah tidak valid")
+		}
 		return dto.ResponsePayment{}, err
 	}
-	err = tx.QueryRow("UPDATE trx_topup_method_payment SET status=$1, updated_at=$2 WHERE id =$3 RETURNING user_id,ammount", "Pembayaran berhasil", time.Now(), payload.OrderId).Scan(&payload.UserId, &saldoTopup)
+
+	err = tx.QueryRow(`UPDATE mst_saldo SET saldo = saldo + $1 WHERE user_id=$2 RETURNING saldo`, saldoTopup, payload.UserId).Scan(&payload.Saldo)
 	if err != nil {
 		tx.Rollback()
 		return dto.ResponsePayment{}, err
 	}
-
-	payload.Saldo += saldoTopup
-	_, err = tx.Query(`UPDATE mst_saldo SET saldo=$1 WHERE user_id=$2`, payload.Saldo, payload.UserId)
+	err = postFee(tx, payload.UserId, model.FeeOperationTopup, payload.OrderId, feeRuleId, biaya)
 	if err != nil {
 		tx.Rollback()
 		return dto.ResponsePayment{}, err
@@ -121,6 +132,8 @@ func (t *topupRepository) GetAll(id string, page int) ([]model.TableTopupPayment
 		user_id,
 		token_midtrans,
 		ammount,
+		biaya,
+		COALESCE(channel, ''),
 		deskripsi,
 		status,
 		url_payment,
@@ -143,7 +156,7 @@ func (t *topupRepository) GetAll(id string, page int) ([]model.TableTopupPayment
 	for res.Next() {
 		var data model.TableTopupPayment
 
-		err := res.Scan(&data.OrderId, &data.UserId, &data.TokenMidtrans, &data.Ammount, &data.Deskripsi, &data.Status, &data.UrlPayment, &data.Created_at, &data.Updated_at)
+		err := res.Scan(&data.OrderId, &data.UserId, &data.TokenMidtrans, &data.Ammount, &data.Biaya, &data.Channel, &data.Deskripsi, &data.Status, &data.UrlPayment, &data.Created_at, &data.Updated_at)
 		if err != nil {
 			return []model.TableTopupPayment{}, err
 		}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type TransactionRepository interface {
+	GetAll(query dto.TransactionQuery) ([]model.Transaction, error)
+	Get(id, userId string) (model.Transaction, error)
+}
+
+type transactionRepository struct {
+	db *sql.DB
+}
+
+// transactionUnion menggabungkan seluruh jenis transaksi milik user $1 ke satu bentuk baris,
+// transfer_at disimpan sebagai teks sehingga perlu di-cast agar bisa diurutkan bersama tabel lain
+const transactionUnion = `WITH trx AS (
+	SELECT s.id::text AS id, 'transfer' AS jenis, 'keluar' AS arah, s.jumlah_transfer AS jumlah, s.biaya,
+		s.status, s.tujuan_transfer::text AS pihak_id, COALESCE(u.name, '') AS pihak, COALESCE(s.catatan, '') AS catatan,
+		COALESCE(s.referensi, '') AS referensi, COALESCE(s.kategori_pengirim, '') AS kategori,
+		CAST(s.transfer_at AS TIMESTAMP) AS created_at
+	FROM trx_send_transfer AS s
+	LEFT JOIN mst_user AS u ON s.tujuan_transfer = u.id
+	WHERE s.user_id = $1
+	UNION ALL
+	SELECT s.id::text, 'transfer', 'masuk', r.jumlah_transfer, 0,
+		s.status, s.user_id::text, COALESCE(u.name, ''), COALESCE(s.catatan, ''),
+		COALESCE(s.referensi, ''), COALESCE(r.kategori_penerima, ''),
+		CAST(r.transfer_at AS TIMESTAMP)
+	FROM trx_receive_transfer AS r
+	JOIN trx_send_transfer AS s ON r.trx_id = s.id
+	LEFT JOIN mst_user AS u ON s.user_id = u.id
+	WHERE r.tujuan_transfer = $1
+	UNION ALL
+	SELECT t.id::text, 'topup', 'masuk', t.ammount, t.biaya,
+		t.status, '', COALESCE(t.channel, ''), COALESCE(t.deskripsi, ''),
+		'', '', t.created_at
+	FROM trx_topup_method_payment AS t
+	WHERE t.user_id = $1
+	UNION ALL
+	SELECT w.id::text, 'withdraw', 'keluar', w.withdraw, w.biaya,
+		w.status, '', TRIM(COALESCE(w.bank_code, '') || ' ' || w.rekening), '',
+		COALESCE(w.payout_ref, ''), '', w.created_at
+	FROM withdraw_saldo AS w
+	WHERE w.user_id = $1
+	UNION ALL
+	SELECT mp.id::text, 'merchant_payment', 'keluar', mp.jumlah, 0,
+		mp.status, mp.merchant_id::text, m.nama, COALESCE(mp.catatan, ''),
+		COALESCE(mp.referensi, ''), '', mp.created_at
+	FROM trx_merchant_payment AS mp
+	JOIN mst_merchant AS m ON mp.merchant_id = m.id
+	WHERE mp.user_id = $1
+	UNION ALL
+	SELECT rf.id::text, 'refund', 'masuk', rf.jumlah, 0,
+		rf.status, rf.merchant_id::text, m.nama, COALESCE(rf.alasan, ''),
+		COALESCE(rf.referensi, ''), '', rf.created_at
+	FROM trx_merchant_refund AS rf
+	JOIN mst_merchant AS m ON rf.merchant_id = m.id
+	WHERE rf.user_id = $1
+	UNION ALL
+	SELECT h.id::text, 'hold_capture', 'keluar', h.jumlah_captured, 0,
+		h.status, COALESCE(h.penerima_id, ''), '', COALESCE(h.alasan, ''),
+		COALESCE(h.reference_id, ''), '', h.updated_at` + holdCaptureOutFrom + `
+	UNION ALL
+	SELECT h.id::text, 'hold_capture', 'masuk', h.jumlah_captured, 0,
+		h.status, h.user_id::text, COALESCE(u.name, ''), COALESCE(h.alasan, ''),
+		COALESCE(h.reference_id, ''), '', h.updated_at` + holdCaptureInFrom + `
+	UNION ALL
+	SELECT pm.id::text, 'pocket', CASE WHEN pm.arah = 'masuk' THEN 'keluar' ELSE 'masuk' END, pm.jumlah, 0,
+		'success', pm.pocket_id::text, p.nama, '',
+		'', '', pm.created_at` + pocketMoveFrom + `
+)
+SELECT id, jenis, arah, jumlah, biaya, status, pihak_id, pihak, catatan, referensi, kategori, created_at FROM trx`
+
+func scanTransaction(row interface{ Scan(dest ...any) error }) (model.Transaction, error) {
+	var data model.Transaction
+	err := row.Scan(&data.Id, &data.Jenis, &data.Arah, &data.Jumlah, &data.Biaya, &data.Status, &data.PihakId, &data.Pihak,
+		&data.Catatan, &data.Referensi, &data.Kategori, &data.CreatedAt)
+	return data, err
+}
+
+func nullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// GetAll memakai keyset (created_at, id) sebagai cursor sehingga halaman berikutnya tidak bergeser saat ada transaksi baru
+func (t *transactionRepository) GetAll(query dto.TransactionQuery) ([]model.Transaction, error) {
+	rows, err := t.db.Query(transactionUnion+`
+	WHERE ($2 = '' OR jenis = $2)
+		AND ($3 = '' OR arah = $3)
+		AND ($4::timestamp IS NULL OR created_at >= $4)
+		AND ($5::timestamp IS NULL OR created_at < $5)
+		AND ($6 = 0 OR jumlah >= $6)
+		AND ($7 = 0 OR jumlah <= $7)
+		AND ($8 = '' OR pihak ILIKE '%' || $8 || '%')
+		AND ($9 = '' OR status = $9)
+		AND ($10::timestamp IS NULL OR (created_at, id) < ($10, $11))
+	ORDER BY created_at DESC, id DESC
+	LIMIT $12`, query.UserId, query.Jenis, query.Arah, nullTime(query.Dari), nullTime(query.Sampai), query.Min, query.Max,
+		query.Pihak, query.Status, nullTime(query.CursorAt), query.CursorId, query.Limit)
+	if err != nil {
+		return []model.Transaction{}, err
+	}
+	defer rows.Close()
+
+	var datas []model.Transaction
+	for rows.Next() {
+		data, err := scanTransaction(rows)
+		if err != nil {
+			return []model.Transaction{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (t *transactionRepository) Get(id, userId string) (model.Transaction, error) {
+	data, err := scanTransaction(t.db.QueryRow(transactionUnion+` WHERE id = $2 LIMIT 1`, userId, id))
+	if err == sql.ErrNoRows {
+		return model.Transaction{}, fmt.Errorf("transaksi %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func NewTransactionRepository(db *sql.DB) TransactionRepository {
+	return &transactionRepository{db: db}
+}
@@ -5,35 +5,104 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
 	"github.com/yafireyhan01/e-wallet/model"
 	"github.com/yafireyhan01/e-wallet/model/dto"
 )
 
 type TransferRepository interface {
 	Create(payload dto.TransferRequest, send, receive model.User) (model.Transfer, error)
-	GetSend(id string, page int) ([]model.Transfer, error)
-	GetReceive(id string, page int) ([]model.Transfer, error)
-	CreateWithdraw(payload model.Withdraw, saldo model.UserSaldo) (model.Withdraw, error)
+	GetSend(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error)
+	GetReceive(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error)
+	UpdateCategory(trxId, userId, kategori string) (model.Transfer, error)
+	CreateWithdraw(payload model.Withdraw) (model.Withdraw, error)
 	GetWithdraw(id string, page int) ([]model.Withdraw, error)
+	GetWithdrawById(id string) (model.Withdraw, error)
+	ClaimWithdraw(id string) (bool, error)
+	GetStuckWithdraws(before time.Time, limit int) ([]model.Withdraw, error)
+	ReclaimWithdraw(id string, updatedAt time.Time) (bool, error)
+	FinishWithdraw(id, status, payoutRef, alasan string) (model.Withdraw, error)
+	GetById(id string) (model.Transfer, error)
+	CreateReversal(payload model.Reversal) (model.Reversal, error)
+	GetReversal(id string) (model.Reversal, error)
+	GetActiveReversal(trxId string) (model.Reversal, error)
+	GetPendingReversal(userId string) ([]model.Reversal, error)
+	UpdateReversalStatus(id, status string) (model.Reversal, error)
+	ExecuteReversal(id string) (model.Reversal, error)
+	CreateIntent(payload model.TransferIntent) (model.TransferIntent, error)
+	GetIntent(id string) (model.TransferIntent, error)
+	ClaimIntent(id string) (bool, error)
+	FinishIntent(id, status, transferId string) error
 }
 
 type transferRepository struct {
 	db *sql.DB
 }
 
+// nullString menyimpan string kosong sebagai NULL
+func nullString(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
+// debitWallet mengunci saldo user lalu mendebit dari saldo tersedia (saldo dikurangi hold aktif),
+// dipakai pembayaran dari wallet di dalam transaksi pemanggil
+func debitWallet(tx *sql.Tx, userId string, jumlah int) error {
+	var saldo int
+	err := tx.QueryRow(`SELECT saldo FROM mst_saldo WHERE user_id=$1 FOR UPDATE`, userId).Scan(&saldo)
+	if err != nil {
+		return err
+	}
+	held, err := heldAmount(tx, userId)
+	if err != nil {
+		return err
+	}
+	if saldo-held < jumlah {
+		return fmt.Errorf("saldo tersedia tidak mencukupi untuk pembayaran %d", jumlah)
+	}
+	_, err = tx.Exec(`UPDATE mst_saldo SET saldo = saldo - $1 WHERE user_id=$2`, jumlah, userId)
+
+	return err
+}
+
+// lockWallets mengunci saldo beberapa user dengan urutan tetap agar dua transfer berlawanan arah tidak deadlock
+func lockWallets(tx *sql.Tx, userIds ...string) error {
+	rows, err := tx.Query(`SELECT user_id FROM mst_saldo WHERE user_id = ANY($1) ORDER BY user_id FOR UPDATE`, pq.Array(userIds))
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+// creditWallet menambah saldo user secara relatif di dalam transaksi pemanggil
+func creditWallet(tx *sql.Tx, userId string, jumlah int) error {
+	res, err := tx.Exec(`UPDATE mst_saldo SET saldo = saldo + $1 WHERE user_id=$2`, jumlah, userId)
+	if err != nil {
+		return err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return fmt.Errorf("saldo user %s tidak ditemukan", userId)
+	}
+	return nil
+}
+
 // tulis code kalian disini
+// Create membaca ulang saldo di dalam transaksi, saldo pada send dan receive hanya dipakai sebagai informasi
 func (t *transferRepository) Create(payload dto.TransferRequest, send, receive model.User) (model.Transfer, error) {
 	response := model.Transfer{}
 	tx, err := t.db.Begin()
 	if err != nil {
+		return model.Transfer{}, err
+	}
+	if err := lockWallets(tx, send.Id, receive.Id); err != nil {
 		tx.Rollback()
 		return model.Transfer{}, err
 	}
-	send.Saldo -= payload.JumlahTransfer
-	receive.Saldo += payload.JumlahTransfer
-	if send.Saldo < 0 {
+	if err := debitWallet(tx, send.Id, payload.JumlahTransfer+payload.Biaya); err != nil {
 		tx.Rollback()
-		return model.Transfer{}, fmt.Errorf("saldo anda tidak mendcukupi untuk transfer %d", payload.JumlahTransfer)
+		return model.Transfer{}, err
 	}
 
 	// buat catatan penerima ke database
@@ -42,32 +111,38 @@ func (t *transferRepository) Create(payload dto.TransferRequest, send, receive m
 		tujuan_transfer,
 		jumlah_transfer,
 		jenis_transfer,
-		transfer_at
+		transfer_at,
+		biaya,
+		catatan,
+		referensi,
+		kategori_pengirim
 		)
 	VALUES (
 		$1,
 		$2,
 		$3,
 		$4,
-		$5
-	) RETURNING id`, send.Id, receive.Id, payload.JumlahTransfer, "mengirim", time.Now()).Scan(&response.Id)
+		$5,
+		$6,
+		$7,
+		$8,
+		$9
+	) RETURNING id`, send.Id, receive.Id, payload.JumlahTransfer, "mengirim", time.Now(), payload.Biaya,
+		nullString(payload.Catatan), nullString(payload.Referensi), nullString(payload.Kategori)).Scan(&response.Id)
 	if err != nil {
 		tx.Rollback()
+		if pgErr, ok := err.(*pq.Error); ok && pgErr.Code == "23505" {
+			return model.Transfer{}, fmt.Errorf("transfer dengan referensi %s sudah tercatat", payload.Referensi)
+		}
 		return model.Transfer{}, err
 	}
 
-	_, err = tx.Exec(`UPDATE mst_saldo SET saldo=$1 WHERE user_id=$2`, receive.Saldo, receive.Id)
-	if err != nil {
-		tx.Rollback()
-		return model.Transfer{}, err
-	}
-	_, err = tx.Exec(`UPDATE mst_saldo SET saldo = $1 WHERE user_id=$2`, send.Saldo, send.Id)
-	if err != nil {
+	if err := creditWallet(tx, receive.Id, payload.JumlahTransfer); err != nil {
 		tx.Rollback()
 		return model.Transfer{}, err
 	}
 	response.JenisTransfer = "mengirim"
-	_, err = tx.Query(`INSERT INTO trx_receive_transfer (
+	_, err = tx.Exec(`INSERT INTO trx_receive_transfer (
 		user_id,
 		trx_id,
 		tujuan_transfer,
@@ -79,16 +154,28 @@ func (t *transferRepository) Create(payload dto.TransferRequest, send, receive m
 		tx.Rollback()
 		return model.Transfer{}, err
 	}
+	err = postFee(tx, send.Id, model.FeeOperationTransfer, response.Id, payload.FeeRuleId, payload.Biaya)
+	if err != nil {
+		tx.Rollback()
+		return model.Transfer{}, err
+	}
 
 	response.UserId = send.Id
 	response.TujuanTransfer = receive.Id
 	response.JumlahTransfer = payload.JumlahTransfer
-	tx.Commit()
+	response.Biaya = payload.Biaya
+	response.Catatan = payload.Catatan
+	response.Referensi = payload.Referensi
+	response.Kategori = payload.Kategori
+	response.Status = model.TransferStatusSuccess
+	if err := tx.Commit(); err != nil {
+		return model.Transfer{}, err
+	}
 
 	return response, nil
 }
 
-func (t *transferRepository) GetSend(id string, page int) ([]model.Transfer, error) {
+func (t *transferRepository) GetSend(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
 	var datas []model.Transfer
 	paging := 3
 	limit := (paging * page) - paging
@@ -100,7 +187,14 @@ func (t *transferRepository) GetSend(id string, page int) ([]model.Transfer, err
 		trx.tujuan_transfer,
 		mst_tujuan.name,
 		trx.jumlah_transfer,
-		trx.jenis_transfer
+		trx.biaya,
+		trx.jenis_transfer,
+		trx.status,
+		COALESCE(trx.reversal_of::text, ''),
+		COALESCE(trx.reversed_by::text, ''),
+		COALESCE(trx.catatan, ''),
+		COALESCE(trx.referensi, ''),
+		COALESCE(trx.kategori_pengirim, '')
 	FROM 
 		trx_send_transfer AS trx
 	LEFT JOIN 
@@ -109,9 +203,11 @@ func (t *transferRepository) GetSend(id string, page int) ([]model.Transfer, err
 		mst_user AS mst_tujuan ON trx.tujuan_transfer = mst_tujuan.id
 	WHERE 
 		trx.user_id = $1
+		AND ($4 = '' OR trx.catatan ILIKE '%' || $4 || '%' OR trx.referensi ILIKE '%' || $4 || '%' OR mst_tujuan.name ILIKE '%' || $4 || '%')
+		AND ($5 = '' OR trx.kategori_pengirim = $5)
 	ORDER BY 
 		trx.transfer_at DESC 
-	LIMIT $2 OFFSET $3`, id, paging, limit)
+	LIMIT $2 OFFSET $3`, id, paging, limit, filter.Search, filter.Kategori)
 	if err != nil {
 		return []model.Transfer{}, err
 	}
@@ -119,7 +215,7 @@ func (t *transferRepository) GetSend(id string, page int) ([]model.Transfer, err
 
 	for res.Next() {
 		var data model.Transfer
-		err := res.Scan(&data.Id, &data.UserId, &data.SenderName, &data.TujuanTransfer, &data.Receiver, &data.JumlahTransfer, &data.JenisTransfer)
+		err := res.Scan(&data.Id, &data.UserId, &data.SenderName, &data.TujuanTransfer, &data.Receiver, &data.JumlahTransfer, &data.Biaya, &data.JenisTransfer, &data.Status, &data.ReversalOf, &data.ReversedBy, &data.Catatan, &data.Referensi, &data.Kategori)
 		if err != nil {
 			return []model.Transfer{}, err
 		}
@@ -129,7 +225,7 @@ func (t *transferRepository) GetSend(id string, page int) ([]model.Transfer, err
 	return datas, nil
 }
 
-func (t *transferRepository) GetReceive(id string, page int) ([]model.Transfer, error) {
+func (t *transferRepository) GetReceive(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
 	var datas []model.Transfer
 	paging := 3
 	limit := (paging * page) - paging
@@ -142,18 +238,28 @@ func (t *transferRepository) GetReceive(id string, page int) ([]model.Transfer,
 		trx.tujuan_transfer,
 		mst_tujuan.name,
 		trx.jumlah_transfer,
-		trx.jenis_transfer
+		trx.jenis_transfer,
+		send.status,
+		COALESCE(send.reversal_of::text, ''),
+		COALESCE(send.reversed_by::text, ''),
+		COALESCE(send.catatan, ''),
+		COALESCE(send.referensi, ''),
+		COALESCE(trx.kategori_penerima, '')
 	FROM 
     	trx_receive_transfer AS trx
+	JOIN 
+    	trx_send_transfer AS send ON trx.trx_id = send.id
 	LEFT JOIN 
     	mst_user ON trx.user_id = mst_user.id
 	LEFT JOIN 
     	mst_user AS mst_tujuan ON trx.tujuan_transfer = mst_tujuan.id
 	WHERE 
     	trx.tujuan_transfer = $1
+		AND ($4 = '' OR send.catatan ILIKE '%' || $4 || '%' OR send.referensi ILIKE '%' || $4 || '%' OR mst_user.name ILIKE '%' || $4 || '%')
+		AND ($5 = '' OR trx.kategori_penerima = $5)
 	ORDER BY 
     	trx.transfer_at DESC 
-	LIMIT $2 OFFSET $3`, id, paging, limit)
+	LIMIT $2 OFFSET $3`, id, paging, limit, filter.Search, filter.Kategori)
 	if err != nil {
 		return []model.Transfer{}, err
 	}
@@ -161,7 +267,7 @@ func (t *transferRepository) GetReceive(id string, page int) ([]model.Transfer,
 
 	for res.Next() {
 		var data model.Transfer
-		err := res.Scan(&data.Id, &data.UserId, &data.SenderName, &data.Trx_id, &data.TujuanTransfer, &data.Receiver, &data.JumlahTransfer, &data.JenisTransfer)
+		err := res.Scan(&data.Id, &data.UserId, &data.SenderName, &data.Trx_id, &data.TujuanTransfer, &data.Receiver, &data.JumlahTransfer, &data.JenisTransfer, &data.Status, &data.ReversalOf, &data.ReversedBy, &data.Catatan, &data.Referensi, &data.Kategori)
 		if err != nil {
 			return []model.Transfer{}, err
 		}
@@ -171,30 +277,74 @@ func (t *transferRepository) GetReceive(id string, page int) ([]model.Transfer,
 	return datas, nil
 }
 
-func (t *transferRepository) CreateWithdraw(payload model.Withdraw, saldo model.UserSaldo) (model.Withdraw, error) {
-	response := model.Withdraw{}
-	tx, _ := t.db.Begin()
-	err := tx.QueryRow(`INSERT INTO withdraw_saldo (user_id,withdraw,created_at)
+const withdrawColumns = `id,
+		user_id,
+		withdraw,
+		biaya,
+		COALESCE(fee_rule_id::text, ''),
+		COALESCE(bank_code, ''),
+		rekening,
+		status,
+		COALESCE(hold_id::text, ''),
+		COALESCE(payout_ref, ''),
+		COALESCE(alasan_gagal, ''),
+		created_at,
+		updated_at`
+
+func scanWithdraw(row interface{ Scan(dest ...any) error }) (model.Withdraw, error) {
+	var data model.Withdraw
+	err := row.Scan(&data.Id, &data.UserId, &data.Withdraw, &data.Biaya, &data.FeeRuleId, &data.BankCode, &data.Rekening, &data.Status, &data.HoldId,
+		&data.PayoutRef, &data.AlasanGagal, &data.Created_at, &data.Updated_at)
+	return data, err
+}
+
+// CreateWithdraw mencatat permintaan withdraw dan menahan dananya sampai payout selesai
+func (t *transferRepository) CreateWithdraw(payload model.Withdraw) (model.Withdraw, error) {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return model.Withdraw{}, err
+	}
+
+	var saldo int
+	err = tx.QueryRow(`SELECT saldo FROM mst_saldo WHERE user_id=$1 FOR UPDATE`, payload.UserId).Scan(&saldo)
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+	held, err := heldAmount(tx, payload.UserId)
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+	if saldo-held < payload.Withdraw+payload.Biaya {
+		tx.Rollback()
+		return model.Withdraw{}, fmt.Errorf("saldo anda tidak mencukupi untuk withdraw %d dan biaya %d", payload.Withdraw, payload.Biaya)
+	}
+
+	hold, err := insertHold(tx, model.Hold{UserId: payload.UserId, Jumlah: payload.Withdraw + payload.Biaya, Alasan: "withdraw", Sumber: model.HoldSourceWithdraw})
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+
+	now := time.Now()
+	response, err := scanWithdraw(tx.QueryRow(`INSERT INTO withdraw_saldo (user_id,withdraw,biaya,fee_rule_id,bank_code,rekening,status,hold_id,created_at,updated_at)
 	VALUES
-		($1,$2,$3)
-	RETURNING 
-		id,user_id,withdraw,created_at`, payload.UserId, payload.Withdraw, time.Now()).Scan(
-		&response.Id,
-		&response.UserId,
-		&response.Withdraw,
-		&response.Created_at,
-	)
+		($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+	RETURNING `+withdrawColumns, payload.UserId, payload.Withdraw, payload.Biaya, nullString(payload.FeeRuleId), payload.BankCode, payload.Rekening, model.WithdrawStatusRequested, hold.Id, now, now))
 	if err != nil {
 		tx.Rollback()
 		return model.Withdraw{}, err
 	}
-	_, err = tx.Exec(`UPDATE mst_saldo SET saldo=$1 WHERE user_id=$2`, saldo.Saldo, payload.UserId)
+	_, err = tx.Exec(`UPDATE trx_fund_hold SET reference_id=$1 WHERE id=$2`, response.Id, hold.Id)
 	if err != nil {
 		tx.Rollback()
 		return model.Withdraw{}, err
 	}
 
-	tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return model.Withdraw{}, err
+	}
 
 	return response, nil
 }
@@ -205,11 +355,7 @@ func (t *transferRepository) GetWithdraw(id string, page int) ([]model.Withdraw,
 	paging := 3
 	limit := (paging * page) - paging
 
-	res, err := t.db.Query(`SELECT 
-		id,
-		user_id,
-		withdraw,
-		created_at
+	res, err := t.db.Query(`SELECT `+withdrawColumns+`
 	FROM 
     	withdraw_saldo
 	WHERE 
@@ -223,9 +369,55 @@ func (t *transferRepository) GetWithdraw(id string, page int) ([]model.Withdraw,
 	defer res.Close()
 
 	for res.Next() {
-		var data model.Withdraw
+		data, err := scanWithdraw(res)
+		if err != nil {
+			return []model.Withdraw{}, err
+		}
+		datas = append(datas, data)
+	}
 
-		err := res.Scan(&data.Id, &data.UserId, &data.Withdraw, &data.Created_at)
+	return datas, nil
+}
+
+func (t *transferRepository) GetWithdrawById(id string) (model.Withdraw, error) {
+	data, err := scanWithdraw(t.db.QueryRow(`SELECT `+withdrawColumns+` FROM withdraw_saldo WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Withdraw{}, fmt.Errorf("withdraw %s tidak ditemukan", id)
+		}
+		return model.Withdraw{}, err
+	}
+
+	return data, nil
+}
+
+// ClaimWithdraw memindahkan withdraw ke processing, false jika sudah diproses atau dibatalkan
+func (t *transferRepository) ClaimWithdraw(id string) (bool, error) {
+	res, err := t.db.Exec(`UPDATE withdraw_saldo SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4`,
+		model.WithdrawStatusProcessing, time.Now(), id, model.WithdrawStatusRequested)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// GetStuckWithdraws mengambil withdraw yang belum selesai dan tidak disentuh sejak batas waktu,
+// misalnya karena proses payout mati di tengah jalan
+func (t *transferRepository) GetStuckWithdraws(before time.Time, limit int) ([]model.Withdraw, error) {
+	res, err := t.db.Query(`SELECT `+withdrawColumns+` FROM withdraw_saldo
+	WHERE status IN ($1, $2) AND updated_at <= $3
+	ORDER BY updated_at
+	LIMIT $4`, model.WithdrawStatusRequested, model.WithdrawStatusProcessing, before, limit)
+	if err != nil {
+		return []model.Withdraw{}, err
+	}
+	defer res.Close()
+
+	var datas []model.Withdraw
+	for res.Next() {
+		data, err := scanWithdraw(res)
 		if err != nil {
 			return []model.Withdraw{}, err
 		}
@@ -235,6 +427,506 @@ func (t *transferRepository) GetWithdraw(id string, page int) ([]model.Withdraw,
 	return datas, nil
 }
 
+// ReclaimWithdraw mengambil alih withdraw yang macet, updated_at dipakai sebagai lease agar hanya satu proses yang mengulang
+func (t *transferRepository) ReclaimWithdraw(id string, updatedAt time.Time) (bool, error) {
+	res, err := t.db.Exec(`UPDATE withdraw_saldo SET status=$1, updated_at=$2 WHERE id=$3 AND status IN ($4, $1) AND updated_at=$5`,
+		model.WithdrawStatusProcessing, time.Now(), id, model.WithdrawStatusRequested, updatedAt)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+// FinishWithdraw menutup withdraw: paid memotong saldo dari hold, failed/cancelled melepas hold
+func (t *transferRepository) FinishWithdraw(id, status, payoutRef, alasan string) (model.Withdraw, error) {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return model.Withdraw{}, err
+	}
+
+	withdraw, err := scanWithdraw(tx.QueryRow(`SELECT `+withdrawColumns+` FROM withdraw_saldo WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+	fromStatus := model.WithdrawStatusProcessing
+	if status == model.WithdrawStatusCancelled {
+		fromStatus = model.WithdrawStatusRequested
+	}
+	if withdraw.Status != fromStatus {
+		tx.Rollback()
+		return model.Withdraw{}, fmt.Errorf("withdraw dengan status %s tidak bisa menjadi %s", withdraw.Status, status)
+	}
+
+	var holdStatus string
+	err = tx.QueryRow(`SELECT status FROM trx_fund_hold WHERE id=$1 FOR UPDATE`, withdraw.HoldId).Scan(&holdStatus)
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+	if holdStatus != model.HoldStatusActive {
+		tx.Rollback()
+		return model.Withdraw{}, fmt.Errorf("hold withdraw %s sudah tidak aktif", withdraw.HoldId)
+	}
+
+	now := time.Now()
+	if status == model.WithdrawStatusPaid {
+		total := withdraw.Withdraw + withdraw.Biaya
+		// hold ditutup lebih dulu agar debit hanya dibatasi oleh hold lain yang masih aktif
+		_, err = tx.Exec(`UPDATE trx_fund_hold SET status=$1, jumlah_captured=$2, updated_at=$3 WHERE id=$4`,
+			model.HoldStatusCaptured, total, now, withdraw.HoldId)
+		if err != nil {
+			tx.Rollback()
+			return model.Withdraw{}, err
+		}
+		err = debitWallet(tx, withdraw.UserId, total)
+		if err != nil {
+			tx.Rollback()
+			return model.Withdraw{}, err
+		}
+		err = postFee(tx, withdraw.UserId, model.FeeOperationWithdraw, withdraw.Id, withdraw.FeeRuleId, withdraw.Biaya)
+	} else {
+		_, err = tx.Exec(`UPDATE trx_fund_hold SET status=$1, updated_at=$2 WHERE id=$3`, model.HoldStatusVoided, now, withdraw.HoldId)
+	}
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+
+	withdraw, err = scanWithdraw(tx.QueryRow(`UPDATE withdraw_saldo SET 
+		status=$1,
+		payout_ref=$2,
+		alasan_gagal=$3,
+		updated_at=$4
+	WHERE id=$5
+	RETURNING `+withdrawColumns, status, payoutRef, alasan, now, id))
+	if err != nil {
+		tx.Rollback()
+		return model.Withdraw{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Withdraw{}, err
+	}
+
+	return withdraw, nil
+}
+
+func (t *transferRepository) GetById(id string) (model.Transfer, error) {
+	var data model.Transfer
+	err := t.db.QueryRow(`SELECT 
+		id,
+		user_id,
+		tujuan_transfer,
+		jumlah_transfer,
+		jenis_transfer,
+		status,
+		COALESCE(reversal_of::text, ''),
+		COALESCE(reversed_by::text, ''),
+		biaya,
+		COALESCE(catatan, ''),
+		COALESCE(referensi, '')
+	FROM 
+		trx_send_transfer
+	WHERE 
+		id = $1`, id).Scan(&data.Id, &data.UserId, &data.TujuanTransfer, &data.JumlahTransfer, &data.JenisTransfer, &data.Status, &data.ReversalOf, &data.ReversedBy,
+		&data.Biaya, &data.Catatan, &data.Referensi)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Transfer{}, fmt.Errorf("transaksi %s tidak ditemukan", id)
+		}
+		return model.Transfer{}, err
+	}
+
+	return data, nil
+}
+
+func (t *transferRepository) CreateReversal(payload model.Reversal) (model.Reversal, error) {
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := t.db.QueryRow(`INSERT INTO trx_reversal (
+		trx_id,
+		requested_by,
+		alasan,
+		policy,
+		require_consent,
+		jumlah_diminta,
+		status,
+		created_at,
+		updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)
+	RETURNING id`, payload.TrxId, payload.RequestedBy, payload.Alasan, payload.Policy, payload.RequireConsent,
+		payload.JumlahDiminta, payload.Status, payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" && pgErr.Constraint == "trx_reversal_active_key" {
+			return model.Reversal{}, fmt.Errorf("transaksi ini sudah atau sedang di-reversal")
+		}
+		return model.Reversal{}, err
+	}
+
+	return payload, nil
+}
+
+func (t *transferRepository) GetReversal(id string) (model.Reversal, error) {
+	var data model.Reversal
+	err := t.db.QueryRow(`SELECT 
+		id,
+		trx_id,
+		requested_by,
+		COALESCE(alasan, ''),
+		policy,
+		require_consent,
+		jumlah_diminta,
+		jumlah_dikembalikan,
+		COALESCE(reversal_trx_id::text, ''),
+		status,
+		created_at,
+		updated_at
+	FROM 
+		trx_reversal
+	WHERE 
+		id = $1`, id).Scan(&data.Id, &data.TrxId, &data.RequestedBy, &data.Alasan, &data.Policy, &data.RequireConsent,
+		&data.JumlahDiminta, &data.JumlahDikembalikan, &data.ReversalTrxId, &data.Status, &data.CreatedAt, &data.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Reversal{}, fmt.Errorf("reversal %s tidak ditemukan", id)
+		}
+		return model.Reversal{}, err
+	}
+
+	return data, nil
+}
+
+// GetActiveReversal mengembalikan reversal yang sedang berjalan atau sudah selesai untuk transaksi,
+// Reversal kosong bila belum ada
+func (t *transferRepository) GetActiveReversal(trxId string) (model.Reversal, error) {
+	var data model.Reversal
+	err := t.db.QueryRow(`SELECT 
+		id,
+		trx_id,
+		requested_by,
+		COALESCE(alasan, ''),
+		policy,
+		require_consent,
+		jumlah_diminta,
+		jumlah_dikembalikan,
+		COALESCE(reversal_trx_id::text, ''),
+		status,
+		created_at,
+		updated_at
+	FROM 
+		trx_reversal
+	WHERE 
+		trx_id = $1 AND status IN ($2, $3, $4)`, trxId, model.ReversalStatusPending, model.ReversalStatusPendingConsent,
+		model.ReversalStatusCompleted).Scan(&data.Id, &data.TrxId, &data.RequestedBy, &data.Alasan, &data.Policy,
+		&data.RequireConsent, &data.JumlahDiminta, &data.JumlahDikembalikan, &data.ReversalTrxId, &data.Status,
+		&data.CreatedAt, &data.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return model.Reversal{}, nil
+	}
+	if err != nil {
+		return model.Reversal{}, err
+	}
+
+	return data, nil
+}
+
+func (t *transferRepository) GetPendingReversal(userId string) ([]model.Reversal, error) {
+	var datas []model.Reversal
+	res, err := t.db.Query(`SELECT 
+		r.id,
+		r.trx_id,
+		r.requested_by,
+		COALESCE(r.alasan, ''),
+		r.policy,
+		r.require_consent,
+		r.jumlah_diminta,
+		r.jumlah_dikembalikan,
+		r.status,
+		r.created_at,
+		r.updated_at
+	FROM 
+		trx_reversal AS r
+	JOIN 
+		trx_send_transfer AS trx ON r.trx_id = trx.id
+	WHERE 
+		trx.tujuan_transfer = $1 AND r.status = $2
+	ORDER BY 
+		r.created_at DESC`, userId, model.ReversalStatusPendingConsent)
+	if err != nil {
+		return []model.Reversal{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var data model.Reversal
+		err := res.Scan(&data.Id, &data.TrxId, &data.RequestedBy, &data.Alasan, &data.Policy, &data.RequireConsent,
+			&data.JumlahDiminta, &data.JumlahDikembalikan, &data.Status, &data.CreatedAt, &data.UpdatedAt)
+		if err != nil {
+			return []model.Reversal{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// UpdateReversalStatus hanya mengubah reversal yang masih menunggu, reversal yang sudah selesai tidak ditimpa
+func (t *transferRepository) UpdateReversalStatus(id, status string) (model.Reversal, error) {
+	res, err := t.db.Exec(`UPDATE trx_reversal SET status=$1, updated_at=$2 WHERE id=$3 AND status IN ($4, $5)`,
+		status, time.Now(), id, model.ReversalStatusPending, model.ReversalStatusPendingConsent)
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return model.Reversal{}, fmt.Errorf("reversal ini sudah diproses")
+	}
+
+	return t.GetReversal(id)
+}
+
+// ExecuteReversal memposting entri kompensasi (penerima -> pengirim asal) dan menandai transaksi asal
+func (t *transferRepository) ExecuteReversal(id string) (model.Reversal, error) {
+	tx, err := t.db.Begin()
+	if err != nil {
+		return model.Reversal{}, err
+	}
+
+	var reversal model.Reversal
+	err = tx.QueryRow(`SELECT id, trx_id, policy, status FROM trx_reversal WHERE id=$1 FOR UPDATE`, id).Scan(
+		&reversal.Id, &reversal.TrxId, &reversal.Policy, &reversal.Status)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	if reversal.Status != model.ReversalStatusPending && reversal.Status != model.ReversalStatusPendingConsent {
+		tx.Rollback()
+		return model.Reversal{}, fmt.Errorf("reversal ini sudah %s", reversal.Status)
+	}
+
+	var original model.Transfer
+	err = tx.QueryRow(`SELECT user_id, tujuan_transfer, jumlah_transfer, status FROM trx_send_transfer WHERE id=$1 FOR UPDATE`, reversal.TrxId).Scan(
+		&original.UserId, &original.TujuanTransfer, &original.JumlahTransfer, &original.Status)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	if original.Status != model.TransferStatusSuccess {
+		tx.Rollback()
+		return model.Reversal{}, fmt.Errorf("transaksi dengan status %s tidak bisa di-reversal", original.Status)
+	}
+
+	err = lockWallets(tx, original.TujuanTransfer, original.UserId)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	// saldo yang ditahan (withdraw, hold, dispute) tidak ikut ditarik kembali
+	var saldoPenerima int
+	err = tx.QueryRow(`SELECT saldo FROM mst_saldo WHERE user_id=$1`, original.TujuanTransfer).Scan(&saldoPenerima)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	held, err := heldAmount(tx, original.TujuanTransfer)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	tersedia := saldoPenerima - held
+
+	jumlah := original.JumlahTransfer
+	status := model.TransferStatusReversed
+	if tersedia < jumlah {
+		if reversal.Policy != model.ReversalPolicyPartial || tersedia <= 0 {
+			tx.Rollback()
+			return model.Reversal{}, fmt.Errorf("saldo tersedia penerima tidak mencukupi untuk reversal %d", jumlah)
+		}
+		jumlah = tersedia
+		status = model.TransferStatusPartiallyReversed
+	}
+
+	now := time.Now()
+	err = tx.QueryRow(`INSERT INTO trx_send_transfer (
+		user_id,
+		tujuan_transfer,
+		jumlah_transfer,
+		jenis_transfer,
+		transfer_at,
+		reversal_of
+		)
+	VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`, original.TujuanTransfer, original.UserId, jumlah, "reversal", now, reversal.TrxId).Scan(&reversal.ReversalTrxId)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	_, err = tx.Exec(`INSERT INTO trx_receive_transfer (
+		user_id,
+		trx_id,
+		tujuan_transfer,
+		jumlah_transfer,
+		jenis_transfer,
+		transfer_at)
+	VALUES ($1,$2,$3,$4,$5,$6)`, original.TujuanTransfer, reversal.ReversalTrxId, original.UserId, jumlah, "reversal", now)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+
+	err = debitWallet(tx, original.TujuanTransfer, jumlah)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	err = creditWallet(tx, original.UserId, jumlah)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	_, err = tx.Exec(`UPDATE trx_send_transfer SET status=$1, reversed_by=$2 WHERE id=$3`, status, reversal.ReversalTrxId, reversal.TrxId)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+	_, err = tx.Exec(`UPDATE trx_reversal SET 
+		status=$1,
+		jumlah_dikembalikan=$2,
+		reversal_trx_id=$3,
+		updated_at=$4
+	WHERE id=$5`, model.ReversalStatusCompleted, jumlah, reversal.ReversalTrxId, now, reversal.Id)
+	if err != nil {
+		tx.Rollback()
+		return model.Reversal{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Reversal{}, err
+	}
+
+	return t.GetReversal(id)
+}
+
+const intentColumns = `id,
+	user_id,
+	tujuan_transfer,
+	jumlah_transfer,
+	biaya,
+	COALESCE(fee_rule_id::text, ''),
+	COALESCE(catatan, ''),
+	COALESCE(referensi, ''),
+	COALESCE(kategori, ''),
+	status,
+	COALESCE(transfer_id::text, ''),
+	expires_at,
+	created_at,
+	updated_at`
+
+func scanIntent(row interface{ Scan(dest ...any) error }) (model.TransferIntent, error) {
+	var data model.TransferIntent
+	err := row.Scan(
+		&data.Id,
+		&data.UserId,
+		&data.TujuanTransfer,
+		&data.JumlahTransfer,
+		&data.Biaya,
+		&data.FeeRuleId,
+		&data.Catatan,
+		&data.Referensi,
+		&data.Kategori,
+		&data.Status,
+		&data.TransferId,
+		&data.ExpiresAt,
+		&data.CreatedAt,
+		&data.UpdatedAt,
+	)
+	data.Total = data.JumlahTransfer + data.Biaya
+	return data, err
+}
+
+func (t *transferRepository) CreateIntent(payload model.TransferIntent) (model.TransferIntent, error) {
+	var feeRuleId any
+	if payload.FeeRuleId != "" {
+		feeRuleId = payload.FeeRuleId
+	}
+	now := time.Now()
+	return scanIntent(t.db.QueryRow(`INSERT INTO trx_transfer_intent (
+		user_id,
+		tujuan_transfer,
+		jumlah_transfer,
+		biaya,
+		fee_rule_id,
+		catatan,
+		referensi,
+		kategori,
+		status,
+		expires_at,
+		created_at,
+		updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+	RETURNING `+intentColumns, payload.UserId, payload.TujuanTransfer, payload.JumlahTransfer, payload.Biaya,
+		feeRuleId, nullString(payload.Catatan), nullString(payload.Referensi), nullString(payload.Kategori),
+		model.IntentStatusPending, payload.ExpiresAt, now, now))
+}
+
+func (t *transferRepository) GetIntent(id string) (model.TransferIntent, error) {
+	data, err := scanIntent(t.db.QueryRow(`SELECT `+intentColumns+` FROM trx_transfer_intent WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.TransferIntent{}, fmt.Errorf("intent transfer %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+// ClaimIntent mengunci intent yang masih pending dan belum kedaluwarsa, false jika sudah dipakai
+func (t *transferRepository) ClaimIntent(id string) (bool, error) {
+	now := time.Now()
+	res, err := t.db.Exec(`UPDATE trx_transfer_intent SET status=$1, updated_at=$2 WHERE id=$3 AND status=$4 AND expires_at > $2`,
+		model.IntentStatusProcessing, now, id, model.IntentStatusPending)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func (t *transferRepository) FinishIntent(id, status, transferId string) error {
+	var trxId any
+	if transferId != "" {
+		trxId = transferId
+	}
+	_, err := t.db.Exec(`UPDATE trx_transfer_intent SET status=$1, transfer_id=$2, updated_at=$3 WHERE id=$4`,
+		status, trxId, time.Now(), id)
+	return err
+}
+
+// UpdateCategory mengubah kategori milik pemanggil: kategori pengirim atau kategori penerima
+func (t *transferRepository) UpdateCategory(trxId, userId, kategori string) (model.Transfer, error) {
+	res, err := t.db.Exec(`UPDATE trx_send_transfer SET kategori_pengirim=$1 WHERE id=$2 AND user_id=$3`, kategori, trxId, userId)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		res, err = t.db.Exec(`UPDATE trx_receive_transfer SET kategori_penerima=$1 WHERE trx_id=$2 AND tujuan_transfer=$3`, kategori, trxId, userId)
+		if err != nil {
+			return model.Transfer{}, err
+		}
+		if affected, _ := res.RowsAffected(); affected == 0 {
+			return model.Transfer{}, fmt.Errorf("transaksi %s tidak ditemukan", trxId)
+		}
+	}
+
+	data, err := t.GetById(trxId)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	data.Kategori = kategori
+
+	return data, nil
+}
+
 func NewTransferRepository(db *sql.DB) TransferRepository {
 	return &transferRepository{db: db}
 }
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type TransferRepositoryTestSuite struct {
+	suite.Suite
+	mockDB  *sql.DB
+	mockSql sqlmock.Sqlmock
+	repo    TransferRepository
+}
+
+func (suite *TransferRepositoryTestSuite) SetupTest() {
+	db, mock, err := sqlmock.New()
+	assert.NoError(suite.T(), err)
+	suite.mockDB = db
+	suite.mockSql = mock
+	suite.repo = NewTransferRepository(suite.mockDB)
+}
+
+func TestTransferRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferRepositoryTestSuite))
+}
+
+func (suite *TransferRepositoryTestSuite) TestExecuteReversal_HeldBalanceNotReversed() {
+	suite.mockSql.ExpectBegin()
+	suite.mockSql.ExpectQuery("SELECT id, trx_id, policy, status FROM trx_reversal").WithArgs("rev-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "trx_id", "policy", "status"}).
+			AddRow("rev-1", "trx-1", model.ReversalPolicyReject, model.ReversalStatusPending))
+	suite.mockSql.ExpectQuery("SELECT user_id, tujuan_transfer, jumlah_transfer, status FROM trx_send_transfer").WithArgs("trx-1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "tujuan_transfer", "jumlah_transfer", "status"}).
+			AddRow("a", "b", 50000, model.TransferStatusSuccess))
+	suite.mockSql.ExpectQuery("SELECT user_id FROM mst_saldo").WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("a").AddRow("b"))
+	suite.mockSql.ExpectQuery("SELECT saldo FROM mst_saldo").WithArgs("b").WillReturnRows(sqlmock.NewRows([]string{"saldo"}).AddRow(100000))
+	suite.mockSql.ExpectQuery("SELECT COALESCE\\(SUM\\(jumlah\\), 0\\) FROM trx_fund_hold").WithArgs("b", model.HoldStatusActive).
+		WillReturnRows(sqlmock.NewRows([]string{"sum"}).AddRow(80000))
+	suite.mockSql.ExpectRollback()
+
+	_, err := suite.repo.ExecuteReversal("rev-1")
+	assert.EqualError(suite.T(), err, "saldo tersedia penerima tidak mencukupi untuk reversal 50000")
+	assert.NoError(suite.T(), suite.mockSql.ExpectationsWereMet())
+}
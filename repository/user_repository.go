@@ -15,12 +15,20 @@ type UserRepository interface {
 	Create(payload dto.UserRequestDto) (model.User, error)
 	GetBalance(user_id string) (model.UserSaldo, error)
 	GetByUsername(username string) (model.User, error)
+	GetByRecipient(query, phone string) (model.User, error)
+	GetByPaymentTag(tag string) (model.User, error)
+	UpdatePaymentTag(id, tag string) (model.User, error)
 	Update(id string, payload model.User) (model.User, error)
 	Verify(payload dto.VerifyUser) (dto.VerifyUser, error)
 	UpdatePin(payload dto.UpdatePinRequest) (dto.UpdatePinResponse, error)
 	GetInfoUser(Info string, limit, offset int) ([]model.User, error)
 	GetRekening(id string) (model.Rekening, error)
+	GetRekeningById(id string) (model.Rekening, error)
+	GetAllRekening(userId string) ([]model.Rekening, error)
 	CreateRekening(payload model.Rekening) (model.Rekening, error)
+	SetDefaultRekening(id, userId string) (model.Rekening, error)
+	DeleteRekening(id, userId string) error
+	VerifyRekening(id string) (model.Rekening, error)
 }
 
 type userRepository struct {
@@ -122,6 +130,98 @@ func (u *userRepository) GetByUsername(username string) (model.User, error) {
 	return user, nil
 }
 
+func (u *userRepository) GetByRecipient(query, phone string) (model.User, error) {
+	var user model.User
+	err := u.db.QueryRow(`
+  SELECT
+    id, name, username, COALESCE(payment_tag, ''), role, email, phone_number, created_at, updated_at
+  FROM
+    mst_user
+  WHERE
+    role = 'user' AND (LOWER(payment_tag) = LOWER($1) OR LOWER(username) = LOWER($1)
+    OR phone_number IN ($1, $2) OR LOWER(email) = LOWER($1))
+  ORDER BY
+    CASE WHEN LOWER(username) = LOWER($1) THEN 0 WHEN LOWER(payment_tag) = LOWER($1) THEN 2 ELSE 1 END
+  LIMIT 1
+    `, query, phone,
+	).Scan(
+		&user.Id,
+		&user.Name,
+		&user.Username,
+		&user.PaymentTag,
+		&user.Role,
+		&user.Email,
+		&user.PhoneNumber,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (u *userRepository) GetByPaymentTag(tag string) (model.User, error) {
+	var user model.User
+	err := u.db.QueryRow(`
+  SELECT
+    id, name, username, payment_tag, role, email, phone_number, created_at, updated_at
+  FROM
+    mst_user
+  WHERE
+    role = 'user' AND LOWER(payment_tag) = LOWER($1)
+    `, tag,
+	).Scan(
+		&user.Id,
+		&user.Name,
+		&user.Username,
+		&user.PaymentTag,
+		&user.Role,
+		&user.Email,
+		&user.PhoneNumber,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (u *userRepository) UpdatePaymentTag(id, tag string) (model.User, error) {
+	var user model.User
+	err := u.db.QueryRow(`
+  UPDATE mst_user SET payment_tag = $2, updated_at = $3
+  WHERE id = $1 AND NOT EXISTS (
+    SELECT 1 FROM mst_user WHERE id <> $1 AND (LOWER(username) = $2 OR LOWER(email) = $2 OR phone_number = $2)
+  )
+  RETURNING id, name, username, payment_tag, role, email, phone_number, created_at, updated_at
+    `, id, tag, time.Now(),
+	).Scan(
+		&user.Id,
+		&user.Name,
+		&user.Username,
+		&user.PaymentTag,
+		&user.Role,
+		&user.Email,
+		&user.PhoneNumber,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.User{}, fmt.Errorf("payment tag sudah dipakai")
+		}
+		// tag yang sama dengan username, email atau nomor hp user lain ditolak agar tidak membajak penerima
+		if err == sql.ErrNoRows {
+			return model.User{}, fmt.Errorf("payment tag sudah dipakai")
+		}
+		return model.User{}, err
+	}
+	return user, nil
+}
+
 func (u *userRepository) GetBalance(user_id string) (model.UserSaldo, error) {
 	var response model.UserSaldo
 
@@ -133,7 +233,10 @@ func (u *userRepository) GetBalance(user_id string) (model.UserSaldo, error) {
 		u.created_at,
 		u.updated_at,
 		s.saldo,
-		s.pin
+		s.pin,
+		COALESCE((SELECT SUM(h.jumlah) FROM trx_fund_hold AS h 
+			WHERE h.user_id = u.id AND h.status = 'active' AND (h.expires_at IS NULL OR h.expires_at > NOW())), 0),
+		COALESCE((SELECT SUM(p.saldo) FROM mst_pocket AS p WHERE p.user_id = u.id AND p.status = 'active'), 0)
 	FROM 
    		 mst_user AS u
 	LEFT JOIN 
@@ -148,6 +251,8 @@ func (u *userRepository) GetBalance(user_id string) (model.UserSaldo, error) {
 		&response.User.UpdatedAt,
 		&response.Saldo,
 		&response.Pin,
+		&response.SaldoTertahan,
+		&response.SaldoPocket,
 	)
 	if response.Pin == "" {
 		return model.UserSaldo{}, fmt.Errorf("1")
@@ -155,6 +260,7 @@ func (u *userRepository) GetBalance(user_id string) (model.UserSaldo, error) {
 	if err != nil {
 		return model.UserSaldo{}, err
 	}
+	response.SaldoTersedia = response.Saldo - response.SaldoTertahan
 
 	return response, nil
 }
@@ -300,14 +406,26 @@ func (u *userRepository) UpdatePin(payload dto.UpdatePinRequest) (dto.UpdatePinR
 	return response, nil
 }
 
+const rekeningColumns = `id,
+		user_id,
+		bank_code,
+		rekening,
+		nama_pemilik,
+		is_default,
+		is_verified,
+		created_at,
+		updated_at`
+
+func scanRekening(row interface{ Scan(dest ...any) error }) (model.Rekening, error) {
+	var data model.Rekening
+	err := row.Scan(&data.Id, &data.UserId, &data.BankCode, &data.Rekening, &data.NamaPemilik, &data.IsDefault,
+		&data.IsVerified, &data.Created_at, &data.Updated_at)
+	return data, err
+}
+
+// GetRekening mengambil rekening default milik user
 func (u *userRepository) GetRekening(id string) (model.Rekening, error) {
-	response := model.Rekening{}
-	err := u.db.QueryRow(`SELECT * FROM mst_rekening_user WHERE user_id = $1`, id).Scan(&response.Id,
-		&response.UserId,
-		&response.Rekening,
-		&response.Created_at,
-		&response.Updated_at,
-	)
+	response, err := scanRekening(u.db.QueryRow(`SELECT `+rekeningColumns+` FROM mst_rekening_user WHERE user_id = $1 AND is_default`, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return model.Rekening{}, fmt.Errorf("1")
@@ -318,17 +436,118 @@ func (u *userRepository) GetRekening(id string) (model.Rekening, error) {
 	return response, nil
 }
 
+func (u *userRepository) GetRekeningById(id string) (model.Rekening, error) {
+	response, err := scanRekening(u.db.QueryRow(`SELECT `+rekeningColumns+` FROM mst_rekening_user WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return model.Rekening{}, fmt.Errorf("1")
+		}
+		return model.Rekening{}, err
+	}
+
+	return response, nil
+}
+
+func (u *userRepository) GetAllRekening(userId string) ([]model.Rekening, error) {
+	var datas []model.Rekening
+	res, err := u.db.Query(`SELECT `+rekeningColumns+` FROM mst_rekening_user WHERE user_id = $1 ORDER BY is_default DESC, created_at DESC`, userId)
+	if err != nil {
+		return []model.Rekening{}, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		data, err := scanRekening(res)
+		if err != nil {
+			return []model.Rekening{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+// CreateRekening otomatis menjadikan rekening pertama user sebagai default
 func (u *userRepository) CreateRekening(payload model.Rekening) (model.Rekening, error) {
-	err := u.db.QueryRow(`INSERT INTO mst_rekening_user (user_id,rekening,created_at,updated_at)
+	response, err := scanRekening(u.db.QueryRow(`INSERT INTO mst_rekening_user (user_id,bank_code,rekening,nama_pemilik,is_default,created_at,updated_at)
 	VALUES
-		($1,$2,$3,$4)
-	RETURNING id,created_at,updated_at
-	`, payload.UserId, payload.Rekening, time.Now(), time.Now()).Scan(&payload.Id, &payload.Created_at, &payload.Updated_at)
+		($1,$2,$3,$4,NOT EXISTS (SELECT 1 FROM mst_rekening_user WHERE user_id = $1),$5,$6)
+	RETURNING `+rekeningColumns, payload.UserId, payload.BankCode, payload.Rekening, payload.NamaPemilik, time.Now(), time.Now()))
 	if err != nil {
+		pgErr, ok := err.(*pq.Error)
+		if ok && pgErr.Code == "23505" {
+			return model.Rekening{}, fmt.Errorf("rekening sudah terdaftar")
+		}
 		return model.Rekening{}, err
 	}
 
-	return payload, nil
+	return response, nil
+}
+
+func (u *userRepository) SetDefaultRekening(id, userId string) (model.Rekening, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return model.Rekening{}, err
+	}
+	_, err = tx.Exec(`UPDATE mst_rekening_user SET is_default = FALSE, updated_at = $1 WHERE user_id = $2 AND is_default`, time.Now(), userId)
+	if err != nil {
+		tx.Rollback()
+		return model.Rekening{}, err
+	}
+	response, err := scanRekening(tx.QueryRow(`UPDATE mst_rekening_user SET is_default = TRUE, updated_at = $1 
+	WHERE id = $2 AND user_id = $3 
+	RETURNING `+rekeningColumns, time.Now(), id, userId))
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return model.Rekening{}, fmt.Errorf("rekening %s tidak ditemukan", id)
+		}
+		return model.Rekening{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Rekening{}, err
+	}
+
+	return response, nil
+}
+
+// DeleteRekening menghapus rekening, jika yang dihapus default maka rekening terbaru jadi default
+func (u *userRepository) DeleteRekening(id, userId string) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	var isDefault bool
+	err = tx.QueryRow(`DELETE FROM mst_rekening_user WHERE id = $1 AND user_id = $2 RETURNING is_default`, id, userId).Scan(&isDefault)
+	if err != nil {
+		tx.Rollback()
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("rekening %s tidak ditemukan", id)
+		}
+		return err
+	}
+	if isDefault {
+		_, err = tx.Exec(`UPDATE mst_rekening_user SET is_default = TRUE, updated_at = $1 
+		WHERE id = (SELECT id FROM mst_rekening_user WHERE user_id = $2 ORDER BY created_at DESC LIMIT 1)`, time.Now(), userId)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (u *userRepository) VerifyRekening(id string) (model.Rekening, error) {
+	response, err := scanRekening(u.db.QueryRow(`UPDATE mst_rekening_user SET is_verified = TRUE, updated_at = $1 
+	WHERE id = $2 
+	RETURNING `+rekeningColumns, time.Now(), id))
+	if err != nil {
+		return model.Rekening{}, err
+	}
+
+	return response, nil
 }
 
 func NewUserRepository(db *sql.DB) UserRepository {
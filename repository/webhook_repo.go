@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(payload model.WebhookEndpoint) (model.WebhookEndpoint, error)
+	GetEndpoint(id string) (model.WebhookEndpoint, error)
+	GetEndpoints(merchantId string) ([]model.WebhookEndpoint, error)
+	GetSubscribedEndpoints(merchantId, eventType string) ([]model.WebhookEndpoint, error)
+	UpdateEndpoint(payload model.WebhookEndpoint) (model.WebhookEndpoint, error)
+	CreateDeliveries(payload []model.WebhookDelivery) ([]model.WebhookDelivery, error)
+	GetDelivery(id string) (model.WebhookDelivery, error)
+	GetDeliveries(endpointId, status string, page int) ([]model.WebhookDelivery, error)
+	GetDue(now time.Time, limit int) ([]model.WebhookDelivery, error)
+	ClaimDue(id string, dueAt, leaseUntil time.Time) (bool, error)
+	RecordAttempt(payload model.WebhookDelivery, attempt model.WebhookAttempt) error
+}
+
+type webhookRepository struct {
+	db *sql.DB
+}
+
+const webhookEndpointColumns = `id,
+		merchant_id,
+		url,
+		secret_enc,
+		events,
+		is_active,
+		created_at,
+		updated_at`
+
+func scanWebhookEndpoint(row interface{ Scan(dest ...any) error }) (model.WebhookEndpoint, error) {
+	var data model.WebhookEndpoint
+	err := row.Scan(&data.Id, &data.MerchantId, &data.Url, &data.SecretEnc, pq.Array(&data.Events), &data.IsActive,
+		&data.CreatedAt, &data.UpdatedAt)
+	return data, err
+}
+
+const webhookDeliveryColumns = `id,
+		endpoint_id,
+		event_id,
+		event_type,
+		payload,
+		status,
+		attempts,
+		next_attempt_at,
+		COALESCE(last_status_code, 0),
+		COALESCE(last_error, ''),
+		delivered_at,
+		created_at,
+		updated_at`
+
+func scanWebhookDelivery(row interface{ Scan(dest ...any) error }) (model.WebhookDelivery, error) {
+	var data model.WebhookDelivery
+	var payload []byte
+	var deliveredAt sql.NullTime
+	err := row.Scan(&data.Id, &data.EndpointId, &data.EventId, &data.EventType, &payload, &data.Status, &data.Attempts,
+		&data.NextAttemptAt, &data.LastStatusCode, &data.LastError, &deliveredAt, &data.CreatedAt, &data.UpdatedAt)
+	data.Payload = payload
+	if deliveredAt.Valid {
+		data.DeliveredAt = &deliveredAt.Time
+	}
+	return data, err
+}
+
+func (w *webhookRepository) CreateEndpoint(payload model.WebhookEndpoint) (model.WebhookEndpoint, error) {
+	payload.IsActive = true
+	payload.CreatedAt = time.Now()
+	payload.UpdatedAt = time.Now()
+	err := w.db.QueryRow(`INSERT INTO mst_webhook_endpoint (merchant_id, url, secret_enc, events, is_active, created_at, updated_at)
+	VALUES ($1,$2,$3,$4,$5,$6,$7)
+	RETURNING id`, payload.MerchantId, payload.Url, payload.SecretEnc, pq.Array(payload.Events), payload.IsActive,
+		payload.CreatedAt, payload.UpdatedAt).Scan(&payload.Id)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	return payload, nil
+}
+
+func (w *webhookRepository) GetEndpoint(id string) (model.WebhookEndpoint, error) {
+	data, err := scanWebhookEndpoint(w.db.QueryRow(`SELECT `+webhookEndpointColumns+` FROM mst_webhook_endpoint WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.WebhookEndpoint{}, fmt.Errorf("webhook %s tidak ditemukan", id)
+	}
+	return data, err
+}
+
+func (w *webhookRepository) queryEndpoints(query string, args ...any) ([]model.WebhookEndpoint, error) {
+	var datas []model.WebhookEndpoint
+	rows, err := w.db.Query(query, args...)
+	if err != nil {
+		return []model.WebhookEndpoint{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanWebhookEndpoint(rows)
+		if err != nil {
+			return []model.WebhookEndpoint{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (w *webhookRepository) GetEndpoints(merchantId string) ([]model.WebhookEndpoint, error) {
+	return w.queryEndpoints(`SELECT `+webhookEndpointColumns+` FROM mst_webhook_endpoint WHERE merchant_id = $1
+	ORDER BY created_at DESC`, merchantId)
+}
+
+func (w *webhookRepository) GetSubscribedEndpoints(merchantId, eventType string) ([]model.WebhookEndpoint, error) {
+	return w.queryEndpoints(`SELECT `+webhookEndpointColumns+` FROM mst_webhook_endpoint 
+	WHERE merchant_id = $1 AND is_active AND (cardinality(events) = 0 OR $2 = ANY(events))`, merchantId, eventType)
+}
+
+func (w *webhookRepository) UpdateEndpoint(payload model.WebhookEndpoint) (model.WebhookEndpoint, error) {
+	_, err := w.db.Exec(`UPDATE mst_webhook_endpoint SET url=$1, events=$2, is_active=$3, updated_at=$4 WHERE id=$5`,
+		payload.Url, pq.Array(payload.Events), payload.IsActive, time.Now(), payload.Id)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	return w.GetEndpoint(payload.Id)
+}
+
+func (w *webhookRepository) CreateDeliveries(payload []model.WebhookDelivery) ([]model.WebhookDelivery, error) {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i := range payload {
+		payload[i].Status = model.WebhookDeliveryPending
+		payload[i].CreatedAt = time.Now()
+		payload[i].UpdatedAt = time.Now()
+		err := tx.QueryRow(`INSERT INTO trx_webhook_delivery (endpoint_id, event_id, event_type, payload, status, next_attempt_at, created_at, updated_at)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+		RETURNING id`, payload[i].EndpointId, payload[i].EventId, payload[i].EventType, []byte(payload[i].Payload),
+			payload[i].Status, payload[i].NextAttemptAt, payload[i].CreatedAt, payload[i].UpdatedAt).Scan(&payload[i].Id)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// GetDelivery mengembalikan delivery beserta log setiap percobaan pengiriman
+func (w *webhookRepository) GetDelivery(id string) (model.WebhookDelivery, error) {
+	data, err := scanWebhookDelivery(w.db.QueryRow(`SELECT `+webhookDeliveryColumns+` FROM trx_webhook_delivery WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return model.WebhookDelivery{}, fmt.Errorf("pengiriman webhook %s tidak ditemukan", id)
+	}
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	rows, err := w.db.Query(`SELECT attempt, COALESCE(status_code, 0), COALESCE(error, ''), duration_ms, created_at
+	FROM trx_webhook_attempt WHERE delivery_id = $1 ORDER BY created_at`, id)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt model.WebhookAttempt
+		err := rows.Scan(&attempt.Attempt, &attempt.StatusCode, &attempt.Error, &attempt.DurationMs, &attempt.CreatedAt)
+		if err != nil {
+			return model.WebhookDelivery{}, err
+		}
+		data.Log = append(data.Log, attempt)
+	}
+
+	return data, nil
+}
+
+func (w *webhookRepository) queryDeliveries(query string, args ...any) ([]model.WebhookDelivery, error) {
+	var datas []model.WebhookDelivery
+	rows, err := w.db.Query(query, args...)
+	if err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		data, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return []model.WebhookDelivery{}, err
+		}
+		datas = append(datas, data)
+	}
+
+	return datas, nil
+}
+
+func (w *webhookRepository) GetDeliveries(endpointId, status string, page int) ([]model.WebhookDelivery, error) {
+	paging := 10
+	offset := (paging * page) - paging
+
+	return w.queryDeliveries(`SELECT `+webhookDeliveryColumns+` FROM trx_webhook_delivery 
+	WHERE endpoint_id = $1 AND ($2 = '' OR status = $2)
+	ORDER BY created_at DESC LIMIT $3 OFFSET $4`, endpointId, status, paging, offset)
+}
+
+func (w *webhookRepository) GetDue(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	return w.queryDeliveries(`SELECT `+webhookDeliveryColumns+` FROM trx_webhook_delivery 
+	WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT $3`, model.WebhookDeliveryPending, now, limit)
+}
+
+// ClaimDue menggeser next_attempt_at sebagai lease agar delivery tidak dikirim dua kali oleh dispatcher lain
+func (w *webhookRepository) ClaimDue(id string, dueAt, leaseUntil time.Time) (bool, error) {
+	res, err := w.db.Exec(`UPDATE trx_webhook_delivery SET next_attempt_at=$1 WHERE id=$2 AND status=$3 AND next_attempt_at=$4`,
+		leaseUntil, id, model.WebhookDeliveryPending, dueAt)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+
+	return affected > 0, nil
+}
+
+func (w *webhookRepository) RecordAttempt(payload model.WebhookDelivery, attempt model.WebhookAttempt) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var statusCode any
+	if attempt.StatusCode != 0 {
+		statusCode = attempt.StatusCode
+	}
+	_, err = tx.Exec(`INSERT INTO trx_webhook_attempt (delivery_id, attempt, status_code, error, duration_ms, created_at)
+	VALUES ($1,$2,$3,$4,$5,$6)`, payload.Id, attempt.Attempt, statusCode, nullString(attempt.Error), attempt.DurationMs,
+		attempt.CreatedAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`UPDATE trx_webhook_delivery SET status=$1, attempts=$2, next_attempt_at=$3, last_status_code=$4,
+	last_error=$5, delivered_at=$6, updated_at=$7 WHERE id=$8`, payload.Status, payload.Attempts, payload.NextAttemptAt,
+		statusCode, nullString(payload.LastError), payload.DeliveredAt, time.Now(), payload.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+// jumlah pihak teratas yang ditampilkan
+const analyticsTopCounterparty = 10
+
+type AnalyticsUseCase interface {
+	GetAnalytics(userId, dari, sampai, periode string) (model.SpendingAnalytics, error)
+}
+
+type analyticsUseCase struct {
+	repo repository.AnalyticsRepository
+}
+
+func percentChange(now, before int) *float64 {
+	if before == 0 {
+		return nil
+	}
+	change := float64(now-before) / float64(before) * 100
+	return &change
+}
+
+// GetAnalytics merangkum arus dana user per kategori, per pihak dan per periode beserta perbandingan bulanan
+func (a *analyticsUseCase) GetAnalytics(userId, dari, sampai, periode string) (model.SpendingAnalytics, error) {
+	start, end, err := parsePeriod(dari, sampai)
+	if err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+	if periode == "" {
+		periode = model.AnalyticsPeriodDaily
+	}
+	if periode != model.AnalyticsPeriodDaily && periode != model.AnalyticsPeriodWeekly && periode != model.AnalyticsPeriodMonthly {
+		return model.SpendingAnalytics{}, fmt.Errorf("period harus daily, weekly atau monthly")
+	}
+	endExclusive := end.AddDate(0, 0, 1)
+
+	result := model.SpendingAnalytics{Dari: start, Sampai: end, Periode: periode}
+	if result.Total, err = a.repo.GetTotals(userId, start, endExclusive); err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+	result.Net = result.Total.Masuk - result.Total.Keluar
+	if result.PerKategori, err = a.repo.GetByCategory(userId, start, endExclusive); err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+	if result.PerPihak, err = a.repo.GetByCounterparty(userId, start, endExclusive, analyticsTopCounterparty); err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+	if result.PerPeriode, err = a.repo.GetByPeriod(userId, start, endExclusive, periode); err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+
+	bulanIni := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+	bulanLalu := bulanIni.AddDate(0, -1, 0)
+	if result.Perbandingan.BulanIni, err = a.repo.GetTotals(userId, bulanIni, bulanIni.AddDate(0, 1, 0)); err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+	if result.Perbandingan.BulanLalu, err = a.repo.GetTotals(userId, bulanLalu, bulanIni); err != nil {
+		return model.SpendingAnalytics{}, err
+	}
+	result.Perbandingan.BulanIni.Label = bulanIni.Format("2006-01")
+	result.Perbandingan.BulanLalu.Label = bulanLalu.Format("2006-01")
+	result.Perbandingan.PerubahanMasuk = percentChange(result.Perbandingan.BulanIni.Masuk, result.Perbandingan.BulanLalu.Masuk)
+	result.Perbandingan.PerubahanKeluar = percentChange(result.Perbandingan.BulanIni.Keluar, result.Perbandingan.BulanLalu.Keluar)
+
+	return result, nil
+}
+
+func NewAnalyticsUseCase(repo repository.AnalyticsRepository) AnalyticsUseCase {
+	return &analyticsUseCase{repo: repo}
+}
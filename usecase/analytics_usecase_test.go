@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type AnalyticsUseCaseTestSuite struct {
+	suite.Suite
+	arm *repomock.AnalyticsRepoMock
+	ua  AnalyticsUseCase
+}
+
+func (suite *AnalyticsUseCaseTestSuite) SetupTest() {
+	suite.arm = new(repomock.AnalyticsRepoMock)
+	suite.ua = NewAnalyticsUseCase(suite.arm)
+}
+
+func TestAnalyticsUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AnalyticsUseCaseTestSuite))
+}
+
+func (suite *AnalyticsUseCaseTestSuite) TestGetAnalytics() {
+	dari := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	sampai := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	suite.arm.On("GetTotals", "u-1", dari, sampai).Return(model.AnalyticsBucket{Masuk: 300000, Keluar: 120000, JumlahTransaksi: 7}, nil)
+	suite.arm.On("GetTotals", "u-1", april, dari).Return(model.AnalyticsBucket{Masuk: 0, Keluar: 80000}, nil)
+	suite.arm.On("GetByCategory", "u-1", dari, sampai).Return([]model.AnalyticsBucket{{Label: "makan", Keluar: 70000}}, nil)
+	suite.arm.On("GetByCounterparty", "u-1", dari, sampai, analyticsTopCounterparty).Return([]model.AnalyticsBucket{{Label: "Ani", Keluar: 50000}}, nil)
+	suite.arm.On("GetByPeriod", "u-1", dari, sampai, model.AnalyticsPeriodWeekly).Return([]model.AnalyticsBucket{}, nil)
+
+	result, err := suite.ua.GetAnalytics("u-1", "2024-05-01", "2024-05-31", "weekly")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 180000, result.Net)
+	assert.Equal(suite.T(), "makan", result.PerKategori[0].Label)
+	assert.Equal(suite.T(), "2024-05", result.Perbandingan.BulanIni.Label)
+	assert.Equal(suite.T(), "2024-04", result.Perbandingan.BulanLalu.Label)
+	assert.Nil(suite.T(), result.Perbandingan.PerubahanMasuk)
+	assert.InDelta(suite.T(), 50.0, *result.Perbandingan.PerubahanKeluar, 0.001)
+}
+
+func (suite *AnalyticsUseCaseTestSuite) TestGetAnalytics_InvalidPeriod() {
+	_, err := suite.ua.GetAnalytics("u-1", "2024-05-01", "2024-05-31", "yearly")
+	assert.Error(suite.T(), err)
+	suite.arm.AssertNotCalled(suite.T(), "GetTotals", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+// batas baris per batch transfer
+const batchMaxRows = 500
+
+type BatchUseCase interface {
+	CreateBatch(userId, mode string, rows []dto.BatchRow) (model.BatchTransfer, error)
+	FindBatches(userId string) ([]model.BatchTransfer, error)
+	FindBatch(id, userId string) (model.BatchTransfer, error)
+	ConfirmBatch(id, userId string) (model.BatchTransfer, error)
+}
+
+type batchUseCase struct {
+	repo   repository.BatchRepository
+	uc     UserUseCase
+	fee    FeeUseCase
+	budget BudgetUseCase
+	family FamilyUseCase
+}
+
+// CreateBatch memvalidasi tiap baris (penerima, jumlah, biaya) dan menyimpan hasilnya tanpa memindahkan saldo
+func (b *batchUseCase) CreateBatch(userId, mode string, rows []dto.BatchRow) (model.BatchTransfer, error) {
+	if mode == "" {
+		mode = model.BatchModeAllOrNothing
+	}
+	if mode != model.BatchModeAllOrNothing && mode != model.BatchModeBestEffort {
+		return model.BatchTransfer{}, fmt.Errorf("mode harus all_or_nothing atau best_effort")
+	}
+	if len(rows) == 0 {
+		return model.BatchTransfer{}, fmt.Errorf("batch transfer tidak memiliki baris")
+	}
+	if len(rows) > batchMaxRows {
+		return model.BatchTransfer{}, fmt.Errorf("batch transfer maksimal %d baris", batchMaxRows)
+	}
+	balance, err := b.uc.GetBalanceCase(userId)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+
+	batch := model.BatchTransfer{UserId: userId, Mode: mode, Status: model.BatchStatusValidated}
+	// baris valid sebelumnya ikut mengurangi kuota gratis agar satu batch tidak memakai kuota yang sama berulang kali
+	valid := 0
+	for i, row := range rows {
+		item := b.validateRow(userId, row, valid)
+		item.Baris = i + 1
+		batch.Items = append(batch.Items, item)
+		if item.Status == model.BatchItemValid {
+			valid++
+		}
+	}
+	summarizeBatchItems(&batch)
+	if batch.JumlahValid == 0 || (mode == model.BatchModeAllOrNothing && batch.JumlahValid < batch.JumlahItem) {
+		batch.Status = model.BatchStatusInvalid
+	}
+
+	batch, err = b.repo.Create(batch)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	summarizeBatchItems(&batch)
+	batch.SaldoTersedia = balance.SaldoTersedia
+
+	return batch, nil
+}
+
+func (b *batchUseCase) validateRow(userId string, row dto.BatchRow, terpakai int) model.BatchItem {
+	item := model.BatchItem{
+		Tujuan:  strings.TrimSpace(row.Tujuan),
+		Jumlah:  row.Jumlah,
+		Catatan: strings.TrimSpace(row.Catatan),
+		Status:  model.BatchItemInvalid,
+	}
+	switch {
+	case row.Error != "":
+		item.Error = row.Error
+	case item.Tujuan == "":
+		item.Error = "tujuan harus diisi"
+	case item.Jumlah <= 0:
+		item.Error = "jumlah harus lebih dari 0"
+	case len(item.Catatan) > 250:
+		item.Error = "catatan maksimal 250 karakter"
+	}
+	if item.Error != "" {
+		return item
+	}
+
+	receive, err := b.uc.ResolveRecipient(item.Tujuan)
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+	if receive.Id == userId {
+		item.Error = "tidak dapat transfer ke akun sendiri"
+		return item
+	}
+	if _, err := b.uc.GetBalanceCase(receive.Id); err != nil {
+		item.Error = "penerima belum memverifikasi akun"
+		return item
+	}
+	quote, err := b.fee.Quote(dto.FeeQuoteRequest{UserId: userId, Operation: model.FeeOperationTransfer, Jumlah: item.Jumlah,
+		Terpakai: terpakai})
+	if err != nil {
+		item.Error = err.Error()
+		return item
+	}
+	item.TujuanTransfer = receive.Id
+	item.NamaPenerima = maskName(receive.Name)
+	item.Biaya = quote.Biaya
+	item.FeeRuleId = quote.RuleId
+	item.Status = model.BatchItemValid
+
+	return item
+}
+
+func (b *batchUseCase) FindBatches(userId string) ([]model.BatchTransfer, error) {
+	datas, err := b.repo.GetByUser(userId)
+	if err != nil {
+		return []model.BatchTransfer{}, err
+	}
+
+	return datas, nil
+}
+
+func (b *batchUseCase) FindBatch(id, userId string) (model.BatchTransfer, error) {
+	batch, err := b.repo.Get(id)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	if batch.UserId != userId {
+		return model.BatchTransfer{}, fmt.Errorf("batch transfer %s tidak ditemukan", id)
+	}
+	summarizeBatchItems(&batch)
+
+	return batch, nil
+}
+
+// ConfirmBatch mengeksekusi batch yang sudah divalidasi, PIN dicek sekali di controller untuk seluruh batch
+func (b *batchUseCase) ConfirmBatch(id, userId string) (model.BatchTransfer, error) {
+	batch, err := b.FindBatch(id, userId)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	if batch.Status != model.BatchStatusValidated {
+		return model.BatchTransfer{}, fmt.Errorf("batch transfer berstatus %s, tidak bisa dieksekusi", batch.Status)
+	}
+	claimed, err := b.repo.Claim(id)
+	if err != nil {
+		return model.BatchTransfer{}, err
+	}
+	if !claimed {
+		return model.BatchTransfer{}, fmt.Errorf("batch transfer sedang atau sudah diproses")
+	}
+	// batch yang ditolak kontrol keluarga dikembalikan ke validated agar bisa dikonfirmasi lagi setelah disetujui
+	keterangan := fmt.Sprintf("batch transfer %d penerima", batch.JumlahValid)
+	reservation, err := b.family.Authorize(dto.FamilySpendRequest{UserId: userId, Jenis: model.FamilySpendTransfer, Jumlah: batch.TotalJumlah,
+		Tujuan: batch.Id, Keterangan: keterangan})
+	if err != nil {
+		b.repo.Release(id, model.BatchStatusValidated)
+		return model.BatchTransfer{}, err
+	}
+
+	batch, err = b.repo.Execute(id)
+	if err != nil {
+		b.repo.Release(id, model.BatchStatusFailed)
+		b.family.Release(reservation.Id)
+		return model.BatchTransfer{}, err
+	}
+	summarizeBatchItems(&batch)
+	b.budget.Evaluate(userId)
+	b.family.Record(model.FamilyActivity{UserId: userId, Jenis: model.FamilySpendTransfer, Jumlah: batch.TotalJumlah,
+		Keterangan: keterangan, Referensi: batch.Id})
+
+	return batch, nil
+}
+
+func summarizeBatchItems(batch *model.BatchTransfer) {
+	batch.JumlahItem = len(batch.Items)
+	batch.JumlahValid, batch.TotalJumlah, batch.TotalBiaya = 0, 0, 0
+	for _, item := range batch.Items {
+		if item.Status == model.BatchItemInvalid {
+			continue
+		}
+		batch.JumlahValid++
+		batch.TotalJumlah += item.Jumlah
+		batch.TotalBiaya += item.Biaya
+	}
+}
+
+// ParseBatchCSV membaca kolom tujuan,jumlah,catatan. Baris header opsional, baris yang tidak terbaca
+// tetap dikembalikan dengan Error agar dilaporkan per baris.
+func ParseBatchCSV(reader io.Reader) ([]dto.BatchRow, error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("file CSV tidak valid: %v", err.Error())
+	}
+
+	var rows []dto.BatchRow
+	for i, record := range records {
+		if i == 0 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "tujuan") {
+			continue
+		}
+		row := dto.BatchRow{}
+		if len(record) < 2 {
+			row.Error = "baris harus berisi tujuan dan jumlah"
+			rows = append(rows, row)
+			continue
+		}
+		row.Tujuan = record[0]
+		if len(record) > 2 {
+			row.Catatan = record[2]
+		}
+		jumlah, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			row.Error = fmt.Sprintf("jumlah %q bukan angka", record[1])
+		}
+		row.Jumlah = jumlah
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func NewBatchUseCase(repo repository.BatchRepository, uc UserUseCase, fee FeeUseCase, budget BudgetUseCase, family FamilyUseCase) BatchUseCase {
+	return &batchUseCase{repo: repo, uc: uc, fee: fee, budget: budget, family: family}
+}
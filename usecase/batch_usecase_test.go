@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type BatchUseCaseTestSuite struct {
+	suite.Suite
+	brm *repomock.BatchRepoMock
+	uum *usecasemock.UserUseCaseMock
+	fum *usecasemock.FeeUseCaseMock
+	bum *usecasemock.BudgetUseCaseMock
+	fam *usecasemock.FamilyUseCaseMock
+	bu  BatchUseCase
+}
+
+func (suite *BatchUseCaseTestSuite) SetupTest() {
+	suite.brm = new(repomock.BatchRepoMock)
+	suite.uum = new(usecasemock.UserUseCaseMock)
+	suite.fum = new(usecasemock.FeeUseCaseMock)
+	suite.uum.On("GetBalanceCase", mock.Anything).Return(model.UserSaldo{SaldoTersedia: 100000}, nil)
+	suite.uum.On("ResolveRecipient", "budi").Return(model.User{Id: "b", Name: "Budi Santoso"}, nil)
+	suite.uum.On("ResolveRecipient", "siapa").Return(model.User{}, errors.New("penerima tidak ditemukan"))
+	suite.fum.On("Quote", mock.Anything).Return(model.FeeQuote{Biaya: 500}, nil)
+	suite.bum = new(usecasemock.BudgetUseCaseMock)
+	suite.bum.On("Evaluate", mock.Anything).Return(nil)
+	suite.fam = new(usecasemock.FamilyUseCaseMock)
+	suite.fam.On("Authorize", mock.Anything).Return(model.FamilyReservation{}, nil)
+	suite.fam.On("Release", mock.Anything).Return(nil)
+	suite.fam.On("Record", mock.Anything).Return(nil)
+	suite.bu = NewBatchUseCase(suite.brm, suite.uum, suite.fum, suite.bum, suite.fam)
+}
+
+func TestBatchUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchUseCaseTestSuite))
+}
+
+func (suite *BatchUseCaseTestSuite) TestCreateBatch_ReportsRowErrors() {
+	rows := []dto.BatchRow{
+		{Tujuan: "budi", Jumlah: 10000},
+		{Tujuan: "siapa", Jumlah: 5000},
+		{Tujuan: "budi", Jumlah: 0},
+	}
+
+	var actual model.BatchTransfer
+	suite.brm.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		actual = args.Get(0).(model.BatchTransfer)
+	}).Return(model.BatchTransfer{}, nil)
+
+	_, err := suite.bu.CreateBatch("a", model.BatchModeBestEffort, rows)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.BatchStatusValidated, actual.Status)
+	assert.Equal(suite.T(), 1, actual.JumlahValid)
+	assert.Equal(suite.T(), 10000, actual.TotalJumlah)
+	assert.Equal(suite.T(), 500, actual.TotalBiaya)
+	assert.Equal(suite.T(), "penerima tidak ditemukan", actual.Items[1].Error)
+	assert.Equal(suite.T(), 3, actual.Items[2].Baris)
+	assert.Equal(suite.T(), model.BatchItemInvalid, actual.Items[2].Status)
+}
+
+func (suite *BatchUseCaseTestSuite) TestCreateBatch_AllOrNothingInvalid() {
+	rows := []dto.BatchRow{
+		{Tujuan: "budi", Jumlah: 10000},
+		{Tujuan: "siapa", Jumlah: 5000},
+	}
+
+	var actual model.BatchTransfer
+	suite.brm.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		actual = args.Get(0).(model.BatchTransfer)
+	}).Return(model.BatchTransfer{}, nil)
+
+	_, err := suite.bu.CreateBatch("a", model.BatchModeAllOrNothing, rows)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.BatchStatusInvalid, actual.Status)
+}
+
+func (suite *BatchUseCaseTestSuite) TestConfirmBatch_RejectsInvalid() {
+	suite.brm.On("Get", "batch-1").Return(model.BatchTransfer{Id: "batch-1", UserId: "a", Status: model.BatchStatusInvalid}, nil)
+
+	_, err := suite.bu.ConfirmBatch("batch-1", "a")
+	assert.Error(suite.T(), err)
+	suite.brm.AssertNotCalled(suite.T(), "Claim", mock.Anything)
+}
+
+func (suite *BatchUseCaseTestSuite) TestConfirmBatch_ExecuteErrorMarksFailed() {
+	suite.brm.On("Get", "batch-1").Return(model.BatchTransfer{Id: "batch-1", UserId: "a", Status: model.BatchStatusValidated}, nil)
+	suite.brm.On("Claim", "batch-1").Return(true, nil)
+	suite.brm.On("Execute", "batch-1").Return(model.BatchTransfer{}, errors.New("koneksi terputus"))
+	suite.brm.On("Release", "batch-1", model.BatchStatusFailed).Return(true, nil)
+
+	_, err := suite.bu.ConfirmBatch("batch-1", "a")
+	assert.EqualError(suite.T(), err, "koneksi terputus")
+	suite.brm.AssertCalled(suite.T(), "Release", "batch-1", model.BatchStatusFailed)
+}
+
+func (suite *BatchUseCaseTestSuite) TestCreateBatch_FreeQuotaSharedAcrossRows() {
+	fum := new(usecasemock.FeeUseCaseMock)
+	fum.On("Quote", dto.FeeQuoteRequest{UserId: "a", Operation: model.FeeOperationTransfer, Jumlah: 10000, Terpakai: 0}).
+		Return(model.FeeQuote{}, nil)
+	fum.On("Quote", dto.FeeQuoteRequest{UserId: "a", Operation: model.FeeOperationTransfer, Jumlah: 20000, Terpakai: 1}).
+		Return(model.FeeQuote{Biaya: 500}, nil)
+	bu := NewBatchUseCase(suite.brm, suite.uum, fum, suite.bum, suite.fam)
+	var actual model.BatchTransfer
+	suite.brm.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		actual = args.Get(0).(model.BatchTransfer)
+	}).Return(model.BatchTransfer{}, nil)
+
+	_, err := bu.CreateBatch("a", model.BatchModeBestEffort, []dto.BatchRow{
+		{Tujuan: "budi", Jumlah: 10000},
+		{Tujuan: "siapa", Jumlah: 5000},
+		{Tujuan: "budi", Jumlah: 20000},
+	})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, actual.Items[0].Biaya)
+	assert.Equal(suite.T(), 500, actual.Items[2].Biaya)
+}
+
+func (suite *BatchUseCaseTestSuite) TestConfirmBatch_FamilyBlockedReleasesClaim() {
+	fam := new(usecasemock.FamilyUseCaseMock)
+	fam.On("Authorize", mock.Anything).Return(model.FamilyReservation{}, errors.New("transfer menunggu persetujuan pemilik keluarga"))
+	bu := NewBatchUseCase(suite.brm, suite.uum, suite.fum, suite.bum, fam)
+	suite.brm.On("Get", "batch-1").Return(model.BatchTransfer{Id: "batch-1", UserId: "a", Status: model.BatchStatusValidated}, nil)
+	suite.brm.On("Claim", "batch-1").Return(true, nil)
+	suite.brm.On("Release", "batch-1", model.BatchStatusValidated).Return(true, nil)
+
+	_, err := bu.ConfirmBatch("batch-1", "a")
+	assert.Error(suite.T(), err)
+	suite.brm.AssertCalled(suite.T(), "Release", "batch-1", model.BatchStatusValidated)
+	suite.brm.AssertNotCalled(suite.T(), "Execute", mock.Anything)
+}
+
+func TestParseBatchCSV(t *testing.T) {
+	rows, err := ParseBatchCSV(strings.NewReader("tujuan,jumlah,catatan\nbudi,10000,makan siang\n@sari,abc\nrudi\n"))
+	assert.Nil(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, dto.BatchRow{Tujuan: "budi", Jumlah: 10000, Catatan: "makan siang"}, rows[0])
+	assert.NotEmpty(t, rows[1].Error)
+	assert.NotEmpty(t, rows[2].Error)
+}
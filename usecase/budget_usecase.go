@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+type BudgetUseCase interface {
+	CreateBudget(payload dto.BudgetRequest) (model.Budget, error)
+	FindBudgets(userId string) ([]model.Budget, error)
+	FindBudget(id, userId string) (model.Budget, error)
+	UpdateBudget(id string, payload dto.BudgetRequest) (model.Budget, error)
+	DeleteBudget(id, userId string) error
+	Evaluate(userId string) error
+}
+
+type budgetUseCase struct {
+	repo   repository.BudgetRepository
+	notify NotificationUseCase
+}
+
+// budgetMonth mengembalikan awal bulan berjalan, awal bulan berikutnya dan label periode YYYY-MM
+func budgetMonth(now time.Time) (time.Time, time.Time, string) {
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 1, 0), start.Format("2006-01")
+}
+
+func validateBudget(payload *dto.BudgetRequest) error {
+	payload.Kategori = normalizeCategory(payload.Kategori)
+	if payload.Jumlah <= 0 {
+		return fmt.Errorf("jumlah budget harus lebih dari 0")
+	}
+	if len(payload.Kategori) > 50 {
+		return fmt.Errorf("kategori maksimal 50 karakter")
+	}
+	return nil
+}
+
+// withUsage mengisi pemakaian budget untuk bulan berjalan
+func (b *budgetUseCase) withUsage(budget model.Budget) (model.Budget, error) {
+	start, end, periode := budgetMonth(time.Now())
+	spent, err := b.repo.GetSpent(budget.UserId, budget.Kategori, start, end)
+	if err != nil {
+		return model.Budget{}, err
+	}
+	budget.Periode = periode
+	budget.Terpakai = spent
+	budget.Sisa = budget.Jumlah - spent
+	if budget.Jumlah > 0 {
+		budget.Persen = float64(spent) / float64(budget.Jumlah) * 100
+	}
+
+	return budget, nil
+}
+
+func (b *budgetUseCase) CreateBudget(payload dto.BudgetRequest) (model.Budget, error) {
+	if err := validateBudget(&payload); err != nil {
+		return model.Budget{}, err
+	}
+	isActive := true
+	if payload.IsActive != nil {
+		isActive = *payload.IsActive
+	}
+	budget, err := b.repo.Create(model.Budget{
+		UserId:   payload.UserId,
+		Kategori: payload.Kategori,
+		Jumlah:   payload.Jumlah,
+		IsActive: isActive,
+	})
+	if err != nil {
+		return model.Budget{}, err
+	}
+
+	return b.withUsage(budget)
+}
+
+func (b *budgetUseCase) FindBudgets(userId string) ([]model.Budget, error) {
+	budgets, err := b.repo.GetByUser(userId, false)
+	if err != nil {
+		return []model.Budget{}, err
+	}
+	for i := range budgets {
+		if budgets[i], err = b.withUsage(budgets[i]); err != nil {
+			return []model.Budget{}, err
+		}
+	}
+
+	return budgets, nil
+}
+
+func (b *budgetUseCase) FindBudget(id, userId string) (model.Budget, error) {
+	budget, err := b.repo.Get(id)
+	if err != nil {
+		return model.Budget{}, err
+	}
+	if budget.UserId != userId {
+		return model.Budget{}, fmt.Errorf("budget %s tidak ditemukan", id)
+	}
+
+	return b.withUsage(budget)
+}
+
+func (b *budgetUseCase) UpdateBudget(id string, payload dto.BudgetRequest) (model.Budget, error) {
+	budget, err := b.FindBudget(id, payload.UserId)
+	if err != nil {
+		return model.Budget{}, err
+	}
+	if err := validateBudget(&payload); err != nil {
+		return model.Budget{}, err
+	}
+	budget.Kategori = payload.Kategori
+	budget.Jumlah = payload.Jumlah
+	if payload.IsActive != nil {
+		budget.IsActive = *payload.IsActive
+	}
+	budget, err = b.repo.Update(budget)
+	if err != nil {
+		return model.Budget{}, err
+	}
+
+	return b.withUsage(budget)
+}
+
+func (b *budgetUseCase) DeleteBudget(id, userId string) error {
+	if _, err := b.FindBudget(id, userId); err != nil {
+		return err
+	}
+
+	return b.repo.Delete(id)
+}
+
+// Evaluate dipanggil setelah transaksi keluar. Setiap ambang hanya diberitahukan sekali per budget per bulan,
+// jika beberapa ambang terlewati sekaligus hanya ambang tertinggi yang dikirim.
+func (b *budgetUseCase) Evaluate(userId string) error {
+	budgets, err := b.repo.GetByUser(userId, true)
+	if err != nil {
+		return err
+	}
+	for _, budget := range budgets {
+		budget, err = b.withUsage(budget)
+		if err != nil {
+			return err
+		}
+		notified := false
+		for _, ambang := range model.BudgetThresholds {
+			if budget.Persen < float64(ambang) {
+				continue
+			}
+			claimed, err := b.repo.ClaimAlert(budget.Id, budget.Periode, ambang)
+			if err != nil {
+				return err
+			}
+			if !claimed || notified {
+				continue
+			}
+			b.notify.Notify(budgetNotification(budget, ambang))
+			notified = true
+		}
+	}
+
+	return nil
+}
+
+func budgetNotification(budget model.Budget, ambang int) model.Notification {
+	nama := "keseluruhan"
+	if budget.Kategori != "" {
+		nama = "kategori " + budget.Kategori
+	}
+	notification := model.Notification{
+		UserId:      budget.UserId,
+		Jenis:       model.NotificationBudgetWarning,
+		Judul:       fmt.Sprintf("Budget %s sudah terpakai %d%%", nama, ambang),
+		Pesan:       fmt.Sprintf("Pengeluaran %s bulan %s sebesar %d dari budget %d", nama, budget.Periode, budget.Terpakai, budget.Jumlah),
+		ReferenceId: budget.Id,
+	}
+	if ambang >= 100 {
+		notification.Jenis = model.NotificationBudgetExceeded
+		notification.Judul = fmt.Sprintf("Budget %s sudah habis", nama)
+	}
+
+	return notification
+}
+
+func NewBudgetUseCase(repo repository.BudgetRepository, notify NotificationUseCase) BudgetUseCase {
+	return &budgetUseCase{repo: repo, notify: notify}
+}
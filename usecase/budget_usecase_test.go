@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type BudgetUseCaseTestSuite struct {
+	suite.Suite
+	brm     *repomock.BudgetRepoMock
+	num     *usecasemock.NotificationUseCaseMock
+	ub      BudgetUseCase
+	periode string
+}
+
+func (suite *BudgetUseCaseTestSuite) SetupTest() {
+	suite.brm = new(repomock.BudgetRepoMock)
+	suite.num = new(usecasemock.NotificationUseCaseMock)
+	suite.num.On("Notify", mock.Anything).Return(nil)
+	suite.ub = NewBudgetUseCase(suite.brm, suite.num)
+	_, _, suite.periode = budgetMonth(time.Now())
+}
+
+func TestBudgetUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(BudgetUseCaseTestSuite))
+}
+
+func (suite *BudgetUseCaseTestSuite) TestCreateBudget_Success() {
+	budget := model.Budget{Id: "b-1", UserId: "u-1", Kategori: "makan", Jumlah: 1000000, IsActive: true}
+	suite.brm.On("Create", model.Budget{UserId: "u-1", Kategori: "makan", Jumlah: 1000000, IsActive: true}).Return(budget, nil)
+	suite.brm.On("GetSpent", "u-1", "makan", mock.Anything, mock.Anything).Return(250000, nil)
+
+	result, err := suite.ub.CreateBudget(dto.BudgetRequest{UserId: "u-1", Kategori: "  Makan ", Jumlah: 1000000})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 250000, result.Terpakai)
+	assert.Equal(suite.T(), 750000, result.Sisa)
+	assert.Equal(suite.T(), 25.0, result.Persen)
+	assert.Equal(suite.T(), suite.periode, result.Periode)
+}
+
+func (suite *BudgetUseCaseTestSuite) TestCreateBudget_InvalidJumlah() {
+	_, err := suite.ub.CreateBudget(dto.BudgetRequest{UserId: "u-1", Jumlah: -5})
+	assert.Error(suite.T(), err)
+	suite.brm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *BudgetUseCaseTestSuite) TestFindBudget_OtherUser() {
+	suite.brm.On("Get", "b-1").Return(model.Budget{Id: "b-1", UserId: "u-2"}, nil)
+
+	_, err := suite.ub.FindBudget("b-1", "u-1")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *BudgetUseCaseTestSuite) TestDeleteBudget_Success() {
+	suite.brm.On("Get", "b-1").Return(model.Budget{Id: "b-1", UserId: "u-1", Jumlah: 500000}, nil)
+	suite.brm.On("GetSpent", "u-1", "", mock.Anything, mock.Anything).Return(0, nil)
+	suite.brm.On("Delete", "b-1").Return(nil)
+
+	err := suite.ub.DeleteBudget("b-1", "u-1")
+	assert.Nil(suite.T(), err)
+	suite.brm.AssertCalled(suite.T(), "Delete", "b-1")
+}
+
+func (suite *BudgetUseCaseTestSuite) TestEvaluate_Warning() {
+	suite.brm.On("GetByUser", "u-1", true).Return([]model.Budget{{Id: "b-1", UserId: "u-1", Kategori: "makan", Jumlah: 100000}}, nil)
+	suite.brm.On("GetSpent", "u-1", "makan", mock.Anything, mock.Anything).Return(85000, nil)
+	suite.brm.On("ClaimAlert", "b-1", suite.periode, 80).Return(true, nil)
+
+	err := suite.ub.Evaluate("u-1")
+	assert.Nil(suite.T(), err)
+	suite.brm.AssertNotCalled(suite.T(), "ClaimAlert", "b-1", suite.periode, 100)
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.Jenis == model.NotificationBudgetWarning && n.ReferenceId == "b-1"
+	}))
+}
+
+func (suite *BudgetUseCaseTestSuite) TestEvaluate_AlreadyNotified() {
+	suite.brm.On("GetByUser", "u-1", true).Return([]model.Budget{{Id: "b-1", UserId: "u-1", Jumlah: 100000}}, nil)
+	suite.brm.On("GetSpent", "u-1", "", mock.Anything, mock.Anything).Return(90000, nil)
+	suite.brm.On("ClaimAlert", "b-1", suite.periode, 80).Return(false, nil)
+
+	err := suite.ub.Evaluate("u-1")
+	assert.Nil(suite.T(), err)
+	suite.num.AssertNotCalled(suite.T(), "Notify", mock.Anything)
+}
+
+func (suite *BudgetUseCaseTestSuite) TestEvaluate_JumpToExceeded() {
+	suite.brm.On("GetByUser", "u-1", true).Return([]model.Budget{{Id: "b-1", UserId: "u-1", Jumlah: 100000}}, nil)
+	suite.brm.On("GetSpent", "u-1", "", mock.Anything, mock.Anything).Return(120000, nil)
+	suite.brm.On("ClaimAlert", "b-1", suite.periode, 100).Return(true, nil)
+	suite.brm.On("ClaimAlert", "b-1", suite.periode, 80).Return(true, nil)
+
+	err := suite.ub.Evaluate("u-1")
+	assert.Nil(suite.T(), err)
+	// ambang 80 ikut diklaim agar tidak dikirim belakangan, tapi hanya satu notifikasi yang terkirim
+	suite.brm.AssertCalled(suite.T(), "ClaimAlert", "b-1", suite.periode, 80)
+	suite.num.AssertNumberOfCalls(suite.T(), "Notify", 1)
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.Jenis == model.NotificationBudgetExceeded
+	}))
+}
+
+func (suite *BudgetUseCaseTestSuite) TestEvaluate_RepoError() {
+	suite.brm.On("GetByUser", "u-1", true).Return([]model.Budget{}, fmt.Errorf("db error"))
+
+	err := suite.ub.Evaluate("u-1")
+	assert.Error(suite.T(), err)
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+const (
+	checkoutTTL    = 30 * time.Minute
+	checkoutMaxTTL = 24 * time.Hour
+	// halaman konfirmasi yang dibuka user wallet
+	checkoutPath = "/api/v1/checkout/"
+)
+
+type CheckoutUseCase interface {
+	CreateSession(merchantId string, payload dto.CheckoutSessionRequest) (model.CheckoutSession, error)
+	FindMerchantSession(id, merchantId string) (model.CheckoutSession, error)
+	FindSession(id string) (model.CheckoutSession, error)
+	ConfirmSession(id, userId string) (model.CheckoutSession, error)
+	CancelSession(id, userId string) (model.CheckoutSession, error)
+}
+
+type checkoutUseCase struct {
+	repo    repository.CheckoutRepository
+	webhook WebhookUseCase
+	budget  BudgetUseCase
+	family  FamilyUseCase
+}
+
+func validReturnUrl(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != "" && len(raw) <= 500
+}
+
+// checkoutRedirect menambahkan id session, order id dan status ke URL kembali milik merchant
+func checkoutRedirect(raw string, session model.CheckoutSession) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	query := u.Query()
+	query.Set("checkout_session_id", session.Id)
+	query.Set("order_id", session.OrderId)
+	query.Set("status", session.Status)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func withCheckoutUrl(session model.CheckoutSession) model.CheckoutSession {
+	session.CheckoutUrl = checkoutPath + session.Id
+	return session
+}
+
+// CreateSession dipanggil merchant lewat API, user diarahkan ke CheckoutUrl untuk menyetujui pembayaran
+func (c *checkoutUseCase) CreateSession(merchantId string, payload dto.CheckoutSessionRequest) (model.CheckoutSession, error) {
+	if payload.Jumlah <= 0 {
+		return model.CheckoutSession{}, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	if len(payload.OrderId) > 100 {
+		return model.CheckoutSession{}, fmt.Errorf("order_id maksimal 100 karakter")
+	}
+	if len(payload.Deskripsi) > 250 {
+		return model.CheckoutSession{}, fmt.Errorf("deskripsi maksimal 250 karakter")
+	}
+	if !validReturnUrl(payload.SuccessUrl) || !validReturnUrl(payload.CancelUrl) {
+		return model.CheckoutSession{}, fmt.Errorf("success_url dan cancel_url harus berupa URL http atau https")
+	}
+	ttl := checkoutTTL
+	if payload.ExpiresIn > 0 {
+		ttl = time.Duration(payload.ExpiresIn) * time.Minute
+	}
+	if ttl > checkoutMaxTTL {
+		return model.CheckoutSession{}, fmt.Errorf("masa berlaku checkout maksimal 24 jam")
+	}
+
+	session, err := c.repo.Create(model.CheckoutSession{
+		MerchantId: merchantId,
+		OrderId:    payload.OrderId,
+		Jumlah:     payload.Jumlah,
+		Deskripsi:  payload.Deskripsi,
+		SuccessUrl: payload.SuccessUrl,
+		CancelUrl:  payload.CancelUrl,
+		ExpiresAt:  time.Now().Add(ttl),
+	})
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+
+	return withCheckoutUrl(session), nil
+}
+
+func (c *checkoutUseCase) FindMerchantSession(id, merchantId string) (model.CheckoutSession, error) {
+	session, err := c.repo.Get(id)
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+	if session.MerchantId != merchantId {
+		return model.CheckoutSession{}, fmt.Errorf("checkout session %s tidak ditemukan", id)
+	}
+
+	return withCheckoutUrl(session), nil
+}
+
+// FindSession ditampilkan ke user sebelum menyetujui, siapa pun yang memegang link boleh melihat
+func (c *checkoutUseCase) FindSession(id string) (model.CheckoutSession, error) {
+	session, err := c.repo.Get(id)
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+	session.SuccessUrl = ""
+	session.CancelUrl = ""
+	session.UserId = ""
+
+	return session, nil
+}
+
+// ConfirmSession membayar checkout setelah PIN diperiksa di controller, lalu mengabari merchant lewat webhook
+func (c *checkoutUseCase) ConfirmSession(id, userId string) (model.CheckoutSession, error) {
+	session, err := c.repo.Get(id)
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+	if session.Status != model.CheckoutStatusOpen {
+		return model.CheckoutSession{}, fmt.Errorf("checkout session berstatus %s", session.Status)
+	}
+	if session.OwnerId == userId {
+		return model.CheckoutSession{}, fmt.Errorf("tidak dapat membayar merchant milik sendiri")
+	}
+	reservation, err := c.family.Authorize(dto.FamilySpendRequest{UserId: userId, Jenis: model.FamilySpendMerchant, Jumlah: session.Jumlah,
+		Tujuan: session.MerchantId, Keterangan: session.NamaMerchant})
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+
+	session, payment, err := c.repo.Pay(id, userId)
+	if err != nil {
+		c.family.Release(reservation.Id)
+		return model.CheckoutSession{}, err
+	}
+	c.webhook.Publish(session.MerchantId, model.WebhookEventPaymentSucceeded, payment)
+	c.webhook.Publish(session.MerchantId, model.WebhookEventCheckoutCompleted, withCheckoutUrl(session))
+	session.RedirectUrl = checkoutRedirect(session.SuccessUrl, session)
+	c.budget.Evaluate(userId)
+	c.family.Record(model.FamilyActivity{UserId: userId, Jenis: model.FamilySpendMerchant, Jumlah: payment.Jumlah,
+		Keterangan: session.NamaMerchant, Referensi: payment.Id})
+
+	return session, nil
+}
+
+func (c *checkoutUseCase) CancelSession(id, userId string) (model.CheckoutSession, error) {
+	cancelled, err := c.repo.Cancel(id, userId)
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+	if !cancelled {
+		return model.CheckoutSession{}, fmt.Errorf("checkout session sudah dibayar, dibatalkan atau kedaluwarsa")
+	}
+	session, err := c.repo.Get(id)
+	if err != nil {
+		return model.CheckoutSession{}, err
+	}
+	session.RedirectUrl = checkoutRedirect(session.CancelUrl, session)
+
+	return session, nil
+}
+
+func NewCheckoutUseCase(repo repository.CheckoutRepository, webhook WebhookUseCase, budget BudgetUseCase, family FamilyUseCase) CheckoutUseCase {
+	return &checkoutUseCase{repo: repo, webhook: webhook, budget: budget, family: family}
+}
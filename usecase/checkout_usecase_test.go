@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type CheckoutUseCaseTestSuite struct {
+	suite.Suite
+	crm     *repomock.CheckoutRepoMock
+	wum     *usecasemock.WebhookUseCaseMock
+	bum     *usecasemock.BudgetUseCaseMock
+	fam     *usecasemock.FamilyUseCaseMock
+	uco     CheckoutUseCase
+	session model.CheckoutSession
+}
+
+func (suite *CheckoutUseCaseTestSuite) SetupTest() {
+	suite.crm = new(repomock.CheckoutRepoMock)
+	suite.wum = new(usecasemock.WebhookUseCaseMock)
+	suite.wum.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.bum = new(usecasemock.BudgetUseCaseMock)
+	suite.bum.On("Evaluate", mock.Anything).Return(nil)
+	suite.fam = new(usecasemock.FamilyUseCaseMock)
+	suite.fam.On("Authorize", mock.Anything).Return(model.FamilyReservation{}, nil)
+	suite.fam.On("Release", mock.Anything).Return(nil)
+	suite.fam.On("Record", mock.Anything).Return(nil)
+	suite.uco = NewCheckoutUseCase(suite.crm, suite.wum, suite.bum, suite.fam)
+	suite.session = model.CheckoutSession{Id: "cs-1", MerchantId: "m-1", OwnerId: "owner", OrderId: "INV-1", Jumlah: 75000,
+		SuccessUrl: "https://toko.example/selesai?ref=web", CancelUrl: "https://toko.example/batal",
+		Status: model.CheckoutStatusOpen, ExpiresAt: time.Now().Add(time.Minute)}
+}
+
+func TestCheckoutUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CheckoutUseCaseTestSuite))
+}
+
+func (suite *CheckoutUseCaseTestSuite) TestCreateSession_InvalidReturnUrl() {
+	_, err := suite.uco.CreateSession("m-1", dto.CheckoutSessionRequest{OrderId: "INV-1", Jumlah: 1000,
+		SuccessUrl: "javascript:alert(1)", CancelUrl: "https://toko.example/batal"})
+	assert.Error(suite.T(), err)
+	suite.crm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *CheckoutUseCaseTestSuite) TestConfirmSession_RedirectsAndNotifies() {
+	completed := suite.session
+	completed.Status = model.CheckoutStatusCompleted
+	completed.UserId = "a"
+	payment := model.MerchantPayment{Id: "pay-1", MerchantId: "m-1", Jumlah: 75000}
+	suite.crm.On("Get", "cs-1").Return(suite.session, nil)
+	suite.crm.On("Pay", "cs-1", "a").Return(completed, payment, nil)
+
+	actual, err := suite.uco.ConfirmSession("cs-1", "a")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "https://toko.example/selesai?checkout_session_id=cs-1&order_id=INV-1&ref=web&status=completed", actual.RedirectUrl)
+	suite.wum.AssertCalled(suite.T(), "Publish", "m-1", model.WebhookEventPaymentSucceeded, payment)
+	suite.wum.AssertCalled(suite.T(), "Publish", "m-1", model.WebhookEventCheckoutCompleted, mock.Anything)
+	suite.bum.AssertCalled(suite.T(), "Evaluate", "a")
+}
+
+func (suite *CheckoutUseCaseTestSuite) TestConfirmSession_Expired() {
+	expired := suite.session
+	expired.Status = model.CheckoutStatusExpired
+	suite.crm.On("Get", "cs-1").Return(expired, nil)
+
+	_, err := suite.uco.ConfirmSession("cs-1", "a")
+	assert.Error(suite.T(), err)
+	suite.crm.AssertNotCalled(suite.T(), "Pay", mock.Anything, mock.Anything)
+	suite.bum.AssertNotCalled(suite.T(), "Evaluate", mock.Anything)
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+type DisputeUseCase interface {
+	OpenDispute(payload dto.DisputeRequest) (model.Dispute, error)
+	FindById(id, userId, role string) (model.Dispute, error)
+	FindAll(userId string, page int) ([]model.Dispute, error)
+	FindByStatus(status string, page int) ([]model.Dispute, error)
+	AddComment(id string, payload dto.DisputeCommentRequest, authorId, role string) (model.DisputeComment, error)
+	UpdateStatus(id string, payload dto.DisputeStatusRequest, adminId string) (model.Dispute, error)
+}
+
+type disputeUseCase struct {
+	repo     repository.DisputeRepository
+	holdRepo repository.HoldRepository
+	ut       TransferUseCase
+}
+
+func (d *disputeUseCase) OpenDispute(payload dto.DisputeRequest) (model.Dispute, error) {
+	dispute, err := d.repo.FindTransaction(payload.TrxType, payload.TrxId, payload.UserId)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+	dispute.Deskripsi = payload.Deskripsi
+
+	return d.repo.Create(dispute)
+}
+
+func (d *disputeUseCase) FindById(id, userId, role string) (model.Dispute, error) {
+	dispute, err := d.repo.Get(id)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+	if role != "admin" && dispute.UserId != userId {
+		return model.Dispute{}, fmt.Errorf("dispute %s tidak ditemukan", id)
+	}
+	dispute.Comments, err = d.repo.GetComments(id)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+
+	return dispute, nil
+}
+
+func (d *disputeUseCase) FindAll(userId string, page int) ([]model.Dispute, error) {
+	datas, err := d.repo.GetAll(userId, page)
+	if err != nil {
+		return []model.Dispute{}, err
+	}
+
+	return datas, nil
+}
+
+func (d *disputeUseCase) FindByStatus(status string, page int) ([]model.Dispute, error) {
+	datas, err := d.repo.GetByStatus(status, page)
+	if err != nil {
+		return []model.Dispute{}, err
+	}
+
+	return datas, nil
+}
+
+func (d *disputeUseCase) AddComment(id string, payload dto.DisputeCommentRequest, authorId, role string) (model.DisputeComment, error) {
+	dispute, err := d.FindById(id, authorId, role)
+	if err != nil {
+		return model.DisputeComment{}, err
+	}
+	if isDisputeClosed(dispute.Status) {
+		return model.DisputeComment{}, fmt.Errorf("dispute ini sudah ditutup")
+	}
+
+	return d.repo.AddComment(model.DisputeComment{
+		DisputeId:  id,
+		AuthorId:   authorId,
+		AuthorRole: role,
+		Komentar:   payload.Komentar,
+	})
+}
+
+func (d *disputeUseCase) UpdateStatus(id string, payload dto.DisputeStatusRequest, adminId string) (model.Dispute, error) {
+	dispute, err := d.repo.Get(id)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+	if isDisputeClosed(dispute.Status) {
+		return model.Dispute{}, fmt.Errorf("dispute ini sudah ditutup")
+	}
+
+	switch payload.Status {
+	case model.DisputeStatusInvestigating:
+		if dispute.Status != model.DisputeStatusOpen {
+			return model.Dispute{}, fmt.Errorf("dispute dengan status %s tidak bisa diinvestigasi", dispute.Status)
+		}
+		if payload.TahanDana && dispute.CounterpartyId != "" && dispute.HoldId == "" {
+			// dana yang sudah ditahan hold lain tidak ikut dibekukan dua kali
+			hold, err := d.holdRepo.Authorize(model.Hold{
+				UserId:      dispute.CounterpartyId,
+				Jumlah:      dispute.Jumlah,
+				Alasan:      "investigasi dispute",
+				ReferenceId: dispute.Id,
+				Sumber:      model.HoldSourceDispute,
+			})
+			if err != nil {
+				return model.Dispute{}, err
+			}
+			dispute.HoldId = hold.Id
+		}
+	case model.DisputeStatusResolvedInFavour:
+		// hanya dispute transfer yang punya lawan transaksi untuk di-reversal. Reversal yang sudah selesai untuk dispute ini
+		// dipakai ulang agar penyelesaian yang gagal setelah reversal (misal saat melepas hold) bisa diulang
+		if dispute.TrxType == model.DisputeTrxTypeTransfer {
+			alasan := fmt.Sprintf("dispute %s", dispute.Id)
+			reversal, err := d.ut.FindActiveReversal(dispute.TrxId)
+			if err != nil {
+				return model.Dispute{}, err
+			}
+			if reversal.Status != model.ReversalStatusCompleted || reversal.Alasan != alasan {
+				reversal, err = d.ut.ReverseTransfer(dto.ReversalRequest{
+					TrxId:  dispute.TrxId,
+					Alasan: alasan,
+					Policy: payload.Policy,
+				}, adminId)
+				if err != nil {
+					return model.Dispute{}, err
+				}
+			}
+			dispute.ReversalId = reversal.Id
+		}
+		if err := d.releaseHold(dispute); err != nil {
+			return model.Dispute{}, err
+		}
+	case model.DisputeStatusRejected:
+		if err := d.releaseHold(dispute); err != nil {
+			return model.Dispute{}, err
+		}
+	default:
+		return model.Dispute{}, fmt.Errorf("status %s tidak dikenal", payload.Status)
+	}
+
+	dispute.Status = payload.Status
+	dispute, err = d.repo.Update(dispute)
+	if err != nil {
+		return model.Dispute{}, err
+	}
+	if payload.Komentar != "" {
+		if _, err := d.repo.AddComment(model.DisputeComment{
+			DisputeId:  id,
+			AuthorId:   adminId,
+			AuthorRole: "admin",
+			Komentar:   payload.Komentar,
+		}); err != nil {
+			return model.Dispute{}, err
+		}
+	}
+
+	return dispute, nil
+}
+
+// releaseHold melepas hold investigasi, hold yang sudah tidak aktif (misal dilepas admin) dilewati
+func (d *disputeUseCase) releaseHold(dispute model.Dispute) error {
+	if dispute.HoldId == "" {
+		return nil
+	}
+	hold, err := d.holdRepo.Get(dispute.HoldId)
+	if err != nil {
+		return err
+	}
+	if hold.Status != model.HoldStatusActive {
+		return nil
+	}
+	_, err = d.holdRepo.Void(dispute.HoldId)
+
+	return err
+}
+
+func isDisputeClosed(status string) bool {
+	return status == model.DisputeStatusResolvedInFavour || status == model.DisputeStatusRejected
+}
+
+func NewDisputeUseCase(repo repository.DisputeRepository, holdRepo repository.HoldRepository, ut TransferUseCase) DisputeUseCase {
+	return &disputeUseCase{repo: repo, holdRepo: holdRepo, ut: ut}
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type DisputeUseCaseTestSuite struct {
+	suite.Suite
+	drm *repomock.DisputeRepoMock
+	hrm *repomock.HoldRepoMock
+	tum *usecasemock.TransferUseCaseMock
+	ud  DisputeUseCase
+}
+
+func (suite *DisputeUseCaseTestSuite) SetupTest() {
+	suite.drm = new(repomock.DisputeRepoMock)
+	suite.hrm = new(repomock.HoldRepoMock)
+	suite.tum = new(usecasemock.TransferUseCaseMock)
+	suite.ud = NewDisputeUseCase(suite.drm, suite.hrm, suite.tum)
+}
+
+func TestDisputeUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(DisputeUseCaseTestSuite))
+}
+
+func (suite *DisputeUseCaseTestSuite) TestOpenDispute_Success() {
+	found := model.Dispute{UserId: "u-1", TrxType: model.DisputeTrxTypeTransfer, TrxId: "trx-1", CounterpartyId: "u-2", Jumlah: 50000}
+	suite.drm.On("FindTransaction", model.DisputeTrxTypeTransfer, "trx-1", "u-1").Return(found, nil)
+	created := found
+	created.Deskripsi = "barang tidak dikirim"
+	suite.drm.On("Create", created).Return(model.Dispute{Id: "d-1", Status: model.DisputeStatusOpen}, nil)
+
+	result, err := suite.ud.OpenDispute(dto.DisputeRequest{UserId: "u-1", TrxType: model.DisputeTrxTypeTransfer, TrxId: "trx-1", Deskripsi: "barang tidak dikirim"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "d-1", result.Id)
+}
+
+func (suite *DisputeUseCaseTestSuite) TestInvestigate_FreezeOverAvailableBalance() {
+	suite.drm.On("Get", "d-1").Return(model.Dispute{Id: "d-1", CounterpartyId: "u-2", Jumlah: 50000, Status: model.DisputeStatusOpen}, nil)
+	suite.hrm.On("Authorize", mock.MatchedBy(func(hold model.Hold) bool {
+		return hold.UserId == "u-2" && hold.Jumlah == 50000 && hold.Sumber == model.HoldSourceDispute
+	})).Return(model.Hold{}, errors.New("saldo tersedia tidak mencukupi untuk menahan 50000"))
+
+	_, err := suite.ud.UpdateStatus("d-1", dto.DisputeStatusRequest{Status: model.DisputeStatusInvestigating, TahanDana: true}, "admin-1")
+	assert.EqualError(suite.T(), err, "saldo tersedia tidak mencukupi untuk menahan 50000")
+	suite.drm.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *DisputeUseCaseTestSuite) TestResolve_ReversesAndReleasesHold() {
+	suite.drm.On("Get", "d-1").Return(model.Dispute{Id: "d-1", TrxType: model.DisputeTrxTypeTransfer, TrxId: "trx-1",
+		HoldId: "h-1", Status: model.DisputeStatusInvestigating}, nil)
+	suite.tum.On("FindActiveReversal", "trx-1").Return(model.Reversal{}, nil)
+	suite.tum.On("ReverseTransfer", dto.ReversalRequest{TrxId: "trx-1", Alasan: "dispute d-1", Policy: model.ReversalPolicyPartial}, "admin-1").
+		Return(model.Reversal{Id: "rev-1"}, nil)
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusActive}, nil)
+	suite.hrm.On("Void", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusVoided}, nil)
+	suite.drm.On("Update", mock.MatchedBy(func(dispute model.Dispute) bool {
+		return dispute.ReversalId == "rev-1" && dispute.Status == model.DisputeStatusResolvedInFavour
+	})).Return(model.Dispute{Id: "d-1", ReversalId: "rev-1", Status: model.DisputeStatusResolvedInFavour}, nil)
+
+	result, err := suite.ud.UpdateStatus("d-1", dto.DisputeStatusRequest{Status: model.DisputeStatusResolvedInFavour, Policy: model.ReversalPolicyPartial}, "admin-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "rev-1", result.ReversalId)
+	suite.hrm.AssertCalled(suite.T(), "Void", "h-1")
+}
+
+func (suite *DisputeUseCaseTestSuite) TestResolve_RetryReusesCompletedReversal() {
+	suite.drm.On("Get", "d-1").Return(model.Dispute{Id: "d-1", TrxType: model.DisputeTrxTypeTransfer, TrxId: "trx-1",
+		HoldId: "h-1", Status: model.DisputeStatusInvestigating}, nil)
+	suite.tum.On("FindActiveReversal", "trx-1").
+		Return(model.Reversal{Id: "rev-1", Alasan: "dispute d-1", Status: model.ReversalStatusCompleted}, nil)
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusActive}, nil)
+	suite.hrm.On("Void", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusVoided}, nil)
+	suite.drm.On("Update", mock.MatchedBy(func(dispute model.Dispute) bool {
+		return dispute.ReversalId == "rev-1"
+	})).Return(model.Dispute{Id: "d-1", ReversalId: "rev-1", Status: model.DisputeStatusResolvedInFavour}, nil)
+
+	result, err := suite.ud.UpdateStatus("d-1", dto.DisputeStatusRequest{Status: model.DisputeStatusResolvedInFavour}, "admin-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "rev-1", result.ReversalId)
+	suite.tum.AssertNotCalled(suite.T(), "ReverseTransfer", mock.Anything, mock.Anything)
+}
+
+func (suite *DisputeUseCaseTestSuite) TestReject_ReleaseHoldFailed() {
+	suite.drm.On("Get", "d-1").Return(model.Dispute{Id: "d-1", HoldId: "h-1", Status: model.DisputeStatusInvestigating}, nil)
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusActive}, nil)
+	suite.hrm.On("Void", "h-1").Return(model.Hold{}, errors.New("koneksi terputus"))
+
+	_, err := suite.ud.UpdateStatus("d-1", dto.DisputeStatusRequest{Status: model.DisputeStatusRejected}, "admin-1")
+	assert.EqualError(suite.T(), err, "koneksi terputus")
+	suite.drm.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func (suite *DisputeUseCaseTestSuite) TestReject_HoldAlreadyReleased() {
+	suite.drm.On("Get", "d-1").Return(model.Dispute{Id: "d-1", HoldId: "h-1", Status: model.DisputeStatusInvestigating}, nil)
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusVoided}, nil)
+	suite.drm.On("Update", mock.Anything).Return(model.Dispute{Id: "d-1", Status: model.DisputeStatusRejected}, nil)
+
+	result, err := suite.ud.UpdateStatus("d-1", dto.DisputeStatusRequest{Status: model.DisputeStatusRejected}, "admin-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.DisputeStatusRejected, result.Status)
+	suite.hrm.AssertNotCalled(suite.T(), "Void", "h-1")
+}
+
+func (suite *DisputeUseCaseTestSuite) TestUpdateStatus_AlreadyClosed() {
+	suite.drm.On("Get", "d-1").Return(model.Dispute{Id: "d-1", Status: model.DisputeStatusRejected}, nil)
+
+	_, err := suite.ud.UpdateStatus("d-1", dto.DisputeStatusRequest{Status: model.DisputeStatusResolvedInFavour}, "admin-1")
+	assert.EqualError(suite.T(), err, "dispute ini sudah ditutup")
+}
@@ -0,0 +1,484 @@
+package usecase
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+// masa berlaku permintaan persetujuan dan persetujuan yang belum dipakai
+const familyApprovalTTL = 24 * time.Hour
+
+type FamilyUseCase interface {
+	CreateFamily(payload dto.FamilyRequest) (model.Family, error)
+	FindFamily(userId string) (model.Family, error)
+	AddMember(payload dto.FamilyMemberRequest) (model.FamilyMember, error)
+	UpdateMember(memberId string, payload dto.FamilyMemberRequest) (model.FamilyMember, error)
+	RemoveMember(memberId, ownerId string) error
+	FindMember(memberId, ownerId string) (model.FamilyMember, error)
+	AcceptInvite(userId string) (model.FamilyMember, error)
+	DeclineInvite(userId string) error
+	LeaveFamily(userId string) error
+	Authorize(payload dto.FamilySpendRequest) (model.FamilyReservation, error)
+	Check(payload dto.FamilySpendRequest) (bool, error)
+	Release(reservationId string) error
+	Record(payload model.FamilyActivity) error
+	FindApprovals(ownerId, status string) ([]model.FamilyApproval, error)
+	ApproveRequest(id, ownerId string) (model.FamilyApproval, error)
+	RejectRequest(id, ownerId string) (model.FamilyApproval, error)
+	FindActivity(ownerId string, page int) ([]model.FamilyActivity, error)
+}
+
+type familyUseCase struct {
+	repo   repository.FamilyRepository
+	uc     UserUseCase
+	notify NotificationUseCase
+}
+
+func startOfDay(now time.Time) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+func validateFamilyMember(payload *dto.FamilyMemberRequest) error {
+	if payload.LimitHarian < 0 || payload.AmbangPersetujuan < 0 {
+		return fmt.Errorf("limit_harian dan ambang_persetujuan tidak boleh negatif")
+	}
+	if len(payload.JenisDiizinkan) == 0 {
+		payload.JenisDiizinkan = model.FamilySpendTypes
+	}
+	for _, jenis := range payload.JenisDiizinkan {
+		if !slices.Contains(model.FamilySpendTypes, jenis) {
+			return fmt.Errorf("jenis %s tidak dikenal, gunakan %s", jenis, strings.Join(model.FamilySpendTypes, ", "))
+		}
+	}
+	return nil
+}
+
+func (f *familyUseCase) CreateFamily(payload dto.FamilyRequest) (model.Family, error) {
+	payload.Nama = strings.TrimSpace(payload.Nama)
+	if payload.Nama == "" || len(payload.Nama) > 50 {
+		return model.Family{}, fmt.Errorf("nama keluarga harus diisi, maksimal 50 karakter")
+	}
+	member, err := f.repo.GetMember(payload.OwnerId)
+	if err != nil {
+		return model.Family{}, err
+	}
+	if member.Status == model.FamilyMemberPending {
+		return model.Family{}, fmt.Errorf("tolak undangan keluarga terlebih dahulu sebelum membuat keluarga baru")
+	}
+	if member.Id != "" {
+		return model.Family{}, fmt.Errorf("anggota keluarga tidak dapat membuat keluarga baru")
+	}
+	family, err := f.repo.Create(model.Family{OwnerId: payload.OwnerId, Nama: payload.Nama})
+	if err != nil {
+		return model.Family{}, err
+	}
+	family.Members = []model.FamilyMember{}
+
+	return family, nil
+}
+
+// FindFamily mengembalikan keluarga milik user, atau keluarga tempat user menjadi anggota
+func (f *familyUseCase) FindFamily(userId string) (model.Family, error) {
+	family, err := f.repo.GetByOwner(userId)
+	if err != nil {
+		member, memberErr := f.repo.GetMember(userId)
+		if memberErr != nil || member.Id == "" {
+			return model.Family{}, err
+		}
+		if family, err = f.repo.Get(member.FamilyId); err != nil {
+			return model.Family{}, err
+		}
+	}
+	family.Members, err = f.repo.GetMembers(family.Id)
+	if err != nil {
+		return model.Family{}, err
+	}
+	since := startOfDay(time.Now())
+	for i := range family.Members {
+		family.Members[i].TerpakaiHariIni, err = f.repo.GetSpentSince(family.Members[i].UserId, since)
+		if err != nil {
+			return model.Family{}, err
+		}
+	}
+
+	return family, nil
+}
+
+// AddMember mengundang user ke keluarga, kontrol keluarga baru berlaku setelah undangan diterima
+func (f *familyUseCase) AddMember(payload dto.FamilyMemberRequest) (model.FamilyMember, error) {
+	family, err := f.repo.GetByOwner(payload.OwnerId)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	if err := validateFamilyMember(&payload); err != nil {
+		return model.FamilyMember{}, err
+	}
+	user, err := f.uc.ResolveRecipient(payload.User)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	if user.Id == payload.OwnerId {
+		return model.FamilyMember{}, fmt.Errorf("owner tidak dapat menjadi anggota keluarga sendiri")
+	}
+	if _, err := f.repo.GetByOwner(user.Id); err == nil {
+		return model.FamilyMember{}, fmt.Errorf("user sudah menjadi owner keluarga lain")
+	}
+
+	member, err := f.repo.AddMember(model.FamilyMember{
+		FamilyId:          family.Id,
+		UserId:            user.Id,
+		LimitHarian:       payload.LimitHarian,
+		JenisDiizinkan:    payload.JenisDiizinkan,
+		AmbangPersetujuan: payload.AmbangPersetujuan,
+	})
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	member.Nama = user.Name
+	f.notify.Notify(model.Notification{
+		UserId:      user.Id,
+		Jenis:       model.NotificationFamilyInvite,
+		Judul:       "Undangan keluarga",
+		Pesan:       fmt.Sprintf("%s mengundang anda bergabung ke keluarga %s", family.NamaOwner, family.Nama),
+		ReferenceId: family.Id,
+	})
+
+	return member, nil
+}
+
+// FindMember mencari anggota berdasarkan user id anggota dan memastikan owner-nya sesuai
+func (f *familyUseCase) FindMember(memberId, ownerId string) (model.FamilyMember, error) {
+	family, err := f.repo.GetByOwner(ownerId)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	member, err := f.repo.GetMember(memberId)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	if member.FamilyId != family.Id {
+		return model.FamilyMember{}, fmt.Errorf("user bukan anggota keluarga anda")
+	}
+	member.TerpakaiHariIni, err = f.repo.GetSpentSince(member.UserId, startOfDay(time.Now()))
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+
+	return member, nil
+}
+
+func (f *familyUseCase) UpdateMember(memberId string, payload dto.FamilyMemberRequest) (model.FamilyMember, error) {
+	member, err := f.FindMember(memberId, payload.OwnerId)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	if err := validateFamilyMember(&payload); err != nil {
+		return model.FamilyMember{}, err
+	}
+	member.LimitHarian = payload.LimitHarian
+	member.JenisDiizinkan = payload.JenisDiizinkan
+	member.AmbangPersetujuan = payload.AmbangPersetujuan
+
+	return f.repo.UpdateMember(member)
+}
+
+func (f *familyUseCase) AcceptInvite(userId string) (model.FamilyMember, error) {
+	member, err := f.repo.GetMember(userId)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	if member.Status != model.FamilyMemberPending {
+		return model.FamilyMember{}, fmt.Errorf("tidak ada undangan keluarga untuk anda")
+	}
+	if _, err := f.repo.GetByOwner(userId); err == nil {
+		return model.FamilyMember{}, fmt.Errorf("owner keluarga tidak dapat bergabung ke keluarga lain")
+	}
+	activated, err := f.repo.ActivateMember(userId)
+	if err != nil {
+		return model.FamilyMember{}, err
+	}
+	if !activated {
+		return model.FamilyMember{}, fmt.Errorf("undangan keluarga sudah tidak berlaku")
+	}
+	member.Status = model.FamilyMemberActive
+	f.memberChanged(member, model.FamilyActivityMemberJoined, "bergabung ke")
+
+	return member, nil
+}
+
+func (f *familyUseCase) DeclineInvite(userId string) error {
+	member, err := f.repo.GetMember(userId)
+	if err != nil {
+		return err
+	}
+	if member.Status != model.FamilyMemberPending {
+		return fmt.Errorf("tidak ada undangan keluarga untuk anda")
+	}
+
+	return f.repo.RemoveMember(member.FamilyId, userId)
+}
+
+func (f *familyUseCase) LeaveFamily(userId string) error {
+	member, err := f.repo.GetMember(userId)
+	if err != nil {
+		return err
+	}
+	if member.Status != model.FamilyMemberActive {
+		return fmt.Errorf("anda bukan anggota keluarga manapun")
+	}
+	if err := f.repo.RemoveMember(member.FamilyId, userId); err != nil {
+		return err
+	}
+	f.memberChanged(member, model.FamilyActivityMemberLeft, "keluar dari")
+
+	return nil
+}
+
+// memberChanged mencatat anggota yang bergabung atau keluar ke feed dan mengabari owner
+func (f *familyUseCase) memberChanged(member model.FamilyMember, jenis, aksi string) {
+	f.repo.CreateActivity(model.FamilyActivity{FamilyId: member.FamilyId, UserId: member.UserId, Jenis: jenis})
+	family, err := f.repo.Get(member.FamilyId)
+	if err != nil {
+		return
+	}
+	f.notify.Notify(model.Notification{
+		UserId:      family.OwnerId,
+		Jenis:       model.NotificationFamilyMember,
+		Judul:       "Anggota keluarga",
+		Pesan:       fmt.Sprintf("%s %s keluarga %s", member.Nama, aksi, family.Nama),
+		ReferenceId: family.Id,
+	})
+}
+
+func (f *familyUseCase) RemoveMember(memberId, ownerId string) error {
+	family, err := f.repo.GetByOwner(ownerId)
+	if err != nil {
+		return err
+	}
+
+	return f.repo.RemoveMember(family.Id, memberId)
+}
+
+// Authorize dipanggil sebelum saldo anggota keluarga dipindahkan. User yang bukan anggota aktif selalu lolos
+// dengan reservasi kosong. Jumlah di atas ambang membutuhkan persetujuan owner: permintaan dibuat lalu transaksi ditolak,
+// setelah disetujui anggota mengulang transaksi yang sama. Persetujuan tetap tunduk pada limit harian.
+// Reservasi yang dikembalikan harus dilepas dengan Release jika pemindahan saldo gagal.
+func (f *familyUseCase) Authorize(payload dto.FamilySpendRequest) (model.FamilyReservation, error) {
+	member, err := f.repo.GetMember(payload.UserId)
+	if err != nil {
+		return model.FamilyReservation{}, err
+	}
+	if member.Status != model.FamilyMemberActive {
+		return model.FamilyReservation{}, nil
+	}
+	if !slices.Contains(member.JenisDiizinkan, payload.Jenis) {
+		return model.FamilyReservation{}, fmt.Errorf("transaksi %s tidak diizinkan oleh owner keluarga", payload.Jenis)
+	}
+
+	reservation := model.FamilyReservation{FamilyId: member.FamilyId, UserId: member.UserId, Jenis: payload.Jenis, Jumlah: payload.Jumlah}
+	if member.AmbangPersetujuan > 0 && payload.Jumlah > member.AmbangPersetujuan {
+		approval, err := f.repo.FindApproval(member.UserId, payload.Jenis, payload.Tujuan, payload.Jumlah, model.FamilyApprovalApproved)
+		if err != nil {
+			return model.FamilyReservation{}, err
+		}
+		if approval.Id == "" {
+			approval, err = f.requestApproval(member, payload)
+			if err != nil {
+				return model.FamilyReservation{}, err
+			}
+			return model.FamilyReservation{}, fmt.Errorf("transaksi di atas %d memerlukan persetujuan owner keluarga, permintaan %s menunggu persetujuan",
+				member.AmbangPersetujuan, approval.Id)
+		}
+		reservation.ApprovalId = approval.Id
+	}
+
+	return f.repo.Reserve(reservation, startOfDay(time.Now()))
+}
+
+// Check menjalankan pemeriksaan Authorize tanpa reservasi maupun permintaan persetujuan, dipakai saat quote.
+// Nilai true berarti transaksi masih memerlukan persetujuan owner yang diminta saat konfirmasi.
+func (f *familyUseCase) Check(payload dto.FamilySpendRequest) (bool, error) {
+	member, err := f.repo.GetMember(payload.UserId)
+	if err != nil {
+		return false, err
+	}
+	if member.Status != model.FamilyMemberActive {
+		return false, nil
+	}
+	if !slices.Contains(member.JenisDiizinkan, payload.Jenis) {
+		return false, fmt.Errorf("transaksi %s tidak diizinkan oleh owner keluarga", payload.Jenis)
+	}
+	if member.LimitHarian > 0 {
+		spent, err := f.repo.GetSpentSince(member.UserId, startOfDay(time.Now()))
+		if err != nil {
+			return false, err
+		}
+		if spent+payload.Jumlah > member.LimitHarian {
+			return false, fmt.Errorf("melebihi limit harian keluarga, sisa limit hari ini %d", max(member.LimitHarian-spent, 0))
+		}
+	}
+	if member.AmbangPersetujuan > 0 && payload.Jumlah > member.AmbangPersetujuan {
+		approval, err := f.repo.FindApproval(member.UserId, payload.Jenis, payload.Tujuan, payload.Jumlah, model.FamilyApprovalApproved)
+		if err != nil {
+			return false, err
+		}
+		return approval.Id == "", nil
+	}
+
+	return false, nil
+}
+
+// Release melepas reservasi dari Authorize, reservasi kosong milik user bukan anggota diabaikan
+func (f *familyUseCase) Release(reservationId string) error {
+	if reservationId == "" {
+		return nil
+	}
+
+	return f.repo.Release(reservationId)
+}
+
+// requestApproval membuat permintaan persetujuan baru, permintaan yang sama dan masih pending dipakai ulang
+func (f *familyUseCase) requestApproval(member model.FamilyMember, payload dto.FamilySpendRequest) (model.FamilyApproval, error) {
+	approval, err := f.repo.FindApproval(member.UserId, payload.Jenis, payload.Tujuan, payload.Jumlah, model.FamilyApprovalPending)
+	if err != nil || approval.Id != "" {
+		return approval, err
+	}
+	approval, err = f.repo.CreateApproval(model.FamilyApproval{
+		FamilyId:   member.FamilyId,
+		UserId:     member.UserId,
+		Jenis:      payload.Jenis,
+		Tujuan:     payload.Tujuan,
+		Keterangan: payload.Keterangan,
+		Jumlah:     payload.Jumlah,
+		ExpiresAt:  time.Now().Add(familyApprovalTTL),
+	})
+	if err != nil {
+		return model.FamilyApproval{}, err
+	}
+	family, err := f.repo.Get(member.FamilyId)
+	if err != nil {
+		return model.FamilyApproval{}, err
+	}
+	f.repo.CreateActivity(model.FamilyActivity{
+		FamilyId:   member.FamilyId,
+		UserId:     member.UserId,
+		Jenis:      model.FamilyActivityApprovalRequest,
+		Jumlah:     payload.Jumlah,
+		Keterangan: payload.Keterangan,
+		Referensi:  approval.Id,
+	})
+	f.notify.Notify(model.Notification{
+		UserId:      family.OwnerId,
+		Jenis:       model.NotificationFamilyApproval,
+		Judul:       "Permintaan persetujuan transaksi",
+		Pesan:       fmt.Sprintf("%s meminta persetujuan %s sebesar %d", member.Nama, payload.Jenis, payload.Jumlah),
+		ReferenceId: approval.Id,
+	})
+
+	return approval, nil
+}
+
+// Record mencatat transaksi anggota yang berhasil ke feed aktivitas keluarga
+func (f *familyUseCase) Record(payload model.FamilyActivity) error {
+	member, err := f.repo.GetMember(payload.UserId)
+	if err != nil || member.Status != model.FamilyMemberActive {
+		return err
+	}
+	payload.FamilyId = member.FamilyId
+
+	return f.repo.CreateActivity(payload)
+}
+
+func (f *familyUseCase) FindApprovals(ownerId, status string) ([]model.FamilyApproval, error) {
+	family, err := f.repo.GetByOwner(ownerId)
+	if err != nil {
+		return []model.FamilyApproval{}, err
+	}
+	datas, err := f.repo.GetApprovals(family.Id, status)
+	if err != nil {
+		return []model.FamilyApproval{}, err
+	}
+	for i := range datas {
+		if datas[i].Status == model.FamilyApprovalPending && time.Now().After(datas[i].ExpiresAt) {
+			datas[i].Status = model.FamilyApprovalExpired
+		}
+	}
+
+	return datas, nil
+}
+
+func (f *familyUseCase) ApproveRequest(id, ownerId string) (model.FamilyApproval, error) {
+	return f.decide(id, ownerId, model.FamilyApprovalApproved)
+}
+
+func (f *familyUseCase) RejectRequest(id, ownerId string) (model.FamilyApproval, error) {
+	return f.decide(id, ownerId, model.FamilyApprovalRejected)
+}
+
+func (f *familyUseCase) decide(id, ownerId, status string) (model.FamilyApproval, error) {
+	family, err := f.repo.GetByOwner(ownerId)
+	if err != nil {
+		return model.FamilyApproval{}, err
+	}
+	approval, err := f.repo.GetApproval(id)
+	if err != nil {
+		return model.FamilyApproval{}, err
+	}
+	if approval.FamilyId != family.Id {
+		return model.FamilyApproval{}, fmt.Errorf("permintaan persetujuan %s tidak ditemukan", id)
+	}
+	decided, err := f.repo.DecideApproval(id, status)
+	if err != nil {
+		return model.FamilyApproval{}, err
+	}
+	if !decided {
+		return model.FamilyApproval{}, fmt.Errorf("permintaan persetujuan sudah diputuskan atau kedaluwarsa")
+	}
+	now := time.Now()
+	approval.Status = status
+	approval.DecidedAt = &now
+
+	jenis, pesan := model.FamilyActivityApprovalApproved, "disetujui, silahkan ulangi transaksi"
+	if status == model.FamilyApprovalRejected {
+		jenis, pesan = model.FamilyActivityApprovalRejected, "ditolak"
+	}
+	f.repo.CreateActivity(model.FamilyActivity{
+		FamilyId:  family.Id,
+		UserId:    approval.UserId,
+		Jenis:     jenis,
+		Jumlah:    approval.Jumlah,
+		Referensi: approval.Id,
+	})
+	f.notify.Notify(model.Notification{
+		UserId:      approval.UserId,
+		Jenis:       model.NotificationFamilyDecision,
+		Judul:       "Keputusan persetujuan transaksi",
+		Pesan:       fmt.Sprintf("Permintaan %s sebesar %d %s", approval.Jenis, approval.Jumlah, pesan),
+		ReferenceId: approval.Id,
+	})
+
+	return approval, nil
+}
+
+func (f *familyUseCase) FindActivity(ownerId string, page int) ([]model.FamilyActivity, error) {
+	family, err := f.repo.GetByOwner(ownerId)
+	if err != nil {
+		return []model.FamilyActivity{}, err
+	}
+	datas, err := f.repo.GetActivity(family.Id, page)
+	if err != nil {
+		return []model.FamilyActivity{}, err
+	}
+
+	return datas, nil
+}
+
+func NewFamilyUseCase(repo repository.FamilyRepository, uc UserUseCase, notify NotificationUseCase) FamilyUseCase {
+	return &familyUseCase{repo: repo, uc: uc, notify: notify}
+}
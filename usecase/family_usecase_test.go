@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type FamilyUseCaseTestSuite struct {
+	suite.Suite
+	frm    *repomock.FamilyRepoMock
+	uum    *usecasemock.UserUseCaseMock
+	num    *usecasemock.NotificationUseCaseMock
+	uf     FamilyUseCase
+	family model.Family
+	member model.FamilyMember
+}
+
+func (suite *FamilyUseCaseTestSuite) SetupTest() {
+	suite.frm = new(repomock.FamilyRepoMock)
+	suite.uum = new(usecasemock.UserUseCaseMock)
+	suite.num = new(usecasemock.NotificationUseCaseMock)
+	suite.num.On("Notify", mock.Anything).Return(nil)
+	suite.frm.On("CreateActivity", mock.Anything).Return(nil)
+	suite.uf = NewFamilyUseCase(suite.frm, suite.uum, suite.num)
+	suite.family = model.Family{Id: "f-1", OwnerId: "ortu", Nama: "Keluarga Santoso"}
+	suite.member = model.FamilyMember{Id: "fm-1", FamilyId: "f-1", UserId: "anak", Nama: "Dimas", LimitHarian: 100000,
+		JenisDiizinkan: []string{model.FamilySpendMerchant}, AmbangPersetujuan: 50000, Status: model.FamilyMemberActive}
+}
+
+func TestFamilyUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(FamilyUseCaseTestSuite))
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_NotMember() {
+	suite.frm.On("GetMember", "a").Return(model.FamilyMember{}, nil)
+
+	reservation, err := suite.uf.Authorize(dto.FamilySpendRequest{UserId: "a", Jenis: model.FamilySpendWithdraw, Jumlah: 1000000})
+	assert.Nil(suite.T(), err)
+	assert.Empty(suite.T(), reservation.Id)
+	suite.frm.AssertNotCalled(suite.T(), "Reserve", mock.Anything, mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_PendingInviteNotRestricted() {
+	suite.member.Status = model.FamilyMemberPending
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+
+	_, err := suite.uf.Authorize(dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendWithdraw, Jumlah: 1000000})
+	assert.Nil(suite.T(), err)
+	suite.frm.AssertNotCalled(suite.T(), "Reserve", mock.Anything, mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_TypeNotAllowed() {
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+
+	_, err := suite.uf.Authorize(dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendTransfer, Jumlah: 1000})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_Reserves() {
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("Reserve", model.FamilyReservation{FamilyId: "f-1", UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 20000}, mock.Anything).
+		Return(model.FamilyReservation{Id: "rsv-1", Status: model.FamilyReservationActive}, nil)
+
+	reservation, err := suite.uf.Authorize(dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 20000})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "rsv-1", reservation.Id)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_RequestsApproval() {
+	spend := dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 75000, Tujuan: "m-1", Keterangan: "Toko Buku"}
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("FindApproval", "anak", spend.Jenis, "m-1", 75000, model.FamilyApprovalApproved).Return(model.FamilyApproval{}, nil)
+	suite.frm.On("FindApproval", "anak", spend.Jenis, "m-1", 75000, model.FamilyApprovalPending).Return(model.FamilyApproval{}, nil)
+	suite.frm.On("CreateApproval", mock.MatchedBy(func(a model.FamilyApproval) bool {
+		return a.FamilyId == "f-1" && a.Jumlah == 75000 && a.Tujuan == "m-1"
+	})).Return(model.FamilyApproval{Id: "ap-1", FamilyId: "f-1", UserId: "anak", Jumlah: 75000}, nil)
+	suite.frm.On("Get", "f-1").Return(suite.family, nil)
+
+	_, err := suite.uf.Authorize(spend)
+	assert.ErrorContains(suite.T(), err, "ap-1")
+	suite.frm.AssertNotCalled(suite.T(), "Reserve", mock.Anything, mock.Anything)
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.UserId == "ortu" && n.Jenis == model.NotificationFamilyApproval && n.ReferenceId == "ap-1"
+	}))
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_PendingApprovalReused() {
+	spend := dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 75000, Tujuan: "m-1"}
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("FindApproval", "anak", spend.Jenis, "m-1", 75000, model.FamilyApprovalApproved).Return(model.FamilyApproval{}, nil)
+	suite.frm.On("FindApproval", "anak", spend.Jenis, "m-1", 75000, model.FamilyApprovalPending).Return(model.FamilyApproval{Id: "ap-1"}, nil)
+
+	_, err := suite.uf.Authorize(spend)
+	assert.ErrorContains(suite.T(), err, "ap-1")
+	suite.frm.AssertNotCalled(suite.T(), "CreateApproval", mock.Anything)
+	suite.num.AssertNotCalled(suite.T(), "Notify", mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAuthorize_ApprovedStillChecksLimit() {
+	spend := dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 150000, Tujuan: "m-1"}
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("FindApproval", "anak", spend.Jenis, "m-1", 150000, model.FamilyApprovalApproved).Return(model.FamilyApproval{Id: "ap-1"}, nil)
+	suite.frm.On("Reserve", mock.MatchedBy(func(r model.FamilyReservation) bool {
+		return r.ApprovalId == "ap-1" && r.Jumlah == 150000
+	}), mock.Anything).Return(model.FamilyReservation{}, errors.New("melebihi limit harian keluarga, sisa limit hari ini 100000"))
+
+	_, err := suite.uf.Authorize(spend)
+	assert.EqualError(suite.T(), err, "melebihi limit harian keluarga, sisa limit hari ini 100000")
+}
+
+func (suite *FamilyUseCaseTestSuite) TestCheck_DoesNotReserve() {
+	spend := dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 60000, Tujuan: "m-1"}
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("GetSpentSince", "anak", mock.Anything).Return(30000, nil)
+	suite.frm.On("FindApproval", "anak", spend.Jenis, "m-1", 60000, model.FamilyApprovalApproved).Return(model.FamilyApproval{}, nil)
+
+	perluPersetujuan, err := suite.uf.Check(spend)
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), perluPersetujuan)
+	suite.frm.AssertNotCalled(suite.T(), "CreateApproval", mock.Anything)
+	suite.frm.AssertNotCalled(suite.T(), "Reserve", mock.Anything, mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestCheck_OverDailyLimit() {
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("GetSpentSince", "anak", mock.Anything).Return(80000, nil)
+
+	_, err := suite.uf.Check(dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendMerchant, Jumlah: 30000})
+	assert.EqualError(suite.T(), err, "melebihi limit harian keluarga, sisa limit hari ini 20000")
+}
+
+func (suite *FamilyUseCaseTestSuite) TestRelease() {
+	suite.frm.On("Release", "rsv-1").Return(nil)
+
+	assert.Nil(suite.T(), suite.uf.Release(""))
+	assert.Nil(suite.T(), suite.uf.Release("rsv-1"))
+	suite.frm.AssertNumberOfCalls(suite.T(), "Release", 1)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAddMember_InvitesPending() {
+	suite.frm.On("GetByOwner", "ortu").Return(suite.family, nil)
+	suite.uum.On("ResolveRecipient", "dimas").Return(model.User{Id: "anak", Name: "Dimas"}, nil)
+	suite.frm.On("GetByOwner", "anak").Return(model.Family{}, errors.New("anda belum memiliki keluarga"))
+	suite.frm.On("AddMember", mock.MatchedBy(func(m model.FamilyMember) bool {
+		return m.FamilyId == "f-1" && m.UserId == "anak"
+	})).Return(model.FamilyMember{Id: "fm-1", FamilyId: "f-1", UserId: "anak", Status: model.FamilyMemberPending}, nil)
+
+	result, err := suite.uf.AddMember(dto.FamilyMemberRequest{OwnerId: "ortu", User: "dimas"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.FamilyMemberPending, result.Status)
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.UserId == "anak" && n.Jenis == model.NotificationFamilyInvite
+	}))
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAcceptInvite_Success() {
+	suite.member.Status = model.FamilyMemberPending
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("GetByOwner", "anak").Return(model.Family{}, errors.New("anda belum memiliki keluarga"))
+	suite.frm.On("ActivateMember", "anak").Return(true, nil)
+	suite.frm.On("Get", "f-1").Return(suite.family, nil)
+
+	result, err := suite.uf.AcceptInvite("anak")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.FamilyMemberActive, result.Status)
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.UserId == "ortu" && n.Jenis == model.NotificationFamilyMember
+	}))
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAcceptInvite_NoInvite() {
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+
+	_, err := suite.uf.AcceptInvite("anak")
+	assert.EqualError(suite.T(), err, "tidak ada undangan keluarga untuk anda")
+	suite.frm.AssertNotCalled(suite.T(), "ActivateMember", mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestLeaveFamily_Success() {
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+	suite.frm.On("RemoveMember", "f-1", "anak").Return(nil)
+	suite.frm.On("Get", "f-1").Return(suite.family, nil)
+
+	err := suite.uf.LeaveFamily("anak")
+	assert.Nil(suite.T(), err)
+	suite.frm.AssertCalled(suite.T(), "CreateActivity", mock.MatchedBy(func(a model.FamilyActivity) bool {
+		return a.Jenis == model.FamilyActivityMemberLeft && a.FamilyId == "f-1"
+	}))
+}
+
+func (suite *FamilyUseCaseTestSuite) TestLeaveFamily_PendingInvite() {
+	suite.member.Status = model.FamilyMemberPending
+	suite.frm.On("GetMember", "anak").Return(suite.member, nil)
+
+	err := suite.uf.LeaveFamily("anak")
+	assert.EqualError(suite.T(), err, "anda bukan anggota keluarga manapun")
+	suite.frm.AssertNotCalled(suite.T(), "RemoveMember", mock.Anything, mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestApproveRequest_OtherFamily() {
+	suite.frm.On("GetByOwner", "ortu").Return(suite.family, nil)
+	suite.frm.On("GetApproval", "ap-9").Return(model.FamilyApproval{Id: "ap-9", FamilyId: "f-2"}, nil)
+
+	_, err := suite.uf.ApproveRequest("ap-9", "ortu")
+	assert.Error(suite.T(), err)
+	suite.frm.AssertNotCalled(suite.T(), "DecideApproval", mock.Anything, mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestApproveRequest_NotifiesMember() {
+	suite.frm.On("GetByOwner", "ortu").Return(suite.family, nil)
+	suite.frm.On("GetApproval", "ap-1").Return(model.FamilyApproval{Id: "ap-1", FamilyId: "f-1", UserId: "anak",
+		Jenis: model.FamilySpendMerchant, Jumlah: 75000, Status: model.FamilyApprovalPending}, nil)
+	suite.frm.On("DecideApproval", "ap-1", model.FamilyApprovalApproved).Return(true, nil)
+
+	result, err := suite.uf.ApproveRequest("ap-1", "ortu")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.FamilyApprovalApproved, result.Status)
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.UserId == "anak" && n.Jenis == model.NotificationFamilyDecision
+	}))
+}
+
+func (suite *FamilyUseCaseTestSuite) TestAddMember_InvalidType() {
+	suite.frm.On("GetByOwner", "ortu").Return(suite.family, nil)
+
+	_, err := suite.uf.AddMember(dto.FamilyMemberRequest{OwnerId: "ortu", User: "dimas", JenisDiizinkan: []string{"crypto"}})
+	assert.Error(suite.T(), err)
+	suite.frm.AssertNotCalled(suite.T(), "AddMember", mock.Anything)
+}
+
+func (suite *FamilyUseCaseTestSuite) TestRecord_NotMember() {
+	suite.frm.On("GetMember", "a").Return(model.FamilyMember{}, nil)
+
+	err := suite.uf.Record(model.FamilyActivity{UserId: "a", Jenis: model.FamilySpendTransfer, Jumlah: 1000})
+	assert.Nil(suite.T(), err)
+	suite.frm.AssertNotCalled(suite.T(), "CreateActivity", mock.Anything)
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+type FeeUseCase interface {
+	Quote(payload dto.FeeQuoteRequest) (model.FeeQuote, error)
+	CreateRule(payload model.FeeRule) (model.FeeRule, error)
+	UpdateRule(id string, payload model.FeeRule) (model.FeeRule, error)
+	DeactivateRule(id string) (model.FeeRule, error)
+	FindRules() ([]model.FeeRule, error)
+	FindFees(userId string, page int) ([]model.FeeLine, error)
+	GetRevenue() (model.HouseWallet, error)
+}
+
+type feeUseCase struct {
+	repo repository.FeeRepository
+}
+
+func (f *feeUseCase) Quote(payload dto.FeeQuoteRequest) (model.FeeQuote, error) {
+	if payload.Jumlah <= 0 {
+		return model.FeeQuote{}, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	quote := model.FeeQuote{
+		Operation: payload.Operation,
+		Channel:   payload.Channel,
+		Jumlah:    payload.Jumlah,
+		Total:     payload.Jumlah,
+	}
+	rule, err := f.repo.FindActiveRule(payload.Operation, payload.Channel)
+	if err != nil {
+		return model.FeeQuote{}, err
+	}
+	if rule.Id == "" {
+		return quote, nil
+	}
+	quote.RuleId = rule.Id
+
+	if rule.FreeQuota > 0 {
+		used, err := f.repo.CountThisMonth(payload.UserId, payload.Operation)
+		if err != nil {
+			return model.FeeQuote{}, err
+		}
+		used += payload.Terpakai
+		if used < rule.FreeQuota {
+			quote.SisaGratis = rule.FreeQuota - used - 1
+			return quote, nil
+		}
+	}
+
+	quote.Biaya = calculateFee(rule, payload.Jumlah)
+	quote.Total = payload.Jumlah + quote.Biaya
+
+	return quote, nil
+}
+
+// calculateFee menghitung biaya dari satu rule tanpa memperhitungkan kuota gratis
+func calculateFee(rule model.FeeRule, jumlah int) int {
+	var fee int
+	switch rule.FeeType {
+	case model.FeeTypeFlat:
+		fee = int(rule.Nilai)
+	case model.FeeTypePercentage:
+		fee = int(math.Ceil(float64(jumlah) * rule.Nilai / 100))
+	case model.FeeTypeTiered:
+		for _, tier := range rule.Tiers {
+			if jumlah >= tier.MinJumlah {
+				fee = tier.Fee
+			}
+		}
+	}
+	if rule.MinFee > 0 && fee < rule.MinFee {
+		fee = rule.MinFee
+	}
+	if rule.MaxFee > 0 && fee > rule.MaxFee {
+		fee = rule.MaxFee
+	}
+
+	return fee
+}
+
+func validateFeeRule(rule model.FeeRule) error {
+	switch rule.Operation {
+	case model.FeeOperationTransfer, model.FeeOperationTopup, model.FeeOperationWithdraw, model.FeeOperationMerchant:
+	default:
+		return fmt.Errorf("operation harus transfer, topup, withdraw atau merchant")
+	}
+	switch rule.FeeType {
+	case model.FeeTypeFlat, model.FeeTypePercentage:
+		if rule.Nilai < 0 {
+			return fmt.Errorf("nilai tidak boleh negatif")
+		}
+		if rule.FeeType == model.FeeTypePercentage && rule.Nilai > 100 {
+			return fmt.Errorf("nilai percentage maksimal 100")
+		}
+	case model.FeeTypeTiered:
+		if len(rule.Tiers) == 0 {
+			return fmt.Errorf("tiers harus diisi untuk fee_type tiered")
+		}
+		for _, tier := range rule.Tiers {
+			if tier.MinJumlah < 0 || tier.Fee < 0 {
+				return fmt.Errorf("min_jumlah dan fee pada tiers tidak boleh negatif")
+			}
+		}
+	default:
+		return fmt.Errorf("fee_type harus flat, percentage atau tiered")
+	}
+	if rule.MinFee < 0 || rule.MaxFee < 0 {
+		return fmt.Errorf("min_fee dan max_fee tidak boleh negatif")
+	}
+	if rule.MaxFee > 0 && rule.MaxFee < rule.MinFee {
+		return fmt.Errorf("max_fee tidak boleh lebih kecil dari min_fee")
+	}
+
+	return nil
+}
+
+func (f *feeUseCase) CreateRule(payload model.FeeRule) (model.FeeRule, error) {
+	if err := validateFeeRule(payload); err != nil {
+		return model.FeeRule{}, err
+	}
+	sort.Slice(payload.Tiers, func(i, j int) bool { return payload.Tiers[i].MinJumlah < payload.Tiers[j].MinJumlah })
+	payload.IsActive = true
+
+	return f.repo.CreateRule(payload)
+}
+
+func (f *feeUseCase) UpdateRule(id string, payload model.FeeRule) (model.FeeRule, error) {
+	if err := validateFeeRule(payload); err != nil {
+		return model.FeeRule{}, err
+	}
+	sort.Slice(payload.Tiers, func(i, j int) bool { return payload.Tiers[i].MinJumlah < payload.Tiers[j].MinJumlah })
+	payload.Id = id
+
+	return f.repo.UpdateRule(payload)
+}
+
+func (f *feeUseCase) DeactivateRule(id string) (model.FeeRule, error) {
+	rule, err := f.repo.GetRule(id)
+	if err != nil {
+		return model.FeeRule{}, err
+	}
+	rule.IsActive = false
+
+	return f.repo.UpdateRule(rule)
+}
+
+func (f *feeUseCase) FindRules() ([]model.FeeRule, error) {
+	datas, err := f.repo.GetRules()
+	if err != nil {
+		return []model.FeeRule{}, err
+	}
+
+	return datas, nil
+}
+
+func (f *feeUseCase) FindFees(userId string, page int) ([]model.FeeLine, error) {
+	datas, err := f.repo.GetFees(userId, page)
+	if err != nil {
+		return []model.FeeLine{}, err
+	}
+
+	return datas, nil
+}
+
+func (f *feeUseCase) GetRevenue() (model.HouseWallet, error) {
+	return f.repo.GetHouseWallet()
+}
+
+func NewFeeUseCase(repo repository.FeeRepository) FeeUseCase {
+	return &feeUseCase{repo: repo}
+}
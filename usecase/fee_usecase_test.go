@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+func TestCalculateFee(t *testing.T) {
+	tiers := []model.FeeTier{{MinJumlah: 0, Fee: 1000}, {MinJumlah: 100000, Fee: 2500}, {MinJumlah: 1000000, Fee: 5000}}
+	cases := []struct {
+		name   string
+		rule   model.FeeRule
+		jumlah int
+		want   int
+	}{
+		{"flat", model.FeeRule{FeeType: model.FeeTypeFlat, Nilai: 2500}, 50000, 2500},
+		{"percentage rounds up", model.FeeRule{FeeType: model.FeeTypePercentage, Nilai: 1.5}, 10001, 151},
+		{"percentage min fee", model.FeeRule{FeeType: model.FeeTypePercentage, Nilai: 1, MinFee: 500}, 10000, 500},
+		{"percentage capped", model.FeeRule{FeeType: model.FeeTypePercentage, Nilai: 2, MaxFee: 10000}, 1000000, 10000},
+		{"tiered lowest", model.FeeRule{FeeType: model.FeeTypeTiered, Tiers: tiers}, 50000, 1000},
+		{"tiered middle", model.FeeRule{FeeType: model.FeeTypeTiered, Tiers: tiers}, 100000, 2500},
+		{"tiered highest", model.FeeRule{FeeType: model.FeeTypeTiered, Tiers: tiers}, 2000000, 5000},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.want, calculateFee(c.rule, c.jumlah))
+		})
+	}
+}
+
+func TestValidateFeeRule(t *testing.T) {
+	cases := []struct {
+		name string
+		rule model.FeeRule
+		want string
+	}{
+		{"valid flat", model.FeeRule{Operation: model.FeeOperationTransfer, FeeType: model.FeeTypeFlat, Nilai: 2500}, ""},
+		{"unknown operation", model.FeeRule{Operation: "refund", FeeType: model.FeeTypeFlat}, "operation harus transfer, topup, withdraw atau merchant"},
+		{"unknown fee type", model.FeeRule{Operation: model.FeeOperationTopup, FeeType: "bebas"}, "fee_type harus flat, percentage atau tiered"},
+		{"negative nilai", model.FeeRule{Operation: model.FeeOperationWithdraw, FeeType: model.FeeTypePercentage, Nilai: -1}, "nilai tidak boleh negatif"},
+		{"tiered without tiers", model.FeeRule{Operation: model.FeeOperationMerchant, FeeType: model.FeeTypeTiered}, "tiers harus diisi untuk fee_type tiered"},
+		{"percentage above 100", model.FeeRule{Operation: model.FeeOperationMerchant, FeeType: model.FeeTypePercentage, Nilai: 150}, "nilai percentage maksimal 100"},
+		{"negative tier fee", model.FeeRule{Operation: model.FeeOperationTransfer, FeeType: model.FeeTypeTiered, Tiers: []model.FeeTier{{MinJumlah: 0, Fee: -500}}}, "min_jumlah dan fee pada tiers tidak boleh negatif"},
+		{"negative min fee", model.FeeRule{Operation: model.FeeOperationTopup, FeeType: model.FeeTypeFlat, MinFee: -100}, "min_fee dan max_fee tidak boleh negatif"},
+		{"negative max fee", model.FeeRule{Operation: model.FeeOperationTopup, FeeType: model.FeeTypeFlat, MaxFee: -100}, "min_fee dan max_fee tidak boleh negatif"},
+		{"max below min", model.FeeRule{Operation: model.FeeOperationTransfer, FeeType: model.FeeTypeFlat, MinFee: 5000, MaxFee: 1000}, "max_fee tidak boleh lebih kecil dari min_fee"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateFeeRule(c.rule)
+			if c.want == "" {
+				assert.Nil(t, err)
+				return
+			}
+			assert.EqualError(t, err, c.want)
+		})
+	}
+}
+
+func TestQuote_NoActiveRule(t *testing.T) {
+	frm := new(repomock.FeeRepoMock)
+	frm.On("FindActiveRule", model.FeeOperationTopup, "bca").Return(model.FeeRule{}, nil)
+
+	quote, err := NewFeeUseCase(frm).Quote(dto.FeeQuoteRequest{UserId: "u-1", Operation: model.FeeOperationTopup, Channel: "bca", Jumlah: 50000})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, quote.Biaya)
+	assert.Equal(t, 50000, quote.Total)
+	assert.Empty(t, quote.RuleId)
+}
+
+func TestQuote_UsesFreeQuota(t *testing.T) {
+	frm := new(repomock.FeeRepoMock)
+	frm.On("FindActiveRule", model.FeeOperationWithdraw, "").
+		Return(model.FeeRule{Id: "r-1", FeeType: model.FeeTypeFlat, Nilai: 6500, FreeQuota: 3}, nil)
+	frm.On("CountThisMonth", "u-1", model.FeeOperationWithdraw).Return(1, nil)
+
+	quote, err := NewFeeUseCase(frm).Quote(dto.FeeQuoteRequest{UserId: "u-1", Operation: model.FeeOperationWithdraw, Jumlah: 100000})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, quote.Biaya)
+	assert.Equal(t, 1, quote.SisaGratis)
+	assert.Equal(t, "r-1", quote.RuleId)
+}
+
+func TestQuote_ChargesAfterFreeQuota(t *testing.T) {
+	frm := new(repomock.FeeRepoMock)
+	frm.On("FindActiveRule", model.FeeOperationWithdraw, "").
+		Return(model.FeeRule{Id: "r-1", FeeType: model.FeeTypeFlat, Nilai: 6500, FreeQuota: 3}, nil)
+	frm.On("CountThisMonth", "u-1", model.FeeOperationWithdraw).Return(3, nil)
+
+	quote, err := NewFeeUseCase(frm).Quote(dto.FeeQuoteRequest{UserId: "u-1", Operation: model.FeeOperationWithdraw, Jumlah: 100000})
+	assert.Nil(t, err)
+	assert.Equal(t, 6500, quote.Biaya)
+	assert.Equal(t, 106500, quote.Total)
+	assert.Equal(t, "r-1", quote.RuleId)
+}
+
+func TestQuote_EarlierBatchItemsUseFreeQuota(t *testing.T) {
+	frm := new(repomock.FeeRepoMock)
+	frm.On("FindActiveRule", model.FeeOperationMerchant, "").
+		Return(model.FeeRule{Id: "r-1", FeeType: model.FeeTypeFlat, Nilai: 1000, FreeQuota: 3}, nil)
+	frm.On("CountThisMonth", "u-1", model.FeeOperationMerchant).Return(1, nil)
+
+	quote, err := NewFeeUseCase(frm).Quote(dto.FeeQuoteRequest{UserId: "u-1", Operation: model.FeeOperationMerchant, Jumlah: 50000, Terpakai: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, 1000, quote.Biaya)
+}
+
+func TestQuote_InvalidJumlah(t *testing.T) {
+	frm := new(repomock.FeeRepoMock)
+
+	_, err := NewFeeUseCase(frm).Quote(dto.FeeQuoteRequest{UserId: "u-1", Operation: model.FeeOperationTransfer, Jumlah: 0})
+	assert.EqualError(t, err, "jumlah harus lebih dari 0")
+	frm.AssertNotCalled(t, "FindActiveRule", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+type HoldUseCase interface {
+	Authorize(payload dto.HoldRequest) (model.Hold, error)
+	FindActive(userId string) ([]model.Hold, error)
+	Capture(id string, payload dto.CaptureRequest) (model.Hold, error)
+	Void(id, userId, role string) (model.Hold, error)
+	MerchantCapture(id, merchantId string, jumlah int) (model.Hold, error)
+	MerchantVoid(id, merchantId string) (model.Hold, error)
+	ExpireDue() (int, error)
+}
+
+// batas lama hold merchant dalam menit, hold merchant tidak bisa dilepas user sehingga wajib kedaluwarsa
+const holdMerchantMaxTTL = 7 * 24 * 60
+
+type holdUseCase struct {
+	repo repository.HoldRepository
+}
+
+func (h *holdUseCase) Authorize(payload dto.HoldRequest) (model.Hold, error) {
+	if payload.Jumlah <= 0 {
+		return model.Hold{}, fmt.Errorf("jumlah hold harus lebih dari 0")
+	}
+	hold := model.Hold{
+		UserId:      payload.UserId,
+		Jumlah:      payload.Jumlah,
+		Alasan:      payload.Alasan,
+		ReferenceId: payload.ReferenceId,
+		Sumber:      model.HoldSourceUser,
+	}
+	if payload.MerchantId != "" {
+		if payload.ExpiresIn <= 0 || payload.ExpiresIn > holdMerchantMaxTTL {
+			return model.Hold{}, fmt.Errorf("expires_in hold merchant harus antara 1 dan %d menit", holdMerchantMaxTTL)
+		}
+		hold.Sumber = model.HoldSourceMerchant
+		hold.PenerimaJenis = model.HoldPayeeMerchant
+		hold.PenerimaId = payload.MerchantId
+	}
+	if payload.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(payload.ExpiresIn) * time.Minute)
+		hold.ExpiresAt = &expiresAt
+	}
+
+	return h.repo.Authorize(hold)
+}
+
+func (h *holdUseCase) FindActive(userId string) ([]model.Hold, error) {
+	if _, err := h.repo.ExpireDue(); err != nil {
+		return []model.Hold{}, err
+	}
+	datas, err := h.repo.GetActive(userId)
+	if err != nil {
+		return []model.Hold{}, err
+	}
+
+	return datas, nil
+}
+
+func (h *holdUseCase) Capture(id string, payload dto.CaptureRequest) (model.Hold, error) {
+	switch payload.PenerimaJenis {
+	case model.HoldPayeeMerchant, model.HoldPayeeUser:
+		if payload.PenerimaId == "" {
+			return model.Hold{}, fmt.Errorf("penerima_id wajib diisi untuk penerima %s", payload.PenerimaJenis)
+		}
+	case model.HoldPayeeHouse:
+	default:
+		return model.Hold{}, fmt.Errorf("penerima_jenis harus merchant, user atau house")
+	}
+
+	return h.repo.Capture(id, payload.Jumlah, payload.PenerimaJenis, payload.PenerimaId)
+}
+
+func (h *holdUseCase) Void(id, userId, role string) (model.Hold, error) {
+	hold, err := h.repo.Get(id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if role != "admin" && hold.UserId != userId {
+		return model.Hold{}, fmt.Errorf("hold %s tidak ditemukan", id)
+	}
+	// hold withdraw dilepas lewat proses withdraw, hold dispute hanya oleh admin
+	if hold.Sumber == model.HoldSourceWithdraw || (role != "admin" && hold.Sumber != model.HoldSourceUser) {
+		return model.Hold{}, fmt.Errorf("hold %s dikelola oleh proses %s dan tidak bisa dilepas manual", id, hold.Sumber)
+	}
+
+	return h.repo.Void(id)
+}
+
+func (h *holdUseCase) MerchantCapture(id, merchantId string, jumlah int) (model.Hold, error) {
+	if _, err := h.findMerchantHold(id, merchantId); err != nil {
+		return model.Hold{}, err
+	}
+
+	return h.repo.Capture(id, jumlah, model.HoldPayeeMerchant, merchantId)
+}
+
+func (h *holdUseCase) MerchantVoid(id, merchantId string) (model.Hold, error) {
+	if _, err := h.findMerchantHold(id, merchantId); err != nil {
+		return model.Hold{}, err
+	}
+
+	return h.repo.Void(id)
+}
+
+// findMerchantHold memastikan hold dibuat untuk merchant pemilik API key
+func (h *holdUseCase) findMerchantHold(id, merchantId string) (model.Hold, error) {
+	hold, err := h.repo.Get(id)
+	if err != nil {
+		return model.Hold{}, err
+	}
+	if hold.Sumber != model.HoldSourceMerchant || hold.PenerimaId != merchantId {
+		return model.Hold{}, fmt.Errorf("hold %s tidak ditemukan", id)
+	}
+
+	return hold, nil
+}
+
+func (h *holdUseCase) ExpireDue() (int, error) {
+	return h.repo.ExpireDue()
+}
+
+func NewHoldUseCase(repo repository.HoldRepository) HoldUseCase {
+	return &holdUseCase{repo: repo}
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type HoldUseCaseTestSuite struct {
+	suite.Suite
+	hrm *repomock.HoldRepoMock
+	uh  HoldUseCase
+}
+
+func (suite *HoldUseCaseTestSuite) SetupTest() {
+	suite.hrm = new(repomock.HoldRepoMock)
+	suite.uh = NewHoldUseCase(suite.hrm)
+}
+
+func TestHoldUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(HoldUseCaseTestSuite))
+}
+
+func (suite *HoldUseCaseTestSuite) TestAuthorize_Success() {
+	suite.hrm.On("Authorize", mock.MatchedBy(func(hold model.Hold) bool {
+		return hold.UserId == "u-1" && hold.Jumlah == 50000 && hold.Sumber == model.HoldSourceUser && hold.ExpiresAt != nil
+	})).Return(model.Hold{Id: "h-1", UserId: "u-1", Jumlah: 50000, Status: model.HoldStatusActive}, nil)
+
+	result, err := suite.uh.Authorize(dto.HoldRequest{UserId: "u-1", Jumlah: 50000, ExpiresIn: 30})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "h-1", result.Id)
+}
+
+func (suite *HoldUseCaseTestSuite) TestAuthorize_InvalidJumlah() {
+	_, err := suite.uh.Authorize(dto.HoldRequest{UserId: "u-1", Jumlah: 0})
+	assert.EqualError(suite.T(), err, "jumlah hold harus lebih dari 0")
+	suite.hrm.AssertNotCalled(suite.T(), "Authorize", mock.Anything)
+}
+
+func (suite *HoldUseCaseTestSuite) TestAuthorize_MerchantHold() {
+	suite.hrm.On("Authorize", mock.MatchedBy(func(hold model.Hold) bool {
+		return hold.Sumber == model.HoldSourceMerchant && hold.PenerimaJenis == model.HoldPayeeMerchant && hold.PenerimaId == "m-1"
+	})).Return(model.Hold{Id: "h-1", Sumber: model.HoldSourceMerchant, PenerimaId: "m-1"}, nil)
+
+	_, err := suite.uh.Authorize(dto.HoldRequest{UserId: "u-1", Jumlah: 50000, MerchantId: "m-1"})
+	assert.EqualError(suite.T(), err, "expires_in hold merchant harus antara 1 dan 10080 menit")
+
+	result, err := suite.uh.Authorize(dto.HoldRequest{UserId: "u-1", Jumlah: 50000, MerchantId: "m-1", ExpiresIn: 60})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "m-1", result.PenerimaId)
+}
+
+func (suite *HoldUseCaseTestSuite) TestMerchantCapture_OwnHold() {
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", Sumber: model.HoldSourceMerchant, PenerimaId: "m-1"}, nil)
+	suite.hrm.On("Capture", "h-1", 0, model.HoldPayeeMerchant, "m-1").
+		Return(model.Hold{Id: "h-1", Jumlah: 50000, JumlahCaptured: 50000, Status: model.HoldStatusCaptured}, nil)
+
+	result, err := suite.uh.MerchantCapture("h-1", "m-1", 0)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 50000, result.JumlahCaptured)
+}
+
+func (suite *HoldUseCaseTestSuite) TestMerchantVoid_OtherMerchant() {
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", Sumber: model.HoldSourceMerchant, PenerimaId: "m-1"}, nil)
+
+	_, err := suite.uh.MerchantVoid("h-1", "m-2")
+	assert.EqualError(suite.T(), err, "hold h-1 tidak ditemukan")
+	suite.hrm.AssertNotCalled(suite.T(), "Void", "h-1")
+}
+
+func (suite *HoldUseCaseTestSuite) TestCapture_PartialToMerchant() {
+	suite.hrm.On("Capture", "h-1", 20000, model.HoldPayeeMerchant, "m-1").
+		Return(model.Hold{Id: "h-1", Jumlah: 50000, JumlahCaptured: 20000, Status: model.HoldStatusCaptured}, nil)
+
+	result, err := suite.uh.Capture("h-1", dto.CaptureRequest{Jumlah: 20000, PenerimaJenis: model.HoldPayeeMerchant, PenerimaId: "m-1"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 20000, result.JumlahCaptured)
+}
+
+func (suite *HoldUseCaseTestSuite) TestCapture_UnknownPayee() {
+	_, err := suite.uh.Capture("h-1", dto.CaptureRequest{PenerimaJenis: "bank"})
+	assert.EqualError(suite.T(), err, "penerima_jenis harus merchant, user atau house")
+
+	_, err = suite.uh.Capture("h-1", dto.CaptureRequest{PenerimaJenis: model.HoldPayeeUser})
+	assert.EqualError(suite.T(), err, "penerima_id wajib diisi untuk penerima user")
+}
+
+func (suite *HoldUseCaseTestSuite) TestVoid_OwnHold() {
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", UserId: "u-1", Sumber: model.HoldSourceUser, Status: model.HoldStatusActive}, nil)
+	suite.hrm.On("Void", "h-1").Return(model.Hold{Id: "h-1", Status: model.HoldStatusVoided}, nil)
+
+	result, err := suite.uh.Void("h-1", "u-1", "user")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.HoldStatusVoided, result.Status)
+}
+
+func (suite *HoldUseCaseTestSuite) TestVoid_DisputeHoldByUser() {
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", UserId: "u-1", Sumber: model.HoldSourceDispute, Status: model.HoldStatusActive}, nil)
+
+	_, err := suite.uh.Void("h-1", "u-1", "user")
+	assert.EqualError(suite.T(), err, "hold h-1 dikelola oleh proses dispute dan tidak bisa dilepas manual")
+	suite.hrm.AssertNotCalled(suite.T(), "Void", "h-1")
+}
+
+func (suite *HoldUseCaseTestSuite) TestVoid_WithdrawHoldByAdmin() {
+	suite.hrm.On("Get", "h-1").Return(model.Hold{Id: "h-1", UserId: "u-1", Sumber: model.HoldSourceWithdraw, Status: model.HoldStatusActive}, nil)
+
+	_, err := suite.uh.Void("h-1", "admin-1", "admin")
+	assert.EqualError(suite.T(), err, "hold h-1 dikelola oleh proses withdraw dan tidak bisa dilepas manual")
+	suite.hrm.AssertNotCalled(suite.T(), "Void", "h-1")
+}
+
+func (suite *HoldUseCaseTestSuite) TestFindActive_ExpiresFirst() {
+	suite.hrm.On("ExpireDue").Return(2, nil)
+	suite.hrm.On("GetActive", "u-1").Return([]model.Hold{{Id: "h-2"}}, nil)
+
+	datas, err := suite.uh.FindActive("u-1")
+	assert.Nil(suite.T(), err)
+	assert.Len(suite.T(), datas, 1)
+	suite.hrm.AssertCalled(suite.T(), "ExpireDue")
+}
@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/encryption"
+)
+
+const (
+	// selisih maksimal timestamp request dengan waktu server
+	signatureTolerance = 5 * time.Minute
+	// nonce disimpan lebih lama dari toleransi agar replay di kedua sisi window tetap tertolak
+	nonceRetention = 2 * signatureTolerance
+)
+
+type MerchantKeyUseCase interface {
+	CreateKey(merchantId, ownerId string, scopes []string) (model.MerchantApiKey, error)
+	FindKeys(merchantId, ownerId string) ([]model.MerchantApiKey, error)
+	RotateKey(id, merchantId, ownerId string) (model.MerchantApiKey, error)
+	RevokeKey(id, merchantId, ownerId string) (model.MerchantApiKey, error)
+	Authenticate(payload dto.SignedRequest) (model.MerchantApiKey, error)
+}
+
+type merchantKeyUseCase struct {
+	repo repository.MerchantKeyRepository
+	um   MerchantUseCase
+}
+
+// SignMerchantRequest menghasilkan tanda tangan HMAC-SHA256 (hex) atas
+// METHOD\nPATH\nTIMESTAMP\nNONCE\nSHA256(BODY), path termasuk query string
+func SignMerchantRequest(secret, method, path, timestamp, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func validateScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("scope harus diisi")
+	}
+	seen := map[string]bool{}
+	var result []string
+	for _, scope := range scopes {
+		valid := false
+		for _, s := range model.MerchantScopes {
+			if s == scope {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("scope %s tidak dikenal", scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+func newMerchantKey(merchantId string, scopes []string) (model.MerchantApiKey, error) {
+	keyId, err := randomHex(12)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	secret = "sk_" + secret
+	secretEnc, err := encryption.EncryptSecret(secret)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+
+	return model.MerchantApiKey{
+		MerchantId: merchantId,
+		KeyId:      "mk_" + keyId,
+		Secret:     secret,
+		SecretEnc:  secretEnc,
+		Scopes:     scopes,
+	}, nil
+}
+
+// CreateKey membuat pasangan key id dan secret, secret hanya ditampilkan sekali
+func (m *merchantKeyUseCase) CreateKey(merchantId, ownerId string, scopes []string) (model.MerchantApiKey, error) {
+	if _, err := m.um.FindMerchant(merchantId, ownerId); err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	scopes, err := validateScopes(scopes)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	key, err := newMerchantKey(merchantId, scopes)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	created, err := m.repo.Create(key)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	created.Secret = key.Secret
+
+	return created, nil
+}
+
+func (m *merchantKeyUseCase) FindKeys(merchantId, ownerId string) ([]model.MerchantApiKey, error) {
+	if _, err := m.um.FindMerchant(merchantId, ownerId); err != nil {
+		return []model.MerchantApiKey{}, err
+	}
+	datas, err := m.repo.GetByMerchant(merchantId)
+	if err != nil {
+		return []model.MerchantApiKey{}, err
+	}
+
+	return datas, nil
+}
+
+func (m *merchantKeyUseCase) findKey(id, merchantId, ownerId string) (model.MerchantApiKey, error) {
+	if _, err := m.um.FindMerchant(merchantId, ownerId); err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	key, err := m.repo.Get(id)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if key.MerchantId != merchantId {
+		return model.MerchantApiKey{}, fmt.Errorf("API key %s tidak ditemukan", id)
+	}
+
+	return key, nil
+}
+
+// RotateKey mengganti key dengan pasangan baru berscope sama, key lama langsung tidak berlaku
+func (m *merchantKeyUseCase) RotateKey(id, merchantId, ownerId string) (model.MerchantApiKey, error) {
+	old, err := m.findKey(id, merchantId, ownerId)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if old.Status != model.ApiKeyStatusActive {
+		return model.MerchantApiKey{}, fmt.Errorf("API key sudah dicabut")
+	}
+	key, err := newMerchantKey(merchantId, old.Scopes)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	rotated, err := m.repo.Rotate(id, key)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	rotated.Secret = key.Secret
+
+	return rotated, nil
+}
+
+func (m *merchantKeyUseCase) RevokeKey(id, merchantId, ownerId string) (model.MerchantApiKey, error) {
+	if _, err := m.findKey(id, merchantId, ownerId); err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if err := m.repo.Revoke(id); err != nil {
+		return model.MerchantApiKey{}, err
+	}
+
+	return m.repo.Get(id)
+}
+
+// Authenticate memeriksa key, timestamp, tanda tangan, status merchant lalu nonce. Nonce baru dicatat setelah tanda tangan valid
+// agar pihak lain tidak bisa menghabiskan nonce milik merchant.
+func (m *merchantKeyUseCase) Authenticate(payload dto.SignedRequest) (model.MerchantApiKey, error) {
+	if payload.KeyId == "" || payload.Timestamp == "" || payload.Nonce == "" || payload.Signature == "" {
+		return model.MerchantApiKey{}, fmt.Errorf("header X-Api-Key, X-Timestamp, X-Nonce dan X-Signature wajib diisi")
+	}
+	if len(payload.Nonce) > 64 {
+		return model.MerchantApiKey{}, fmt.Errorf("nonce maksimal 64 karakter")
+	}
+	unix, err := strconv.ParseInt(payload.Timestamp, 10, 64)
+	if err != nil {
+		return model.MerchantApiKey{}, fmt.Errorf("timestamp tidak valid")
+	}
+	skew := time.Since(time.Unix(unix, 0))
+	if skew > signatureTolerance || skew < -signatureTolerance {
+		return model.MerchantApiKey{}, fmt.Errorf("timestamp di luar batas toleransi")
+	}
+
+	key, err := m.repo.GetByKeyId(payload.KeyId)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if key.Status != model.ApiKeyStatusActive {
+		return model.MerchantApiKey{}, fmt.Errorf("API key sudah dicabut")
+	}
+	secret, err := encryption.DecryptSecret(key.SecretEnc)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	expected := SignMerchantRequest(secret, payload.Method, payload.Path, payload.Timestamp, payload.Nonce, payload.Body)
+	if !hmac.Equal([]byte(expected), []byte(payload.Signature)) {
+		return model.MerchantApiKey{}, fmt.Errorf("tanda tangan tidak valid")
+	}
+	merchant, err := m.um.FindMerchant(key.MerchantId, key.OwnerId)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if merchant.Status != model.MerchantStatusActive {
+		return model.MerchantApiKey{}, fmt.Errorf("merchant %s tidak aktif", key.MerchantId)
+	}
+
+	fresh, err := m.repo.UseNonce(key.KeyId, payload.Nonce, nonceRetention)
+	if err != nil {
+		return model.MerchantApiKey{}, err
+	}
+	if !fresh {
+		return model.MerchantApiKey{}, fmt.Errorf("nonce sudah dipakai")
+	}
+	m.repo.Touch(key.Id)
+
+	return key, nil
+}
+
+func NewMerchantKeyUseCase(repo repository.MerchantKeyRepository, um MerchantUseCase) MerchantKeyUseCase {
+	return &merchantKeyUseCase{repo: repo, um: um}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/utils/encryption"
+)
+
+type MerchantKeyUseCaseTestSuite struct {
+	suite.Suite
+	mkm    *repomock.MerchantKeyRepoMock
+	mrm    *repomock.MerchantRepoMock
+	uk     MerchantKeyUseCase
+	secret string
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) SetupTest() {
+	suite.mkm = new(repomock.MerchantKeyRepoMock)
+	suite.mrm = new(repomock.MerchantRepoMock)
+	suite.uk = NewMerchantKeyUseCase(suite.mkm, NewMerchantUseCase(suite.mrm, nil, nil, nil, nil))
+	suite.secret = "sk_test"
+	suite.T().Setenv("API_SECRET_KEY", "test-secret-key")
+	secretEnc, err := encryption.EncryptSecret(suite.secret)
+	suite.Require().Nil(err)
+	suite.mkm.On("GetByKeyId", "mk_1").Return(model.MerchantApiKey{Id: "key-1", MerchantId: "m-1", OwnerId: "u-1", KeyId: "mk_1",
+		SecretEnc: secretEnc, Scopes: []string{model.MerchantScopePaymentsRead}, Status: model.ApiKeyStatusActive}, nil)
+	suite.mkm.On("Touch", "key-1").Return(nil)
+}
+
+func TestMerchantKeyUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantKeyUseCaseTestSuite))
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) signed(timestamp time.Time, nonce string) dto.SignedRequest {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	body := []byte(`{"jumlah":10000}`)
+	return dto.SignedRequest{
+		KeyId:     "mk_1",
+		Timestamp: ts,
+		Nonce:     nonce,
+		Signature: SignMerchantRequest(suite.secret, "POST", "/api/v1/merchant/qr", ts, nonce, body),
+		Method:    "POST",
+		Path:      "/api/v1/merchant/qr",
+		Body:      body,
+	}
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) TestAuthenticate_Success() {
+	suite.mrm.On("Get", "m-1").Return(model.Merchant{Id: "m-1", OwnerId: "u-1", Status: model.MerchantStatusActive}, nil)
+	suite.mkm.On("UseNonce", "mk_1", "n-1", nonceRetention).Return(true, nil)
+
+	actual, err := suite.uk.Authenticate(suite.signed(time.Now(), "n-1"))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "m-1", actual.MerchantId)
+	assert.False(suite.T(), actual.HasScope(model.MerchantScopeQRWrite))
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) TestAuthenticate_SuspendedMerchant() {
+	suite.mrm.On("Get", "m-1").Return(model.Merchant{Id: "m-1", OwnerId: "u-1", Status: model.MerchantStatusSuspended}, nil)
+
+	_, err := suite.uk.Authenticate(suite.signed(time.Now(), "n-1"))
+	assert.EqualError(suite.T(), err, "merchant m-1 tidak aktif")
+	suite.mkm.AssertNotCalled(suite.T(), "UseNonce", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) TestAuthenticate_ReplayedNonce() {
+	suite.mrm.On("Get", "m-1").Return(model.Merchant{Id: "m-1", OwnerId: "u-1", Status: model.MerchantStatusActive}, nil)
+	suite.mkm.On("UseNonce", "mk_1", "n-1", nonceRetention).Return(false, nil)
+
+	_, err := suite.uk.Authenticate(suite.signed(time.Now(), "n-1"))
+	assert.EqualError(suite.T(), err, "nonce sudah dipakai")
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) TestAuthenticate_StaleTimestamp() {
+	_, err := suite.uk.Authenticate(suite.signed(time.Now().Add(-10*time.Minute), "n-1"))
+	assert.Error(suite.T(), err)
+	suite.mkm.AssertNotCalled(suite.T(), "UseNonce", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *MerchantKeyUseCaseTestSuite) TestAuthenticate_TamperedBodyKeepsNonce() {
+	request := suite.signed(time.Now(), "n-1")
+	request.Body = []byte(`{"jumlah":1}`)
+
+	_, err := suite.uk.Authenticate(request)
+	assert.EqualError(suite.T(), err, "tanda tangan tidak valid")
+	suite.mkm.AssertNotCalled(suite.T(), "UseNonce", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestValidateScopes(t *testing.T) {
+	scopes, err := validateScopes([]string{model.MerchantScopeQRWrite, model.MerchantScopeQRWrite})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{model.MerchantScopeQRWrite}, scopes)
+
+	_, err = validateScopes([]string{"admin"})
+	assert.Error(t, err)
+}
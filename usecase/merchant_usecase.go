@@ -0,0 +1,331 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/bank"
+	"github.com/yafireyhan01/e-wallet/utils/qris"
+)
+
+// masa berlaku default dan maksimal QR dinamis
+const (
+	merchantQRTTL    = 15 * time.Minute
+	merchantQRMaxTTL = 24 * time.Hour
+)
+
+// merchant category code (ISO 18245) 4 digit
+var mccPattern = regexp.MustCompile(`^[0-9]{4}$`)
+
+type MerchantUseCase interface {
+	RegisterMerchant(payload dto.MerchantRequest) (model.Merchant, error)
+	FindMerchants(ownerId string) ([]model.Merchant, error)
+	FindMerchant(id, ownerId string) (model.Merchant, error)
+	UpdateMerchant(id string, payload dto.MerchantRequest) (model.Merchant, error)
+	StaticQR(id, ownerId string) (model.MerchantQR, error)
+	CreateDynamicQR(id, ownerId string, payload dto.MerchantQRRequest) (model.MerchantQR, error)
+	PreviewQR(raw string) (model.QRPreview, error)
+	PayQR(payload dto.QRPaymentRequest) (model.MerchantPayment, error)
+	FindPayments(id, ownerId string, page int) ([]model.MerchantPayment, error)
+	FindPayment(id, merchantId string) (model.MerchantPayment, error)
+	UpdateSettlementConfig(id, ownerId string, payload dto.SettlementConfigRequest) (model.Merchant, error)
+}
+
+type merchantUseCase struct {
+	repo    repository.MerchantRepository
+	inquiry bank.BankInquiry
+	webhook WebhookUseCase
+	budget  BudgetUseCase
+	family  FamilyUseCase
+}
+
+func (m *merchantUseCase) validateMerchant(payload dto.MerchantRequest) error {
+	if !mccPattern.MatchString(payload.Kategori) {
+		return fmt.Errorf("kategori harus berupa kode MCC 4 digit")
+	}
+	if len(payload.Nama) > 100 || len(payload.Kota) > 50 || len(payload.Alamat) > 250 {
+		return fmt.Errorf("nama maksimal 100, kota maksimal 50 dan alamat maksimal 250 karakter")
+	}
+	result, err := m.inquiry.Inquire(bank.InquiryRequest{
+		BankCode:    payload.BankCode,
+		Rekening:    payload.Rekening,
+		NamaPemilik: payload.NamaPemilik,
+	})
+	if err != nil {
+		return err
+	}
+	if !bank.SameName(result.NamaPemilik, payload.NamaPemilik) {
+		return fmt.Errorf("nama pemilik rekening settlement (%s) tidak sesuai", result.NamaPemilik)
+	}
+
+	return nil
+}
+
+// RegisterMerchant mendaftarkan merchant baru milik user, rekening settlement diperiksa ke bank terlebih dahulu
+func (m *merchantUseCase) RegisterMerchant(payload dto.MerchantRequest) (model.Merchant, error) {
+	if err := m.validateMerchant(payload); err != nil {
+		return model.Merchant{}, err
+	}
+
+	return m.repo.Create(model.Merchant{
+		OwnerId:     payload.OwnerId,
+		Nama:        strings.TrimSpace(payload.Nama),
+		Kategori:    payload.Kategori,
+		Kota:        strings.TrimSpace(payload.Kota),
+		Alamat:      strings.TrimSpace(payload.Alamat),
+		BankCode:    payload.BankCode,
+		Rekening:    payload.Rekening,
+		NamaPemilik: payload.NamaPemilik,
+	})
+}
+
+func (m *merchantUseCase) FindMerchants(ownerId string) ([]model.Merchant, error) {
+	datas, err := m.repo.GetByOwner(ownerId)
+	if err != nil {
+		return []model.Merchant{}, err
+	}
+
+	return datas, nil
+}
+
+func (m *merchantUseCase) FindMerchant(id, ownerId string) (model.Merchant, error) {
+	merchant, err := m.repo.Get(id)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+	if merchant.OwnerId != ownerId {
+		return model.Merchant{}, fmt.Errorf("merchant %s tidak ditemukan", id)
+	}
+
+	return merchant, nil
+}
+
+func (m *merchantUseCase) UpdateMerchant(id string, payload dto.MerchantRequest) (model.Merchant, error) {
+	merchant, err := m.FindMerchant(id, payload.OwnerId)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+	if err := m.validateMerchant(payload); err != nil {
+		return model.Merchant{}, err
+	}
+	merchant.Nama = strings.TrimSpace(payload.Nama)
+	merchant.Kategori = payload.Kategori
+	merchant.Kota = strings.TrimSpace(payload.Kota)
+	merchant.Alamat = strings.TrimSpace(payload.Alamat)
+	merchant.BankCode = payload.BankCode
+	merchant.Rekening = payload.Rekening
+	merchant.NamaPemilik = payload.NamaPemilik
+
+	return m.repo.Update(merchant)
+}
+
+// StaticQR dicetak sekali dan dipajang di kasir, nominal diisi user saat membayar
+func (m *merchantUseCase) StaticQR(id, ownerId string) (model.MerchantQR, error) {
+	merchant, err := m.FindMerchant(id, ownerId)
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+
+	return model.MerchantQR{
+		MerchantId: merchant.Id,
+		Jenis:      model.MerchantQRStatic,
+		Payload:    qris.Encode(merchantPayload(merchant)),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// CreateDynamicQR membuat QR sekali pakai dengan nominal tetap
+func (m *merchantUseCase) CreateDynamicQR(id, ownerId string, payload dto.MerchantQRRequest) (model.MerchantQR, error) {
+	merchant, err := m.FindMerchant(id, ownerId)
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+	if merchant.Status != model.MerchantStatusActive {
+		return model.MerchantQR{}, fmt.Errorf("merchant tidak aktif")
+	}
+	if payload.Jumlah <= 0 {
+		return model.MerchantQR{}, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	if len(payload.Referensi) > 100 {
+		return model.MerchantQR{}, fmt.Errorf("referensi maksimal 100 karakter")
+	}
+	if payload.ExpiresIn > int(merchantQRMaxTTL/time.Minute) {
+		return model.MerchantQR{}, fmt.Errorf("masa berlaku QR maksimal 24 jam")
+	}
+	ttl := merchantQRTTL
+	if payload.ExpiresIn > 0 {
+		ttl = time.Duration(payload.ExpiresIn) * time.Minute
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	qr, err := m.repo.CreateQR(model.MerchantQR{
+		MerchantId: merchant.Id,
+		Jenis:      model.MerchantQRDynamic,
+		Jumlah:     payload.Jumlah,
+		Referensi:  payload.Referensi,
+		ExpiresAt:  &expiresAt,
+	})
+	if err != nil {
+		return model.MerchantQR{}, err
+	}
+	qrPayload := merchantPayload(merchant)
+	qrPayload.Dynamic = true
+	qrPayload.Jumlah = qr.Jumlah
+	qrPayload.Referensi = qr.Id
+	qr.Payload = qris.Encode(qrPayload)
+
+	return qr, nil
+}
+
+func merchantPayload(merchant model.Merchant) qris.Payload {
+	return qris.Payload{
+		MerchantId:   merchant.Id,
+		MerchantName: merchant.Nama,
+		MerchantCity: merchant.Kota,
+		CategoryCode: merchant.Kategori,
+	}
+}
+
+// resolveQR membaca payload QR, nominal QR dinamis selalu diambil dari database, bukan dari payload
+func (m *merchantUseCase) resolveQR(raw string) (model.Merchant, model.MerchantQR, error) {
+	payload, err := qris.Decode(raw)
+	if err != nil {
+		return model.Merchant{}, model.MerchantQR{}, err
+	}
+	merchant, err := m.repo.Get(payload.MerchantId)
+	if err != nil {
+		return model.Merchant{}, model.MerchantQR{}, err
+	}
+	if merchant.Status != model.MerchantStatusActive {
+		return model.Merchant{}, model.MerchantQR{}, fmt.Errorf("merchant tidak aktif")
+	}
+	if !payload.Dynamic {
+		return merchant, model.MerchantQR{MerchantId: merchant.Id, Jenis: model.MerchantQRStatic}, nil
+	}
+
+	qr, err := m.repo.GetQR(payload.Referensi)
+	if err != nil {
+		return model.Merchant{}, model.MerchantQR{}, err
+	}
+	if qr.MerchantId != merchant.Id {
+		return model.Merchant{}, model.MerchantQR{}, fmt.Errorf("QR tidak valid")
+	}
+	if qr.Status != model.MerchantQRActive {
+		return model.Merchant{}, model.MerchantQR{}, fmt.Errorf("QR sudah dibayar atau kedaluwarsa")
+	}
+
+	return merchant, qr, nil
+}
+
+func (m *merchantUseCase) PreviewQR(raw string) (model.QRPreview, error) {
+	merchant, qr, err := m.resolveQR(raw)
+	if err != nil {
+		return model.QRPreview{}, err
+	}
+
+	return model.QRPreview{
+		MerchantId:   merchant.Id,
+		NamaMerchant: merchant.Nama,
+		Kota:         merchant.Kota,
+		Jenis:        qr.Jenis,
+		Jumlah:       qr.Jumlah,
+		QrId:         qr.Id,
+	}, nil
+}
+
+// PayQR membayar merchant dari hasil scan QR, PIN sudah diperiksa di controller
+func (m *merchantUseCase) PayQR(payload dto.QRPaymentRequest) (model.MerchantPayment, error) {
+	merchant, qr, err := m.resolveQR(payload.Payload)
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+	if merchant.OwnerId == payload.UserId {
+		return model.MerchantPayment{}, fmt.Errorf("tidak dapat membayar merchant milik sendiri")
+	}
+	if len(payload.Catatan) > 250 {
+		return model.MerchantPayment{}, fmt.Errorf("catatan maksimal 250 karakter")
+	}
+	jumlah := qr.Jumlah
+	if qr.Jenis == model.MerchantQRStatic {
+		jumlah = payload.Jumlah
+	}
+	if jumlah <= 0 {
+		return model.MerchantPayment{}, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	reservation, err := m.family.Authorize(dto.FamilySpendRequest{UserId: payload.UserId, Jenis: model.FamilySpendMerchant, Jumlah: jumlah,
+		Tujuan: merchant.Id, Keterangan: merchant.Nama})
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+
+	payment, err := m.repo.Pay(model.MerchantPayment{
+		MerchantId: merchant.Id,
+		UserId:     payload.UserId,
+		QrId:       qr.Id,
+		Jumlah:     jumlah,
+		Catatan:    payload.Catatan,
+		Referensi:  qr.Referensi,
+	})
+	if err != nil {
+		m.family.Release(reservation.Id)
+		return model.MerchantPayment{}, err
+	}
+	m.webhook.Publish(merchant.Id, model.WebhookEventPaymentSucceeded, payment)
+	m.budget.Evaluate(payload.UserId)
+	m.family.Record(model.FamilyActivity{UserId: payload.UserId, Jenis: model.FamilySpendMerchant, Jumlah: jumlah,
+		Keterangan: merchant.Nama, Referensi: payment.Id})
+
+	return payment, nil
+}
+
+func (m *merchantUseCase) FindPayments(id, ownerId string, page int) ([]model.MerchantPayment, error) {
+	if _, err := m.FindMerchant(id, ownerId); err != nil {
+		return []model.MerchantPayment{}, err
+	}
+	datas, err := m.repo.GetPayments(id, page)
+	if err != nil {
+		return []model.MerchantPayment{}, err
+	}
+
+	return datas, nil
+}
+
+func (m *merchantUseCase) FindPayment(id, merchantId string) (model.MerchantPayment, error) {
+	payment, err := m.repo.GetPayment(id)
+	if err != nil {
+		return model.MerchantPayment{}, err
+	}
+	if payment.MerchantId != merchantId {
+		return model.MerchantPayment{}, fmt.Errorf("pembayaran %s tidak ditemukan", id)
+	}
+
+	return payment, nil
+}
+
+// UpdateSettlementConfig mengatur jadwal settlement (T+0/T+1) dan nilai bersih minimum sebelum dana dikirim
+func (m *merchantUseCase) UpdateSettlementConfig(id, ownerId string, payload dto.SettlementConfigRequest) (model.Merchant, error) {
+	merchant, err := m.FindMerchant(id, ownerId)
+	if err != nil {
+		return model.Merchant{}, err
+	}
+	if payload.Jadwal != model.SettlementScheduleT0 && payload.Jadwal != model.SettlementScheduleT1 {
+		return model.Merchant{}, fmt.Errorf("jadwal harus T+0 atau T+1")
+	}
+	if payload.Minimum < 0 {
+		return model.Merchant{}, fmt.Errorf("minimum tidak boleh negatif")
+	}
+	merchant.JadwalSettlement = payload.Jadwal
+	merchant.MinimumSettlement = payload.Minimum
+
+	return m.repo.Update(merchant)
+}
+
+func NewMerchantUseCase(repo repository.MerchantRepository, inquiry bank.BankInquiry, webhook WebhookUseCase, budget BudgetUseCase,
+	family FamilyUseCase) MerchantUseCase {
+	return &merchantUseCase{repo: repo, inquiry: inquiry, webhook: webhook, budget: budget, family: family}
+}
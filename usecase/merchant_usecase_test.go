@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/utils/bank"
+	"github.com/yafireyhan01/e-wallet/utils/qris"
+)
+
+type MerchantUseCaseTestSuite struct {
+	suite.Suite
+	mrm      *repomock.MerchantRepoMock
+	wum      *usecasemock.WebhookUseCaseMock
+	bum      *usecasemock.BudgetUseCaseMock
+	fam      *usecasemock.FamilyUseCaseMock
+	mu       MerchantUseCase
+	merchant model.Merchant
+}
+
+func (suite *MerchantUseCaseTestSuite) SetupTest() {
+	suite.mrm = new(repomock.MerchantRepoMock)
+	suite.wum = new(usecasemock.WebhookUseCaseMock)
+	suite.wum.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	suite.bum = new(usecasemock.BudgetUseCaseMock)
+	suite.bum.On("Evaluate", mock.Anything).Return(nil)
+	suite.fam = new(usecasemock.FamilyUseCaseMock)
+	suite.fam.On("Authorize", mock.Anything).Return(model.FamilyReservation{}, nil)
+	suite.fam.On("Release", mock.Anything).Return(nil)
+	suite.fam.On("Record", mock.Anything).Return(nil)
+	suite.mu = NewMerchantUseCase(suite.mrm, bank.NewFakeBankInquiry(), suite.wum, suite.bum, suite.fam)
+	suite.merchant = model.Merchant{Id: "m-1", OwnerId: "owner", Nama: "Kopi Kita", Kota: "Bandung", Kategori: "5814",
+		Status: model.MerchantStatusActive}
+	suite.mrm.On("Get", "m-1").Return(suite.merchant, nil)
+}
+
+func TestMerchantUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(MerchantUseCaseTestSuite))
+}
+
+func (suite *MerchantUseCaseTestSuite) TestRegisterMerchant_InvalidSettlementAccount() {
+	_, err := suite.mu.RegisterMerchant(dto.MerchantRequest{OwnerId: "owner", Nama: "Kopi Kita", Kategori: "5814", Kota: "Bandung",
+		BankCode: "014", Rekening: "9991234567", NamaPemilik: "Budi"})
+	assert.Error(suite.T(), err)
+	suite.mrm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *MerchantUseCaseTestSuite) TestCreateDynamicQR_ExpiresInTooLong() {
+	_, err := suite.mu.CreateDynamicQR("m-1", "owner", dto.MerchantQRRequest{Jumlah: 10000, ExpiresIn: 24*60 + 1})
+	assert.EqualError(suite.T(), err, "masa berlaku QR maksimal 24 jam")
+	suite.mrm.AssertNotCalled(suite.T(), "CreateQR", mock.Anything)
+}
+
+func (suite *MerchantUseCaseTestSuite) TestPayQR_StaticRequiresAmount() {
+	raw := qris.Encode(merchantPayload(suite.merchant))
+
+	_, err := suite.mu.PayQR(dto.QRPaymentRequest{UserId: "a", Payload: raw})
+	assert.Error(suite.T(), err)
+	suite.mrm.AssertNotCalled(suite.T(), "Pay", mock.Anything)
+}
+
+func (suite *MerchantUseCaseTestSuite) TestPayQR_DynamicUsesStoredAmount() {
+	payload := merchantPayload(suite.merchant)
+	payload.Dynamic = true
+	payload.Jumlah = 1000
+	payload.Referensi = "qr-1"
+	expiresAt := time.Now().Add(time.Minute)
+	suite.mrm.On("GetQR", "qr-1").Return(model.MerchantQR{Id: "qr-1", MerchantId: "m-1", Jenis: model.MerchantQRDynamic,
+		Jumlah: 50000, Status: model.MerchantQRActive, ExpiresAt: &expiresAt}, nil)
+	suite.mrm.On("Pay", mock.MatchedBy(func(p model.MerchantPayment) bool {
+		return p.Jumlah == 50000 && p.QrId == "qr-1" && p.MerchantId == "m-1"
+	})).Return(model.MerchantPayment{Id: "pay-1", Jumlah: 50000}, nil)
+
+	actual, err := suite.mu.PayQR(dto.QRPaymentRequest{UserId: "a", Payload: qris.Encode(payload), Jumlah: 1})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 50000, actual.Jumlah)
+	suite.wum.AssertCalled(suite.T(), "Publish", "m-1", model.WebhookEventPaymentSucceeded, actual)
+}
+
+func (suite *MerchantUseCaseTestSuite) TestPayQR_OwnMerchant() {
+	raw := qris.Encode(merchantPayload(suite.merchant))
+
+	_, err := suite.mu.PayQR(dto.QRPaymentRequest{UserId: "owner", Payload: raw, Jumlah: 10000})
+	assert.Error(suite.T(), err)
+}
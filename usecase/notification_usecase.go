@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+type NotificationUseCase interface {
+	Notify(payload model.Notification) error
+	FindAll(userId string, page int) ([]model.Notification, error)
+	MarkRead(id, userId string) error
+}
+
+type notificationUseCase struct {
+	repo repository.NotificationRepository
+}
+
+func (n *notificationUseCase) Notify(payload model.Notification) error {
+	_, err := n.repo.Create(payload)
+	return err
+}
+
+func (n *notificationUseCase) FindAll(userId string, page int) ([]model.Notification, error) {
+	datas, err := n.repo.GetByUser(userId, page)
+	if err != nil {
+		return []model.Notification{}, err
+	}
+
+	return datas, nil
+}
+
+func (n *notificationUseCase) MarkRead(id, userId string) error {
+	return n.repo.MarkRead(id, userId)
+}
+
+func NewNotificationUseCase(repo repository.NotificationRepository) NotificationUseCase {
+	return &notificationUseCase{repo: repo}
+}
@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+// masa berlaku default permintaan pembayaran
+const paymentRequestTTL = 72 * time.Hour
+
+type PaymentRequestUseCase interface {
+	CreateRequest(payload dto.PaymentRequestRequest, payer model.User) (model.PaymentRequest, error)
+	FindIncoming(userId, status string) ([]model.PaymentRequest, error)
+	FindOutgoing(userId, status string) ([]model.PaymentRequest, error)
+	FindRequest(id, userId string) (model.PaymentRequest, error)
+	AcceptRequest(id string, send, receive model.User) (model.PaymentRequest, error)
+	DeclineRequest(id, userId string) (model.PaymentRequest, error)
+	CancelRequest(id, userId string) (model.PaymentRequest, error)
+	CreateSplit(payload dto.SplitBillRequest, peserta []model.User) (model.SplitBill, error)
+	FindSplits(userId string) ([]model.SplitBill, error)
+	FindSplit(id, userId string) (model.SplitBill, error)
+	CancelSplit(id, userId string) (model.SplitBill, error)
+}
+
+type paymentRequestUseCase struct {
+	repo   repository.PaymentRequestRepository
+	ut     TransferUseCase
+	notify NotificationUseCase
+}
+
+func (p *paymentRequestUseCase) CreateRequest(payload dto.PaymentRequestRequest, payer model.User) (model.PaymentRequest, error) {
+	if payload.Jumlah <= 0 {
+		return model.PaymentRequest{}, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	if payer.Id == payload.RequesterId {
+		return model.PaymentRequest{}, fmt.Errorf("tidak dapat meminta pembayaran ke akun sendiri")
+	}
+	payload.Catatan = strings.TrimSpace(payload.Catatan)
+	if len(payload.Catatan) > 250 {
+		return model.PaymentRequest{}, fmt.Errorf("catatan maksimal 250 karakter")
+	}
+	ttl := paymentRequestTTL
+	if payload.ExpiresIn > 0 {
+		ttl = time.Duration(payload.ExpiresIn) * time.Hour
+	}
+
+	request, err := p.repo.Create(model.PaymentRequest{
+		RequesterId: payload.RequesterId,
+		PayerId:     payer.Id,
+		Jumlah:      payload.Jumlah,
+		Catatan:     payload.Catatan,
+		ExpiresAt:   time.Now().Add(ttl),
+	})
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	p.notify.Notify(model.Notification{
+		UserId:      payer.Id,
+		Jenis:       model.NotificationPaymentRequest,
+		Judul:       "Permintaan pembayaran baru",
+		Pesan:       fmt.Sprintf("Anda menerima permintaan pembayaran sebesar %d", payload.Jumlah),
+		ReferenceId: request.Id,
+	})
+
+	return request, nil
+}
+
+func (p *paymentRequestUseCase) FindIncoming(userId, status string) ([]model.PaymentRequest, error) {
+	if _, err := p.repo.ExpireDue(); err != nil {
+		return []model.PaymentRequest{}, err
+	}
+	datas, err := p.repo.GetIncoming(userId, status)
+	if err != nil {
+		return []model.PaymentRequest{}, err
+	}
+
+	return datas, nil
+}
+
+func (p *paymentRequestUseCase) FindOutgoing(userId, status string) ([]model.PaymentRequest, error) {
+	if _, err := p.repo.ExpireDue(); err != nil {
+		return []model.PaymentRequest{}, err
+	}
+	datas, err := p.repo.GetOutgoing(userId, status)
+	if err != nil {
+		return []model.PaymentRequest{}, err
+	}
+
+	return datas, nil
+}
+
+// FindRequest hanya bisa dilihat oleh peminta atau pembayar
+func (p *paymentRequestUseCase) FindRequest(id, userId string) (model.PaymentRequest, error) {
+	request, err := p.repo.Get(id)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if request.RequesterId != userId && request.PayerId != userId {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran %s tidak ditemukan", id)
+	}
+	if request.Status == model.PaymentRequestPending && time.Now().After(request.ExpiresAt) {
+		request.Status = model.PaymentRequestExpired
+	}
+
+	return request, nil
+}
+
+// AcceptRequest membayar permintaan lewat TransferUseCase, PIN sudah dicek di controller
+func (p *paymentRequestUseCase) AcceptRequest(id string, send, receive model.User) (model.PaymentRequest, error) {
+	request, err := p.FindRequest(id, send.Id)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if request.PayerId != send.Id || request.RequesterId != receive.Id {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran %s tidak ditemukan", id)
+	}
+	if request.Status != model.PaymentRequestPending {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran sudah %s", request.Status)
+	}
+	claimed, err := p.repo.UpdateStatus(id, model.PaymentRequestPending, model.PaymentRequestProcessing, "")
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if !claimed {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran sedang atau sudah diproses")
+	}
+
+	trx, err := p.ut.TransferRequest(dto.TransferRequest{
+		UserId:         send.Id,
+		TujuanTransfer: receive.Id,
+		JumlahTransfer: request.Jumlah,
+		Catatan:        request.Catatan,
+		Referensi:      "payment-request:" + request.Id,
+	}, send, receive)
+	if err != nil {
+		p.repo.UpdateStatus(id, model.PaymentRequestProcessing, model.PaymentRequestPending, "")
+		return model.PaymentRequest{}, err
+	}
+	if _, err := p.repo.UpdateStatus(id, model.PaymentRequestProcessing, model.PaymentRequestPaid, trx.Id); err != nil {
+		return model.PaymentRequest{}, err
+	}
+	p.notify.Notify(model.Notification{
+		UserId:      request.RequesterId,
+		Jenis:       model.NotificationPaymentRequest,
+		Judul:       "Permintaan pembayaran dibayar",
+		Pesan:       fmt.Sprintf("%s membayar permintaan sebesar %d", send.Name, request.Jumlah),
+		ReferenceId: request.Id,
+	})
+	if request.SplitId != "" {
+		p.settleSplit(request.SplitId)
+	}
+
+	return p.repo.Get(id)
+}
+
+func (p *paymentRequestUseCase) DeclineRequest(id, userId string) (model.PaymentRequest, error) {
+	request, err := p.FindRequest(id, userId)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if request.PayerId != userId {
+		return model.PaymentRequest{}, fmt.Errorf("hanya pembayar yang bisa menolak permintaan")
+	}
+	request, err = p.changeStatus(request, model.PaymentRequestDeclined)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	p.notify.Notify(model.Notification{
+		UserId:      request.RequesterId,
+		Jenis:       model.NotificationPaymentRequest,
+		Judul:       "Permintaan pembayaran ditolak",
+		Pesan:       fmt.Sprintf("Permintaan pembayaran sebesar %d ditolak", request.Jumlah),
+		ReferenceId: request.Id,
+	})
+
+	return request, nil
+}
+
+func (p *paymentRequestUseCase) CancelRequest(id, userId string) (model.PaymentRequest, error) {
+	request, err := p.FindRequest(id, userId)
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if request.RequesterId != userId {
+		return model.PaymentRequest{}, fmt.Errorf("hanya peminta yang bisa membatalkan permintaan")
+	}
+
+	return p.changeStatus(request, model.PaymentRequestCancelled)
+}
+
+func (p *paymentRequestUseCase) changeStatus(request model.PaymentRequest, to string) (model.PaymentRequest, error) {
+	if request.Status != model.PaymentRequestPending {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran sudah %s", request.Status)
+	}
+	updated, err := p.repo.UpdateStatus(request.Id, model.PaymentRequestPending, to, "")
+	if err != nil {
+		return model.PaymentRequest{}, err
+	}
+	if !updated {
+		return model.PaymentRequest{}, fmt.Errorf("permintaan pembayaran sedang atau sudah diproses")
+	}
+	request.Status = to
+
+	return request, nil
+}
+
+// CreateSplit membuat split bill dan satu payment request untuk tiap peserta
+func (p *paymentRequestUseCase) CreateSplit(payload dto.SplitBillRequest, peserta []model.User) (model.SplitBill, error) {
+	payload.Judul = strings.TrimSpace(payload.Judul)
+	if payload.Judul == "" || len(payload.Judul) > 100 {
+		return model.SplitBill{}, fmt.Errorf("judul harus diisi, maksimal 100 karakter")
+	}
+	if len(peserta) == 0 || len(peserta) != len(payload.Peserta) {
+		return model.SplitBill{}, fmt.Errorf("peserta split bill harus diisi")
+	}
+	seen := map[string]bool{}
+	for _, user := range peserta {
+		if user.Id == payload.OrganiserId {
+			return model.SplitBill{}, fmt.Errorf("organiser tidak perlu dimasukkan sebagai peserta, gunakan termasuk_saya")
+		}
+		if seen[user.Id] {
+			return model.SplitBill{}, fmt.Errorf("peserta %s terdaftar lebih dari sekali", user.Name)
+		}
+		seen[user.Id] = true
+	}
+	custom := make([]int, len(payload.Peserta))
+	for i, participant := range payload.Peserta {
+		custom[i] = participant.Jumlah
+	}
+	shares, err := splitShares(payload.Total, payload.Mode, custom, payload.TermasukSaya)
+	if err != nil {
+		return model.SplitBill{}, err
+	}
+
+	ttl := paymentRequestTTL
+	if payload.ExpiresIn > 0 {
+		ttl = time.Duration(payload.ExpiresIn) * time.Hour
+	}
+	requests := make([]model.PaymentRequest, len(peserta))
+	for i, user := range peserta {
+		requests[i] = model.PaymentRequest{
+			RequesterId: payload.OrganiserId,
+			PayerId:     user.Id,
+			Jumlah:      shares[i],
+			Catatan:     payload.Judul,
+			ExpiresAt:   time.Now().Add(ttl),
+		}
+	}
+	split, err := p.repo.CreateSplit(model.SplitBill{
+		OrganiserId: payload.OrganiserId,
+		Judul:       payload.Judul,
+		Total:       payload.Total,
+		Mode:        payload.Mode,
+	}, requests)
+	if err != nil {
+		return model.SplitBill{}, err
+	}
+	for _, share := range split.Shares {
+		p.notify.Notify(model.Notification{
+			UserId:      share.PayerId,
+			Jenis:       model.NotificationPaymentRequest,
+			Judul:       "Split bill baru",
+			Pesan:       fmt.Sprintf("Bagian anda untuk %s sebesar %d", split.Judul, share.Jumlah),
+			ReferenceId: share.Id,
+		})
+	}
+
+	return split, nil
+}
+
+func (p *paymentRequestUseCase) FindSplits(userId string) ([]model.SplitBill, error) {
+	datas, err := p.repo.GetSplitsByOrganiser(userId)
+	if err != nil {
+		return []model.SplitBill{}, err
+	}
+
+	return datas, nil
+}
+
+// FindSplit bisa dilihat organiser maupun peserta split
+func (p *paymentRequestUseCase) FindSplit(id, userId string) (model.SplitBill, error) {
+	if _, err := p.repo.ExpireDue(); err != nil {
+		return model.SplitBill{}, err
+	}
+	split, err := p.repo.GetSplit(id)
+	if err != nil {
+		return model.SplitBill{}, err
+	}
+	if split.OrganiserId == userId {
+		return split, nil
+	}
+	for _, share := range split.Shares {
+		if share.PayerId == userId {
+			return split, nil
+		}
+	}
+
+	return model.SplitBill{}, fmt.Errorf("split bill %s tidak ditemukan", id)
+}
+
+func (p *paymentRequestUseCase) CancelSplit(id, userId string) (model.SplitBill, error) {
+	split, err := p.FindSplit(id, userId)
+	if err != nil {
+		return model.SplitBill{}, err
+	}
+	if split.OrganiserId != userId {
+		return model.SplitBill{}, fmt.Errorf("hanya organiser yang bisa membatalkan split bill")
+	}
+	if err := p.repo.CancelSplit(id); err != nil {
+		return model.SplitBill{}, err
+	}
+
+	return p.repo.GetSplit(id)
+}
+
+func (p *paymentRequestUseCase) settleSplit(splitId string) {
+	settled, err := p.repo.SettleSplit(splitId)
+	if err != nil || !settled {
+		return
+	}
+	split, err := p.repo.GetSplit(splitId)
+	if err != nil {
+		return
+	}
+	p.notify.Notify(model.Notification{
+		UserId:      split.OrganiserId,
+		Jenis:       model.NotificationPaymentRequest,
+		Judul:       "Split bill lunas",
+		Pesan:       fmt.Sprintf("Semua peserta %s sudah membayar", split.Judul),
+		ReferenceId: split.Id,
+	})
+}
+
+// splitShares menghitung bagian tiap peserta. Mode equal membagi rata (sisa pembagian ke peserta pertama),
+// mode custom memakai jumlah per peserta dan sisanya ditanggung organiser jika termasukSaya
+func splitShares(total int, mode string, custom []int, termasukSaya bool) ([]int, error) {
+	if total <= 0 {
+		return nil, fmt.Errorf("total harus lebih dari 0")
+	}
+	shares := make([]int, len(custom))
+	switch mode {
+	case model.SplitModeEqual:
+		n := len(custom)
+		if termasukSaya {
+			n++
+		}
+		base := total / n
+		if base == 0 {
+			return nil, fmt.Errorf("total terlalu kecil untuk dibagi ke %d orang", n)
+		}
+		for i := range shares {
+			shares[i] = base
+			if i < total%n {
+				shares[i]++
+			}
+		}
+	case model.SplitModeCustom:
+		sum := 0
+		for i, jumlah := range custom {
+			if jumlah <= 0 {
+				return nil, fmt.Errorf("jumlah tiap peserta harus lebih dari 0")
+			}
+			shares[i] = jumlah
+			sum += jumlah
+		}
+		if sum > total || (!termasukSaya && sum != total) {
+			return nil, fmt.Errorf("jumlah bagian peserta (%d) tidak sesuai dengan total %d", sum, total)
+		}
+	default:
+		return nil, fmt.Errorf("mode harus equal atau custom")
+	}
+
+	return shares, nil
+}
+
+func NewPaymentRequestUseCase(repo repository.PaymentRequestRepository, ut TransferUseCase, notify NotificationUseCase) PaymentRequestUseCase {
+	return &paymentRequestUseCase{repo: repo, ut: ut, notify: notify}
+}
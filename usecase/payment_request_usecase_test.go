@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type PaymentRequestUseCaseTestSuite struct {
+	suite.Suite
+	prm *repomock.PaymentRequestRepoMock
+	tum *usecasemock.TransferUseCaseMock
+	num *usecasemock.NotificationUseCaseMock
+	pu  PaymentRequestUseCase
+}
+
+func (suite *PaymentRequestUseCaseTestSuite) SetupTest() {
+	suite.prm = new(repomock.PaymentRequestRepoMock)
+	suite.tum = new(usecasemock.TransferUseCaseMock)
+	suite.num = new(usecasemock.NotificationUseCaseMock)
+	suite.num.On("Notify", mock.Anything).Return(nil)
+	suite.pu = NewPaymentRequestUseCase(suite.prm, suite.tum, suite.num)
+}
+
+func TestPaymentRequestUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PaymentRequestUseCaseTestSuite))
+}
+
+func (suite *PaymentRequestUseCaseTestSuite) TestAcceptRequest_Success() {
+	request := model.PaymentRequest{Id: "pr-1", RequesterId: "b", PayerId: "a", Jumlah: 5000,
+		Status: model.PaymentRequestPending, ExpiresAt: time.Now().Add(time.Hour)}
+	paid := request
+	paid.Status = model.PaymentRequestPaid
+	suite.prm.On("Get", "pr-1").Return(request, nil).Once()
+	suite.prm.On("UpdateStatus", "pr-1", model.PaymentRequestPending, model.PaymentRequestProcessing, "").Return(true, nil)
+	suite.tum.On("TransferRequest", mock.Anything, model.User{Id: "a"}, model.User{Id: "b"}).Return(model.Transfer{Id: "trx-1"}, nil)
+	suite.prm.On("UpdateStatus", "pr-1", model.PaymentRequestProcessing, model.PaymentRequestPaid, "trx-1").Return(true, nil)
+	suite.prm.On("Get", "pr-1").Return(paid, nil).Once()
+
+	actual, err := suite.pu.AcceptRequest("pr-1", model.User{Id: "a"}, model.User{Id: "b"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.PaymentRequestPaid, actual.Status)
+}
+
+func (suite *PaymentRequestUseCaseTestSuite) TestAcceptRequest_TransferFailedReleases() {
+	request := model.PaymentRequest{Id: "pr-1", RequesterId: "b", PayerId: "a", Jumlah: 5000,
+		Status: model.PaymentRequestPending, ExpiresAt: time.Now().Add(time.Hour)}
+	suite.prm.On("Get", "pr-1").Return(request, nil)
+	suite.prm.On("UpdateStatus", "pr-1", model.PaymentRequestPending, model.PaymentRequestProcessing, "").Return(true, nil)
+	suite.tum.On("TransferRequest", mock.Anything, mock.Anything, mock.Anything).Return(model.Transfer{}, errors.New("saldo tidak cukup"))
+	suite.prm.On("UpdateStatus", "pr-1", model.PaymentRequestProcessing, model.PaymentRequestPending, "").Return(true, nil)
+
+	_, err := suite.pu.AcceptRequest("pr-1", model.User{Id: "a"}, model.User{Id: "b"})
+	assert.Error(suite.T(), err)
+	suite.prm.AssertExpectations(suite.T())
+}
+
+func (suite *PaymentRequestUseCaseTestSuite) TestDeclineRequest_OnlyPayer() {
+	request := model.PaymentRequest{Id: "pr-1", RequesterId: "b", PayerId: "a", Status: model.PaymentRequestPending,
+		ExpiresAt: time.Now().Add(time.Hour)}
+	suite.prm.On("Get", "pr-1").Return(request, nil)
+
+	_, err := suite.pu.DeclineRequest("pr-1", "b")
+	assert.Error(suite.T(), err)
+	suite.prm.AssertNotCalled(suite.T(), "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PaymentRequestUseCaseTestSuite) TestCreateRequest_Self() {
+	_, err := suite.pu.CreateRequest(dto.PaymentRequestRequest{RequesterId: "a", Jumlah: 1000}, model.User{Id: "a"})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *PaymentRequestUseCaseTestSuite) TestAcceptRequest_SettlesSplit() {
+	request := model.PaymentRequest{Id: "pr-1", RequesterId: "b", PayerId: "a", Jumlah: 5000, SplitId: "split-1",
+		Status: model.PaymentRequestPending, ExpiresAt: time.Now().Add(time.Hour)}
+	suite.prm.On("Get", "pr-1").Return(request, nil)
+	suite.prm.On("UpdateStatus", "pr-1", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	suite.tum.On("TransferRequest", mock.Anything, mock.Anything, mock.Anything).Return(model.Transfer{Id: "trx-1"}, nil)
+	suite.prm.On("SettleSplit", "split-1").Return(true, nil)
+	suite.prm.On("GetSplit", "split-1").Return(model.SplitBill{Id: "split-1", OrganiserId: "b"}, nil)
+
+	_, err := suite.pu.AcceptRequest("pr-1", model.User{Id: "a"}, model.User{Id: "b"})
+	assert.Nil(suite.T(), err)
+	suite.prm.AssertCalled(suite.T(), "SettleSplit", "split-1")
+}
+
+func TestSplitShares(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		mode         string
+		custom       []int
+		termasukSaya bool
+		expected     []int
+		wantErr      bool
+	}{
+		{"equal with remainder", 10000, model.SplitModeEqual, []int{0, 0, 0}, false, []int{3334, 3333, 3333}, false},
+		{"equal including organiser", 9000, model.SplitModeEqual, []int{0, 0}, true, []int{3000, 3000}, false},
+		{"custom exact", 10000, model.SplitModeCustom, []int{4000, 6000}, false, []int{4000, 6000}, false},
+		{"custom organiser covers rest", 10000, model.SplitModeCustom, []int{4000}, true, []int{4000}, false},
+		{"custom mismatch", 10000, model.SplitModeCustom, []int{4000, 5000}, false, nil, true},
+		{"custom over total", 10000, model.SplitModeCustom, []int{8000, 5000}, true, nil, true},
+		{"unknown mode", 10000, "percent", []int{1}, false, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := splitShares(tt.total, tt.mode, tt.custom, tt.termasukSaya)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+// batas pocket aktif per user
+const maxPockets = 10
+
+type PocketUseCase interface {
+	CreatePocket(payload dto.PocketRequest) (model.Pocket, error)
+	FindPockets(userId, status string) ([]model.Pocket, error)
+	FindPocket(id, userId string) (model.Pocket, error)
+	UpdatePocket(id string, payload dto.PocketRequest) (model.Pocket, error)
+	MovePocket(payload dto.PocketMoveRequest) (model.Pocket, error)
+	ClosePocket(id, userId string) (model.Pocket, error)
+	FindMoves(id, userId string, page int) ([]model.PocketMove, error)
+}
+
+type pocketUseCase struct {
+	repo repository.PocketRepository
+}
+
+func validatePocket(payload *dto.PocketRequest) error {
+	payload.Nama = strings.TrimSpace(payload.Nama)
+	if payload.Nama == "" || len(payload.Nama) > 50 {
+		return fmt.Errorf("nama pocket harus diisi, maksimal 50 karakter")
+	}
+	if payload.Target < 0 {
+		return fmt.Errorf("target tidak boleh negatif")
+	}
+	if payload.TargetDate != nil && payload.TargetDate.Before(time.Now()) {
+		return fmt.Errorf("target_date harus di masa depan")
+	}
+	return nil
+}
+
+// withProgress menghitung pencapaian target pocket
+func withProgress(pocket model.Pocket) model.Pocket {
+	if pocket.Target > 0 {
+		pocket.Progress = float64(pocket.Saldo) / float64(pocket.Target) * 100
+		if pocket.Saldo < pocket.Target {
+			pocket.SisaTarget = pocket.Target - pocket.Saldo
+		}
+	}
+	return pocket
+}
+
+func (p *pocketUseCase) CreatePocket(payload dto.PocketRequest) (model.Pocket, error) {
+	if err := validatePocket(&payload); err != nil {
+		return model.Pocket{}, err
+	}
+	total, err := p.repo.CountActive(payload.UserId)
+	if err != nil {
+		return model.Pocket{}, err
+	}
+	if total >= maxPockets {
+		return model.Pocket{}, fmt.Errorf("maksimal %d pocket aktif", maxPockets)
+	}
+	pocket, err := p.repo.Create(model.Pocket{
+		UserId:     payload.UserId,
+		Nama:       payload.Nama,
+		Target:     payload.Target,
+		TargetDate: payload.TargetDate,
+	})
+	if err != nil {
+		return model.Pocket{}, err
+	}
+
+	return withProgress(pocket), nil
+}
+
+func (p *pocketUseCase) FindPockets(userId, status string) ([]model.Pocket, error) {
+	if status == "" {
+		status = model.PocketStatusActive
+	}
+	datas, err := p.repo.GetByUser(userId, status)
+	if err != nil {
+		return []model.Pocket{}, err
+	}
+	for i := range datas {
+		datas[i] = withProgress(datas[i])
+	}
+
+	return datas, nil
+}
+
+func (p *pocketUseCase) FindPocket(id, userId string) (model.Pocket, error) {
+	pocket, err := p.repo.Get(id)
+	if err != nil {
+		return model.Pocket{}, err
+	}
+	if pocket.UserId != userId {
+		return model.Pocket{}, fmt.Errorf("pocket %s tidak ditemukan", id)
+	}
+
+	return withProgress(pocket), nil
+}
+
+func (p *pocketUseCase) UpdatePocket(id string, payload dto.PocketRequest) (model.Pocket, error) {
+	pocket, err := p.FindPocket(id, payload.UserId)
+	if err != nil {
+		return model.Pocket{}, err
+	}
+	if err := validatePocket(&payload); err != nil {
+		return model.Pocket{}, err
+	}
+	pocket.Nama = payload.Nama
+	pocket.Target = payload.Target
+	pocket.TargetDate = payload.TargetDate
+	pocket, err = p.repo.Update(pocket)
+	if err != nil {
+		return model.Pocket{}, err
+	}
+
+	return withProgress(pocket), nil
+}
+
+// MovePocket memindahkan dana antara saldo utama dan pocket secara langsung
+func (p *pocketUseCase) MovePocket(payload dto.PocketMoveRequest) (model.Pocket, error) {
+	if payload.Arah != model.PocketMoveIn && payload.Arah != model.PocketMoveOut {
+		return model.Pocket{}, fmt.Errorf("arah harus masuk atau keluar")
+	}
+	if payload.Jumlah <= 0 {
+		return model.Pocket{}, fmt.Errorf("jumlah harus lebih dari 0")
+	}
+	pocket, err := p.repo.Move(payload.PocketId, payload.UserId, payload.Arah, payload.Jumlah)
+	if err != nil {
+		return model.Pocket{}, err
+	}
+
+	return withProgress(pocket), nil
+}
+
+func (p *pocketUseCase) ClosePocket(id, userId string) (model.Pocket, error) {
+	pocket, err := p.repo.Close(id, userId)
+	if err != nil {
+		return model.Pocket{}, err
+	}
+
+	return withProgress(pocket), nil
+}
+
+func (p *pocketUseCase) FindMoves(id, userId string, page int) ([]model.PocketMove, error) {
+	if _, err := p.FindPocket(id, userId); err != nil {
+		return []model.PocketMove{}, err
+	}
+	datas, err := p.repo.GetMoves(id, page)
+	if err != nil {
+		return []model.PocketMove{}, err
+	}
+
+	return datas, nil
+}
+
+func NewPocketUseCase(repo repository.PocketRepository) PocketUseCase {
+	return &pocketUseCase{repo: repo}
+}
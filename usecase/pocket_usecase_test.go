@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type PocketUseCaseTestSuite struct {
+	suite.Suite
+	prm *repomock.PocketRepoMock
+	up  PocketUseCase
+}
+
+func (suite *PocketUseCaseTestSuite) SetupTest() {
+	suite.prm = new(repomock.PocketRepoMock)
+	suite.up = NewPocketUseCase(suite.prm)
+}
+
+func TestPocketUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PocketUseCaseTestSuite))
+}
+
+func (suite *PocketUseCaseTestSuite) TestCreatePocket_Success() {
+	target := time.Now().AddDate(0, 6, 0)
+	suite.prm.On("CountActive", "u-1").Return(2, nil)
+	suite.prm.On("Create", model.Pocket{UserId: "u-1", Nama: "Liburan", Target: 5000000, TargetDate: &target}).
+		Return(model.Pocket{Id: "p-1", UserId: "u-1", Nama: "Liburan", Target: 5000000, TargetDate: &target, Status: model.PocketStatusActive}, nil)
+
+	result, err := suite.up.CreatePocket(dto.PocketRequest{UserId: "u-1", Nama: " Liburan ", Target: 5000000, TargetDate: &target})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 5000000, result.SisaTarget)
+	assert.Equal(suite.T(), 0.0, result.Progress)
+}
+
+func (suite *PocketUseCaseTestSuite) TestCreatePocket_LimitReached() {
+	suite.prm.On("CountActive", "u-1").Return(maxPockets, nil)
+
+	_, err := suite.up.CreatePocket(dto.PocketRequest{UserId: "u-1", Nama: "Darurat"})
+	assert.Error(suite.T(), err)
+	suite.prm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *PocketUseCaseTestSuite) TestCreatePocket_PastTargetDate() {
+	past := time.Now().AddDate(0, 0, -1)
+
+	_, err := suite.up.CreatePocket(dto.PocketRequest{UserId: "u-1", Nama: "Darurat", TargetDate: &past})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *PocketUseCaseTestSuite) TestMovePocket_Progress() {
+	suite.prm.On("Move", "p-1", "u-1", model.PocketMoveIn, 1500000).
+		Return(model.Pocket{Id: "p-1", UserId: "u-1", Saldo: 2000000, Target: 8000000}, nil)
+
+	result, err := suite.up.MovePocket(dto.PocketMoveRequest{UserId: "u-1", PocketId: "p-1", Arah: model.PocketMoveIn, Jumlah: 1500000})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 25.0, result.Progress)
+	assert.Equal(suite.T(), 6000000, result.SisaTarget)
+}
+
+func (suite *PocketUseCaseTestSuite) TestMovePocket_InvalidArah() {
+	_, err := suite.up.MovePocket(dto.PocketMoveRequest{UserId: "u-1", PocketId: "p-1", Arah: "ke-samping", Jumlah: 1000})
+	assert.Error(suite.T(), err)
+	suite.prm.AssertNotCalled(suite.T(), "Move", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PocketUseCaseTestSuite) TestMovePocket_InsufficientPocket() {
+	suite.prm.On("Move", "p-1", "u-1", model.PocketMoveOut, 5000).Return(model.Pocket{}, fmt.Errorf("saldo pocket tidak mencukupi, tersisa 1000"))
+
+	_, err := suite.up.MovePocket(dto.PocketMoveRequest{UserId: "u-1", PocketId: "p-1", Arah: model.PocketMoveOut, Jumlah: 5000})
+	assert.Error(suite.T(), err)
+}
+
+func (suite *PocketUseCaseTestSuite) TestFindMoves_OtherUser() {
+	suite.prm.On("Get", "p-1").Return(model.Pocket{Id: "p-1", UserId: "u-2"}, nil)
+
+	_, err := suite.up.FindMoves("p-1", "u-1", 1)
+	assert.Error(suite.T(), err)
+	suite.prm.AssertNotCalled(suite.T(), "GetMoves", mock.Anything, mock.Anything)
+}
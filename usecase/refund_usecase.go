@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+type RefundUseCase interface {
+	CreateRefund(payload dto.RefundRequest) (model.MerchantRefund, error)
+	FindMerchantRefunds(merchantId, ownerId string, page int) ([]model.MerchantRefund, error)
+	FindPaymentRefunds(paymentId, merchantId, ownerId string) ([]model.MerchantRefund, error)
+	FindUserRefunds(userId string, page int) ([]model.MerchantRefund, error)
+}
+
+type refundUseCase struct {
+	repo     repository.RefundRepository
+	merchant MerchantUseCase
+	webhook  WebhookUseCase
+	notify   NotificationUseCase
+}
+
+// CreateRefund mengembalikan dana pembayaran ke wallet pembayar, penuh atau sebagian
+func (r *refundUseCase) CreateRefund(payload dto.RefundRequest) (model.MerchantRefund, error) {
+	if _, err := r.merchant.FindMerchant(payload.MerchantId, payload.OwnerId); err != nil {
+		return model.MerchantRefund{}, err
+	}
+	payment, err := r.merchant.FindPayment(payload.PaymentId, payload.MerchantId)
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	if len(payload.Alasan) > 250 || len(payload.Referensi) > 100 {
+		return model.MerchantRefund{}, fmt.Errorf("alasan maksimal 250 dan referensi maksimal 100 karakter")
+	}
+	sisa := payment.Jumlah - payment.JumlahRefund
+	if sisa <= 0 {
+		return model.MerchantRefund{}, fmt.Errorf("pembayaran sudah direfund penuh")
+	}
+	jumlah := payload.Jumlah
+	if jumlah == 0 {
+		jumlah = sisa
+	}
+	if jumlah < 0 {
+		return model.MerchantRefund{}, fmt.Errorf("jumlah refund harus lebih dari 0")
+	}
+	if jumlah > sisa {
+		return model.MerchantRefund{}, fmt.Errorf("jumlah refund melebihi sisa pembayaran %d", sisa)
+	}
+
+	refund, err := r.repo.Create(model.MerchantRefund{
+		PaymentId:  payment.Id,
+		MerchantId: payment.MerchantId,
+		Jumlah:     jumlah,
+		Alasan:     payload.Alasan,
+		Referensi:  payload.Referensi,
+	})
+	if err != nil {
+		return model.MerchantRefund{}, err
+	}
+	r.webhook.Publish(refund.MerchantId, model.WebhookEventRefundCreated, refund)
+	r.notify.Notify(model.Notification{
+		UserId:      refund.UserId,
+		Jenis:       model.NotificationRefund,
+		Judul:       "Refund diterima",
+		Pesan:       fmt.Sprintf("Anda menerima refund sebesar %d dari %s", refund.Jumlah, refund.NamaMerchant),
+		ReferenceId: refund.Id,
+	})
+
+	return refund, nil
+}
+
+func (r *refundUseCase) FindMerchantRefunds(merchantId, ownerId string, page int) ([]model.MerchantRefund, error) {
+	if _, err := r.merchant.FindMerchant(merchantId, ownerId); err != nil {
+		return []model.MerchantRefund{}, err
+	}
+	datas, err := r.repo.GetByMerchant(merchantId, page)
+	if err != nil {
+		return []model.MerchantRefund{}, err
+	}
+
+	return datas, nil
+}
+
+func (r *refundUseCase) FindPaymentRefunds(paymentId, merchantId, ownerId string) ([]model.MerchantRefund, error) {
+	if _, err := r.merchant.FindMerchant(merchantId, ownerId); err != nil {
+		return []model.MerchantRefund{}, err
+	}
+	if _, err := r.merchant.FindPayment(paymentId, merchantId); err != nil {
+		return []model.MerchantRefund{}, err
+	}
+	datas, err := r.repo.GetByPayment(paymentId)
+	if err != nil {
+		return []model.MerchantRefund{}, err
+	}
+
+	return datas, nil
+}
+
+// FindUserRefunds menampilkan refund yang diterima user sebagai pembayar
+func (r *refundUseCase) FindUserRefunds(userId string, page int) ([]model.MerchantRefund, error) {
+	datas, err := r.repo.GetByUser(userId, page)
+	if err != nil {
+		return []model.MerchantRefund{}, err
+	}
+
+	return datas, nil
+}
+
+func NewRefundUseCase(repo repository.RefundRepository, merchant MerchantUseCase, webhook WebhookUseCase, notify NotificationUseCase) RefundUseCase {
+	return &refundUseCase{repo: repo, merchant: merchant, webhook: webhook, notify: notify}
+}
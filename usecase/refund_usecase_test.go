@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type RefundUseCaseTestSuite struct {
+	suite.Suite
+	rrm *repomock.RefundRepoMock
+	mrm *repomock.MerchantRepoMock
+	wum *usecasemock.WebhookUseCaseMock
+	num *usecasemock.NotificationUseCaseMock
+	ur  RefundUseCase
+}
+
+func (suite *RefundUseCaseTestSuite) SetupTest() {
+	suite.rrm = new(repomock.RefundRepoMock)
+	suite.mrm = new(repomock.MerchantRepoMock)
+	suite.wum = new(usecasemock.WebhookUseCaseMock)
+	suite.num = new(usecasemock.NotificationUseCaseMock)
+	suite.ur = NewRefundUseCase(suite.rrm, NewMerchantUseCase(suite.mrm, nil, suite.wum, nil, nil), suite.wum, suite.num)
+	suite.mrm.On("Get", "m-1").Return(model.Merchant{Id: "m-1", OwnerId: "owner-1"}, nil)
+	suite.mrm.On("GetPayment", "p-1").Return(model.MerchantPayment{Id: "p-1", MerchantId: "m-1", UserId: "u-1",
+		Jumlah: 50000, JumlahRefund: 20000}, nil)
+}
+
+func TestRefundUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(RefundUseCaseTestSuite))
+}
+
+func (suite *RefundUseCaseTestSuite) TestCreateRefund_FullRemaining() {
+	refund := model.MerchantRefund{Id: "r-1", PaymentId: "p-1", MerchantId: "m-1", UserId: "u-1", Jumlah: 30000}
+	suite.rrm.On("Create", model.MerchantRefund{PaymentId: "p-1", MerchantId: "m-1", Jumlah: 30000, Alasan: "barang habis"}).
+		Return(refund, nil)
+	suite.wum.On("Publish", "m-1", model.WebhookEventRefundCreated, refund).Return(nil)
+	suite.num.On("Notify", mock.AnythingOfType("model.Notification")).Return(nil)
+
+	actual, err := suite.ur.CreateRefund(dto.RefundRequest{MerchantId: "m-1", OwnerId: "owner-1", PaymentId: "p-1", Alasan: "barang habis"})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 30000, actual.Jumlah)
+	suite.wum.AssertExpectations(suite.T())
+	suite.num.AssertCalled(suite.T(), "Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.UserId == "u-1" && n.Jenis == model.NotificationRefund
+	}))
+}
+
+func (suite *RefundUseCaseTestSuite) TestCreateRefund_ExceedsRemaining() {
+	_, err := suite.ur.CreateRefund(dto.RefundRequest{MerchantId: "m-1", OwnerId: "owner-1", PaymentId: "p-1", Jumlah: 30001})
+	assert.Error(suite.T(), err)
+	suite.rrm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *RefundUseCaseTestSuite) TestCreateRefund_NotOwner() {
+	_, err := suite.ur.CreateRefund(dto.RefundRequest{MerchantId: "m-1", OwnerId: "other", PaymentId: "p-1", Jumlah: 1000})
+	assert.Error(suite.T(), err)
+	suite.rrm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *RefundUseCaseTestSuite) TestCreateRefund_RepoError() {
+	suite.rrm.On("Create", mock.AnythingOfType("model.MerchantRefund")).
+		Return(model.MerchantRefund{}, fmt.Errorf("saldo merchant tidak mencukupi untuk refund 1000"))
+
+	_, err := suite.ur.CreateRefund(dto.RefundRequest{MerchantId: "m-1", OwnerId: "owner-1", PaymentId: "p-1", Jumlah: 1000})
+	assert.Error(suite.T(), err)
+	suite.wum.AssertNotCalled(suite.T(), "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
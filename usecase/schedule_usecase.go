@@ -0,0 +1,343 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+const (
+	// percobaan ulang saat saldo tidak cukup sebelum satu jadwal dianggap gagal
+	scheduleMaxRetry      = 3
+	scheduleRetryInterval = time.Hour
+	// lama jadwal dikunci selama dieksekusi, lihat ScheduleRepository.ClaimDue
+	scheduleLease     = 10 * time.Minute
+	scheduleBatchSize = 50
+)
+
+type ScheduleUseCase interface {
+	CreateSchedule(payload dto.ScheduleRequest) (model.ScheduledTransfer, error)
+	FindSchedules(userId string) ([]model.ScheduledTransfer, error)
+	FindSchedule(id, userId string) (model.ScheduledTransfer, error)
+	UpdateSchedule(id string, payload dto.ScheduleRequest) (model.ScheduledTransfer, error)
+	PauseSchedule(id, userId string) (model.ScheduledTransfer, error)
+	ResumeSchedule(id, userId string) (model.ScheduledTransfer, error)
+	CancelSchedule(id, userId string) (model.ScheduledTransfer, error)
+	RunDue(now time.Time) (int, error)
+}
+
+type scheduleUseCase struct {
+	repo   repository.ScheduleRepository
+	ut     TransferUseCase
+	uc     UserUseCase
+	notify NotificationUseCase
+}
+
+func (s *scheduleUseCase) CreateSchedule(payload dto.ScheduleRequest) (model.ScheduledTransfer, error) {
+	if payload.StartAt.IsZero() {
+		payload.StartAt = time.Now()
+	}
+	data := model.ScheduledTransfer{
+		UserId:         payload.UserId,
+		TujuanTransfer: payload.TujuanTransfer,
+		JumlahTransfer: payload.JumlahTransfer,
+		Catatan:        strings.TrimSpace(payload.Catatan),
+		Kategori:       normalizeCategory(payload.Kategori),
+		Frekuensi:      payload.Frekuensi,
+		StartAt:        payload.StartAt,
+		NextRunAt:      payload.StartAt,
+		EndDate:        payload.EndDate,
+		MaxRuns:        payload.MaxRuns,
+	}
+	if err := validateSchedule(data); err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	return s.repo.Create(data)
+}
+
+func (s *scheduleUseCase) FindSchedules(userId string) ([]model.ScheduledTransfer, error) {
+	datas, err := s.repo.GetByUser(userId)
+	if err != nil {
+		return []model.ScheduledTransfer{}, err
+	}
+
+	return datas, nil
+}
+
+func (s *scheduleUseCase) FindSchedule(id, userId string) (model.ScheduledTransfer, error) {
+	data, err := s.repo.Get(id)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+	if data.UserId != userId {
+		return model.ScheduledTransfer{}, fmt.Errorf("jadwal transfer %s tidak ditemukan", id)
+	}
+
+	return data, nil
+}
+
+// UpdateSchedule hanya mengubah field yang diisi, start_at baru mengulang hitungan jadwal dari awal
+func (s *scheduleUseCase) UpdateSchedule(id string, payload dto.ScheduleRequest) (model.ScheduledTransfer, error) {
+	data, err := s.FindSchedule(id, payload.UserId)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+	if data.Status != model.ScheduleStatusActive && data.Status != model.ScheduleStatusPaused {
+		return model.ScheduledTransfer{}, fmt.Errorf("jadwal transfer sudah %s", data.Status)
+	}
+	if payload.TujuanTransfer != "" {
+		data.TujuanTransfer = payload.TujuanTransfer
+	}
+	if payload.JumlahTransfer != 0 {
+		data.JumlahTransfer = payload.JumlahTransfer
+	}
+	if payload.Catatan != "" {
+		data.Catatan = strings.TrimSpace(payload.Catatan)
+	}
+	if payload.Kategori != "" {
+		data.Kategori = normalizeCategory(payload.Kategori)
+	}
+	if payload.Frekuensi != "" {
+		data.Frekuensi = payload.Frekuensi
+	}
+	if payload.EndDate != nil {
+		data.EndDate = payload.EndDate
+	}
+	if payload.MaxRuns != 0 {
+		data.MaxRuns = payload.MaxRuns
+	}
+	if !payload.StartAt.IsZero() {
+		data.StartAt = payload.StartAt
+		data.NextRunAt = payload.StartAt
+		data.RunCount = 0
+		data.RetryCount = 0
+	}
+	if err := validateSchedule(data); err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+
+	return s.repo.Update(data)
+}
+
+func (s *scheduleUseCase) PauseSchedule(id, userId string) (model.ScheduledTransfer, error) {
+	return s.changeStatus(id, userId, model.ScheduleStatusActive, model.ScheduleStatusPaused)
+}
+
+func (s *scheduleUseCase) ResumeSchedule(id, userId string) (model.ScheduledTransfer, error) {
+	return s.changeStatus(id, userId, model.ScheduleStatusPaused, model.ScheduleStatusActive)
+}
+
+func (s *scheduleUseCase) CancelSchedule(id, userId string) (model.ScheduledTransfer, error) {
+	data, err := s.FindSchedule(id, userId)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+	if data.Status != model.ScheduleStatusActive && data.Status != model.ScheduleStatusPaused {
+		return model.ScheduledTransfer{}, fmt.Errorf("jadwal transfer sudah %s", data.Status)
+	}
+	data.Status = model.ScheduleStatusCancelled
+
+	return s.repo.Update(data)
+}
+
+func (s *scheduleUseCase) changeStatus(id, userId, from, to string) (model.ScheduledTransfer, error) {
+	data, err := s.FindSchedule(id, userId)
+	if err != nil {
+		return model.ScheduledTransfer{}, err
+	}
+	if data.Status != from {
+		return model.ScheduledTransfer{}, fmt.Errorf("jadwal transfer berstatus %s, tidak bisa diubah ke %s", data.Status, to)
+	}
+	data.Status = to
+
+	return s.repo.Update(data)
+}
+
+// RunDue dipanggil scheduler secara berkala, mengembalikan jumlah jadwal yang dieksekusi
+func (s *scheduleUseCase) RunDue(now time.Time) (int, error) {
+	datas, err := s.repo.GetDue(now, scheduleBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	executed := 0
+	for _, data := range datas {
+		claimed, err := s.repo.ClaimDue(data.Id, data.NextRunAt, now.Add(scheduleLease))
+		if err != nil {
+			return executed, err
+		}
+		if !claimed {
+			continue
+		}
+		if _, err := s.repo.UpdateRun(s.execute(data, now)); err != nil {
+			return executed, err
+		}
+		executed++
+	}
+
+	return executed, nil
+}
+
+// execute menjalankan satu jadwal lewat TransferUseCase dan mengembalikan state jadwal berikutnya
+func (s *scheduleUseCase) execute(data model.ScheduledTransfer, now time.Time) model.ScheduledTransfer {
+	trx, err := s.transfer(data)
+	if err == nil {
+		data.RunCount++
+		data.RetryCount = 0
+		data.LastError = ""
+		data.LastTransferId = trx.Id
+		advanceSchedule(&data)
+		s.notifySchedule(data, model.NotificationScheduleSuccess, "Transfer terjadwal berhasil",
+			fmt.Sprintf("Transfer terjadwal sebesar %d berhasil dikirim", data.JumlahTransfer))
+		return data
+	}
+
+	data.LastError = err.Error()
+	if len(data.LastError) > 250 {
+		data.LastError = data.LastError[:250]
+	}
+	data.RetryCount++
+	if data.RetryCount <= scheduleMaxRetry {
+		data.NextRunAt = now.Add(scheduleRetryInterval)
+		s.notifySchedule(data, model.NotificationScheduleRetry, "Transfer terjadwal tertunda",
+			fmt.Sprintf("Transfer terjadwal sebesar %d gagal (%s), akan dicoba lagi", data.JumlahTransfer, err.Error()))
+		return data
+	}
+
+	// percobaan habis: jadwal sekali jalan gagal, jadwal berulang lanjut ke periode berikutnya
+	data.RetryCount = 0
+	data.RunCount++
+	if data.Frekuensi == model.ScheduleOnce {
+		data.Status = model.ScheduleStatusFailed
+	} else {
+		advanceSchedule(&data)
+	}
+	s.notifySchedule(data, model.NotificationScheduleFailed, "Transfer terjadwal gagal",
+		fmt.Sprintf("Transfer terjadwal sebesar %d gagal setelah %d percobaan: %s", data.JumlahTransfer, scheduleMaxRetry, err.Error()))
+	return data
+}
+
+func (s *scheduleUseCase) transfer(data model.ScheduledTransfer) (model.Transfer, error) {
+	// lease yang habis membuat jadwal bisa diambil instance lain, transfer yang sudah tercatat tidak dikirim ulang
+	referensi := scheduleReferensi(data)
+	trxId, err := s.repo.GetRunTransfer(data.UserId, referensi)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if trxId != "" {
+		return model.Transfer{Id: trxId}, nil
+	}
+	send, err := s.uc.FindById(data.UserId)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	sendBalance, err := s.uc.GetBalanceCase(data.UserId)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if sendBalance.SaldoTersedia < data.JumlahTransfer {
+		return model.Transfer{}, fmt.Errorf("saldo tersedia tidak mencukupi")
+	}
+	receive, err := s.uc.FindById(data.TujuanTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	receiveBalance, err := s.uc.GetBalanceCase(data.TujuanTransfer)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	send.Saldo = sendBalance.Saldo
+	receive.Saldo = receiveBalance.Saldo
+
+	return s.ut.TransferRequest(dto.TransferRequest{
+		UserId:         data.UserId,
+		TujuanTransfer: data.TujuanTransfer,
+		JumlahTransfer: data.JumlahTransfer,
+		Catatan:        data.Catatan,
+		Kategori:       data.Kategori,
+		Referensi:      referensi,
+	}, send, receive)
+}
+
+// scheduleReferensi adalah kunci idempotensi satu eksekusi: jadwal, start_at dan urutan eksekusi,
+// start_at ikut disertakan karena mengubah start_at mengulang run_count dari 0
+func scheduleReferensi(data model.ScheduledTransfer) string {
+	return fmt.Sprintf("schedule:%s:%d:%d", data.Id, data.StartAt.Unix(), data.RunCount+1)
+}
+
+func (s *scheduleUseCase) notifySchedule(data model.ScheduledTransfer, jenis, judul, pesan string) {
+	s.notify.Notify(model.Notification{
+		UserId:      data.UserId,
+		Jenis:       jenis,
+		Judul:       judul,
+		Pesan:       pesan,
+		ReferenceId: data.Id,
+	})
+}
+
+// advanceSchedule menghitung next_run_at berikutnya atau menutup jadwal jika batas tanggal/jumlah tercapai
+func advanceSchedule(data *model.ScheduledTransfer) {
+	if data.Frekuensi == model.ScheduleOnce || (data.MaxRuns > 0 && data.RunCount >= data.MaxRuns) {
+		data.Status = model.ScheduleStatusCompleted
+		return
+	}
+	next := nextOccurrence(data.StartAt, data.Frekuensi, data.RunCount)
+	if data.EndDate != nil && next.After(*data.EndDate) {
+		data.Status = model.ScheduleStatusCompleted
+		return
+	}
+	data.NextRunAt = next
+}
+
+// nextOccurrence menghitung jadwal ke-n dari start, bulanan dibatasi ke akhir bulan (31 Jan -> 28/29 Feb)
+func nextOccurrence(start time.Time, frekuensi string, n int) time.Time {
+	switch frekuensi {
+	case model.ScheduleDaily:
+		return start.AddDate(0, 0, n)
+	case model.ScheduleWeekly:
+		return start.AddDate(0, 0, 7*n)
+	case model.ScheduleMonthly:
+		firstOfMonth := time.Date(start.Year(), start.Month(), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+		target := firstOfMonth.AddDate(0, n, 0)
+		lastDay := target.AddDate(0, 1, -1).Day()
+		day := start.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return target.AddDate(0, 0, day-1)
+	}
+	return start
+}
+
+func validateSchedule(data model.ScheduledTransfer) error {
+	switch data.Frekuensi {
+	case model.ScheduleOnce, model.ScheduleDaily, model.ScheduleWeekly, model.ScheduleMonthly:
+	default:
+		return fmt.Errorf("frekuensi harus once, daily, weekly atau monthly")
+	}
+	if data.JumlahTransfer <= 0 {
+		return fmt.Errorf("jumlah transfer harus lebih dari 0")
+	}
+	if data.TujuanTransfer == data.UserId {
+		return fmt.Errorf("tidak dapat transfer ke akun sendiri")
+	}
+	if data.MaxRuns < 0 {
+		return fmt.Errorf("max_runs tidak boleh negatif")
+	}
+	if data.EndDate != nil && data.EndDate.Before(data.StartAt) {
+		return fmt.Errorf("end_date harus setelah start_at")
+	}
+	if len(data.Catatan) > 250 || len(data.Kategori) > 50 {
+		return fmt.Errorf("catatan maksimal 250 karakter dan kategori maksimal 50 karakter")
+	}
+	return nil
+}
+
+func NewScheduleUseCase(repo repository.ScheduleRepository, ut TransferUseCase, uc UserUseCase, notify NotificationUseCase) ScheduleUseCase {
+	return &scheduleUseCase{repo: repo, ut: ut, uc: uc, notify: notify}
+}
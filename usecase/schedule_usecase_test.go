@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type ScheduleUseCaseTestSuite struct {
+	suite.Suite
+	srm *repomock.ScheduleRepoMock
+	tum *usecasemock.TransferUseCaseMock
+	uum *usecasemock.UserUseCaseMock
+	num *usecasemock.NotificationUseCaseMock
+	su  ScheduleUseCase
+}
+
+func (suite *ScheduleUseCaseTestSuite) SetupTest() {
+	suite.srm = new(repomock.ScheduleRepoMock)
+	suite.tum = new(usecasemock.TransferUseCaseMock)
+	suite.uum = new(usecasemock.UserUseCaseMock)
+	suite.num = new(usecasemock.NotificationUseCaseMock)
+	suite.su = NewScheduleUseCase(suite.srm, suite.tum, suite.uum, suite.num)
+}
+
+func TestScheduleUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ScheduleUseCaseTestSuite))
+}
+
+func (suite *ScheduleUseCaseTestSuite) TestRunDue_Success() {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := model.ScheduledTransfer{Id: "s-1", UserId: "a", TujuanTransfer: "b", JumlahTransfer: 5000,
+		Frekuensi: model.ScheduleMonthly, StartAt: now, NextRunAt: now, Status: model.ScheduleStatusActive}
+	suite.srm.On("GetDue", now, scheduleBatchSize).Return([]model.ScheduledTransfer{due}, nil)
+	suite.srm.On("ClaimDue", "s-1", now, now.Add(scheduleLease)).Return(true, nil)
+	suite.srm.On("GetRunTransfer", "a", scheduleReferensi(due)).Return("", nil)
+	suite.uum.On("FindById", "a").Return(model.User{Id: "a"}, nil)
+	suite.uum.On("FindById", "b").Return(model.User{Id: "b"}, nil)
+	suite.uum.On("GetBalanceCase", "a").Return(model.UserSaldo{Saldo: 10000, SaldoTersedia: 10000}, nil)
+	suite.uum.On("GetBalanceCase", "b").Return(model.UserSaldo{Saldo: 0}, nil)
+	suite.tum.On("TransferRequest", mock.Anything, mock.Anything, mock.Anything).Return(model.Transfer{Id: "trx-1"}, nil)
+	suite.num.On("Notify", mock.Anything).Return(nil)
+	suite.srm.On("UpdateRun", mock.MatchedBy(func(s model.ScheduledTransfer) bool {
+		return s.RunCount == 1 && s.LastTransferId == "trx-1" && s.NextRunAt.Equal(now.AddDate(0, 1, 0))
+	})).Return(true, nil)
+
+	executed, err := suite.su.RunDue(now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, executed)
+	suite.srm.AssertExpectations(suite.T())
+}
+
+func (suite *ScheduleUseCaseTestSuite) TestRunDue_InsufficientBalanceRetries() {
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	due := model.ScheduledTransfer{Id: "s-1", UserId: "a", TujuanTransfer: "b", JumlahTransfer: 5000,
+		Frekuensi: model.ScheduleOnce, StartAt: now, NextRunAt: now, Status: model.ScheduleStatusActive}
+	suite.srm.On("GetDue", now, scheduleBatchSize).Return([]model.ScheduledTransfer{due}, nil)
+	suite.srm.On("ClaimDue", "s-1", now, now.Add(scheduleLease)).Return(true, nil)
+	suite.srm.On("GetRunTransfer", "a", scheduleReferensi(due)).Return("", nil)
+	suite.uum.On("FindById", "a").Return(model.User{Id: "a"}, nil)
+	suite.uum.On("GetBalanceCase", "a").Return(model.UserSaldo{Saldo: 1000, SaldoTersedia: 1000}, nil)
+	suite.num.On("Notify", mock.MatchedBy(func(n model.Notification) bool {
+		return n.Jenis == model.NotificationScheduleRetry
+	})).Return(nil)
+	suite.srm.On("UpdateRun", mock.MatchedBy(func(s model.ScheduledTransfer) bool {
+		return s.RetryCount == 1 && s.Status == model.ScheduleStatusActive && s.NextRunAt.Equal(now.Add(scheduleRetryInterval))
+	})).Return(true, nil)
+
+	_, err := suite.su.RunDue(now)
+	assert.Nil(suite.T(), err)
+	suite.tum.AssertNotCalled(suite.T(), "TransferRequest", mock.Anything, mock.Anything, mock.Anything)
+	suite.num.AssertExpectations(suite.T())
+}
+
+func (suite *ScheduleUseCaseTestSuite) TestRunDue_AlreadyTransferredAfterLeaseExpired() {
+	now := time.Date(2024, 3, 1, 9, 10, 0, 0, time.UTC)
+	start := now.Add(-10 * time.Minute)
+	due := model.ScheduledTransfer{Id: "s-1", UserId: "a", TujuanTransfer: "b", JumlahTransfer: 5000,
+		Frekuensi: model.ScheduleMonthly, StartAt: start, NextRunAt: now, Status: model.ScheduleStatusActive}
+	suite.srm.On("GetDue", now, scheduleBatchSize).Return([]model.ScheduledTransfer{due}, nil)
+	suite.srm.On("ClaimDue", "s-1", now, now.Add(scheduleLease)).Return(true, nil)
+	suite.srm.On("GetRunTransfer", "a", "schedule:s-1:"+fmt.Sprint(start.Unix())+":1").Return("trx-1", nil)
+	suite.num.On("Notify", mock.Anything).Return(nil)
+	suite.srm.On("UpdateRun", mock.MatchedBy(func(s model.ScheduledTransfer) bool {
+		return s.RunCount == 1 && s.LastTransferId == "trx-1"
+	})).Return(true, nil)
+
+	executed, err := suite.su.RunDue(now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, executed)
+	suite.tum.AssertNotCalled(suite.T(), "TransferRequest", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ScheduleUseCaseTestSuite) TestPauseSchedule_NotOwner() {
+	suite.srm.On("Get", "s-1").Return(model.ScheduledTransfer{Id: "s-1", UserId: "a", Status: model.ScheduleStatusActive}, nil)
+
+	_, err := suite.su.PauseSchedule("s-1", "x")
+	assert.Error(suite.T(), err)
+	suite.srm.AssertNotCalled(suite.T(), "Update", mock.Anything)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		frekuensi string
+		n         int
+		expected  time.Time
+	}{
+		{"daily", model.ScheduleDaily, 2, time.Date(2024, 2, 2, 8, 0, 0, 0, time.UTC)},
+		{"weekly", model.ScheduleWeekly, 1, time.Date(2024, 2, 7, 8, 0, 0, 0, time.UTC)},
+		{"monthly clamps to leap february", model.ScheduleMonthly, 1, time.Date(2024, 2, 29, 8, 0, 0, 0, time.UTC)},
+		{"monthly keeps anchor day", model.ScheduleMonthly, 2, time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, nextOccurrence(start, tt.frekuensi, tt.n))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/payout"
+)
+
+// batch settlement harian dijalankan mulai jam ini (waktu server)
+const settlementHour = 22
+
+const (
+	// settlement yang tertahan di processing selama ini dianggap macet dan dikirim ulang oleh RetryStuckSettlements
+	settlementStuckAfter = 30 * time.Minute
+	settlementBatchSize  = 50
+)
+
+type SettlementUseCase interface {
+	RunDue(now time.Time) (int, error)
+	RetryStuckSettlements(now time.Time) (int, error)
+	FindSettlements(merchantId, ownerId string, page int) ([]model.Settlement, error)
+	FindSettlement(id, merchantId, ownerId string) (model.Settlement, error)
+	RetrySettlement(id, merchantId, ownerId string) (model.Settlement, error)
+	WriteReport(w io.Writer, settlement model.Settlement) error
+}
+
+type settlementUseCase struct {
+	repo     repository.SettlementRepository
+	merchant MerchantUseCase
+	fee      FeeUseCase
+	payout   payout.PayoutProvider
+}
+
+// settlementCutoff: T+0 menyertakan penerimaan hari ini, T+1 hanya sampai akhir hari sebelumnya
+func settlementCutoff(jadwal string, now time.Time) time.Time {
+	if jadwal == model.SettlementScheduleT0 {
+		return now
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// RunDue membuat batch settlement hari ini untuk setiap merchant yang belum disettle, aman dipanggil berulang.
+// Merchant yang gagal tidak menghentikan merchant lain, seluruh error dikembalikan bersama untuk dicatat.
+func (s *settlementUseCase) RunDue(now time.Time) (int, error) {
+	if now.Hour() < settlementHour {
+		return 0, nil
+	}
+	tanggal := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	merchants, err := s.repo.GetMerchantsDue(tanggal)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	var errs []error
+	for _, merchant := range merchants {
+		settlement, err := s.settle(merchant, tanggal, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("merchant %s: %w", merchant.Id, err))
+			continue
+		}
+		if settlement.Id == "" {
+			continue
+		}
+		count++
+	}
+
+	return count, errors.Join(errs...)
+}
+
+// RetryStuckSettlements mengirim ulang payout settlement yang tertahan di processing lebih lama dari settlementStuckAfter,
+// payout provider menerima ReferenceId yang sama sehingga pengiriman ulang tidak mencairkan dana dua kali
+func (s *settlementUseCase) RetryStuckSettlements(now time.Time) (int, error) {
+	datas, err := s.repo.GetStuck(now.Add(-settlementStuckAfter), settlementBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	retried := 0
+	var errs []error
+	for _, data := range datas {
+		claimed, err := s.repo.Reclaim(data.Id, data.UpdatedAt)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("settlement %s: %w", data.Id, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if _, err := s.disburse(data); err != nil {
+			errs = append(errs, fmt.Errorf("settlement %s: %w", data.Id, err))
+			continue
+		}
+		retried++
+	}
+
+	return retried, errors.Join(errs...)
+}
+
+// settle menghitung nilai bersih setelah MDR per transaksi, settlement kosong berarti dilewati
+func (s *settlementUseCase) settle(merchant model.Merchant, tanggal, now time.Time) (model.Settlement, error) {
+	cutoff := settlementCutoff(merchant.JadwalSettlement, now)
+	items, err := s.repo.GetUnsettled(merchant.Id, cutoff)
+	if err != nil || len(items) == 0 {
+		return model.Settlement{}, err
+	}
+
+	settlement := model.Settlement{
+		MerchantId: merchant.Id,
+		OwnerId:    merchant.OwnerId,
+		Tanggal:    tanggal,
+		Cutoff:     cutoff,
+		BankCode:   merchant.BankCode,
+		Rekening:   merchant.Rekening,
+	}
+	for i, item := range items {
+		quote, err := s.fee.Quote(dto.FeeQuoteRequest{
+			UserId:    merchant.OwnerId,
+			Operation: model.FeeOperationMerchant,
+			Jumlah:    item.Jumlah,
+			Terpakai:  i,
+		})
+		if err != nil {
+			return model.Settlement{}, err
+		}
+		item.Biaya = quote.Biaya
+		item.FeeRuleId = quote.RuleId
+		item.Net = item.Jumlah - item.Biaya
+		settlement.Items = append(settlement.Items, item)
+		settlement.JumlahTransaksi++
+		settlement.TotalGross += item.Jumlah
+		settlement.TotalBiaya += item.Biaya
+		settlement.TotalNet += item.Net
+	}
+	if settlement.TotalNet <= 0 || settlement.TotalNet < merchant.MinimumSettlement {
+		return model.Settlement{}, nil
+	}
+
+	settlement, err = s.repo.Create(settlement)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+
+	return s.disburse(settlement)
+}
+
+func (s *settlementUseCase) disburse(settlement model.Settlement) (model.Settlement, error) {
+	result, err := s.payout.Disburse(payout.PayoutRequest{
+		ReferenceId: settlement.Id,
+		BankCode:    settlement.BankCode,
+		Rekening:    settlement.Rekening,
+		Jumlah:      settlement.TotalNet,
+	})
+	if err != nil {
+		return s.repo.Finish(settlement.Id, model.SettlementStatusFailed, "", err.Error())
+	}
+	if !result.Success {
+		return s.repo.Finish(settlement.Id, model.SettlementStatusFailed, result.ProviderRef, result.Alasan)
+	}
+
+	return s.repo.Finish(settlement.Id, model.SettlementStatusPaid, result.ProviderRef, "")
+}
+
+func (s *settlementUseCase) FindSettlements(merchantId, ownerId string, page int) ([]model.Settlement, error) {
+	if _, err := s.merchant.FindMerchant(merchantId, ownerId); err != nil {
+		return []model.Settlement{}, err
+	}
+	datas, err := s.repo.GetByMerchant(merchantId, page)
+	if err != nil {
+		return []model.Settlement{}, err
+	}
+
+	return datas, nil
+}
+
+func (s *settlementUseCase) FindSettlement(id, merchantId, ownerId string) (model.Settlement, error) {
+	if _, err := s.merchant.FindMerchant(merchantId, ownerId); err != nil {
+		return model.Settlement{}, err
+	}
+	settlement, err := s.repo.Get(id)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	if settlement.MerchantId != merchantId {
+		return model.Settlement{}, fmt.Errorf("settlement %s tidak ditemukan", id)
+	}
+
+	return settlement, nil
+}
+
+// RetrySettlement mengirim ulang payout settlement yang gagal, misalnya setelah rekening settlement diperbaiki
+func (s *settlementUseCase) RetrySettlement(id, merchantId, ownerId string) (model.Settlement, error) {
+	settlement, err := s.FindSettlement(id, merchantId, ownerId)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	claimed, err := s.repo.Claim(id)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	if !claimed {
+		return model.Settlement{}, fmt.Errorf("hanya settlement yang gagal dapat dicoba ulang")
+	}
+	merchant, err := s.merchant.FindMerchant(merchantId, ownerId)
+	if err != nil {
+		return model.Settlement{}, err
+	}
+	settlement.BankCode = merchant.BankCode
+	settlement.Rekening = merchant.Rekening
+
+	return s.disburse(settlement)
+}
+
+// WriteReport menulis laporan settlement dalam format CSV, satu baris per transaksi yang disettle
+func (s *settlementUseCase) WriteReport(w io.Writer, settlement model.Settlement) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"payment_id", "waktu_bayar", "nama_pembayar", "referensi", "jumlah", "biaya", "net"})
+	for _, item := range settlement.Items {
+		writer.Write([]string{
+			item.PaymentId,
+			item.PaidAt.Format(time.RFC3339),
+			item.NamaPembayar,
+			item.Referensi,
+			strconv.Itoa(item.Jumlah),
+			strconv.Itoa(item.Biaya),
+			strconv.Itoa(item.Net),
+		})
+	}
+	writer.Write([]string{"TOTAL", "", "", "", strconv.Itoa(settlement.TotalGross), strconv.Itoa(settlement.TotalBiaya), strconv.Itoa(settlement.TotalNet)})
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func NewSettlementUseCase(repo repository.SettlementRepository, merchant MerchantUseCase, fee FeeUseCase, payout payout.PayoutProvider) SettlementUseCase {
+	return &settlementUseCase{repo: repo, merchant: merchant, fee: fee, payout: payout}
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/utils/payout"
+)
+
+type SettlementUseCaseTestSuite struct {
+	suite.Suite
+	srm *repomock.SettlementRepoMock
+	mrm *repomock.MerchantRepoMock
+	fum *usecasemock.FeeUseCaseMock
+	us  SettlementUseCase
+	now time.Time
+}
+
+func (suite *SettlementUseCaseTestSuite) SetupTest() {
+	suite.srm = new(repomock.SettlementRepoMock)
+	suite.mrm = new(repomock.MerchantRepoMock)
+	suite.fum = new(usecasemock.FeeUseCaseMock)
+	suite.us = NewSettlementUseCase(suite.srm, NewMerchantUseCase(suite.mrm, nil, nil, nil, nil), suite.fum, payout.NewFakePayoutProvider())
+	suite.now = time.Date(2024, 5, 10, 22, 5, 0, 0, time.Local)
+}
+
+func TestSettlementUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SettlementUseCaseTestSuite))
+}
+
+func (suite *SettlementUseCaseTestSuite) dueMerchant(merchant model.Merchant, cutoff time.Time) {
+	tanggal := time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local)
+	suite.srm.On("GetMerchantsDue", tanggal).Return([]model.Merchant{merchant}, nil)
+	suite.srm.On("GetUnsettled", merchant.Id, cutoff).Return([]model.SettlementItem{
+		{PaymentId: "p-1", Jumlah: 10000},
+		{PaymentId: "p-2", Jumlah: 20000},
+	}, nil)
+	for i, jumlah := range []int{10000, 20000} {
+		suite.fum.On("Quote", dto.FeeQuoteRequest{UserId: merchant.OwnerId, Operation: model.FeeOperationMerchant, Jumlah: jumlah, Terpakai: i}).
+			Return(model.FeeQuote{Jumlah: jumlah, Biaya: jumlah / 100, RuleId: "rule-mdr"}, nil)
+	}
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRunDue_BeforeSettlementHour() {
+	count, err := suite.us.RunDue(time.Date(2024, 5, 10, 9, 0, 0, 0, time.Local))
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, count)
+	suite.srm.AssertNotCalled(suite.T(), "GetMerchantsDue", mock.Anything)
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRunDue_NetOfFees() {
+	merchant := model.Merchant{Id: "m-1", OwnerId: "u-1", BankCode: "014", Rekening: "1234567890", JadwalSettlement: model.SettlementScheduleT1}
+	suite.dueMerchant(merchant, time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local))
+	var created model.Settlement
+	suite.srm.On("Create", mock.AnythingOfType("model.Settlement")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(model.Settlement)
+	}).Return(model.Settlement{Id: "s-1", TotalNet: 29700, BankCode: "014", Rekening: "1234567890"}, nil)
+	suite.srm.On("Finish", "s-1", model.SettlementStatusPaid, mock.AnythingOfType("string"), "").
+		Return(model.Settlement{Id: "s-1", Status: model.SettlementStatusPaid}, nil)
+
+	count, err := suite.us.RunDue(suite.now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, count)
+	assert.Equal(suite.T(), 2, created.JumlahTransaksi)
+	assert.Equal(suite.T(), 30000, created.TotalGross)
+	assert.Equal(suite.T(), 300, created.TotalBiaya)
+	assert.Equal(suite.T(), 29700, created.TotalNet)
+	assert.Equal(suite.T(), "rule-mdr", created.Items[0].FeeRuleId)
+	assert.Equal(suite.T(), 19800, created.Items[1].Net)
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRunDue_BelowMinimum() {
+	merchant := model.Merchant{Id: "m-1", OwnerId: "u-1", JadwalSettlement: model.SettlementScheduleT0, MinimumSettlement: 50000}
+	suite.dueMerchant(merchant, suite.now)
+
+	count, err := suite.us.RunDue(suite.now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 0, count)
+	suite.srm.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRunDue_PayoutFailed() {
+	merchant := model.Merchant{Id: "m-1", OwnerId: "u-1", BankCode: "014", Rekening: "0001234", JadwalSettlement: model.SettlementScheduleT1}
+	suite.dueMerchant(merchant, time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local))
+	suite.srm.On("Create", mock.AnythingOfType("model.Settlement")).
+		Return(model.Settlement{Id: "s-1", TotalNet: 29700, BankCode: "014", Rekening: "0001234"}, nil)
+	suite.srm.On("Finish", "s-1", model.SettlementStatusFailed, "", "rekening tujuan tidak valid").
+		Return(model.Settlement{Id: "s-1", Status: model.SettlementStatusFailed}, nil)
+
+	_, err := suite.us.RunDue(suite.now)
+	assert.Nil(suite.T(), err)
+	suite.srm.AssertCalled(suite.T(), "Finish", "s-1", model.SettlementStatusFailed, "", "rekening tujuan tidak valid")
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRunDue_CreateFailedReported() {
+	merchant := model.Merchant{Id: "m-1", OwnerId: "u-1", JadwalSettlement: model.SettlementScheduleT1}
+	suite.dueMerchant(merchant, time.Date(2024, 5, 10, 0, 0, 0, 0, time.Local))
+	suite.srm.On("Create", mock.AnythingOfType("model.Settlement")).
+		Return(model.Settlement{}, errors.New("saldo merchant tidak mencukupi untuk settlement"))
+
+	count, err := suite.us.RunDue(suite.now)
+	assert.Equal(suite.T(), 0, count)
+	assert.EqualError(suite.T(), err, "merchant m-1: saldo merchant tidak mencukupi untuk settlement")
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRetryStuckSettlements() {
+	updatedAt := suite.now.Add(-time.Hour)
+	suite.srm.On("GetStuck", suite.now.Add(-settlementStuckAfter), settlementBatchSize).Return([]model.Settlement{
+		{Id: "s-1", TotalNet: 29700, BankCode: "014", Rekening: "1234567890", UpdatedAt: updatedAt},
+		{Id: "s-2", UpdatedAt: updatedAt},
+	}, nil)
+	suite.srm.On("Reclaim", "s-1", updatedAt).Return(true, nil)
+	suite.srm.On("Reclaim", "s-2", updatedAt).Return(false, nil)
+	suite.srm.On("Finish", "s-1", model.SettlementStatusPaid, mock.AnythingOfType("string"), "").
+		Return(model.Settlement{Id: "s-1", Status: model.SettlementStatusPaid}, nil)
+
+	retried, err := suite.us.RetryStuckSettlements(suite.now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, retried)
+	suite.srm.AssertNotCalled(suite.T(), "Finish", "s-2", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SettlementUseCaseTestSuite) TestRetrySettlement_NotFailed() {
+	suite.mrm.On("Get", "m-1").Return(model.Merchant{Id: "m-1", OwnerId: "u-1"}, nil)
+	suite.srm.On("Get", "s-1").Return(model.Settlement{Id: "s-1", MerchantId: "m-1", Status: model.SettlementStatusPaid}, nil)
+	suite.srm.On("Claim", "s-1").Return(false, nil)
+
+	_, err := suite.us.RetrySettlement("s-1", "m-1", "u-1")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *SettlementUseCaseTestSuite) TestWriteReport() {
+	var buf bytes.Buffer
+	err := suite.us.WriteReport(&buf, model.Settlement{TotalGross: 10000, TotalBiaya: 100, TotalNet: 9900, Items: []model.SettlementItem{
+		{PaymentId: "p-1", NamaPembayar: "Budi", Jumlah: 10000, Biaya: 100, Net: 9900},
+	}})
+	assert.Nil(suite.T(), err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(suite.T(), lines, 3)
+	assert.True(suite.T(), strings.HasPrefix(lines[1], "p-1,"))
+	assert.Equal(suite.T(), "TOTAL,,,,10000,100,9900", lines[2])
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/pdf"
+)
+
+// periode rekening koran dan analitik maksimal satu tahun
+const statementMaxDays = 366
+
+// jumlah baris mutasi per halaman PDF
+const statementRowsPerPage = 50
+
+type StatementUseCase interface {
+	Generate(userId, dari, sampai string) (model.Statement, error)
+	WriteCSV(w io.Writer, statement model.Statement) error
+	WritePDF(w io.Writer, statement model.Statement) error
+}
+
+type statementUseCase struct {
+	repo repository.StatementRepository
+}
+
+// parsePeriod membaca periode dari-sampai (YYYY-MM-DD, inklusif), default bulan berjalan sampai hari ini
+func parsePeriod(dari, sampai string) (time.Time, time.Time, error) {
+	now := time.Now()
+	if dari == "" {
+		dari = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+	}
+	if sampai == "" {
+		sampai = now.Format("2006-01-02")
+	}
+	start, err := time.Parse("2006-01-02", dari)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("from harus berformat YYYY-MM-DD")
+	}
+	end, err := time.Parse("2006-01-02", sampai)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("to harus berformat YYYY-MM-DD")
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from tidak boleh setelah to")
+	}
+	if end.Sub(start) > statementMaxDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("periode maksimal %d hari", statementMaxDays)
+	}
+
+	return start, end, nil
+}
+
+// Generate menyusun rekening koran satu periode.
+// Saldo akhir dihitung mundur dari saldo saat ini dikurangi mutasi setelah periode.
+func (s *statementUseCase) Generate(userId, dari, sampai string) (model.Statement, error) {
+	start, end, err := parsePeriod(dari, sampai)
+	if err != nil {
+		return model.Statement{}, err
+	}
+	endExclusive := end.AddDate(0, 0, 1)
+
+	statement, err := s.repo.GetAccount(userId)
+	if err != nil {
+		return model.Statement{}, err
+	}
+	after, err := s.repo.GetNetSince(userId, endExclusive)
+	if err != nil {
+		return model.Statement{}, err
+	}
+	lines, err := s.repo.GetLines(userId, start, endExclusive)
+	if err != nil {
+		return model.Statement{}, err
+	}
+
+	statement.Dari = start
+	statement.Sampai = end
+	statement.SaldoAkhir -= after
+	var net int
+	for _, line := range lines {
+		net += line.Mutasi
+		if line.Mutasi > 0 {
+			statement.TotalMasuk += line.Mutasi
+		} else {
+			statement.TotalKeluar -= line.Mutasi
+		}
+	}
+	statement.SaldoAwal = statement.SaldoAkhir - net
+	saldo := statement.SaldoAwal
+	for i := range lines {
+		saldo += lines[i].Mutasi
+		lines[i].Saldo = saldo
+	}
+	statement.Lines = lines
+	if statement.Lines == nil {
+		statement.Lines = []model.StatementLine{}
+	}
+	statement.GeneratedAt = time.Now()
+
+	return statement, nil
+}
+
+func (s *statementUseCase) WriteCSV(w io.Writer, statement model.Statement) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"tanggal", "id", "jenis", "pihak", "catatan", "referensi", "kategori", "masuk", "keluar", "saldo"})
+	writer.Write([]string{statement.Dari.Format("2006-01-02"), "", "saldo_awal", "", "", "", "", "", "", strconv.Itoa(statement.SaldoAwal)})
+	for _, line := range statement.Lines {
+		var masuk, keluar string
+		if line.Mutasi > 0 {
+			masuk = strconv.Itoa(line.Mutasi)
+		} else {
+			keluar = strconv.Itoa(-line.Mutasi)
+		}
+		writer.Write([]string{
+			line.Tanggal.Format("2006-01-02 15:04:05"),
+			line.Id,
+			line.Jenis,
+			line.Pihak,
+			line.Catatan,
+			line.Referensi,
+			line.Kategori,
+			masuk,
+			keluar,
+			strconv.Itoa(line.Saldo),
+		})
+	}
+	writer.Write([]string{statement.Sampai.Format("2006-01-02"), "", "saldo_akhir", "", "", "", "", strconv.Itoa(statement.TotalMasuk),
+		strconv.Itoa(statement.TotalKeluar), strconv.Itoa(statement.SaldoAkhir)})
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// formatRupiah memformat nominal dengan pemisah ribuan titik, misalnya -1.250.000
+func formatRupiah(jumlah int) string {
+	sign := ""
+	if jumlah < 0 {
+		sign = "-"
+		jumlah = -jumlah
+	}
+	digits := strconv.Itoa(jumlah)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// fit memotong atau menambah spasi agar teks tepat n karakter, dipakai untuk kolom font monospace
+func fit(text string, n int, right bool) string {
+	runes := []rune(text)
+	if len(runes) > n {
+		return string(runes[:n-1]) + "~"
+	}
+	pad := strings.Repeat(" ", n-len(runes))
+	if right {
+		return pad + text
+	}
+	return text + pad
+}
+
+func statementRow(tanggal, jenis, keterangan, mutasi, saldo string) string {
+	return fit(tanggal, 17, false) + fit(jenis, 17, false) + fit(keterangan, 34, false) + fit(mutasi, 14, true) + fit(saldo, 15, true)
+}
+
+// WritePDF merender rekening koran ke PDF, tabel mutasi memakai font monospace agar kolom rata
+func (s *statementUseCase) WritePDF(w io.Writer, statement model.Statement) error {
+	doc := pdf.New()
+	pages := (len(statement.Lines) + statementRowsPerPage - 1) / statementRowsPerPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	for page := 0; page < pages; page++ {
+		doc.AddPage()
+		y := 50.0
+		if page == 0 {
+			doc.Text(40, y, pdf.FontBold, 16, "Rekening Koran E-Wallet")
+			y += 22
+			doc.Text(40, y, pdf.FontRegular, 10, "Nama   : "+statement.Nama)
+			y += 14
+			doc.Text(40, y, pdf.FontRegular, 10, "ID User: "+statement.UserId)
+			y += 14
+			doc.Text(40, y, pdf.FontRegular, 10, fmt.Sprintf("Periode: %s s/d %s", statement.Dari.Format("02-01-2006"),
+				statement.Sampai.Format("02-01-2006")))
+			y += 14
+			doc.Text(40, y, pdf.FontRegular, 10, "Dicetak: "+statement.GeneratedAt.Format("02-01-2006 15:04"))
+			y += 22
+			doc.Text(40, y, pdf.FontMono, 9, fit("Saldo awal", 20, false)+fit(formatRupiah(statement.SaldoAwal), 18, true))
+			y += 12
+			doc.Text(40, y, pdf.FontMono, 9, fit("Total masuk", 20, false)+fit(formatRupiah(statement.TotalMasuk), 18, true))
+			y += 12
+			doc.Text(40, y, pdf.FontMono, 9, fit("Total keluar", 20, false)+fit(formatRupiah(statement.TotalKeluar), 18, true))
+			y += 12
+			doc.Text(40, y, pdf.FontMono, 9, fit("Saldo akhir", 20, false)+fit(formatRupiah(statement.SaldoAkhir), 18, true))
+			y += 22
+		}
+		doc.Text(40, y, pdf.FontMono, 8, statementRow("Tanggal", "Jenis", "Keterangan", "Mutasi", "Saldo"))
+		y += 4
+		doc.Line(40, pdf.PageWidth-40, y)
+		y += 12
+
+		end := (page + 1) * statementRowsPerPage
+		if end > len(statement.Lines) {
+			end = len(statement.Lines)
+		}
+		for _, line := range statement.Lines[page*statementRowsPerPage : end] {
+			keterangan := line.Pihak
+			if line.Catatan != "" {
+				keterangan = strings.TrimPrefix(keterangan+" - "+line.Catatan, " - ")
+			}
+			if line.Kategori != "" {
+				keterangan += " [" + line.Kategori + "]"
+			}
+			doc.Text(40, y, pdf.FontMono, 8, statementRow(line.Tanggal.Format("02-01-2006 15:04"), line.Jenis, keterangan,
+				formatRupiah(line.Mutasi), formatRupiah(line.Saldo)))
+			y += 12
+		}
+		if page == pages-1 {
+			doc.Line(40, pdf.PageWidth-40, y-8)
+			y += 4
+			doc.Text(40, y, pdf.FontMono, 8, statementRow("", "", "Saldo akhir", "", formatRupiah(statement.SaldoAkhir)))
+		}
+		doc.Text(40, pdf.PageHeight-30, pdf.FontRegular, 8, fmt.Sprintf("Halaman %d dari %d", page+1, pages))
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+func NewStatementUseCase(repo repository.StatementRepository) StatementUseCase {
+	return &statementUseCase{repo: repo}
+}
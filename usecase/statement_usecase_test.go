@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+)
+
+type StatementUseCaseTestSuite struct {
+	suite.Suite
+	srm *repomock.StatementRepoMock
+	us  StatementUseCase
+}
+
+func (suite *StatementUseCaseTestSuite) SetupTest() {
+	suite.srm = new(repomock.StatementRepoMock)
+	suite.us = NewStatementUseCase(suite.srm)
+}
+
+func TestStatementUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(StatementUseCaseTestSuite))
+}
+
+func (suite *StatementUseCaseTestSuite) TestGenerate_Balances() {
+	dari := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	sampai := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.srm.On("GetAccount", "u-1").Return(model.Statement{UserId: "u-1", Nama: "Budi", SaldoAkhir: 120000}, nil)
+	// setelah periode ada topup 20.000 sehingga saldo akhir Mei 100.000
+	suite.srm.On("GetNetSince", "u-1", sampai).Return(20000, nil)
+	suite.srm.On("GetLines", "u-1", dari, sampai).Return([]model.StatementLine{
+		{Id: "t-1", Jenis: "topup", Mutasi: 50000},
+		{Id: "t-2", Jenis: "transfer", Pihak: "Ani", Mutasi: -30000},
+	}, nil)
+
+	statement, err := suite.us.Generate("u-1", "2024-05-01", "2024-05-31")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 100000, statement.SaldoAkhir)
+	assert.Equal(suite.T(), 80000, statement.SaldoAwal)
+	assert.Equal(suite.T(), 50000, statement.TotalMasuk)
+	assert.Equal(suite.T(), 30000, statement.TotalKeluar)
+	assert.Equal(suite.T(), 130000, statement.Lines[0].Saldo)
+	assert.Equal(suite.T(), 100000, statement.Lines[1].Saldo)
+}
+
+func (suite *StatementUseCaseTestSuite) TestGenerate_InvalidPeriod() {
+	_, err := suite.us.Generate("u-1", "2024-05-31", "2024-05-01")
+	assert.Error(suite.T(), err)
+	_, err = suite.us.Generate("u-1", "2023-01-01", "2024-05-01")
+	assert.Error(suite.T(), err)
+	_, err = suite.us.Generate("u-1", "01-05-2024", "2024-05-31")
+	assert.Error(suite.T(), err)
+	suite.srm.AssertNotCalled(suite.T(), "GetAccount", mock.Anything)
+}
+
+func (suite *StatementUseCaseTestSuite) statement() model.Statement {
+	return model.Statement{
+		UserId: "u-1", Nama: "Budi", SaldoAwal: 80000, TotalMasuk: 50000, TotalKeluar: 30000, SaldoAkhir: 100000,
+		Dari: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), Sampai: time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+		Lines: []model.StatementLine{
+			{Id: "t-1", Jenis: "topup", Mutasi: 50000, Saldo: 130000},
+			{Id: "t-2", Jenis: "transfer", Pihak: "Ani", Catatan: "makan (siang)", Kategori: "makan", Mutasi: -30000, Saldo: 100000},
+		},
+	}
+}
+
+func (suite *StatementUseCaseTestSuite) TestWriteCSV() {
+	var buf bytes.Buffer
+	assert.Nil(suite.T(), suite.us.WriteCSV(&buf, suite.statement()))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(suite.T(), lines, 5)
+	assert.True(suite.T(), strings.HasSuffix(lines[1], ",saldo_awal,,,,,,,80000"))
+	assert.Contains(suite.T(), lines[3], ",makan,,30000,100000")
+	assert.True(suite.T(), strings.HasSuffix(lines[4], ",saldo_akhir,,,,,50000,30000,100000"))
+}
+
+func (suite *StatementUseCaseTestSuite) TestWritePDF() {
+	var buf bytes.Buffer
+	assert.Nil(suite.T(), suite.us.WritePDF(&buf, suite.statement()))
+	out := buf.String()
+	assert.True(suite.T(), strings.HasPrefix(out, "%PDF-1.4"))
+	assert.Contains(suite.T(), out, "Ani - makan \\(siang\\) [makan]")
+	assert.Contains(suite.T(), out, "100.000")
+}
+
+func (suite *StatementUseCaseTestSuite) TestFormatRupiah() {
+	assert.Equal(suite.T(), "0", formatRupiah(0))
+	assert.Equal(suite.T(), "999", formatRupiah(999))
+	assert.Equal(suite.T(), "1.000", formatRupiah(1000))
+	assert.Equal(suite.T(), "-1.250.000", formatRupiah(-1250000))
+}
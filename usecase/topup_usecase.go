@@ -16,9 +16,21 @@ type TopupUseCase interface {
 
 type topupUseCase struct {
 	repo repository.TopupRepository
+	fee  FeeUseCase
 }
 
 func (t *topupUseCase) CreateTopup(payload model.TopupModel) (common.ResponseMidtrans, error) {
+	quote, err := t.fee.Quote(dto.FeeQuoteRequest{
+		UserId:    payload.User.Id,
+		Operation: model.FeeOperationTopup,
+		Channel:   payload.Channel,
+		Jumlah:    int(payload.TransactionDetails.GrossAmt),
+	})
+	if err != nil {
+		return common.ResponseMidtrans{}, err
+	}
+	payload.Biaya = quote.Biaya
+	payload.FeeRuleId = quote.RuleId
 
 	midtransResponse, err := t.repo.Create(payload)
 	if err != nil {
@@ -55,6 +67,6 @@ func (t *topupUseCase) FindAll(id string, page int) ([]model.TableTopupPayment,
 	return datas, nil
 }
 
-func NewTopupUseCase(repo repository.TopupRepository) TopupUseCase {
-	return &topupUseCase{repo: repo}
+func NewTopupUseCase(repo repository.TopupRepository, fee FeeUseCase) TopupUseCase {
+	return &topupUseCase{repo: repo, fee: fee}
 }
@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+)
+
+const (
+	transactionDefaultLimit = 20
+	transactionMaxLimit     = 100
+)
+
+type TransactionUseCase interface {
+	FindTransactions(userId string, filter dto.TransactionFilter) (model.TransactionPage, error)
+	FindTransaction(id, userId string) (model.Transaction, error)
+}
+
+type transactionUseCase struct {
+	repo repository.TransactionRepository
+}
+
+// encodeCursor menyimpan posisi baris terakhir (created_at, id) sebagai token opaque
+func encodeCursor(data model.Transaction) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(data.CreatedAt.Format(time.RFC3339Nano) + "|" + data.Id))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("cursor tidak valid")
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return time.Time{}, "", fmt.Errorf("cursor tidak valid")
+	}
+	at, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("cursor tidak valid")
+	}
+
+	return at, parts[1], nil
+}
+
+func buildTransactionQuery(userId string, filter dto.TransactionFilter) (dto.TransactionQuery, error) {
+	query := dto.TransactionQuery{
+		UserId: userId,
+		Jenis:  filter.Jenis,
+		Arah:   filter.Arah,
+		Min:    filter.Min,
+		Max:    filter.Max,
+		Pihak:  strings.TrimSpace(filter.Pihak),
+		Status: filter.Status,
+		Limit:  filter.Limit,
+	}
+	switch query.Jenis {
+	case "", model.TransactionTypeTransfer, model.TransactionTypeTopup, model.TransactionTypeWithdraw,
+		model.TransactionTypeMerchantPayment, model.TransactionTypeRefund, model.TransactionTypeHoldCapture, model.TransactionTypePocket:
+	default:
+		return dto.TransactionQuery{}, fmt.Errorf("type harus transfer, topup, withdraw, merchant_payment, refund, hold_capture atau pocket")
+	}
+	if query.Arah != "" && query.Arah != model.TransactionDirectionIn && query.Arah != model.TransactionDirectionOut {
+		return dto.TransactionQuery{}, fmt.Errorf("direction harus masuk atau keluar")
+	}
+	if query.Min < 0 || query.Max < 0 || (query.Max > 0 && query.Min > query.Max) {
+		return dto.TransactionQuery{}, fmt.Errorf("rentang jumlah tidak valid")
+	}
+	if query.Limit <= 0 {
+		query.Limit = transactionDefaultLimit
+	}
+	if query.Limit > transactionMaxLimit {
+		return dto.TransactionQuery{}, fmt.Errorf("limit maksimal %d", transactionMaxLimit)
+	}
+	if filter.Dari != "" {
+		dari, err := time.Parse("2006-01-02", filter.Dari)
+		if err != nil {
+			return dto.TransactionQuery{}, fmt.Errorf("from harus berformat YYYY-MM-DD")
+		}
+		query.Dari = &dari
+	}
+	if filter.Sampai != "" {
+		sampai, err := time.Parse("2006-01-02", filter.Sampai)
+		if err != nil {
+			return dto.TransactionQuery{}, fmt.Errorf("to harus berformat YYYY-MM-DD")
+		}
+		// tanggal akhir inklusif
+		sampai = sampai.AddDate(0, 0, 1)
+		query.Sampai = &sampai
+	}
+	if query.Dari != nil && query.Sampai != nil && !query.Dari.Before(*query.Sampai) {
+		return dto.TransactionQuery{}, fmt.Errorf("from tidak boleh setelah to")
+	}
+	if filter.Cursor != "" {
+		at, id, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return dto.TransactionQuery{}, err
+		}
+		query.CursorAt = &at
+		query.CursorId = id
+	}
+
+	return query, nil
+}
+
+// FindTransactions menampilkan riwayat gabungan, satu baris ekstra diambil untuk mengetahui adanya halaman berikutnya
+func (t *transactionUseCase) FindTransactions(userId string, filter dto.TransactionFilter) (model.TransactionPage, error) {
+	query, err := buildTransactionQuery(userId, filter)
+	if err != nil {
+		return model.TransactionPage{}, err
+	}
+	limit := query.Limit
+	query.Limit = limit + 1
+	datas, err := t.repo.GetAll(query)
+	if err != nil {
+		return model.TransactionPage{}, err
+	}
+
+	page := model.TransactionPage{Data: datas}
+	if len(datas) > limit {
+		page.Data = datas[:limit]
+		page.NextCursor = encodeCursor(page.Data[limit-1])
+	}
+	if page.Data == nil {
+		page.Data = []model.Transaction{}
+	}
+
+	return page, nil
+}
+
+func (t *transactionUseCase) FindTransaction(id, userId string) (model.Transaction, error) {
+	return t.repo.Get(id, userId)
+}
+
+func NewTransactionUseCase(repo repository.TransactionRepository) TransactionUseCase {
+	return &transactionUseCase{repo: repo}
+}
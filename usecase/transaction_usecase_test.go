@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+)
+
+type TransactionUseCaseTestSuite struct {
+	suite.Suite
+	trm *repomock.TransactionRepoMock
+	ut  TransactionUseCase
+}
+
+func (suite *TransactionUseCaseTestSuite) SetupTest() {
+	suite.trm = new(repomock.TransactionRepoMock)
+	suite.ut = NewTransactionUseCase(suite.trm)
+}
+
+func TestTransactionUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TransactionUseCaseTestSuite))
+}
+
+func (suite *TransactionUseCaseTestSuite) TestFindTransactions_NextCursor() {
+	base := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	rows := []model.Transaction{
+		{Id: "c", CreatedAt: base},
+		{Id: "b", CreatedAt: base.Add(-time.Minute)},
+		{Id: "a", CreatedAt: base.Add(-2 * time.Minute)},
+	}
+	var query dto.TransactionQuery
+	suite.trm.On("GetAll", mock.AnythingOfType("dto.TransactionQuery")).Run(func(args mock.Arguments) {
+		query = args.Get(0).(dto.TransactionQuery)
+	}).Return(rows, nil).Once()
+
+	page, err := suite.ut.FindTransactions("u-1", dto.TransactionFilter{Limit: 2})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 3, query.Limit)
+	assert.Len(suite.T(), page.Data, 2)
+	assert.NotEmpty(suite.T(), page.NextCursor)
+
+	suite.trm.On("GetAll", mock.AnythingOfType("dto.TransactionQuery")).Run(func(args mock.Arguments) {
+		query = args.Get(0).(dto.TransactionQuery)
+	}).Return(rows[2:], nil).Once()
+	page, err = suite.ut.FindTransactions("u-1", dto.TransactionFilter{Limit: 2, Cursor: page.NextCursor})
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "b", query.CursorId)
+	assert.True(suite.T(), query.CursorAt.Equal(rows[1].CreatedAt))
+	assert.Len(suite.T(), page.Data, 1)
+	assert.Empty(suite.T(), page.NextCursor)
+}
+
+func (suite *TransactionUseCaseTestSuite) TestFindTransactions_Filters() {
+	var query dto.TransactionQuery
+	suite.trm.On("GetAll", mock.AnythingOfType("dto.TransactionQuery")).Run(func(args mock.Arguments) {
+		query = args.Get(0).(dto.TransactionQuery)
+	}).Return([]model.Transaction{}, nil)
+
+	page, err := suite.ut.FindTransactions("u-1", dto.TransactionFilter{Jenis: "transfer", Arah: "keluar",
+		Dari: "2024-05-01", Sampai: "2024-05-31", Min: 1000, Max: 50000})
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), page.Data)
+	assert.Equal(suite.T(), transactionDefaultLimit+1, query.Limit)
+	assert.Equal(suite.T(), "2024-06-01", query.Sampai.Format("2006-01-02"))
+	assert.Equal(suite.T(), "2024-05-01", query.Dari.Format("2006-01-02"))
+}
+
+func (suite *TransactionUseCaseTestSuite) TestFindTransactions_Invalid() {
+	invalid := []dto.TransactionFilter{
+		{Jenis: "hadiah"},
+		{Arah: "samping"},
+		{Min: 5000, Max: 1000},
+		{Limit: transactionMaxLimit + 1},
+		{Dari: "10-05-2024"},
+		{Dari: "2024-05-10", Sampai: "2024-05-01"},
+		{Cursor: "bukan-cursor"},
+	}
+	for _, filter := range invalid {
+		_, err := suite.ut.FindTransactions("u-1", filter)
+		assert.Error(suite.T(), err, filter)
+	}
+	suite.trm.AssertNotCalled(suite.T(), "GetAll", mock.Anything)
+}
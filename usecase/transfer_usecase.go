@@ -1,36 +1,199 @@
 package usecase
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/yafireyhan01/e-wallet/model"
 	"github.com/yafireyhan01/e-wallet/model/dto"
 	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/payout"
 )
 
 type TransferUseCase interface {
 	TransferRequest(payload dto.TransferRequest, send, receive model.User) (model.Transfer, error)
-	GetSend(id string, page int) ([]model.Transfer, error)
-	GetReceive(id string, page int) ([]model.Transfer, error)
-	Withdraw(payload model.Withdraw, saldo model.UserSaldo) (model.Withdraw, error)
+	QuoteTransfer(payload dto.TransferRequest, send, receive model.User) (model.TransferIntent, error)
+	FindIntent(id, userId string) (model.TransferIntent, error)
+	ConfirmTransfer(id string, send, receive model.User) (model.Transfer, error)
+	GetSend(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error)
+	GetReceive(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error)
+	UpdateCategory(trxId, userId, kategori string) (model.Transfer, error)
+	Withdraw(payload model.Withdraw) (model.Withdraw, error)
 	GetAllWithDraw(id string, page int) ([]model.Withdraw, error)
+	FindWithdraw(id, userId, role string) (model.Withdraw, error)
+	ProcessWithdraw(id string) (model.Withdraw, error)
+	RetryStuckWithdraws(now time.Time) (int, error)
+	CancelWithdraw(id, userId string) (model.Withdraw, error)
+	ReverseTransfer(payload dto.ReversalRequest, adminId string) (model.Reversal, error)
+	FindActiveReversal(trxId string) (model.Reversal, error)
+	GetPendingReversal(userId string) ([]model.Reversal, error)
+	ApproveReversal(id, userId string) (model.Reversal, error)
+	RejectReversal(id, userId string) (model.Reversal, error)
 }
 
+// masa berlaku quote transfer sebelum harus dikonfirmasi
+const transferIntentTTL = 5 * time.Minute
+
+const (
+	// withdraw yang tidak berubah selama ini dianggap macet dan diulang oleh RetryStuckWithdraws
+	withdrawStuckAfter = 10 * time.Minute
+	withdrawBatchSize  = 50
+)
+
 type transferUseCase struct {
-	repo repository.TransferRepository
+	repo   repository.TransferRepository
+	payout payout.PayoutProvider
+	fee    FeeUseCase
+	budget BudgetUseCase
+	family FamilyUseCase
 }
 
 // tulis code kalian disini
 
 func (t *transferUseCase) TransferRequest(payload dto.TransferRequest, send, receive model.User) (model.Transfer, error) {
+	if err := validateTransferMeta(&payload); err != nil {
+		return model.Transfer{}, err
+	}
+	quote, err := t.fee.Quote(dto.FeeQuoteRequest{UserId: send.Id, Operation: model.FeeOperationTransfer, Jumlah: payload.JumlahTransfer})
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	payload.Biaya = quote.Biaya
+	payload.FeeRuleId = quote.RuleId
+	spend := dto.FamilySpendRequest{UserId: send.Id, Jenis: model.FamilySpendTransfer, Jumlah: payload.JumlahTransfer,
+		Tujuan: receive.Id, Keterangan: receive.Name}
+	reservation, err := t.family.Authorize(spend)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+
 	response, err := t.repo.Create(payload, send, receive)
 	if err != nil {
+		t.family.Release(reservation.Id)
 		return model.Transfer{}, err
 	}
+	t.budget.Evaluate(send.Id)
+	t.family.Record(model.FamilyActivity{UserId: send.Id, Jenis: spend.Jenis, Jumlah: spend.Jumlah, Keterangan: receive.Name,
+		Referensi: response.Id})
 
 	return response, nil
 }
 
-func (t *transferUseCase) GetSend(id string, page int) ([]model.Transfer, error) {
-	datas, err := t.repo.GetSend(id, page)
+// QuoteTransfer memvalidasi penerima, biaya dan saldo tersedia lalu menyimpan intent yang berlaku singkat
+func (t *transferUseCase) QuoteTransfer(payload dto.TransferRequest, send, receive model.User) (model.TransferIntent, error) {
+	if payload.JumlahTransfer <= 0 {
+		return model.TransferIntent{}, fmt.Errorf("jumlah transfer harus lebih dari 0")
+	}
+	if send.Id == receive.Id {
+		return model.TransferIntent{}, fmt.Errorf("tidak dapat transfer ke akun sendiri")
+	}
+	if err := validateTransferMeta(&payload); err != nil {
+		return model.TransferIntent{}, err
+	}
+	quote, err := t.fee.Quote(dto.FeeQuoteRequest{UserId: send.Id, Operation: model.FeeOperationTransfer, Jumlah: payload.JumlahTransfer})
+	if err != nil {
+		return model.TransferIntent{}, err
+	}
+	if send.Saldo < quote.Total {
+		return model.TransferIntent{}, fmt.Errorf("saldo tersedia tidak mencukupi untuk transfer %d dengan biaya %d", payload.JumlahTransfer, quote.Biaya)
+	}
+	perluPersetujuan, err := t.family.Check(dto.FamilySpendRequest{UserId: send.Id, Jenis: model.FamilySpendTransfer,
+		Jumlah: payload.JumlahTransfer, Tujuan: receive.Id, Keterangan: receive.Name})
+	if err != nil {
+		return model.TransferIntent{}, err
+	}
+
+	intent, err := t.repo.CreateIntent(model.TransferIntent{
+		UserId:         send.Id,
+		TujuanTransfer: receive.Id,
+		JumlahTransfer: payload.JumlahTransfer,
+		Biaya:          quote.Biaya,
+		FeeRuleId:      quote.RuleId,
+		Catatan:        payload.Catatan,
+		Referensi:      payload.Referensi,
+		Kategori:       payload.Kategori,
+		ExpiresAt:      time.Now().Add(transferIntentTTL),
+	})
+	if err != nil {
+		return model.TransferIntent{}, err
+	}
+	intent.NamaPenerima = maskName(receive.Name)
+	intent.SaldoSetelah = send.Saldo - intent.Total
+	intent.PerluPersetujuan = perluPersetujuan
+
+	return intent, nil
+}
+
+func (t *transferUseCase) FindIntent(id, userId string) (model.TransferIntent, error) {
+	intent, err := t.repo.GetIntent(id)
+	if err != nil {
+		return model.TransferIntent{}, err
+	}
+	if intent.UserId != userId {
+		return model.TransferIntent{}, fmt.Errorf("intent transfer %s tidak ditemukan", id)
+	}
+	if intent.Status == model.IntentStatusPending && time.Now().After(intent.ExpiresAt) {
+		intent.Status = model.IntentStatusExpired
+	}
+
+	return intent, nil
+}
+
+// ConfirmTransfer mengeksekusi intent dengan jumlah dan biaya yang sudah di-quote, bukan tarif terbaru
+func (t *transferUseCase) ConfirmTransfer(id string, send, receive model.User) (model.Transfer, error) {
+	intent, err := t.FindIntent(id, send.Id)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	if intent.TujuanTransfer != receive.Id {
+		return model.Transfer{}, fmt.Errorf("penerima tidak sesuai dengan intent transfer")
+	}
+	spend := dto.FamilySpendRequest{UserId: send.Id, Jenis: model.FamilySpendTransfer, Jumlah: intent.JumlahTransfer,
+		Tujuan: receive.Id, Keterangan: receive.Name}
+	reservation, err := t.family.Authorize(spend)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	claimed, err := t.repo.ClaimIntent(id)
+	if err != nil {
+		t.family.Release(reservation.Id)
+		return model.Transfer{}, err
+	}
+	if !claimed {
+		t.family.Release(reservation.Id)
+		return model.Transfer{}, fmt.Errorf("intent transfer sudah %s, silahkan buat quote baru", intent.Status)
+	}
+
+	response, err := t.repo.Create(dto.TransferRequest{
+		UserId:         send.Id,
+		TujuanTransfer: receive.Id,
+		JumlahTransfer: intent.JumlahTransfer,
+		Biaya:          intent.Biaya,
+		FeeRuleId:      intent.FeeRuleId,
+		Catatan:        intent.Catatan,
+		Referensi:      intent.Referensi,
+		Kategori:       intent.Kategori,
+	}, send, receive)
+	if err != nil {
+		t.repo.FinishIntent(id, model.IntentStatusFailed, "")
+		t.family.Release(reservation.Id)
+		return model.Transfer{}, err
+	}
+	if err := t.repo.FinishIntent(id, model.IntentStatusConfirmed, response.Id); err != nil {
+		return model.Transfer{}, err
+	}
+	t.budget.Evaluate(send.Id)
+	t.family.Record(model.FamilyActivity{UserId: send.Id, Jenis: spend.Jenis, Jumlah: spend.Jumlah, Keterangan: receive.Name,
+		Referensi: response.Id})
+
+	return response, nil
+}
+
+func (t *transferUseCase) GetSend(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
+	filter.Kategori = normalizeCategory(filter.Kategori)
+	datas, err := t.repo.GetSend(id, page, filter)
 	if err != nil {
 		return []model.Transfer{}, err
 	}
@@ -38,8 +201,9 @@ func (t *transferUseCase) GetSend(id string, page int) ([]model.Transfer, error)
 	return datas, nil
 }
 
-func (t *transferUseCase) GetReceive(id string, page int) ([]model.Transfer, error) {
-	datas, err := t.repo.GetReceive(id, page)
+func (t *transferUseCase) GetReceive(id string, page int, filter dto.TransferFilter) ([]model.Transfer, error) {
+	filter.Kategori = normalizeCategory(filter.Kategori)
+	datas, err := t.repo.GetReceive(id, page, filter)
 	if err != nil {
 		return []model.Transfer{}, err
 	}
@@ -47,11 +211,71 @@ func (t *transferUseCase) GetReceive(id string, page int) ([]model.Transfer, err
 	return datas, nil
 }
 
-func (t *transferUseCase) Withdraw(payload model.Withdraw, saldo model.UserSaldo) (model.Withdraw, error) {
-	res, err := t.repo.CreateWithdraw(payload, saldo)
+func (t *transferUseCase) UpdateCategory(trxId, userId, kategori string) (model.Transfer, error) {
+	kategori = normalizeCategory(kategori)
+	if kategori == "" || len(kategori) > 50 {
+		return model.Transfer{}, fmt.Errorf("kategori harus diisi, maksimal 50 karakter")
+	}
+	response, err := t.repo.UpdateCategory(trxId, userId, kategori)
+	if err != nil {
+		return model.Transfer{}, err
+	}
+	// pindah kategori bisa membuat budget kategori tujuan terlampaui
+	t.budget.Evaluate(userId)
+
+	return response, nil
+}
+
+// validateTransferMeta merapikan catatan, referensi dan kategori sebelum disimpan
+func validateTransferMeta(payload *dto.TransferRequest) error {
+	payload.Catatan = strings.TrimSpace(payload.Catatan)
+	payload.Referensi = strings.TrimSpace(payload.Referensi)
+	payload.Kategori = normalizeCategory(payload.Kategori)
+	if len(payload.Catatan) > 250 {
+		return fmt.Errorf("catatan maksimal 250 karakter")
+	}
+	if len(payload.Referensi) > 100 {
+		return fmt.Errorf("referensi maksimal 100 karakter")
+	}
+	if len(payload.Kategori) > 50 {
+		return fmt.Errorf("kategori maksimal 50 karakter")
+	}
+	return nil
+}
+
+func normalizeCategory(kategori string) string {
+	return strings.ToLower(strings.TrimSpace(kategori))
+}
+
+func (t *transferUseCase) Withdraw(payload model.Withdraw) (model.Withdraw, error) {
+	if payload.Withdraw <= 0 {
+		return model.Withdraw{}, fmt.Errorf("jumlah withdraw harus lebih dari 0")
+	}
+	quote, err := t.fee.Quote(dto.FeeQuoteRequest{UserId: payload.UserId, Operation: model.FeeOperationWithdraw, Jumlah: payload.Withdraw})
 	if err != nil {
 		return model.Withdraw{}, err
 	}
+	payload.Biaya = quote.Biaya
+	payload.FeeRuleId = quote.RuleId
+	tujuan := strings.TrimSpace(payload.BankCode + " " + payload.Rekening)
+	spend := dto.FamilySpendRequest{UserId: payload.UserId, Jenis: model.FamilySpendWithdraw, Jumlah: payload.Withdraw,
+		Tujuan: tujuan, Keterangan: tujuan}
+	reservation, err := t.family.Authorize(spend)
+	if err != nil {
+		return model.Withdraw{}, err
+	}
+
+	res, err := t.repo.CreateWithdraw(payload)
+	if err != nil {
+		t.family.Release(reservation.Id)
+		return model.Withdraw{}, err
+	}
+	t.family.Record(model.FamilyActivity{UserId: payload.UserId, Jenis: spend.Jenis, Jumlah: spend.Jumlah, Keterangan: tujuan,
+		Referensi: res.Id})
+
+	// payout diproses di belakang, status bisa dipantau lewat GET /transfer/withdraw/:id;
+	// jika proses ini gagal di tengah jalan withdraw diulang oleh RetryStuckWithdraws
+	go t.ProcessWithdraw(res.Id)
 
 	return res, nil
 }
@@ -66,6 +290,191 @@ func (t *transferUseCase) GetAllWithDraw(id string, page int) ([]model.Withdraw,
 	return datas, nil
 }
 
-func NewTransferUseCase(repo repository.TransferRepository) TransferUseCase {
-	return &transferUseCase{repo: repo}
+func (t *transferUseCase) FindWithdraw(id, userId, role string) (model.Withdraw, error) {
+	withdraw, err := t.repo.GetWithdrawById(id)
+	if err != nil {
+		return model.Withdraw{}, err
+	}
+	if role != "admin" && withdraw.UserId != userId {
+		return model.Withdraw{}, fmt.Errorf("withdraw %s tidak ditemukan", id)
+	}
+
+	return withdraw, nil
+}
+
+func (t *transferUseCase) ProcessWithdraw(id string) (model.Withdraw, error) {
+	claimed, err := t.repo.ClaimWithdraw(id)
+	if err != nil {
+		return model.Withdraw{}, err
+	}
+	withdraw, err := t.repo.GetWithdrawById(id)
+	if err != nil || !claimed {
+		return withdraw, err
+	}
+
+	return t.disburseWithdraw(withdraw)
+}
+
+// RetryStuckWithdraws mengulang withdraw yang tertahan di requested/processing lebih lama dari withdrawStuckAfter,
+// payout provider menerima ReferenceId yang sama sehingga pengiriman ulang tidak mencairkan dana dua kali
+func (t *transferUseCase) RetryStuckWithdraws(now time.Time) (int, error) {
+	datas, err := t.repo.GetStuckWithdraws(now.Add(-withdrawStuckAfter), withdrawBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// satu withdraw yang gagal tidak menahan withdraw lain di batch yang sama
+	retried := 0
+	var errs []error
+	for _, data := range datas {
+		claimed, err := t.repo.ReclaimWithdraw(data.Id, data.Updated_at)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("withdraw %s: %w", data.Id, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		if _, err := t.disburseWithdraw(data); err != nil {
+			errs = append(errs, fmt.Errorf("withdraw %s: %w", data.Id, err))
+			continue
+		}
+		retried++
+	}
+
+	return retried, errors.Join(errs...)
+}
+
+// disburseWithdraw mengirim payout untuk withdraw yang sudah diklaim lalu menutupnya sesuai hasil provider
+func (t *transferUseCase) disburseWithdraw(withdraw model.Withdraw) (model.Withdraw, error) {
+	id := withdraw.Id
+	result, err := t.payout.Disburse(payout.PayoutRequest{
+		ReferenceId: withdraw.Id,
+		BankCode:    withdraw.BankCode,
+		Rekening:    withdraw.Rekening,
+		Jumlah:      withdraw.Withdraw,
+	})
+	if err != nil {
+		return t.repo.FinishWithdraw(id, model.WithdrawStatusFailed, "", err.Error())
+	}
+	if !result.Success {
+		return t.repo.FinishWithdraw(id, model.WithdrawStatusFailed, result.ProviderRef, result.Alasan)
+	}
+
+	return t.repo.FinishWithdraw(id, model.WithdrawStatusPaid, result.ProviderRef, "")
+}
+
+func (t *transferUseCase) CancelWithdraw(id, userId string) (model.Withdraw, error) {
+	if _, err := t.FindWithdraw(id, userId, "user"); err != nil {
+		return model.Withdraw{}, err
+	}
+
+	return t.repo.FinishWithdraw(id, model.WithdrawStatusCancelled, "", "dibatalkan oleh user")
+}
+
+func (t *transferUseCase) ReverseTransfer(payload dto.ReversalRequest, adminId string) (model.Reversal, error) {
+	if payload.Policy == "" {
+		payload.Policy = model.ReversalPolicyReject
+	}
+	if payload.Policy != model.ReversalPolicyReject && payload.Policy != model.ReversalPolicyPartial {
+		return model.Reversal{}, fmt.Errorf("policy harus %s atau %s", model.ReversalPolicyReject, model.ReversalPolicyPartial)
+	}
+
+	original, err := t.repo.GetById(payload.TrxId)
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if original.ReversalOf != "" {
+		return model.Reversal{}, fmt.Errorf("transaksi reversal tidak bisa di-reversal lagi")
+	}
+	if original.Status != model.TransferStatusSuccess {
+		return model.Reversal{}, fmt.Errorf("transaksi dengan status %s tidak bisa di-reversal", original.Status)
+	}
+
+	reversal := model.Reversal{
+		TrxId:          original.Id,
+		RequestedBy:    adminId,
+		Alasan:         payload.Alasan,
+		Policy:         payload.Policy,
+		RequireConsent: payload.RequireConsent,
+		JumlahDiminta:  original.JumlahTransfer,
+		Status:         model.ReversalStatusPending,
+	}
+	if payload.RequireConsent {
+		reversal.Status = model.ReversalStatusPendingConsent
+	}
+	reversal, err = t.repo.CreateReversal(reversal)
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if reversal.RequireConsent {
+		return reversal, nil
+	}
+
+	return t.executeReversal(reversal.Id)
+}
+
+func (t *transferUseCase) FindActiveReversal(trxId string) (model.Reversal, error) {
+	return t.repo.GetActiveReversal(trxId)
+}
+
+func (t *transferUseCase) GetPendingReversal(userId string) ([]model.Reversal, error) {
+	datas, err := t.repo.GetPendingReversal(userId)
+	if err != nil {
+		return []model.Reversal{}, err
+	}
+
+	return datas, nil
+}
+
+func (t *transferUseCase) ApproveReversal(id, userId string) (model.Reversal, error) {
+	if _, err := t.findConsentReversal(id, userId); err != nil {
+		return model.Reversal{}, err
+	}
+
+	return t.executeReversal(id)
+}
+
+func (t *transferUseCase) RejectReversal(id, userId string) (model.Reversal, error) {
+	if _, err := t.findConsentReversal(id, userId); err != nil {
+		return model.Reversal{}, err
+	}
+
+	return t.repo.UpdateReversalStatus(id, model.ReversalStatusDeclined)
+}
+
+// findConsentReversal memastikan reversal menunggu persetujuan dari penerima transaksi asal
+func (t *transferUseCase) findConsentReversal(id, userId string) (model.Reversal, error) {
+	reversal, err := t.repo.GetReversal(id)
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if reversal.Status != model.ReversalStatusPendingConsent {
+		return model.Reversal{}, fmt.Errorf("reversal ini tidak menunggu persetujuan")
+	}
+	original, err := t.repo.GetById(reversal.TrxId)
+	if err != nil {
+		return model.Reversal{}, err
+	}
+	if original.TujuanTransfer != userId {
+		return model.Reversal{}, fmt.Errorf("anda bukan penerima transaksi ini")
+	}
+
+	return reversal, nil
+}
+
+func (t *transferUseCase) executeReversal(id string) (model.Reversal, error) {
+	reversal, err := t.repo.ExecuteReversal(id)
+	if err != nil {
+		// tandai gagal supaya transaksi bisa diajukan reversal ulang
+		t.repo.UpdateReversalStatus(id, model.ReversalStatusFailed)
+		return model.Reversal{}, err
+	}
+
+	return reversal, nil
+}
+
+func NewTransferUseCase(repo repository.TransferRepository, payout payout.PayoutProvider, fee FeeUseCase, budget BudgetUseCase,
+	family FamilyUseCase) TransferUseCase {
+	return &transferUseCase{repo: repo, payout: payout, fee: fee, budget: budget, family: family}
 }
@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	usecasemock "github.com/yafireyhan01/e-wallet/mock/usecase_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/utils/payout"
+)
+
+type TransferUseCaseTestSuite struct {
+	suite.Suite
+	trm *repomock.TransferRepoMock
+	fum *usecasemock.FeeUseCaseMock
+	bum *usecasemock.BudgetUseCaseMock
+	fam *usecasemock.FamilyUseCaseMock
+	tu  TransferUseCase
+}
+
+func (suite *TransferUseCaseTestSuite) SetupTest() {
+	suite.trm = new(repomock.TransferRepoMock)
+	suite.fum = new(usecasemock.FeeUseCaseMock)
+	suite.bum = new(usecasemock.BudgetUseCaseMock)
+	suite.bum.On("Evaluate", mock.Anything).Return(nil)
+	suite.fam = new(usecasemock.FamilyUseCaseMock)
+	suite.fam.On("Authorize", mock.Anything).Return(model.FamilyReservation{}, nil)
+	suite.fam.On("Check", mock.Anything).Return(false, nil)
+	suite.fam.On("Release", mock.Anything).Return(nil)
+	suite.fam.On("Record", mock.Anything).Return(nil)
+	suite.tu = NewTransferUseCase(suite.trm, payout.NewFakePayoutProvider(), suite.fum, suite.bum, suite.fam)
+}
+
+func TestTransferUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TransferUseCaseTestSuite))
+}
+
+func (suite *TransferUseCaseTestSuite) TestReverseTransfer_Success() {
+	original := model.Transfer{Id: "trx-1", UserId: "a", TujuanTransfer: "b", JumlahTransfer: 5000, Status: model.TransferStatusSuccess}
+	created := model.Reversal{Id: "rev-1", TrxId: "trx-1", Status: model.ReversalStatusPending, Policy: model.ReversalPolicyReject}
+	completed := created
+	completed.Status = model.ReversalStatusCompleted
+	suite.trm.On("GetById", "trx-1").Return(original, nil)
+	suite.trm.On("CreateReversal", mock.Anything).Return(created, nil)
+	suite.trm.On("ExecuteReversal", "rev-1").Return(completed, nil)
+
+	actual, err := suite.tu.ReverseTransfer(dto.ReversalRequest{TrxId: "trx-1"}, "admin-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.ReversalStatusCompleted, actual.Status)
+}
+
+func (suite *TransferUseCaseTestSuite) TestReverseTransfer_RequireConsent() {
+	original := model.Transfer{Id: "trx-1", JumlahTransfer: 5000, Status: model.TransferStatusSuccess}
+	created := model.Reversal{Id: "rev-1", TrxId: "trx-1", RequireConsent: true, Status: model.ReversalStatusPendingConsent}
+	suite.trm.On("GetById", "trx-1").Return(original, nil)
+	suite.trm.On("CreateReversal", mock.Anything).Return(created, nil)
+
+	actual, err := suite.tu.ReverseTransfer(dto.ReversalRequest{TrxId: "trx-1", RequireConsent: true}, "admin-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.ReversalStatusPendingConsent, actual.Status)
+	suite.trm.AssertNotCalled(suite.T(), "ExecuteReversal", mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestReverseTransfer_AlreadyReversed() {
+	original := model.Transfer{Id: "trx-1", Status: model.TransferStatusReversed}
+	suite.trm.On("GetById", "trx-1").Return(original, nil)
+
+	_, err := suite.tu.ReverseTransfer(dto.ReversalRequest{TrxId: "trx-1"}, "admin-1")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TransferUseCaseTestSuite) TestReverseTransfer_ExecuteFailed() {
+	original := model.Transfer{Id: "trx-1", Status: model.TransferStatusSuccess}
+	created := model.Reversal{Id: "rev-1", TrxId: "trx-1", Status: model.ReversalStatusPending}
+	suite.trm.On("GetById", "trx-1").Return(original, nil)
+	suite.trm.On("CreateReversal", mock.Anything).Return(created, nil)
+	suite.trm.On("ExecuteReversal", "rev-1").Return(model.Reversal{}, errors.New("saldo penerima tidak mencukupi"))
+	suite.trm.On("UpdateReversalStatus", "rev-1", model.ReversalStatusFailed).Return(model.Reversal{}, nil)
+
+	_, err := suite.tu.ReverseTransfer(dto.ReversalRequest{TrxId: "trx-1"}, "admin-1")
+	assert.Error(suite.T(), err)
+	suite.trm.AssertCalled(suite.T(), "UpdateReversalStatus", "rev-1", model.ReversalStatusFailed)
+}
+
+func (suite *TransferUseCaseTestSuite) TestApproveReversal_NotRecipient() {
+	reversal := model.Reversal{Id: "rev-1", TrxId: "trx-1", Status: model.ReversalStatusPendingConsent}
+	suite.trm.On("GetReversal", "rev-1").Return(reversal, nil)
+	suite.trm.On("GetById", "trx-1").Return(model.Transfer{Id: "trx-1", TujuanTransfer: "b"}, nil)
+
+	_, err := suite.tu.ApproveReversal("rev-1", "c")
+	assert.Error(suite.T(), err)
+}
+
+func (suite *TransferUseCaseTestSuite) TestProcessWithdraw_Paid() {
+	withdraw := model.Withdraw{Id: "wd-1", UserId: "a", Withdraw: 10000, Rekening: "1234567890", Status: model.WithdrawStatusProcessing}
+	paid := withdraw
+	paid.Status = model.WithdrawStatusPaid
+	suite.trm.On("ClaimWithdraw", "wd-1").Return(true, nil)
+	suite.trm.On("GetWithdrawById", "wd-1").Return(withdraw, nil)
+	suite.trm.On("FinishWithdraw", "wd-1", model.WithdrawStatusPaid, mock.Anything, "").Return(paid, nil)
+
+	actual, err := suite.tu.ProcessWithdraw("wd-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.WithdrawStatusPaid, actual.Status)
+}
+
+func (suite *TransferUseCaseTestSuite) TestProcessWithdraw_FailedReleasesHold() {
+	withdraw := model.Withdraw{Id: "wd-1", UserId: "a", Withdraw: 10000, Rekening: "0001234567", Status: model.WithdrawStatusProcessing}
+	failed := withdraw
+	failed.Status = model.WithdrawStatusFailed
+	suite.trm.On("ClaimWithdraw", "wd-1").Return(true, nil)
+	suite.trm.On("GetWithdrawById", "wd-1").Return(withdraw, nil)
+	suite.trm.On("FinishWithdraw", "wd-1", model.WithdrawStatusFailed, mock.Anything, mock.Anything).Return(failed, nil)
+
+	actual, err := suite.tu.ProcessWithdraw("wd-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.WithdrawStatusFailed, actual.Status)
+}
+
+func (suite *TransferUseCaseTestSuite) TestProcessWithdraw_AlreadyCancelled() {
+	cancelled := model.Withdraw{Id: "wd-1", Status: model.WithdrawStatusCancelled}
+	suite.trm.On("ClaimWithdraw", "wd-1").Return(false, nil)
+	suite.trm.On("GetWithdrawById", "wd-1").Return(cancelled, nil)
+
+	actual, err := suite.tu.ProcessWithdraw("wd-1")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), model.WithdrawStatusCancelled, actual.Status)
+	suite.trm.AssertNotCalled(suite.T(), "FinishWithdraw", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestRetryStuckWithdraws_ResumesStaleProcessing() {
+	now := time.Now()
+	stale := model.Withdraw{Id: "wd-1", UserId: "a", Withdraw: 10000, Rekening: "1234567890", Status: model.WithdrawStatusProcessing,
+		Updated_at: now.Add(-time.Hour)}
+	taken := model.Withdraw{Id: "wd-2", Status: model.WithdrawStatusProcessing, Updated_at: now.Add(-time.Hour)}
+	paid := stale
+	paid.Status = model.WithdrawStatusPaid
+	suite.trm.On("GetStuckWithdraws", now.Add(-withdrawStuckAfter), withdrawBatchSize).Return([]model.Withdraw{stale, taken}, nil)
+	suite.trm.On("ReclaimWithdraw", "wd-1", stale.Updated_at).Return(true, nil)
+	suite.trm.On("ReclaimWithdraw", "wd-2", taken.Updated_at).Return(false, nil)
+	suite.trm.On("FinishWithdraw", "wd-1", model.WithdrawStatusPaid, mock.Anything, "").Return(paid, nil)
+
+	retried, err := suite.tu.RetryStuckWithdraws(now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, retried)
+	suite.trm.AssertNotCalled(suite.T(), "FinishWithdraw", "wd-2", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestRetryStuckWithdraws_ContinuesPastFailure() {
+	now := time.Now()
+	broken := model.Withdraw{Id: "wd-1", Status: model.WithdrawStatusProcessing, Updated_at: now.Add(-time.Hour)}
+	stale := model.Withdraw{Id: "wd-2", UserId: "a", Withdraw: 10000, Rekening: "1234567890", Status: model.WithdrawStatusProcessing,
+		Updated_at: now.Add(-time.Hour)}
+	paid := stale
+	paid.Status = model.WithdrawStatusPaid
+	suite.trm.On("GetStuckWithdraws", now.Add(-withdrawStuckAfter), withdrawBatchSize).Return([]model.Withdraw{broken, stale}, nil)
+	suite.trm.On("ReclaimWithdraw", "wd-1", broken.Updated_at).Return(false, errors.New("koneksi terputus"))
+	suite.trm.On("ReclaimWithdraw", "wd-2", stale.Updated_at).Return(true, nil)
+	suite.trm.On("FinishWithdraw", "wd-2", model.WithdrawStatusPaid, mock.Anything, "").Return(paid, nil)
+
+	retried, err := suite.tu.RetryStuckWithdraws(now)
+	assert.EqualError(suite.T(), err, "withdraw wd-1: koneksi terputus")
+	assert.Equal(suite.T(), 1, retried)
+	suite.trm.AssertCalled(suite.T(), "FinishWithdraw", "wd-2", model.WithdrawStatusPaid, mock.Anything, "")
+}
+
+func (suite *TransferUseCaseTestSuite) TestTransferRequest_ChargesQuotedFee() {
+	payload := dto.TransferRequest{JumlahTransfer: 10000}
+	send := model.User{Id: "a", Saldo: 50000}
+	receive := model.User{Id: "b"}
+	quote := model.FeeQuote{Jumlah: 10000, Biaya: 1000, Total: 11000, RuleId: "rule-1"}
+	charged := payload
+	charged.Biaya = 1000
+	charged.FeeRuleId = "rule-1"
+	suite.fum.On("Quote", mock.Anything).Return(quote, nil)
+	suite.trm.On("Create", charged, send, receive).Return(model.Transfer{Id: "trx-1", Biaya: 1000}, nil)
+
+	actual, err := suite.tu.TransferRequest(payload, send, receive)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1000, actual.Biaya)
+}
+
+func (suite *TransferUseCaseTestSuite) TestQuoteTransfer_InsufficientBalance() {
+	payload := dto.TransferRequest{JumlahTransfer: 10000}
+	quote := model.FeeQuote{Jumlah: 10000, Biaya: 1000, Total: 11000}
+	suite.fum.On("Quote", mock.Anything).Return(quote, nil)
+
+	_, err := suite.tu.QuoteTransfer(payload, model.User{Id: "a", Saldo: 10500}, model.User{Id: "b"})
+	assert.Error(suite.T(), err)
+	suite.trm.AssertNotCalled(suite.T(), "CreateIntent", mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestQuoteTransfer_FamilyRejected() {
+	payload := dto.TransferRequest{JumlahTransfer: 10000}
+	suite.fum.On("Quote", mock.Anything).Return(model.FeeQuote{Jumlah: 10000, Total: 10000}, nil)
+	fam := new(usecasemock.FamilyUseCaseMock)
+	fam.On("Check", mock.Anything).Return(false, errors.New("transaksi transfer tidak diizinkan oleh owner keluarga"))
+	tu := NewTransferUseCase(suite.trm, payout.NewFakePayoutProvider(), suite.fum, suite.bum, fam)
+
+	_, err := tu.QuoteTransfer(payload, model.User{Id: "a", Saldo: 50000}, model.User{Id: "b"})
+	assert.EqualError(suite.T(), err, "transaksi transfer tidak diizinkan oleh owner keluarga")
+	suite.trm.AssertNotCalled(suite.T(), "CreateIntent", mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestConfirmTransfer_UsesQuotedTerms() {
+	intent := model.TransferIntent{Id: "intent-1", UserId: "a", TujuanTransfer: "b", JumlahTransfer: 10000, Biaya: 1000,
+		FeeRuleId: "rule-1", Status: model.IntentStatusPending, ExpiresAt: time.Now().Add(time.Minute)}
+	send := model.User{Id: "a", Saldo: 50000}
+	receive := model.User{Id: "b"}
+	expectedPayload := dto.TransferRequest{UserId: "a", TujuanTransfer: "b", JumlahTransfer: 10000, Biaya: 1000, FeeRuleId: "rule-1"}
+	suite.trm.On("GetIntent", "intent-1").Return(intent, nil)
+	suite.trm.On("ClaimIntent", "intent-1").Return(true, nil)
+	suite.trm.On("Create", expectedPayload, send, receive).Return(model.Transfer{Id: "trx-1"}, nil)
+	suite.trm.On("FinishIntent", "intent-1", model.IntentStatusConfirmed, "trx-1").Return(nil)
+
+	actual, err := suite.tu.ConfirmTransfer("intent-1", send, receive)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "trx-1", actual.Id)
+}
+
+func (suite *TransferUseCaseTestSuite) TestConfirmTransfer_Expired() {
+	intent := model.TransferIntent{Id: "intent-1", UserId: "a", TujuanTransfer: "b", Status: model.IntentStatusPending,
+		ExpiresAt: time.Now().Add(-time.Minute)}
+	suite.trm.On("GetIntent", "intent-1").Return(intent, nil)
+	suite.trm.On("ClaimIntent", "intent-1").Return(false, nil)
+
+	_, err := suite.tu.ConfirmTransfer("intent-1", model.User{Id: "a"}, model.User{Id: "b"})
+	assert.Error(suite.T(), err)
+	suite.trm.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestTransferRequest_NormalizesMeta() {
+	payload := dto.TransferRequest{JumlahTransfer: 10000, Catatan: " makan siang ", Kategori: " Makanan "}
+	send := model.User{Id: "a", Saldo: 50000}
+	receive := model.User{Id: "b"}
+	stored := dto.TransferRequest{JumlahTransfer: 10000, Catatan: "makan siang", Kategori: "makanan"}
+	suite.fum.On("Quote", mock.Anything).Return(model.FeeQuote{Jumlah: 10000, Total: 10000}, nil)
+	suite.trm.On("Create", stored, send, receive).Return(model.Transfer{Id: "trx-1", Kategori: "makanan"}, nil)
+
+	actual, err := suite.tu.TransferRequest(payload, send, receive)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "makanan", actual.Kategori)
+}
+
+func (suite *TransferUseCaseTestSuite) TestUpdateCategory_Empty() {
+	_, err := suite.tu.UpdateCategory("trx-1", "a", "  ")
+	assert.Error(suite.T(), err)
+	suite.trm.AssertNotCalled(suite.T(), "UpdateCategory", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestTransferRequest_BlockedByFamily() {
+	payload := dto.TransferRequest{JumlahTransfer: 300000}
+	send := model.User{Id: "anak", Saldo: 500000}
+	receive := model.User{Id: "b", Name: "Budi"}
+	suite.fum.On("Quote", mock.Anything).Return(model.FeeQuote{Jumlah: 300000, Total: 300000}, nil)
+	family := new(usecasemock.FamilyUseCaseMock)
+	family.On("Authorize", dto.FamilySpendRequest{UserId: "anak", Jenis: model.FamilySpendTransfer, Jumlah: 300000, Tujuan: "b",
+		Keterangan: "Budi"}).Return(model.FamilyReservation{}, errors.New("melebihi limit harian keluarga, sisa limit hari ini 50000"))
+	suite.tu = NewTransferUseCase(suite.trm, payout.NewFakePayoutProvider(), suite.fum, suite.bum, family)
+
+	_, err := suite.tu.TransferRequest(payload, send, receive)
+	assert.Error(suite.T(), err)
+	suite.trm.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+	family.AssertNotCalled(suite.T(), "Record", mock.Anything)
+}
+
+func (suite *TransferUseCaseTestSuite) TestTransferRequest_FailedReleasesFamilyReservation() {
+	payload := dto.TransferRequest{JumlahTransfer: 30000}
+	send := model.User{Id: "anak", Saldo: 500000}
+	receive := model.User{Id: "b", Name: "Budi"}
+	suite.fum.On("Quote", mock.Anything).Return(model.FeeQuote{Jumlah: 30000, Total: 30000}, nil)
+	family := new(usecasemock.FamilyUseCaseMock)
+	family.On("Authorize", mock.Anything).Return(model.FamilyReservation{Id: "rsv-1"}, nil)
+	family.On("Release", "rsv-1").Return(nil)
+	suite.trm.On("Create", mock.Anything, send, receive).Return(model.Transfer{}, errors.New("saldo tidak mencukupi"))
+	suite.tu = NewTransferUseCase(suite.trm, payout.NewFakePayoutProvider(), suite.fum, suite.bum, family)
+
+	_, err := suite.tu.TransferRequest(payload, send, receive)
+	assert.EqualError(suite.T(), err, "saldo tidak mencukupi")
+	family.AssertCalled(suite.T(), "Release", "rsv-1")
+	family.AssertNotCalled(suite.T(), "Record", mock.Anything)
+}
@@ -4,12 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/yafireyhan01/e-wallet/model"
 	"github.com/yafireyhan01/e-wallet/model/dto"
 	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/bank"
 	"github.com/yafireyhan01/e-wallet/utils/common"
 	encryption "github.com/yafireyhan01/e-wallet/utils/encription"
 )
@@ -23,11 +26,25 @@ type UserUseCase interface {
 	VerifyUser(payload dto.VerifyUser) (dto.VerifyUser, error)
 	UpdatePinUser(payload dto.UpdatePinRequest) (dto.UpdatePinResponse, error)
 	FindRekening(id string) (model.Rekening, error)
+	FindRekeningById(id, userId string) (model.Rekening, error)
+	FindAllRekening(userId string) ([]model.Rekening, error)
 	CreateRekening(payload model.Rekening) (model.Rekening, error)
+	SetDefaultRekening(id, userId string) (model.Rekening, error)
+	DeleteRekening(id, userId string) error
+	InquiryRekening(id, userId string) (model.Rekening, error)
+	ResolveRecipient(query string) (model.User, error)
+	LookupRecipient(query, userId string) (dto.RecipientResponse, error)
+	SetPaymentTag(userId, tag string) (model.User, error)
 }
 
+var (
+	uuidPattern       = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	paymentTagPattern = regexp.MustCompile(`^[a-z0-9_.]{3,30}$`)
+)
+
 type userUseCase struct {
-	repo repository.UserRepository
+	repo    repository.UserRepository
+	inquiry bank.BankInquiry
 }
 
 func (u *userUseCase) FindById(id string) (model.User, error) {
@@ -139,7 +156,32 @@ func (u *userUseCase) FindRekening(id string) (model.Rekening, error) {
 	return res, nil
 }
 
+func (u *userUseCase) FindRekeningById(id, userId string) (model.Rekening, error) {
+	res, err := u.repo.GetRekeningById(id)
+	if err != nil {
+		return model.Rekening{}, err
+	}
+	if res.UserId != userId {
+		return model.Rekening{}, fmt.Errorf("1")
+	}
+	return res, nil
+}
+
+func (u *userUseCase) FindAllRekening(userId string) ([]model.Rekening, error) {
+	res, err := u.repo.GetAllRekening(userId)
+	if err != nil {
+		return []model.Rekening{}, err
+	}
+	return res, nil
+}
+
 func (u *userUseCase) CreateRekening(payload model.Rekening) (model.Rekening, error) {
+	if !bank.IsValidBankCode(payload.BankCode) {
+		return model.Rekening{}, fmt.Errorf("kode bank %s tidak dikenal", payload.BankCode)
+	}
+	if payload.Rekening == "" || payload.NamaPemilik == "" {
+		return model.Rekening{}, fmt.Errorf("rekening dan nama pemilik harus diisi")
+	}
 	res, err := u.repo.CreateRekening(payload)
 	if err != nil {
 		return model.Rekening{}, err
@@ -148,6 +190,121 @@ func (u *userUseCase) CreateRekening(payload model.Rekening) (model.Rekening, er
 	return res, nil
 }
 
-func NewUserUseCase(repo repository.UserRepository) UserUseCase {
-	return &userUseCase{repo: repo}
+func (u *userUseCase) SetDefaultRekening(id, userId string) (model.Rekening, error) {
+	return u.repo.SetDefaultRekening(id, userId)
+}
+
+func (u *userUseCase) DeleteRekening(id, userId string) error {
+	return u.repo.DeleteRekening(id, userId)
+}
+
+// InquiryRekening mencocokkan nama pemilik dari bank dengan nama KYC user sebelum rekening boleh dipakai withdraw
+func (u *userUseCase) InquiryRekening(id, userId string) (model.Rekening, error) {
+	rekening, err := u.FindRekeningById(id, userId)
+	if err != nil {
+		return model.Rekening{}, err
+	}
+	if _, err := u.repo.GetBalance(userId); err != nil {
+		return model.Rekening{}, err
+	}
+	user, err := u.repo.Get(userId)
+	if err != nil {
+		return model.Rekening{}, err
+	}
+
+	result, err := u.inquiry.Inquire(bank.InquiryRequest{
+		BankCode:    rekening.BankCode,
+		Rekening:    rekening.Rekening,
+		NamaPemilik: rekening.NamaPemilik,
+	})
+	if err != nil {
+		return model.Rekening{}, err
+	}
+	if !bank.SameName(result.NamaPemilik, user.Name) {
+		return model.Rekening{}, fmt.Errorf("nama pemilik rekening (%s) tidak sesuai dengan nama akun", result.NamaPemilik)
+	}
+
+	return u.repo.VerifyRekening(id)
+}
+
+// ResolveRecipient mencari penerima dari UUID, payment tag, username, nomor hp atau email
+func (u *userUseCase) ResolveRecipient(query string) (model.User, error) {
+	query = strings.TrimSpace(query)
+	if uuidPattern.MatchString(query) {
+		return u.FindById(query)
+	}
+	tagOnly := strings.HasPrefix(query, "@") || strings.HasPrefix(query, "$")
+	query = strings.TrimLeft(query, "@$")
+	if query == "" {
+		return model.User{}, fmt.Errorf("penerima harus diisi")
+	}
+	// awalan @ atau $ hanya mencari payment tag, tanpa awalan username/hp/email didahulukan
+	if tagOnly {
+		user, err := u.repo.GetByPaymentTag(query)
+		if err != nil {
+			return model.User{}, fmt.Errorf("penerima tidak ditemukan")
+		}
+		return user, nil
+	}
+	user, err := u.repo.GetByRecipient(query, alternatePhone(query))
+	if err != nil {
+		return model.User{}, fmt.Errorf("penerima tidak ditemukan")
+	}
+	return user, nil
+}
+
+func (u *userUseCase) LookupRecipient(query, userId string) (dto.RecipientResponse, error) {
+	user, err := u.ResolveRecipient(query)
+	if err != nil {
+		return dto.RecipientResponse{}, fmt.Errorf("penerima tidak ditemukan")
+	}
+	if user.Id == userId {
+		return dto.RecipientResponse{}, fmt.Errorf("tidak dapat transfer ke akun sendiri")
+	}
+	return dto.RecipientResponse{
+		NamaTampilan: maskName(user.Name),
+		PaymentTag:   user.PaymentTag,
+	}, nil
+}
+
+func (u *userUseCase) SetPaymentTag(userId, tag string) (model.User, error) {
+	tag = strings.ToLower(strings.TrimLeft(strings.TrimSpace(tag), "@$"))
+	if !paymentTagPattern.MatchString(tag) {
+		return model.User{}, fmt.Errorf("payment tag harus 3-30 karakter huruf kecil, angka, titik atau underscore")
+	}
+	return u.repo.UpdatePaymentTag(userId, tag)
+}
+
+// alternatePhone mengubah format 08xx <-> +628xx agar kedua penulisan nomor hp tetap cocok
+func alternatePhone(query string) string {
+	switch {
+	case strings.HasPrefix(query, "+62"):
+		return "0" + query[3:]
+	case strings.HasPrefix(query, "62"):
+		return "0" + query[2:]
+	case strings.HasPrefix(query, "0"):
+		return "+62" + query[1:]
+	}
+	return query
+}
+
+// maskName hanya menampilkan dua huruf pertama tiap kata, contoh "Budi Santoso" -> "Bu** Sa*****"
+func maskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		runes := []rune(word)
+		keep := 2
+		if len(runes) <= 2 {
+			keep = 1
+		}
+		for j := keep; j < len(runes); j++ {
+			runes[j] = '*'
+		}
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+func NewUserUseCase(repo repository.UserRepository, inquiry bank.BankInquiry) UserUseCase {
+	return &userUseCase{repo: repo, inquiry: inquiry}
 }
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,7 @@ import (
 	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
 	"github.com/yafireyhan01/e-wallet/model"
 	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/utils/bank"
 )
 
 type UserUseCaseTestSuite struct {
@@ -19,7 +21,7 @@ type UserUseCaseTestSuite struct {
 
 func (suite *UserUseCaseTestSuite) SetupTest() {
 	suite.urm = new(repomock.UserRepoMock)
-	suite.uu = NewUserUseCase(suite.urm)
+	suite.uu = NewUserUseCase(suite.urm, bank.NewFakeBankInquiry())
 }
 
 func TestUserUseCaseTestSuite(t *testing.T) {
@@ -120,10 +122,73 @@ func (suite *UserUseCaseTestSuite) TestFindRekening_Success() {
 }
 
 func (suite *UserUseCaseTestSuite) TestCreateRekening_Success() {
-	payloadMock := model.Rekening{}
+	payloadMock := model.Rekening{BankCode: "014", Rekening: "1234567890", NamaPemilik: "user 1"}
 	suite.urm.On("CreateRekening", payloadMock).Return(payloadMock, nil)
 
 	actual, err := suite.uu.CreateRekening(payloadMock)
 	assert.Nil(suite.T(), err)
 	assert.Equal(suite.T(), payloadMock, actual)
 }
+
+func (suite *UserUseCaseTestSuite) TestCreateRekening_InvalidBankCode() {
+	payloadMock := model.Rekening{BankCode: "xxx", Rekening: "1234567890", NamaPemilik: "user 1"}
+
+	_, err := suite.uu.CreateRekening(payloadMock)
+	assert.Error(suite.T(), err)
+}
+
+func (suite *UserUseCaseTestSuite) TestInquiryRekening_Success() {
+	rekeningMock := model.Rekening{Id: "rek-1", UserId: "1", BankCode: "014", Rekening: "1234567890", NamaPemilik: "User  Satu"}
+	verified := rekeningMock
+	verified.IsVerified = true
+	suite.urm.On("GetRekeningById", "rek-1").Return(rekeningMock, nil)
+	suite.urm.On("GetBalance", "1").Return(model.UserSaldo{Pin: "123456"}, nil)
+	suite.urm.On("Get", "1").Return(model.User{Id: "1", Name: "user satu"}, nil)
+	suite.urm.On("VerifyRekening", "rek-1").Return(verified, nil)
+
+	actual, err := suite.uu.InquiryRekening("rek-1", "1")
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), actual.IsVerified)
+}
+
+func (suite *UserUseCaseTestSuite) TestInquiryRekening_NameMismatch() {
+	rekeningMock := model.Rekening{Id: "rek-1", UserId: "1", BankCode: "014", Rekening: "9991234567", NamaPemilik: "user satu"}
+	suite.urm.On("GetRekeningById", "rek-1").Return(rekeningMock, nil)
+	suite.urm.On("GetBalance", "1").Return(model.UserSaldo{Pin: "123456"}, nil)
+	suite.urm.On("Get", "1").Return(model.User{Id: "1", Name: "user satu"}, nil)
+
+	_, err := suite.uu.InquiryRekening("rek-1", "1")
+	assert.Error(suite.T(), err)
+	suite.urm.AssertNotCalled(suite.T(), "VerifyRekening", "rek-1")
+}
+
+func (suite *UserUseCaseTestSuite) TestResolveRecipient_ByPhone() {
+	expected := model.User{Id: "b", Name: "Budi Santoso"}
+	suite.urm.On("GetByRecipient", "+6281234", "081234").Return(expected, nil)
+
+	actual, err := suite.uu.ResolveRecipient(" +6281234 ")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), expected.Id, actual.Id)
+}
+
+func (suite *UserUseCaseTestSuite) TestLookupRecipient_MasksName() {
+	suite.urm.On("GetByPaymentTag", "budi").Return(model.User{Id: "b", Name: "Budi Santoso", PaymentTag: "budi"}, nil)
+
+	actual, err := suite.uu.LookupRecipient("@budi", "a")
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), "Bu** Sa*****", actual.NamaTampilan)
+	suite.urm.AssertNotCalled(suite.T(), "GetByRecipient", mock.Anything, mock.Anything)
+}
+
+func (suite *UserUseCaseTestSuite) TestLookupRecipient_NotFound() {
+	suite.urm.On("GetByRecipient", "nobody", "nobody").Return(model.User{}, fmt.Errorf("sql: no rows in result set"))
+
+	_, err := suite.uu.LookupRecipient("nobody", "a")
+	assert.EqualError(suite.T(), err, "penerima tidak ditemukan")
+}
+
+func (suite *UserUseCaseTestSuite) TestSetPaymentTag_Invalid() {
+	_, err := suite.uu.SetPaymentTag("a", "A!")
+	assert.Error(suite.T(), err)
+	suite.urm.AssertNotCalled(suite.T(), "UpdatePaymentTag", mock.Anything, mock.Anything)
+}
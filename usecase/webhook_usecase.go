@@ -0,0 +1,410 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/model/dto"
+	"github.com/yafireyhan01/e-wallet/repository"
+	"github.com/yafireyhan01/e-wallet/utils/encryption"
+)
+
+const (
+	webhookMaxAttempts = 8
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	webhookLease       = 2 * time.Minute
+	webhookBatchSize   = 50
+)
+
+type WebhookUseCase interface {
+	RegisterEndpoint(merchantId, ownerId string, payload dto.WebhookEndpointRequest) (model.WebhookEndpoint, error)
+	FindEndpoints(merchantId, ownerId string) ([]model.WebhookEndpoint, error)
+	UpdateEndpoint(id, merchantId, ownerId string, payload dto.WebhookEndpointRequest) (model.WebhookEndpoint, error)
+	SendTest(id, merchantId, ownerId string) (model.WebhookDelivery, error)
+	FindDeliveries(id, merchantId, ownerId, status string, page int) ([]model.WebhookDelivery, error)
+	FindDelivery(deliveryId, id, merchantId, ownerId string) (model.WebhookDelivery, error)
+	Redeliver(deliveryId, id, merchantId, ownerId string) (model.WebhookDelivery, error)
+	Publish(merchantId, eventType string, data any) error
+	RunDue(now time.Time) (int, error)
+}
+
+type webhookUseCase struct {
+	repo     repository.WebhookRepository
+	merchant repository.MerchantRepository
+	client   *http.Client
+}
+
+// SignWebhook menghasilkan header X-Webhook-Signature "t=<unix>,v1=<hex HMAC-SHA256(secret, t.body)>"
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	ts := strconv.FormatInt(timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff menghitung jeda sebelum percobaan berikutnya: 30 detik, 1 menit, 2 menit, ... maksimal 6 jam
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return backoff
+}
+
+func newEventId() (string, error) {
+	raw, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	b, _ := hex.DecodeString(raw)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+func validateWebhook(payload dto.WebhookEndpointRequest) error {
+	u, err := url.Parse(payload.Url)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("url webhook harus berupa URL http atau https")
+	}
+	if len(payload.Url) > 500 {
+		return fmt.Errorf("url webhook maksimal 500 karakter")
+	}
+	for _, event := range payload.Events {
+		valid := false
+		for _, e := range model.WebhookEvents {
+			if e == event {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("event %s tidak dikenal", event)
+		}
+	}
+	return nil
+}
+
+func (w *webhookUseCase) checkOwner(merchantId, ownerId string) error {
+	merchant, err := w.merchant.Get(merchantId)
+	if err != nil {
+		return err
+	}
+	if merchant.OwnerId != ownerId {
+		return fmt.Errorf("merchant %s tidak ditemukan", merchantId)
+	}
+	return nil
+}
+
+func (w *webhookUseCase) findEndpoint(id, merchantId, ownerId string) (model.WebhookEndpoint, error) {
+	if err := w.checkOwner(merchantId, ownerId); err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	endpoint, err := w.repo.GetEndpoint(id)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	if endpoint.MerchantId != merchantId {
+		return model.WebhookEndpoint{}, fmt.Errorf("webhook %s tidak ditemukan", id)
+	}
+	return endpoint, nil
+}
+
+// RegisterEndpoint mendaftarkan URL penerima webhook, secret untuk verifikasi tanda tangan hanya ditampilkan sekali
+func (w *webhookUseCase) RegisterEndpoint(merchantId, ownerId string, payload dto.WebhookEndpointRequest) (model.WebhookEndpoint, error) {
+	if err := w.checkOwner(merchantId, ownerId); err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	if err := validateWebhook(payload); err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	secret = "whsec_" + secret
+	secretEnc, err := encryption.EncryptSecret(secret)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+
+	endpoint, err := w.repo.CreateEndpoint(model.WebhookEndpoint{
+		MerchantId: merchantId,
+		Url:        payload.Url,
+		SecretEnc:  secretEnc,
+		Events:     payload.Events,
+	})
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	endpoint.Secret = secret
+
+	return endpoint, nil
+}
+
+func (w *webhookUseCase) FindEndpoints(merchantId, ownerId string) ([]model.WebhookEndpoint, error) {
+	if err := w.checkOwner(merchantId, ownerId); err != nil {
+		return []model.WebhookEndpoint{}, err
+	}
+	datas, err := w.repo.GetEndpoints(merchantId)
+	if err != nil {
+		return []model.WebhookEndpoint{}, err
+	}
+
+	return datas, nil
+}
+
+func (w *webhookUseCase) UpdateEndpoint(id, merchantId, ownerId string, payload dto.WebhookEndpointRequest) (model.WebhookEndpoint, error) {
+	endpoint, err := w.findEndpoint(id, merchantId, ownerId)
+	if err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	if err := validateWebhook(payload); err != nil {
+		return model.WebhookEndpoint{}, err
+	}
+	endpoint.Url = payload.Url
+	endpoint.Events = payload.Events
+	if payload.IsActive != nil {
+		endpoint.IsActive = *payload.IsActive
+	}
+
+	return w.repo.UpdateEndpoint(endpoint)
+}
+
+func newWebhookDelivery(endpointId string, event model.WebhookEvent) (model.WebhookDelivery, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	return model.WebhookDelivery{
+		EndpointId:    endpointId,
+		EventId:       event.Id,
+		EventType:     event.Type,
+		Payload:       body,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+func newWebhookEvent(merchantId, eventType string, data any) (model.WebhookEvent, error) {
+	eventId, err := newEventId()
+	if err != nil {
+		return model.WebhookEvent{}, err
+	}
+	return model.WebhookEvent{Id: eventId, Type: eventType, MerchantId: merchantId, CreatedAt: time.Now(), Data: data}, nil
+}
+
+// Publish mencatat event untuk setiap endpoint yang melanggan, pengiriman dilakukan oleh dispatcher (RunDue)
+func (w *webhookUseCase) Publish(merchantId, eventType string, data any) error {
+	endpoints, err := w.repo.GetSubscribedEndpoints(merchantId, eventType)
+	if err != nil || len(endpoints) == 0 {
+		return err
+	}
+	event, err := newWebhookEvent(merchantId, eventType, data)
+	if err != nil {
+		return err
+	}
+	var deliveries []model.WebhookDelivery
+	for _, endpoint := range endpoints {
+		delivery, err := newWebhookDelivery(endpoint.Id, event)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	_, err = w.repo.CreateDeliveries(deliveries)
+
+	return err
+}
+
+// SendTest mengirim event webhook.test langsung ke endpoint dan mengembalikan hasilnya
+func (w *webhookUseCase) SendTest(id, merchantId, ownerId string) (model.WebhookDelivery, error) {
+	endpoint, err := w.findEndpoint(id, merchantId, ownerId)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	event, err := newWebhookEvent(merchantId, model.WebhookEventTest, map[string]string{"pesan": "webhook berhasil dikonfigurasi"})
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	delivery, err := newWebhookDelivery(endpoint.Id, event)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	deliveries, err := w.repo.CreateDeliveries([]model.WebhookDelivery{delivery})
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if err := w.deliver(deliveries[0], endpoint, time.Now()); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return w.repo.GetDelivery(deliveries[0].Id)
+}
+
+func (w *webhookUseCase) FindDeliveries(id, merchantId, ownerId, status string, page int) ([]model.WebhookDelivery, error) {
+	if _, err := w.findEndpoint(id, merchantId, ownerId); err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+	datas, err := w.repo.GetDeliveries(id, status, page)
+	if err != nil {
+		return []model.WebhookDelivery{}, err
+	}
+
+	return datas, nil
+}
+
+func (w *webhookUseCase) FindDelivery(deliveryId, id, merchantId, ownerId string) (model.WebhookDelivery, error) {
+	if _, err := w.findEndpoint(id, merchantId, ownerId); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	delivery, err := w.repo.GetDelivery(deliveryId)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if delivery.EndpointId != id {
+		return model.WebhookDelivery{}, fmt.Errorf("pengiriman webhook %s tidak ditemukan", deliveryId)
+	}
+
+	return delivery, nil
+}
+
+// Redeliver mengirim ulang delivery saat itu juga, termasuk yang sudah dead atau delivered
+func (w *webhookUseCase) Redeliver(deliveryId, id, merchantId, ownerId string) (model.WebhookDelivery, error) {
+	endpoint, err := w.findEndpoint(id, merchantId, ownerId)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	delivery, err := w.FindDelivery(deliveryId, id, merchantId, ownerId)
+	if err != nil {
+		return model.WebhookDelivery{}, err
+	}
+	if delivery.Status == model.WebhookDeliveryPending {
+		claimed, err := w.repo.ClaimDue(delivery.Id, delivery.NextAttemptAt, time.Now().Add(webhookLease))
+		if err != nil {
+			return model.WebhookDelivery{}, err
+		}
+		if !claimed {
+			return model.WebhookDelivery{}, fmt.Errorf("webhook sedang dikirim, coba lagi nanti")
+		}
+	}
+	if err := w.deliver(delivery, endpoint, time.Now()); err != nil {
+		return model.WebhookDelivery{}, err
+	}
+
+	return w.repo.GetDelivery(delivery.Id)
+}
+
+// RunDue dipanggil dispatcher secara berkala, mengembalikan jumlah delivery yang dicoba
+func (w *webhookUseCase) RunDue(now time.Time) (int, error) {
+	datas, err := w.repo.GetDue(now, webhookBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	attempted := 0
+	endpoints := map[string]model.WebhookEndpoint{}
+	for _, data := range datas {
+		claimed, err := w.repo.ClaimDue(data.Id, data.NextAttemptAt, now.Add(webhookLease))
+		if err != nil {
+			return attempted, err
+		}
+		if !claimed {
+			continue
+		}
+		endpoint, ok := endpoints[data.EndpointId]
+		if !ok {
+			endpoint, err = w.repo.GetEndpoint(data.EndpointId)
+			if err != nil {
+				return attempted, err
+			}
+			endpoints[data.EndpointId] = endpoint
+		}
+		if err := w.deliver(data, endpoint, now); err != nil {
+			return attempted, err
+		}
+		attempted++
+	}
+
+	return attempted, nil
+}
+
+// deliver melakukan satu percobaan pengiriman dan mencatatnya ke delivery log. Gagal setelah
+// webhookMaxAttempts percobaan menjadikan delivery dead (dead-letter) sampai dikirim ulang manual.
+func (w *webhookUseCase) deliver(delivery model.WebhookDelivery, endpoint model.WebhookEndpoint, now time.Time) error {
+	delivery.Attempts++
+	attempt := model.WebhookAttempt{Attempt: delivery.Attempts, CreatedAt: now}
+
+	start := time.Now()
+	statusCode, err := w.send(delivery, endpoint, now)
+	attempt.DurationMs = int(time.Since(start).Milliseconds())
+	attempt.StatusCode = statusCode
+	if err == nil && (statusCode < 200 || statusCode > 299) {
+		err = fmt.Errorf("endpoint merespon HTTP %d", statusCode)
+	}
+
+	delivery.LastError = ""
+	switch {
+	case err == nil:
+		delivery.Status = model.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+	case !endpoint.IsActive || delivery.Attempts >= webhookMaxAttempts:
+		delivery.Status = model.WebhookDeliveryDead
+	default:
+		delivery.Status = model.WebhookDeliveryPending
+		delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+		if len(attempt.Error) > 250 {
+			attempt.Error = attempt.Error[:250]
+		}
+		delivery.LastError = attempt.Error
+	}
+
+	return w.repo.RecordAttempt(delivery, attempt)
+}
+
+func (w *webhookUseCase) send(delivery model.WebhookDelivery, endpoint model.WebhookEndpoint, now time.Time) (int, error) {
+	if !endpoint.IsActive {
+		return 0, fmt.Errorf("endpoint webhook tidak aktif")
+	}
+	secret, err := encryption.DecryptSecret(endpoint.SecretEnc)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Id", delivery.EventId)
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Signature", SignWebhook(secret, now.Unix(), delivery.Payload))
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+
+	return res.StatusCode, nil
+}
+
+func NewWebhookUseCase(repo repository.WebhookRepository, merchant repository.MerchantRepository, client *http.Client) WebhookUseCase {
+	return &webhookUseCase{repo: repo, merchant: merchant, client: client}
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	repomock "github.com/yafireyhan01/e-wallet/mock/repo_mock"
+	"github.com/yafireyhan01/e-wallet/model"
+	"github.com/yafireyhan01/e-wallet/utils/encryption"
+)
+
+type WebhookUseCaseTestSuite struct {
+	suite.Suite
+	wrm      *repomock.WebhookRepoMock
+	wu       WebhookUseCase
+	receiver *httptest.Server
+	status   int
+	received *http.Request
+	body     []byte
+	now      time.Time
+}
+
+func (suite *WebhookUseCaseTestSuite) SetupTest() {
+	suite.wrm = new(repomock.WebhookRepoMock)
+	suite.status = http.StatusOK
+	suite.receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.received = r
+		suite.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(suite.status)
+	}))
+	suite.wu = NewWebhookUseCase(suite.wrm, nil, suite.receiver.Client())
+	suite.now = time.Now().Truncate(time.Second)
+
+	suite.T().Setenv("API_SECRET_KEY", "test-secret-key")
+	secretEnc, err := encryption.EncryptSecret("whsec_test")
+	suite.Require().Nil(err)
+	suite.wrm.On("GetEndpoint", "wh-1").Return(model.WebhookEndpoint{Id: "wh-1", MerchantId: "m-1", Url: suite.receiver.URL,
+		SecretEnc: secretEnc, IsActive: true}, nil)
+	suite.wrm.On("ClaimDue", "dlv-1", mock.Anything, mock.Anything).Return(true, nil)
+}
+
+func (suite *WebhookUseCaseTestSuite) TearDownTest() {
+	suite.receiver.Close()
+}
+
+func TestWebhookUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookUseCaseTestSuite))
+}
+
+func (suite *WebhookUseCaseTestSuite) due(attempts int) {
+	suite.wrm.On("GetDue", suite.now, webhookBatchSize).Return([]model.WebhookDelivery{{Id: "dlv-1", EndpointId: "wh-1",
+		EventId: "evt-1", EventType: model.WebhookEventPaymentSucceeded, Payload: []byte(`{"id":"evt-1"}`),
+		Status: model.WebhookDeliveryPending, Attempts: attempts, NextAttemptAt: suite.now}}, nil)
+}
+
+func (suite *WebhookUseCaseTestSuite) TestRunDue_DeliversSignedPayload() {
+	suite.due(0)
+	suite.wrm.On("RecordAttempt", mock.MatchedBy(func(d model.WebhookDelivery) bool {
+		return d.Status == model.WebhookDeliveryDelivered && d.Attempts == 1 && d.DeliveredAt != nil
+	}), mock.MatchedBy(func(a model.WebhookAttempt) bool {
+		return a.Attempt == 1 && a.StatusCode == http.StatusOK
+	})).Return(nil)
+
+	actual, err := suite.wu.RunDue(suite.now)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), 1, actual)
+	assert.Equal(suite.T(), `{"id":"evt-1"}`, string(suite.body))
+	assert.Equal(suite.T(), model.WebhookEventPaymentSucceeded, suite.received.Header.Get("X-Webhook-Event"))
+	assert.Equal(suite.T(), SignWebhook("whsec_test", suite.now.Unix(), suite.body), suite.received.Header.Get("X-Webhook-Signature"))
+	suite.wrm.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUseCaseTestSuite) TestRunDue_FailureSchedulesBackoff() {
+	suite.status = http.StatusInternalServerError
+	suite.due(2)
+	suite.wrm.On("RecordAttempt", mock.MatchedBy(func(d model.WebhookDelivery) bool {
+		return d.Status == model.WebhookDeliveryPending && d.Attempts == 3 && d.NextAttemptAt.Equal(suite.now.Add(2*time.Minute))
+	}), mock.MatchedBy(func(a model.WebhookAttempt) bool {
+		return a.StatusCode == http.StatusInternalServerError && a.Error != ""
+	})).Return(nil)
+
+	_, err := suite.wu.RunDue(suite.now)
+	assert.Nil(suite.T(), err)
+	suite.wrm.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookUseCaseTestSuite) TestRunDue_DeadAfterMaxAttempts() {
+	suite.status = http.StatusBadGateway
+	suite.due(webhookMaxAttempts - 1)
+	suite.wrm.On("RecordAttempt", mock.MatchedBy(func(d model.WebhookDelivery) bool {
+		return d.Status == model.WebhookDeliveryDead && d.Attempts == webhookMaxAttempts
+	}), mock.Anything).Return(nil)
+
+	_, err := suite.wu.RunDue(suite.now)
+	assert.Nil(suite.T(), err)
+	suite.wrm.AssertExpectations(suite.T())
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, webhookBackoff(1))
+	assert.Equal(t, 4*time.Minute, webhookBackoff(4))
+	assert.Equal(t, webhookMaxBackoff, webhookBackoff(20))
+}
@@ -0,0 +1,63 @@
+package bank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BankCodes berisi kode bank (kode kliring) yang didukung untuk payout
+var BankCodes = map[string]string{
+	"002": "BRI",
+	"008": "Mandiri",
+	"009": "BNI",
+	"014": "BCA",
+	"022": "CIMB Niaga",
+	"451": "BSI",
+}
+
+func IsValidBankCode(code string) bool {
+	_, ok := BankCodes[code]
+	return ok
+}
+
+type InquiryRequest struct {
+	BankCode    string
+	Rekening    string
+	NamaPemilik string
+}
+
+type InquiryResult struct {
+	NamaPemilik string
+}
+
+// BankInquiry memeriksa nama pemilik rekening ke bank tujuan sebelum rekening dipakai
+type BankInquiry interface {
+	Inquire(payload InquiryRequest) (InquiryResult, error)
+}
+
+type fakeBankInquiry struct{}
+
+// Inquire mengembalikan nama yang didaftarkan user, kecuali rekening diawali "000" (tidak ditemukan)
+// atau "999" (milik orang lain), untuk mensimulasikan respon bank
+func (f *fakeBankInquiry) Inquire(payload InquiryRequest) (InquiryResult, error) {
+	if !IsValidBankCode(payload.BankCode) {
+		return InquiryResult{}, fmt.Errorf("kode bank %s tidak dikenal", payload.BankCode)
+	}
+	if strings.HasPrefix(payload.Rekening, "000") {
+		return InquiryResult{}, fmt.Errorf("rekening %s tidak ditemukan", payload.Rekening)
+	}
+	if strings.HasPrefix(payload.Rekening, "999") {
+		return InquiryResult{NamaPemilik: "PEMILIK LAIN"}, nil
+	}
+
+	return InquiryResult{NamaPemilik: strings.ToUpper(payload.NamaPemilik)}, nil
+}
+
+func NewFakeBankInquiry() BankInquiry {
+	return &fakeBankInquiry{}
+}
+
+// SameName membandingkan nama tanpa memperhatikan huruf besar kecil dan spasi berlebih
+func SameName(a, b string) bool {
+	return strings.EqualFold(strings.Join(strings.Fields(a), " "), strings.Join(strings.Fields(b), " "))
+}
@@ -0,0 +1,46 @@
+package payout
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type PayoutRequest struct {
+	ReferenceId string
+	BankCode    string
+	Rekening    string
+	Jumlah      int
+}
+
+type PayoutResult struct {
+	ProviderRef string
+	Success     bool
+	Alasan      string
+}
+
+// PayoutProvider adalah abstraksi bank/penyedia disbursement untuk mengirim dana ke rekening user
+type PayoutProvider interface {
+	Disburse(payload PayoutRequest) (PayoutResult, error)
+}
+
+type fakePayoutProvider struct{}
+
+// Disburse selalu berhasil kecuali nomor rekening diawali "000", untuk mensimulasikan rekening tidak valid
+func (f *fakePayoutProvider) Disburse(payload PayoutRequest) (PayoutResult, error) {
+	if payload.Jumlah <= 0 {
+		return PayoutResult{}, fmt.Errorf("jumlah payout tidak valid")
+	}
+	if strings.HasPrefix(payload.Rekening, "000") {
+		return PayoutResult{Success: false, Alasan: "rekening tujuan tidak valid"}, nil
+	}
+
+	return PayoutResult{
+		ProviderRef: fmt.Sprintf("FAKE-%s-%d", payload.ReferenceId, time.Now().Unix()),
+		Success:     true,
+	}, nil
+}
+
+func NewFakePayoutProvider() PayoutProvider {
+	return &fakePayoutProvider{}
+}
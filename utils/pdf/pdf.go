@@ -0,0 +1,123 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ukuran halaman A4 dalam point
+const (
+	PageWidth  = 595
+	PageHeight = 842
+)
+
+// font standar PDF (Type1) yang tidak perlu di-embed
+const (
+	FontRegular = "F1"
+	FontBold    = "F2"
+	FontMono    = "F3"
+)
+
+var baseFonts = []struct{ name, base string }{
+	{FontRegular, "Helvetica"},
+	{FontBold, "Helvetica-Bold"},
+	{FontMono, "Courier"},
+}
+
+// Document adalah penulis PDF minimal: hanya teks dan garis horizontal dengan font standar
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+func New() *Document {
+	return &Document{}
+}
+
+// AddPage membuka halaman baru, perintah berikutnya ditulis ke halaman ini
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+func (d *Document) current() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// Text menulis satu baris teks, y dihitung dari atas halaman
+func (d *Document) Text(x, y float64, font string, size float64, text string) {
+	fmt.Fprintf(d.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(text))
+}
+
+// Line menggambar garis horizontal dari x1 ke x2 pada posisi y
+func (d *Document) Line(x1, x2, y float64) {
+	fmt.Fprintf(d.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y, x2, PageHeight-y)
+}
+
+// escape meng-escape karakter khusus string PDF, karakter di luar ASCII diganti '?' karena font standar
+// hanya dipakai dengan encoding WinAnsi
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// WriteTo menyusun objek PDF beserta tabel xref dengan offset byte yang tepat
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	var objects []string
+	add := func(body string) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+
+	catalog := add("")
+	pagesObj := add("")
+	var fontRefs []string
+	for _, font := range baseFonts {
+		id := add(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font.base))
+		fontRefs = append(fontRefs, fmt.Sprintf("/%s %d 0 R", font.name, id))
+	}
+	resources := fmt.Sprintf("<< /Font << %s >> >>", strings.Join(fontRefs, " "))
+
+	var kids []string
+	for _, page := range d.pages {
+		content := add(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()))
+		id := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources %s /Contents %d 0 R >>",
+			pagesObj, PageWidth, PageHeight, resources, content))
+		kids = append(kids, fmt.Sprintf("%d 0 R", id))
+	}
+	objects[catalog-1] = fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj)
+	objects[pagesObj-1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, body)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, xref)
+
+	return buf.WriteTo(w)
+}
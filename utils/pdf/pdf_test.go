@@ -0,0 +1,43 @@
+package pdf
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTo_XrefOffsets(t *testing.T) {
+	doc := New()
+	doc.Text(40, 40, FontBold, 14, "Rekening Koran (Mei)")
+	doc.Line(40, 555, 50)
+	doc.AddPage()
+	doc.Text(40, 40, FontMono, 9, "halaman 2")
+
+	var buf bytes.Buffer
+	_, err := doc.WriteTo(&buf)
+	assert.Nil(t, err)
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	assert.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	assert.Contains(t, out, `(Rekening Koran \(Mei\)) Tj`)
+	assert.Contains(t, out, "/Count 2")
+
+	// setiap entri xref harus menunjuk tepat ke awal objeknya
+	entries := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllStringSubmatch(out, -1)
+	assert.NotEmpty(t, entries)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(entry[1])
+		assert.True(t, strings.HasPrefix(out[offset:], strconv.Itoa(i+1)+" 0 obj"), "objek %d", i+1)
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindStringSubmatch(out)
+	offset, _ := strconv.Atoi(startxref[1])
+	assert.True(t, strings.HasPrefix(out[offset:], "xref"))
+}
+
+func TestEscape_NonASCII(t *testing.T) {
+	assert.Equal(t, `a\\b ?`, escape(`a\b é`))
+}
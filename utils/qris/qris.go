@@ -0,0 +1,153 @@
+package qris
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GUI merchant account information (tag 26) milik wallet ini
+const GlobalUniqueIdentifier = "ID.CO.EWALLET.WWW"
+
+const (
+	initiationStatic  = "11"
+	initiationDynamic = "12"
+	currencyIDR       = "360"
+	countryCode       = "ID"
+)
+
+// Payload adalah isi QR merchant-presented mengikuti format EMVCo/QRIS
+type Payload struct {
+	Dynamic      bool
+	MerchantId   string
+	MerchantName string
+	MerchantCity string
+	CategoryCode string
+	Jumlah       int
+	// reference label (tag 62 sub 05), diisi id QR dinamis
+	Referensi string
+}
+
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
+
+// Encode menyusun payload QR beserta CRC16 pada tag 63
+func Encode(p Payload) string {
+	initiation := initiationStatic
+	if p.Dynamic {
+		initiation = initiationDynamic
+	}
+	var b strings.Builder
+	b.WriteString(tlv("00", "01"))
+	b.WriteString(tlv("01", initiation))
+	b.WriteString(tlv("26", tlv("00", GlobalUniqueIdentifier)+tlv("01", p.MerchantId)))
+	b.WriteString(tlv("52", p.CategoryCode))
+	b.WriteString(tlv("53", currencyIDR))
+	if p.Jumlah > 0 {
+		b.WriteString(tlv("54", strconv.Itoa(p.Jumlah)))
+	}
+	b.WriteString(tlv("58", countryCode))
+	b.WriteString(tlv("59", truncate(p.MerchantName, 25)))
+	b.WriteString(tlv("60", truncate(p.MerchantCity, 15)))
+	if p.Referensi != "" {
+		b.WriteString(tlv("62", tlv("05", p.Referensi)))
+	}
+	b.WriteString("6304")
+
+	return b.String() + fmt.Sprintf("%04X", CRC16(b.String()))
+}
+
+// Decode memeriksa CRC lalu membaca tag yang dipakai wallet ini
+func Decode(raw string) (Payload, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) < 8 || raw[len(raw)-8:len(raw)-4] != "6304" {
+		return Payload{}, fmt.Errorf("format QR tidak valid")
+	}
+	crc, err := strconv.ParseUint(raw[len(raw)-4:], 16, 16)
+	if err != nil || uint16(crc) != CRC16(raw[:len(raw)-4]) {
+		return Payload{}, fmt.Errorf("checksum QR tidak valid")
+	}
+	tags, err := parseTLV(raw[:len(raw)-8])
+	if err != nil {
+		return Payload{}, err
+	}
+	if tags["00"] != "01" {
+		return Payload{}, fmt.Errorf("versi QR tidak didukung")
+	}
+
+	p := Payload{
+		Dynamic:      tags["01"] == initiationDynamic,
+		CategoryCode: tags["52"],
+		MerchantName: tags["59"],
+		MerchantCity: tags["60"],
+	}
+	account, err := parseTLV(tags["26"])
+	if err != nil {
+		return Payload{}, err
+	}
+	if account["00"] != GlobalUniqueIdentifier || account["01"] == "" {
+		return Payload{}, fmt.Errorf("QR bukan milik merchant e-wallet ini")
+	}
+	p.MerchantId = account["01"]
+	if tags["53"] != "" && tags["53"] != currencyIDR {
+		return Payload{}, fmt.Errorf("mata uang QR tidak didukung")
+	}
+	if amount := tags["54"]; amount != "" {
+		jumlah, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return Payload{}, fmt.Errorf("jumlah pada QR tidak valid")
+		}
+		p.Jumlah = int(jumlah)
+	}
+	if tags["62"] != "" {
+		additional, err := parseTLV(tags["62"])
+		if err != nil {
+			return Payload{}, err
+		}
+		p.Referensi = additional["05"]
+	}
+
+	return p, nil
+}
+
+func parseTLV(data string) (map[string]string, error) {
+	tags := map[string]string{}
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("format QR tidak valid")
+		}
+		length, err := strconv.Atoi(data[2:4])
+		if err != nil || len(data) < 4+length {
+			return nil, fmt.Errorf("format QR tidak valid")
+		}
+		tags[data[:2]] = data[4 : 4+length]
+		data = data[4+length:]
+	}
+
+	return tags, nil
+}
+
+// CRC16 menghitung CRC16-CCITT (poly 0x1021, init 0xFFFF) sesuai spesifikasi EMVCo
+func CRC16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
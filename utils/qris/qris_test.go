@@ -0,0 +1,33 @@
+package qris
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x29B1), CRC16("123456789"))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	payload := Payload{
+		Dynamic:      true,
+		MerchantId:   "5f0c6c1e-4b1a-4c3e-9a53-2f7d0f2b8c11",
+		MerchantName: "Warung Kopi Sejahtera Selalu Jaya",
+		MerchantCity: "Jakarta",
+		CategoryCode: "5814",
+		Jumlah:       25000,
+		Referensi:    "a1b2c3",
+	}
+
+	raw := Encode(payload)
+	actual, err := Decode(raw)
+	assert.Nil(t, err)
+	payload.MerchantName = "Warung Kopi Sejahtera Sel"
+	assert.Equal(t, payload, actual)
+
+	_, err = Decode(strings.Replace(raw, "25000", "15000", 1))
+	assert.Error(t, err)
+}